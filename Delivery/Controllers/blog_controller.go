@@ -2,6 +2,8 @@ package controllers
 
 import (
 	domain "blog-api/Domain"
+	"context"
+	"errors"
 	"net/http"
 	"time"
 
@@ -12,19 +14,28 @@ type createBlogRequest struct {
 	Title   string   `json:"title"`
 	Content string   `json:"content"`
 	Tags    []string `json:"tags"`
+	Status  string   `json:"status"` // "draft" (default) or "published"
+	// "plain" (default), "markdown" or "html"
+	ContentFormat string `json:"content_format"`
+	// "open" (default), "closed" or "approval"
+	CommentMode string `json:"comment_mode"`
+	// Optional RFC 3339 time; a future value schedules the blog
+	PublishAt *time.Time `json:"publish_at"`
 }
 
-type updateBlogRequest struct {
-	Title   string   `json:"title"`
-	Content string   `json:"content"`
-	Tags    []string `json:"tags"`
+type commentSettingsRequest struct {
+	Mode string `json:"mode" binding:"required"`
 }
 
-type GetBlogsRequest struct {
-	Page     int    `form:"page" binding:"min=1"`
-	Limit    int    `form:"limit" binding:"min=1,max=100"`
-	Sort     string `form:"sort"` // "recent", "popular", "views"
-	AuthorID string `form:"author_id"`
+type scheduleBlogRequest struct {
+	PublishAt time.Time `json:"publish_at" binding:"required"`
+}
+
+type updateBlogRequest struct {
+	Title         string   `json:"title"`
+	Content       string   `json:"content"`
+	ContentFormat string   `json:"content_format"`
+	Tags          []string `json:"tags"`
 }
 
 type PaginatedBlogsResponse struct {
@@ -59,13 +70,17 @@ func (bc *BlogController) CreateBlogHandler(ctx *gin.Context) {
 
 	now := time.Now()
 	blog := &domain.Blog{
-		Title:     req.Title,
-		Content:   req.Content,
-		Tags:      req.Tags,
-		UserID:    userID,
-		CreatedAt: now,
-		UpdatedAt: now,
-		ViewCount: 0,
+		Title:         req.Title,
+		Content:       req.Content,
+		Tags:          req.Tags,
+		UserID:        userID,
+		Status:        domain.BlogStatus(req.Status),
+		PublishAt:     req.PublishAt,
+		ContentFormat: domain.ContentFormat(req.ContentFormat),
+		CommentMode:   domain.CommentMode(req.CommentMode),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		ViewCount:     0,
 	}
 
 	if err := bc.blogUsecase.Create(ctx, blog); err != nil {
@@ -73,15 +88,14 @@ func (bc *BlogController) CreateBlogHandler(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, gin.H{"message": "Blog created successfully"})
+	ctx.JSON(http.StatusCreated, gin.H{"message": "Blog created successfully", "id": blog.ID, "slug": blog.Slug, "status": blog.Status})
 }
 
 func (bc *BlogController) UpdateBlogHandler(ctx *gin.Context) {
 
 	blogID := ctx.Param("id")
 
-	userID, ok := getAuthenticatedUserID(ctx)
-	if !ok {
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
 		return
 	}
 	var req updateBlogRequest
@@ -91,81 +105,33 @@ func (bc *BlogController) UpdateBlogHandler(ctx *gin.Context) {
 	}
 
 	input := domain.UpdateBlogInput{
-		BlogID:  blogID,
-		UserID:  userID,
-		Title:   req.Title,
-		Content: req.Content,
-		Tags:    req.Tags,
+		BlogID:        blogID,
+		Title:         req.Title,
+		Content:       req.Content,
+		ContentFormat: domain.ContentFormat(req.ContentFormat),
+		Tags:          req.Tags,
 	}
 
-	updatedBlog, err := bc.blogUsecase.Update(ctx.Request.Context(), input)
+	updatedBlog, err := bc.blogUsecase.Update(ctx.Request.Context(), input, getViewer(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.JSON(blogErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	ctx.JSON(http.StatusOK, updatedBlog)
 }
 
-func (bc *BlogController) FilterBlogsHandler(ctx *gin.Context) {
-	tag := ctx.Query("tag")
-	date := ctx.Query("date")
-	sort := ctx.DefaultQuery("sort", "recent")
-
-	if sort != "recent" && sort != "popular" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort parameter. Allowed values: recent, popular"})
-		return
-	}
-
-	blogs, err := bc.blogUsecase.FilterBlogs(ctx.Request.Context(), tag, date, sort)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch blogs: " + err.Error()})
-		return
-	}
-
-	ctx.JSON(http.StatusOK, gin.H{
-		"status": "success",
-		"data":   blogs,
-		"meta": gin.H{
-			"filter": gin.H{
-				"tag":  tag,
-				"date": date,
-				"sort": sort,
-			},
-			"count": len(blogs),
-		},
-	})
-}
-
 func (bc *BlogController) DeleteBlog(ctx *gin.Context) {
-	blogID := ctx.Param("id")
-	userID, ok := getAuthenticatedUserID(ctx)
-	if !ok {
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
 		return
 	}
-	userRole := ctx.GetString("role")
-	if err := bc.blogUsecase.DeleteBlog(ctx, blogID, userID, userRole); err != nil {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	if err := bc.blogUsecase.DeleteBlog(ctx.Request.Context(), ctx.Param("id"), getViewer(ctx)); err != nil {
+		ctx.JSON(blogErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	ctx.JSON(http.StatusOK, gin.H{"message": "Blog deleted successfully"})
-
 }
 
-func (bc *BlogController) SearchBlogs(ctx *gin.Context) {
-	tag := ctx.Query("tag")
-	date := ctx.Query("date")
-	sort := ctx.Query("sort")
-	title := ctx.Query("title")
-	userID := ctx.Query("userID")
-
-	blogs, err := bc.blogUsecase.SearchBlogs(ctx.Request.Context(), tag, date, sort, title, userID)
-	if err != nil {
-		ctx.JSON(500, gin.H{"error": err.Error()})
-		return
-	}
-	ctx.JSON(200, blogs)
-}
 func (bc *BlogController) AiSuggestion(ctx *gin.Context) {
 	var req updateBlogRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
@@ -190,23 +156,38 @@ func (bc *BlogController) AiSuggestion(ctx *gin.Context) {
 	ctx.IndentedJSON(http.StatusOK, gin.H{"Suggestion": text})
 }
 
-// handler for getting paginated blogs
+// GetBlogsHandler lists blogs. It also serves /blogs/filter and
+// /blogs/search, which used to be separate endpoints.
 func (bc *BlogController) GetBlogsHandler(ctx *gin.Context) {
-	var req GetBlogsRequest
+	query, err := parseBlogQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	// Set defaults
-	req.Page = 1
-	req.Limit = 10
-	req.Sort = "recent"
+	result, err := bc.blogUsecase.ListBlogs(ctx.Request.Context(), query, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(blogErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
 
-	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters"})
+// GetTrendingBlogsHandler lists published blogs by trending score. It takes
+// the same parameters as GetBlogsHandler, with window defaulting to 7d.
+func (bc *BlogController) GetTrendingBlogsHandler(ctx *gin.Context) {
+	query, err := parseBlogQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	query.Sort = domain.BlogSortTrending
+	query.Status = domain.BlogStatusPublished
 
-	result, err := bc.blogUsecase.GetBlogs(ctx.Request.Context(), req.Page, req.Limit, req.Sort, req.AuthorID)
+	result, err := bc.blogUsecase.ListBlogs(ctx.Request.Context(), query, getViewer(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.JSON(blogErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -217,7 +198,7 @@ func (bc *BlogController) GetBlogsHandler(ctx *gin.Context) {
 func (bc *BlogController) GetBlogByIDHandler(ctx *gin.Context) {
 	blogID := ctx.Param("id")
 
-	blog, err := bc.blogUsecase.GetByIDAndIncrementViews(ctx.Request.Context(), blogID)
+	blog, err := bc.blogUsecase.GetByIDAndIncrementViews(ctx.Request.Context(), blogID, getViewer(ctx), getViewKey(ctx))
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
 		return
@@ -225,3 +206,123 @@ func (bc *BlogController) GetBlogByIDHandler(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, blog)
 }
+
+// Handler for looking a blog up by its slug. Previous slugs answer with a
+// 301 pointing at the current one.
+func (bc *BlogController) GetBlogBySlugHandler(ctx *gin.Context) {
+	slug := ctx.Param("slug")
+
+	blog, err := bc.blogUsecase.GetBySlug(ctx.Request.Context(), slug, getViewer(ctx), getViewKey(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		return
+	}
+
+	if blog.Slug != slug {
+		location := "/blogs/by-slug/" + blog.Slug
+		ctx.Header("Location", location)
+		ctx.JSON(http.StatusMovedPermanently, gin.H{
+			"id":       blog.ID,
+			"slug":     blog.Slug,
+			"location": location,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, blog)
+}
+
+// Handlers for moving a blog between draft, published and archived
+func (bc *BlogController) PublishBlogHandler(ctx *gin.Context) {
+	bc.changeStatus(ctx, bc.blogUsecase.PublishBlog)
+}
+
+func (bc *BlogController) UnpublishBlogHandler(ctx *gin.Context) {
+	bc.changeStatus(ctx, bc.blogUsecase.UnpublishBlog)
+}
+
+func (bc *BlogController) ArchiveBlogHandler(ctx *gin.Context) {
+	bc.changeStatus(ctx, bc.blogUsecase.ArchiveBlog)
+}
+
+func (bc *BlogController) ScheduleBlogHandler(ctx *gin.Context) {
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
+		return
+	}
+	var req scheduleBlogRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "publish_at is required (RFC 3339)"})
+		return
+	}
+
+	blog, err := bc.blogUsecase.ScheduleBlog(ctx.Request.Context(), ctx.Param("id"), req.PublishAt, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(blogErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, blog)
+}
+
+// CommentSettingsHandler sets who may comment on a blog: "open", "closed",
+// or "approval" to hold new comments for moderation.
+func (bc *BlogController) CommentSettingsHandler(ctx *gin.Context) {
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
+		return
+	}
+	var req commentSettingsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "mode is required"})
+		return
+	}
+
+	blog, err := bc.blogUsecase.SetCommentMode(ctx.Request.Context(), ctx.Param("id"), domain.CommentMode(req.Mode), getViewer(ctx))
+	if err != nil {
+		ctx.JSON(blogErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, blog)
+}
+
+// Recompute the like and comment counts stored on blogs (admin only)
+func (bc *BlogController) ReconcileCountsHandler(ctx *gin.Context) {
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
+		return
+	}
+
+	updated, err := bc.blogUsecase.ReconcileCounts(ctx.Request.Context(), getViewer(ctx))
+	if err != nil {
+		ctx.JSON(blogErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"updated": updated})
+}
+
+func (bc *BlogController) changeStatus(ctx *gin.Context, change func(context.Context, string, domain.Viewer) (*domain.Blog, error)) {
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
+		return
+	}
+
+	blog, err := change(ctx.Request.Context(), ctx.Param("id"), getViewer(ctx))
+	if err != nil {
+		ctx.JSON(blogErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, blog)
+}
+
+func blogErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrBlogNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	domain "blog-api/Domain"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseBlogQuery reads the blog listing parameters:
+//
+//	tags=a,b (or repeated tag=a&tag=b)  tag_match=any|all
+//	author (or author_id, userID)       from, to (YYYY-MM-DD or RFC 3339)
+//	date (a single day)                 q (full text)   title
+//	min_views, min_likes                status          sort
+//	window (24h|7d|30d, for sort=trending)
+//	page, limit                         cursor (keyset paging; empty for the first page)
+//
+// A date-only "to" includes that whole day.
+func parseBlogQuery(ctx *gin.Context) (domain.BlogQuery, error) {
+	query := domain.BlogQuery{
+		Tags:     ctx.QueryArray("tag"),
+		TagMatch: domain.TagMatch(ctx.Query("tag_match")),
+		AuthorID: firstQuery(ctx, "author", "author_id", "userID"),
+		Text:     ctx.Query("q"),
+		Title:    ctx.Query("title"),
+		Status:   domain.BlogStatus(ctx.Query("status")),
+		Sort:     domain.BlogSort(ctx.Query("sort")),
+
+		TrendingWindow: domain.TrendingWindow(ctx.Query("window")),
+	}
+
+	if tags := ctx.Query("tags"); tags != "" {
+		query.Tags = append(query.Tags, strings.Split(tags, ",")...)
+	}
+
+	if date := ctx.Query("date"); date != "" {
+		day, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return query, fmt.Errorf("%w: invalid date %q, expected YYYY-MM-DD", domain.ErrInvalidInput, date)
+		}
+		next := day.AddDate(0, 0, 1)
+		query.From, query.To = &day, &next
+	}
+	if from := ctx.Query("from"); from != "" {
+		t, _, err := parseQueryTime(from)
+		if err != nil {
+			return query, fmt.Errorf("%w: invalid from %q, expected YYYY-MM-DD or RFC 3339", domain.ErrInvalidInput, from)
+		}
+		query.From = &t
+	}
+	if to := ctx.Query("to"); to != "" {
+		t, dateOnly, err := parseQueryTime(to)
+		if err != nil {
+			return query, fmt.Errorf("%w: invalid to %q, expected YYYY-MM-DD or RFC 3339", domain.ErrInvalidInput, to)
+		}
+		if dateOnly {
+			t = t.AddDate(0, 0, 1)
+		}
+		query.To = &t
+	}
+
+	var err error
+	if query.MinViews, err = intQuery(ctx, "min_views"); err != nil {
+		return query, err
+	}
+	if query.MinLikes, err = intQuery(ctx, "min_likes"); err != nil {
+		return query, err
+	}
+	if query.Page, err = intQuery(ctx, "page"); err != nil {
+		return query, err
+	}
+	if query.Limit, err = intQuery(ctx, "limit"); err != nil {
+		return query, err
+	}
+	query.Cursor, query.UseCursor = ctx.GetQuery("cursor")
+
+	return query, nil
+}
+
+func firstQuery(ctx *gin.Context, keys ...string) string {
+	for _, key := range keys {
+		if value := ctx.Query(key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func intQuery(ctx *gin.Context, key string) (int, error) {
+	raw := ctx.Query(key)
+	if raw == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s must be a whole number", domain.ErrInvalidInput, key)
+	}
+	return n, nil
+}
+
+// parseQueryTime accepts a date or a full RFC 3339 timestamp and reports
+// which one it got.
+func parseQueryTime(value string) (time.Time, bool, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	return t, false, err
+}
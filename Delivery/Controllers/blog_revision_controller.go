@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	domain "blog-api/Domain"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (bc *BlogController) ListRevisionsHandler(ctx *gin.Context) {
+	revisions, err := bc.blogUsecase.ListRevisions(ctx.Request.Context(), ctx.Param("id"), getViewer(ctx))
+	if err != nil {
+		ctx.JSON(revisionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"revisions": revisions, "count": len(revisions)})
+}
+
+func (bc *BlogController) GetRevisionHandler(ctx *gin.Context) {
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil || version < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "version must be a positive integer"})
+		return
+	}
+
+	revision, err := bc.blogUsecase.GetRevision(ctx.Request.Context(), ctx.Param("id"), version, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(revisionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, revision)
+}
+
+// DiffRevisionsHandler compares ?from= with ?to=; to defaults to 0, the
+// current content of the blog.
+func (bc *BlogController) DiffRevisionsHandler(ctx *gin.Context) {
+	from, err := strconv.Atoi(ctx.Query("from"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "from must be a revision version"})
+		return
+	}
+	to, err := strconv.Atoi(ctx.DefaultQuery("to", "0"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "to must be a revision version or 0 for the current content"})
+		return
+	}
+
+	diff, err := bc.blogUsecase.DiffRevisions(ctx.Request.Context(), ctx.Param("id"), from, to, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(revisionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, diff)
+}
+
+func (bc *BlogController) RestoreRevisionHandler(ctx *gin.Context) {
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil || version < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "version must be a positive integer"})
+		return
+	}
+
+	blog, err := bc.blogUsecase.RestoreRevision(ctx.Request.Context(), ctx.Param("id"), version, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(revisionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, blog)
+}
+
+func revisionErrorStatus(err error) int {
+	if errors.Is(err, domain.ErrRevisionNotFound) {
+		return http.StatusNotFound
+	}
+	return blogErrorStatus(err)
+}
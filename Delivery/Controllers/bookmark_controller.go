@@ -0,0 +1,217 @@
+package controllers
+
+import (
+	domain "blog-api/Domain"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BookmarkController struct {
+	bookmarkUsecase domain.IBookmarkUsecase
+}
+
+func NewBookmarkController(bookmarkUsecase domain.IBookmarkUsecase) *BookmarkController {
+	return &BookmarkController{bookmarkUsecase: bookmarkUsecase}
+}
+
+type readingListRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Public      bool   `json:"public"`
+}
+
+type readingListItemRequest struct {
+	BlogID string `json:"blog_id"`
+	// Position is where to insert the blog, counting from 0; it is
+	// appended when omitted.
+	Position *int `json:"position"`
+}
+
+type reorderReadingListRequest struct {
+	BlogIDs []string `json:"blog_ids"`
+}
+
+// Bookmark a blog
+func (bc *BookmarkController) AddBookmarkHandler(ctx *gin.Context) {
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
+		return
+	}
+	if err := bc.bookmarkUsecase.AddBookmark(ctx.Request.Context(), ctx.Param("id"), getViewer(ctx)); err != nil {
+		ctx.JSON(bookmarkErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Blog bookmarked"})
+}
+
+// Remove a bookmark
+func (bc *BookmarkController) RemoveBookmarkHandler(ctx *gin.Context) {
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
+		return
+	}
+	if err := bc.bookmarkUsecase.RemoveBookmark(ctx.Request.Context(), ctx.Param("id"), getViewer(ctx)); err != nil {
+		ctx.JSON(bookmarkErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Bookmark removed"})
+}
+
+// Get one page of the caller's bookmarks, most recent first
+func (bc *BookmarkController) ListBookmarksHandler(ctx *gin.Context) {
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
+		return
+	}
+	page, err := intQuery(ctx, "page")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	limit, err := intQuery(ctx, "limit")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	bookmarks, err := bc.bookmarkUsecase.ListBookmarks(ctx.Request.Context(), page, limit, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(bookmarkErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, bookmarks)
+}
+
+// Create a reading list
+func (bc *BookmarkController) CreateReadingListHandler(ctx *gin.Context) {
+	var req readingListRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
+		return
+	}
+	list, err := bc.bookmarkUsecase.CreateReadingList(ctx.Request.Context(), domain.ReadingListInput{
+		Name:        req.Name,
+		Description: req.Description,
+		Public:      req.Public,
+	}, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(bookmarkErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusCreated, list)
+}
+
+// Get the caller's reading lists
+func (bc *BookmarkController) ListMyReadingListsHandler(ctx *gin.Context) {
+	userID, ok := getAuthenticatedUserID(ctx)
+	if !ok {
+		return
+	}
+	bc.readingLists(ctx, userID)
+}
+
+// Get a user's public reading lists (all of them for the user themselves)
+func (bc *BookmarkController) ListUserReadingListsHandler(ctx *gin.Context) {
+	bc.readingLists(ctx, ctx.Param("id"))
+}
+
+func (bc *BookmarkController) readingLists(ctx *gin.Context, userID string) {
+	lists, err := bc.bookmarkUsecase.ListReadingLists(ctx.Request.Context(), userID, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(bookmarkErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"reading_lists": lists})
+}
+
+// Get a reading list with its blogs
+func (bc *BookmarkController) GetReadingListHandler(ctx *gin.Context) {
+	list, err := bc.bookmarkUsecase.GetReadingList(ctx.Request.Context(), ctx.Param("listID"), getViewer(ctx))
+	if err != nil {
+		ctx.JSON(bookmarkErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, list)
+}
+
+// Rename a reading list, change its description or visibility
+func (bc *BookmarkController) UpdateReadingListHandler(ctx *gin.Context) {
+	var req readingListRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	list, err := bc.bookmarkUsecase.UpdateReadingList(ctx.Request.Context(), ctx.Param("listID"), domain.ReadingListInput{
+		Name:        req.Name,
+		Description: req.Description,
+		Public:      req.Public,
+	}, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(bookmarkErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, list)
+}
+
+// Delete a reading list
+func (bc *BookmarkController) DeleteReadingListHandler(ctx *gin.Context) {
+	if err := bc.bookmarkUsecase.DeleteReadingList(ctx.Request.Context(), ctx.Param("listID"), getViewer(ctx)); err != nil {
+		ctx.JSON(bookmarkErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Reading list deleted"})
+}
+
+// Add a blog to a reading list
+func (bc *BookmarkController) AddReadingListItemHandler(ctx *gin.Context) {
+	var req readingListItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	list, err := bc.bookmarkUsecase.AddToReadingList(ctx.Request.Context(), ctx.Param("listID"), req.BlogID, req.Position, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(bookmarkErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, list)
+}
+
+// Remove a blog from a reading list
+func (bc *BookmarkController) RemoveReadingListItemHandler(ctx *gin.Context) {
+	list, err := bc.bookmarkUsecase.RemoveFromReadingList(ctx.Request.Context(), ctx.Param("listID"), ctx.Param("blogID"), getViewer(ctx))
+	if err != nil {
+		ctx.JSON(bookmarkErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, list)
+}
+
+// Reorder the blogs of a reading list
+func (bc *BookmarkController) ReorderReadingListHandler(ctx *gin.Context) {
+	var req reorderReadingListRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	list, err := bc.bookmarkUsecase.ReorderReadingList(ctx.Request.Context(), ctx.Param("listID"), req.BlogIDs, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(bookmarkErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, list)
+}
+
+func bookmarkErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrListNotFound), errors.Is(err, domain.ErrBlogNotFound), errors.Is(err, domain.ErrUserNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -2,61 +2,179 @@ package controllers
 
 import (
 	domain "blog-api/Domain"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
 type CommentController struct {
-    commentUsecase domain.ICommentUsecase
+	commentUsecase domain.ICommentUsecase
 }
 
 func NewCommentController(commentUsecase domain.ICommentUsecase) *CommentController {
-    return &CommentController{commentUsecase: commentUsecase}
+	return &CommentController{commentUsecase: commentUsecase}
 }
 
-// Create a new comment for a blog
+// Create a new comment for a blog, or a reply when parent_id is given
 func (cc *CommentController) CreateComment(ctx *gin.Context) {
-    blogID := ctx.Param("id")
-    var req domain.CreateCommentRequest
-    if err := ctx.ShouldBindJSON(&req); err != nil {
-        ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
-        return
-    }
-    userID, ok := getAuthenticatedUserID(ctx)
-    if !ok {
-        return
-    }
-    comment, err := cc.commentUsecase.CreateComment(ctx.Request.Context(), blogID, userID, req.Content)
-    if err != nil {
-        ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-        return
-    }
-    ctx.JSON(http.StatusCreated, comment)
-}
-
-// Get all comments for a blog
+	blogID := ctx.Param("id")
+	var req domain.CreateCommentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
+		return
+	}
+	comment, err := cc.commentUsecase.CreateComment(ctx.Request.Context(), blogID, req.ParentID, req.Content, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(commentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusCreated, comment)
+}
+
+// Get one page of the top-level comments of a blog
 func (cc *CommentController) GetComments(ctx *gin.Context) {
-    blogID := ctx.Param("id")
-    comments, err := cc.commentUsecase.GetCommentsByBlogID(ctx.Request.Context(), blogID)
-    if err != nil {
-        ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-        return
-    }
-    ctx.JSON(http.StatusOK, comments)
+	query, err := parseCommentQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	query.BlogID = ctx.Param("id")
+	page, err := cc.commentUsecase.ListComments(ctx.Request.Context(), query, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(commentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, page)
+}
+
+// Get one page of the direct replies to a comment
+func (cc *CommentController) GetReplies(ctx *gin.Context) {
+	query, err := parseCommentQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	page, err := cc.commentUsecase.GetReplies(ctx.Request.Context(), ctx.Param("id"), ctx.Param("commentID"), query, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(commentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, page)
+}
+
+// Get a comment with all replies nested beneath it
+func (cc *CommentController) GetThread(ctx *gin.Context) {
+	thread, err := cc.commentUsecase.GetThread(ctx.Request.Context(), ctx.Param("id"), ctx.Param("commentID"), getViewer(ctx))
+	if err != nil {
+		ctx.JSON(commentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, thread)
 }
 
-// Delete a comment
+// Edit a comment (author only, within the edit window)
+func (cc *CommentController) UpdateComment(ctx *gin.Context) {
+	var req domain.UpdateCommentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	comment, err := cc.commentUsecase.UpdateComment(ctx.Request.Context(), ctx.Param("commentID"), req.Content, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(commentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, comment)
+}
+
+// Get a comment with its earlier versions (blog owner and admins)
+func (cc *CommentController) GetCommentHistory(ctx *gin.Context) {
+	comment, err := cc.commentUsecase.GetCommentHistory(ctx.Request.Context(), ctx.Param("commentID"), getViewer(ctx))
+	if err != nil {
+		ctx.JSON(commentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, comment)
+}
+
+// Delete a comment (author, blog owner or admin)
 func (cc *CommentController) DeleteComment(ctx *gin.Context) {
-    commentID := ctx.Param("commentID")
-    userID, ok := getAuthenticatedUserID(ctx)
-    if !ok {
-        return
-    }
-    err := cc.commentUsecase.DeleteComment(ctx.Request.Context(), commentID, userID)
-    if err != nil {
-        ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
-        return
-    }
-    ctx.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully"})
-}
\ No newline at end of file
+	err := cc.commentUsecase.DeleteComment(ctx.Request.Context(), ctx.Param("commentID"), getViewer(ctx))
+	if err != nil {
+		ctx.JSON(commentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully"})
+}
+
+// Get the moderation queue of a blog (blog owner and admins)
+func (cc *CommentController) GetPendingComments(ctx *gin.Context) {
+	cc.pending(ctx, ctx.Param("id"))
+}
+
+// Get the moderation queue of every blog (admins)
+func (cc *CommentController) GetAllPendingComments(ctx *gin.Context) {
+	cc.pending(ctx, "")
+}
+
+func (cc *CommentController) pending(ctx *gin.Context, blogID string) {
+	query, err := parseCommentQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	page, err := cc.commentUsecase.ListPending(ctx.Request.Context(), blogID, query.Page, query.Limit, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(commentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, page)
+}
+
+// Approve or reject pending comments in bulk
+func (cc *CommentController) ModerateComments(ctx *gin.Context) {
+	var req domain.ModerateCommentsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	if req.Action != "approve" && req.Action != "reject" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": `action must be "approve" or "reject"`})
+		return
+	}
+	result, err := cc.commentUsecase.ModerateComments(ctx.Request.Context(), req.CommentIDs, req.Action == "approve", getViewer(ctx))
+	if err != nil {
+		ctx.JSON(commentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+func parseCommentQuery(ctx *gin.Context) (domain.CommentQuery, error) {
+	query := domain.CommentQuery{Sort: domain.CommentSort(ctx.Query("sort"))}
+	var err error
+	if query.Page, err = intQuery(ctx, "page"); err != nil {
+		return query, err
+	}
+	if query.Limit, err = intQuery(ctx, "limit"); err != nil {
+		return query, err
+	}
+	return query, nil
+}
+
+func commentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrCommentNotFound), errors.Is(err, domain.ErrBlogNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	domain "blog-api/Domain"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EmailTemplateController struct {
+	emailTemplateUsecase domain.IEmailTemplateUsecase
+}
+
+func NewEmailTemplateController(emailTemplateUsecase domain.IEmailTemplateUsecase) *EmailTemplateController {
+	return &EmailTemplateController{emailTemplateUsecase: emailTemplateUsecase}
+}
+
+// List the email templates (admins)
+func (ec *EmailTemplateController) ListTemplatesHandler(ctx *gin.Context) {
+	names, err := ec.emailTemplateUsecase.ListTemplates(ctx.Request.Context(), getViewer(ctx))
+	if err != nil {
+		ctx.JSON(emailTemplateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"templates": names})
+}
+
+// Render an email template with sample data, as JSON or as the bare HTML or
+// text body (admins)
+func (ec *EmailTemplateController) PreviewTemplateHandler(ctx *gin.Context) {
+	format := ctx.DefaultQuery("format", "json")
+	if format != "json" && format != "html" && format != "text" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%v: format must be json, html or text", domain.ErrInvalidInput)})
+		return
+	}
+	message, err := ec.emailTemplateUsecase.PreviewTemplate(ctx.Request.Context(), ctx.Param("name"), getViewer(ctx))
+	if err != nil {
+		ctx.JSON(emailTemplateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	switch format {
+	case "html":
+		ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(message.HTML))
+	case "text":
+		ctx.String(http.StatusOK, message.Text)
+	default:
+		ctx.JSON(http.StatusOK, message)
+	}
+}
+
+func emailTemplateErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrEmailTemplateNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
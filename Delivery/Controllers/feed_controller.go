@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	domain "blog-api/Domain"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeedController struct {
+	feedUsecase domain.IFeedUsecase
+	feedEncoder domain.IFeedEncoder
+}
+
+func NewFeedController(feedUsecase domain.IFeedUsecase, feedEncoder domain.IFeedEncoder) *FeedController {
+	return &FeedController{feedUsecase: feedUsecase, feedEncoder: feedEncoder}
+}
+
+// Handler for the site-wide feed
+func (fc *FeedController) SiteFeedHandler(ctx *gin.Context) {
+	fc.serve(ctx, func(limit int) (*domain.Feed, error) {
+		return fc.feedUsecase.SiteFeed(ctx.Request.Context(), limit)
+	})
+}
+
+// Handler for one author's feed
+func (fc *FeedController) AuthorFeedHandler(ctx *gin.Context) {
+	fc.serve(ctx, func(limit int) (*domain.Feed, error) {
+		return fc.feedUsecase.AuthorFeed(ctx.Request.Context(), ctx.Param("userID"), limit)
+	})
+}
+
+// Handler for one tag's feed
+func (fc *FeedController) TagFeedHandler(ctx *gin.Context) {
+	fc.serve(ctx, func(limit int) (*domain.Feed, error) {
+		return fc.feedUsecase.TagFeed(ctx.Request.Context(), ctx.Param("tag"), limit)
+	})
+}
+
+// serve loads a feed, encodes it in the :format of the route and answers
+// conditional requests with 304 when the client's copy is still current.
+// There is no Last-Modified: the newest item's date does not move when a
+// blog leaves the feed, so only the ETag of the body is reliable.
+func (fc *FeedController) serve(ctx *gin.Context, load func(limit int) (*domain.Feed, error)) {
+	format := domain.FeedFormat(ctx.Param("format"))
+	if !format.IsValid() {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "unknown feed format; use rss, atom or json"})
+		return
+	}
+	limit := 0
+	if raw := ctx.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+			return
+		}
+		limit = n
+	}
+
+	feed, err := load(limit)
+	if err != nil {
+		ctx.JSON(feedErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	body, contentType, err := fc.feedEncoder.Encode(feed, format)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to encode feed"})
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	ctx.Header("ETag", etag)
+	ctx.Header("Cache-Control", "public, max-age=300")
+
+	if notModified(ctx.Request, etag) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+	ctx.Data(http.StatusOK, contentType, body)
+}
+
+// notModified reports whether If-None-Match names etag.
+func notModified(req *http.Request, etag string) bool {
+	for _, candidate := range strings.Split(req.Header.Get("If-None-Match"), ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func feedErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FollowController struct {
+	followUsecase domain.IFollowUsecase
+}
+
+func NewFollowController(followUsecase domain.IFollowUsecase) *FollowController {
+	return &FollowController{followUsecase: followUsecase}
+}
+
+// Follow a user
+func (fc *FollowController) FollowUserHandler(ctx *gin.Context) {
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
+		return
+	}
+	if err := fc.followUsecase.FollowUser(ctx.Request.Context(), ctx.Param("id"), getViewer(ctx)); err != nil {
+		ctx.JSON(followErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "User followed"})
+}
+
+// Stop following a user
+func (fc *FollowController) UnfollowUserHandler(ctx *gin.Context) {
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
+		return
+	}
+	if err := fc.followUsecase.UnfollowUser(ctx.Request.Context(), ctx.Param("id"), getViewer(ctx)); err != nil {
+		ctx.JSON(followErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "User unfollowed"})
+}
+
+// Get one page of the users following a user
+func (fc *FollowController) ListFollowersHandler(ctx *gin.Context) {
+	fc.list(ctx, fc.followUsecase.ListFollowers)
+}
+
+// Get one page of the users a user follows
+func (fc *FollowController) ListFollowingHandler(ctx *gin.Context) {
+	fc.list(ctx, fc.followUsecase.ListFollowing)
+}
+
+func (fc *FollowController) list(ctx *gin.Context, list func(ctx context.Context, userID string, page, limit int) (*domain.FollowPage, error)) {
+	page, err := intQuery(ctx, "page")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	limit, err := intQuery(ctx, "limit")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := list(ctx.Request.Context(), ctx.Param("id"), page, limit)
+	if err != nil {
+		ctx.JSON(followErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// Get follower and following counts for a user
+func (fc *FollowController) FollowCountsHandler(ctx *gin.Context) {
+	counts, err := fc.followUsecase.GetFollowCounts(ctx.Request.Context(), ctx.Param("id"), getViewer(ctx))
+	if err != nil {
+		ctx.JSON(followErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, counts)
+}
+
+// Get the tags a user follows
+func (fc *FollowController) ListFollowedTagsHandler(ctx *gin.Context) {
+	tags, err := fc.followUsecase.ListFollowedTags(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(followErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"tags": tags})
+}
+
+// Follow a tag
+func (fc *FollowController) FollowTagHandler(ctx *gin.Context) {
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
+		return
+	}
+	tag, err := fc.followUsecase.FollowTag(ctx.Request.Context(), ctx.Param("name"), getViewer(ctx))
+	if err != nil {
+		ctx.JSON(followErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Tag followed", "tag": tag})
+}
+
+// Stop following a tag
+func (fc *FollowController) UnfollowTagHandler(ctx *gin.Context) {
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
+		return
+	}
+	if err := fc.followUsecase.UnfollowTag(ctx.Request.Context(), ctx.Param("name"), getViewer(ctx)); err != nil {
+		ctx.JSON(followErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Tag unfollowed"})
+}
+
+// Get the caller's home feed: blogs from followed authors and tags, newest
+// first, paginated by cursor
+func (fc *FollowController) FeedHandler(ctx *gin.Context) {
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
+		return
+	}
+	limit, err := intQuery(ctx, "limit")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	feed, err := fc.followUsecase.GetFeed(ctx.Request.Context(), ctx.Query("cursor"), limit, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(followErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, feed)
+}
+
+func followErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
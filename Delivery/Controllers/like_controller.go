@@ -32,11 +32,7 @@ func (c *LikeController) LikeBlogHandler(ctx *gin.Context) {
 
 	err := c.likeUsecase.LikeBlog(id, userID)
 	if err != nil {
-		if err == domain.ErrInvalidInput {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.JSON(reactionErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -58,11 +54,7 @@ func (c *LikeController) RemoveLikeBlogHandler(ctx *gin.Context) {
 
 	err := c.likeUsecase.RemoveLikeBlog(id, userID)
 	if err != nil {
-		if err == domain.ErrInvalidInput {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.JSON(reactionErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -78,11 +70,7 @@ func (c *LikeController) GetLikeCountHandler(ctx *gin.Context) {
 
 	count, err := c.likeUsecase.GetLikeCount(id)
 	if err != nil {
-		if err == domain.ErrInvalidInput {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.JSON(reactionErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -104,11 +92,7 @@ func (c *LikeController) IsBlogLikedHandler(ctx *gin.Context) {
 
 	isLiked, err := c.likeUsecase.IsBlogLiked(id, userID)
 	if err != nil {
-		if err == domain.ErrInvalidInput {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.JSON(reactionErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
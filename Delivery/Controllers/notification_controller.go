@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	domain "blog-api/Domain"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationController struct {
+	notificationUsecase domain.INotificationUsecase
+}
+
+func NewNotificationController(notificationUsecase domain.INotificationUsecase) *NotificationController {
+	return &NotificationController{notificationUsecase: notificationUsecase}
+}
+
+type notificationPreferencesRequest struct {
+	Preferences domain.NotificationPreferences `json:"preferences"`
+}
+
+// Get one page of the caller's notifications, most recently updated first
+func (nc *NotificationController) ListNotificationsHandler(ctx *gin.Context) {
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
+		return
+	}
+	page, err := intQuery(ctx, "page")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	limit, err := intQuery(ctx, "limit")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	unreadOnly := false
+	if raw := ctx.Query("unread"); raw != "" {
+		if unreadOnly, err = strconv.ParseBool(raw); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "unread must be true or false"})
+			return
+		}
+	}
+	notifications, err := nc.notificationUsecase.List(ctx.Request.Context(), unreadOnly, page, limit, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(notificationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, notifications)
+}
+
+// Count the caller's unread notifications
+func (nc *NotificationController) CountUnreadHandler(ctx *gin.Context) {
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
+		return
+	}
+	count, err := nc.notificationUsecase.CountUnread(ctx.Request.Context(), getViewer(ctx))
+	if err != nil {
+		ctx.JSON(notificationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"unread": count})
+}
+
+// Mark a notification read
+func (nc *NotificationController) MarkReadHandler(ctx *gin.Context) {
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
+		return
+	}
+	if err := nc.notificationUsecase.MarkRead(ctx.Request.Context(), ctx.Param("id"), getViewer(ctx)); err != nil {
+		ctx.JSON(notificationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Notification marked read"})
+}
+
+// Mark all of the caller's notifications read
+func (nc *NotificationController) MarkAllReadHandler(ctx *gin.Context) {
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
+		return
+	}
+	marked, err := nc.notificationUsecase.MarkAllRead(ctx.Request.Context(), getViewer(ctx))
+	if err != nil {
+		ctx.JSON(notificationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"marked": marked})
+}
+
+// Delete a notification
+func (nc *NotificationController) DeleteNotificationHandler(ctx *gin.Context) {
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
+		return
+	}
+	if err := nc.notificationUsecase.Delete(ctx.Request.Context(), ctx.Param("id"), getViewer(ctx)); err != nil {
+		ctx.JSON(notificationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Notification deleted"})
+}
+
+// Get which notification types the caller receives
+func (nc *NotificationController) GetPreferencesHandler(ctx *gin.Context) {
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
+		return
+	}
+	prefs, err := nc.notificationUsecase.GetPreferences(ctx.Request.Context(), getViewer(ctx))
+	if err != nil {
+		ctx.JSON(notificationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"preferences": prefs})
+}
+
+// Turn notification types on or off; types left out keep their setting
+func (nc *NotificationController) UpdatePreferencesHandler(ctx *gin.Context) {
+	var req notificationPreferencesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
+		return
+	}
+	prefs, err := nc.notificationUsecase.UpdatePreferences(ctx.Request.Context(), req.Preferences, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(notificationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"preferences": prefs})
+}
+
+func notificationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrNotificationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
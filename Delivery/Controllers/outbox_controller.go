@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	domain "blog-api/Domain"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OutboxController struct {
+	outboxUsecase domain.IOutboxUsecase
+}
+
+func NewOutboxController(outboxUsecase domain.IOutboxUsecase) *OutboxController {
+	return &OutboxController{outboxUsecase: outboxUsecase}
+}
+
+// Get one page of outbox messages, newest first (admins)
+func (oc *OutboxController) ListMessagesHandler(ctx *gin.Context) {
+	page, err := intQuery(ctx, "page")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	limit, err := intQuery(ctx, "limit")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	status := domain.OutboxStatus(ctx.Query("status"))
+	messages, err := oc.outboxUsecase.ListMessages(ctx.Request.Context(), status, ctx.Query("topic"), page, limit, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(outboxErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, messages)
+}
+
+// Get a single outbox message; payloads are never shown (admins)
+func (oc *OutboxController) GetMessageHandler(ctx *gin.Context) {
+	message, err := oc.outboxUsecase.GetMessage(ctx.Request.Context(), ctx.Param("id"), getViewer(ctx))
+	if err != nil {
+		ctx.JSON(outboxErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, message)
+}
+
+// Give a dead-lettered message a fresh set of attempts (admins)
+func (oc *OutboxController) RetryMessageHandler(ctx *gin.Context) {
+	message, err := oc.outboxUsecase.RetryMessage(ctx.Request.Context(), ctx.Param("id"), getViewer(ctx))
+	if err != nil {
+		ctx.JSON(outboxErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusAccepted, message)
+}
+
+func outboxErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrOutboxMessageNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
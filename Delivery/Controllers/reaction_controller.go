@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	domain "blog-api/Domain"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReactionController struct {
+	reactionUsecase domain.IReactionUsecase
+}
+
+func NewReactionController(reactionUsecase domain.IReactionUsecase) *ReactionController {
+	return &ReactionController{reactionUsecase: reactionUsecase}
+}
+
+type reactionRequest struct {
+	Type string `json:"type"`
+}
+
+// The same handlers serve blogs and comments; comment routes carry a
+// commentID parameter.
+func reactionTarget(ctx *gin.Context) (domain.ReactionTargetType, string) {
+	if id := ctx.Param("commentID"); id != "" {
+		return domain.ReactionTargetComment, id
+	}
+	return domain.ReactionTargetBlog, ctx.Param("id")
+}
+
+// List the reaction types that can be used
+func (rc *ReactionController) ListReactionTypesHandler(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"types": rc.reactionUsecase.Types()})
+}
+
+// React to a blog or comment, replacing the caller's earlier reaction
+func (rc *ReactionController) ReactHandler(ctx *gin.Context) {
+	var req reactionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
+		return
+	}
+	targetType, targetID := reactionTarget(ctx)
+	summary, err := rc.reactionUsecase.React(ctx.Request.Context(), targetType, targetID, req.Type, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(reactionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, summary)
+}
+
+// Remove the caller's reaction
+func (rc *ReactionController) UnreactHandler(ctx *gin.Context) {
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
+		return
+	}
+	targetType, targetID := reactionTarget(ctx)
+	summary, err := rc.reactionUsecase.Unreact(ctx.Request.Context(), targetType, targetID, "", getViewer(ctx))
+	if err != nil {
+		ctx.JSON(reactionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, summary)
+}
+
+// Get the reaction counts, and the caller's reaction when signed in
+func (rc *ReactionController) GetReactionsHandler(ctx *gin.Context) {
+	targetType, targetID := reactionTarget(ctx)
+	summary, err := rc.reactionUsecase.GetReactions(ctx.Request.Context(), targetType, targetID, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(reactionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, summary)
+}
+
+// Get one page of the users who reacted, optionally with one type only
+func (rc *ReactionController) ListReactorsHandler(ctx *gin.Context) {
+	page, err := intQuery(ctx, "page")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	limit, err := intQuery(ctx, "limit")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	targetType, targetID := reactionTarget(ctx)
+	reactors, err := rc.reactionUsecase.ListReactors(ctx.Request.Context(), targetType, targetID, ctx.Query("type"), page, limit, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(reactionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, reactors)
+}
+
+func reactionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrBlogNotFound), errors.Is(err, domain.ErrCommentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
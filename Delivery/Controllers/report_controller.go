@@ -0,0 +1,209 @@
+package controllers
+
+import (
+	domain "blog-api/Domain"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportController struct {
+	reportUsecase domain.IReportUsecase
+}
+
+func NewReportController(reportUsecase domain.IReportUsecase) *ReportController {
+	return &ReportController{reportUsecase: reportUsecase}
+}
+
+type createReportRequest struct {
+	TargetType string `json:"target_type"`
+	TargetID   string `json:"target_id"`
+	Reason     string `json:"reason"`
+	Details    string `json:"details"`
+}
+
+type resolveReportRequest struct {
+	Action string `json:"action"` // dismiss, hide, warn or suspend
+	Note   string `json:"note"`
+	// SuspendDays is how long a suspension lasts; 0 suspends indefinitely.
+	SuspendDays int `json:"suspend_days"`
+}
+
+// moderationNoteRequest is the optional body of a reversal.
+type moderationNoteRequest struct {
+	Note string `json:"note"`
+}
+
+// Report a blog, comment or user
+func (rc *ReportController) CreateReportHandler(ctx *gin.Context) {
+	var req createReportRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
+		return
+	}
+	report, created, err := rc.reportUsecase.CreateReport(ctx.Request.Context(), domain.CreateReportInput{
+		TargetType: domain.ReportTargetType(req.TargetType),
+		TargetID:   req.TargetID,
+		Reason:     domain.ReportReason(req.Reason),
+		Details:    req.Details,
+	}, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(reportErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	// A repeat report updates the earlier one instead of filing another.
+	if !created {
+		ctx.JSON(http.StatusOK, report)
+		return
+	}
+	ctx.JSON(http.StatusCreated, report)
+}
+
+// Get one page of the report queue (admins)
+func (rc *ReportController) ListReportsHandler(ctx *gin.Context) {
+	query := domain.ReportQuery{
+		Status:       domain.ReportStatus(ctx.Query("status")),
+		TargetType:   domain.ReportTargetType(ctx.Query("target_type")),
+		TargetID:     ctx.Query("target_id"),
+		TargetUserID: ctx.Query("target_user_id"),
+		Reason:       domain.ReportReason(ctx.Query("reason")),
+	}
+	var err error
+	if query.Page, err = intQuery(ctx, "page"); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if query.Limit, err = intQuery(ctx, "limit"); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	page, err := rc.reportUsecase.ListReports(ctx.Request.Context(), query, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(reportErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, page)
+}
+
+// Count reports by status, and open ones by target type and reason (admins)
+func (rc *ReportController) CountReportsHandler(ctx *gin.Context) {
+	counts, err := rc.reportUsecase.CountReports(ctx.Request.Context(), getViewer(ctx))
+	if err != nil {
+		ctx.JSON(reportErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, counts)
+}
+
+// Get a single report (admins)
+func (rc *ReportController) GetReportHandler(ctx *gin.Context) {
+	report, err := rc.reportUsecase.GetReport(ctx.Request.Context(), ctx.Param("id"), getViewer(ctx))
+	if err != nil {
+		ctx.JSON(reportErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
+
+// Act on a report, closing every open report on the same target (admins)
+func (rc *ReportController) ResolveReportHandler(ctx *gin.Context) {
+	var req resolveReportRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	if req.SuspendDays < 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%v: suspend_days must not be negative", domain.ErrInvalidInput)})
+		return
+	}
+	report, err := rc.reportUsecase.ResolveReport(ctx.Request.Context(), ctx.Param("id"), domain.ResolveReportInput{
+		Action:     domain.ModerationActionType(req.Action),
+		Note:       req.Note,
+		SuspendFor: time.Duration(req.SuspendDays) * 24 * time.Hour,
+	}, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(reportErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
+
+// Put a hidden blog back in public view (admins)
+func (rc *ReportController) UnhideBlogHandler(ctx *gin.Context) {
+	rc.unhide(ctx, domain.ReportTargetBlog)
+}
+
+// Restore a comment hidden by moderation (admins)
+func (rc *ReportController) UnhideCommentHandler(ctx *gin.Context) {
+	rc.unhide(ctx, domain.ReportTargetComment)
+}
+
+func (rc *ReportController) unhide(ctx *gin.Context, targetType domain.ReportTargetType) {
+	var req moderationNoteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	action, err := rc.reportUsecase.Unhide(ctx.Request.Context(), targetType, ctx.Param("id"), req.Note, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(reportErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, action)
+}
+
+// Lift a user's suspension (admins)
+func (rc *ReportController) LiftSuspensionHandler(ctx *gin.Context) {
+	var req moderationNoteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	action, err := rc.reportUsecase.LiftSuspension(ctx.Request.Context(), ctx.Param("id"), req.Note, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(reportErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, action)
+}
+
+// Get the moderation audit log, optionally for one user (admins)
+func (rc *ReportController) ListModerationActionsHandler(ctx *gin.Context) {
+	query := domain.ModerationActionQuery{TargetUserID: ctx.Query("user_id")}
+	var err error
+	if query.Page, err = intQuery(ctx, "page"); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if query.Limit, err = intQuery(ctx, "limit"); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	page, err := rc.reportUsecase.ListModerationActions(ctx.Request.Context(), query, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(reportErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, page)
+}
+
+func reportErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrReportNotFound), errors.Is(err, domain.ErrBlogNotFound),
+		errors.Is(err, domain.ErrCommentNotFound), errors.Is(err, domain.ErrUserNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
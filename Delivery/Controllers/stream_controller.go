@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	domain "blog-api/Domain"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// streamHeartbeat keeps idle connections from being closed by proxies.
+	streamHeartbeat = 25 * time.Second
+	// streamRetry is how long browsers wait before reconnecting.
+	streamRetry = 3 * time.Second
+)
+
+type StreamController struct {
+	streamUsecase domain.IStreamUsecase
+}
+
+func NewStreamController(streamUsecase domain.IStreamUsecase) *StreamController {
+	return &StreamController{streamUsecase: streamUsecase}
+}
+
+// Stream new comments and reaction counts of the given blogs, and the
+// caller's notifications, as Server-Sent Events
+func (sc *StreamController) StreamHandler(ctx *gin.Context) {
+	if _, ok := getAuthenticatedUserID(ctx); !ok {
+		return
+	}
+	var blogIDs []string
+	for _, id := range strings.Split(ctx.Query("blogs"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			blogIDs = append(blogIDs, id)
+		}
+	}
+	lastEventID := ctx.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = ctx.Query("last_event_id")
+	}
+	sub, err := sc.streamUsecase.Subscribe(ctx.Request.Context(), blogIDs, lastEventID, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(streamErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	defer sub.Close()
+
+	w := ctx.Writer
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+	if sub.Reset {
+		// The client missed more than the buffer holds. The empty ID clears
+		// its Last-Event-ID so it does not ask again after refetching.
+		writeStreamEvent(w, domain.StreamEvent{Type: "reset", Data: gin.H{}})
+	}
+	for _, event := range sub.Replay {
+		writeStreamEvent(w, event)
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case event, ok := <-sub.Events:
+			if !ok {
+				return
+			}
+			writeStreamEvent(w, event)
+			w.Flush()
+		case <-heartbeat.C:
+			io.WriteString(w, ": ping\n\n")
+			w.Flush()
+		}
+	}
+}
+
+func writeStreamEvent(w io.Writer, event domain.StreamEvent) {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		log.Printf("warning: failed to encode stream event %s: %v", event.ID, err)
+		return
+	}
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+}
+
+func streamErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrBlogNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	domain "blog-api/Domain"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TagController struct {
+	tagUsecase domain.ITagUsecase
+}
+
+func NewTagController(tagUsecase domain.ITagUsecase) *TagController {
+	return &TagController{tagUsecase: tagUsecase}
+}
+
+type updateTagRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+type mergeTagRequest struct {
+	Into string `json:"into" binding:"required"`
+}
+
+type tagAliasRequest struct {
+	Alias string `json:"alias" binding:"required"`
+}
+
+func (tc *TagController) ListTagsHandler(ctx *gin.Context) {
+	tags, err := tc.tagUsecase.ListTags(ctx.Request.Context())
+	if err != nil {
+		ctx.JSON(tagErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"tags": tags, "count": len(tags)})
+}
+
+func (tc *TagController) GetTagHandler(ctx *gin.Context) {
+	tag, err := tc.tagUsecase.GetTag(ctx.Request.Context(), ctx.Param("name"))
+	if err != nil {
+		ctx.JSON(tagErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tag)
+}
+
+// UpdateTagHandler renames a tag and/or sets its description (admin only).
+func (tc *TagController) UpdateTagHandler(ctx *gin.Context) {
+	var req updateTagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request format"})
+		return
+	}
+	if req.Name == nil && req.Description == nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "name or description is required"})
+		return
+	}
+
+	input := domain.UpdateTagInput{Name: req.Name, Description: req.Description}
+	tag, err := tc.tagUsecase.UpdateTag(ctx.Request.Context(), ctx.Param("name"), input, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(tagErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tag)
+}
+
+func (tc *TagController) MergeTagHandler(ctx *gin.Context) {
+	var req mergeTagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "into is required"})
+		return
+	}
+
+	tag, err := tc.tagUsecase.MergeTag(ctx.Request.Context(), ctx.Param("name"), req.Into, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(tagErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tag)
+}
+
+func (tc *TagController) DeleteTagHandler(ctx *gin.Context) {
+	if err := tc.tagUsecase.DeleteTag(ctx.Request.Context(), ctx.Param("name"), getViewer(ctx)); err != nil {
+		ctx.JSON(tagErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Tag deleted successfully"})
+}
+
+func (tc *TagController) AddAliasHandler(ctx *gin.Context) {
+	var req tagAliasRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "alias is required"})
+		return
+	}
+
+	tag, err := tc.tagUsecase.AddAlias(ctx.Request.Context(), ctx.Param("name"), req.Alias, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(tagErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tag)
+}
+
+func (tc *TagController) RemoveAliasHandler(ctx *gin.Context) {
+	tag, err := tc.tagUsecase.RemoveAlias(ctx.Request.Context(), ctx.Param("name"), ctx.Param("alias"), getViewer(ctx))
+	if err != nil {
+		ctx.JSON(tagErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tag)
+}
+
+func tagErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrTagNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrTagExists):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
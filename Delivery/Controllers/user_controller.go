@@ -269,7 +269,7 @@ func (c *UserController) RequestPasswordResetHandler(ctx *gin.Context) {
 		return
 	}
 
-	_, err := c.userUsecase.RequestPasswordReset(ctx, domain.RequestPasswordResetInput{
+	err := c.userUsecase.RequestPasswordReset(ctx, domain.RequestPasswordResetInput{
 		Email: req.Email,
 	})
 	if err != nil {
@@ -1,7 +1,11 @@
 package controllers
 
 import (
+	domain "blog-api/Domain"
+	"crypto/sha256"
+	"encoding/hex"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -19,3 +23,37 @@ func getAuthenticatedUserID(ctx *gin.Context) (string, bool) {
 	}
 	return userID, true
 }
+
+// getViewer returns the caller as seen by the auth middleware. Requests
+// without a valid token yield an anonymous viewer.
+func getViewer(ctx *gin.Context) domain.Viewer {
+	userID, _ := ctx.Get("user_id")
+	role, _ := ctx.Get("role")
+	viewer := domain.Viewer{}
+	viewer.UserID, _ = userID.(string)
+	viewer.Role, _ = role.(domain.Role)
+	return viewer
+}
+
+// botMarkers appear in the User-Agent of crawlers and monitoring tools.
+var botMarkers = []string{"bot", "crawl", "spider", "slurp", "curl", "wget", "python-requests", "headless"}
+
+// getViewKey identifies the reader for unique view counting: the user ID
+// when signed in, otherwise a hash of the client address and User-Agent.
+// Requests that look automated get an empty key and are not counted.
+func getViewKey(ctx *gin.Context) string {
+	agent := strings.ToLower(ctx.Request.UserAgent())
+	if agent == "" {
+		return ""
+	}
+	for _, marker := range botMarkers {
+		if strings.Contains(agent, marker) {
+			return ""
+		}
+	}
+	if userID := getViewer(ctx).UserID; userID != "" {
+		return "user:" + userID
+	}
+	sum := sha256.Sum256([]byte(ctx.ClientIP() + "\n" + agent))
+	return "anon:" + hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,168 @@
+package controllers
+
+import (
+	domain "blog-api/Domain"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookController struct {
+	webhookUsecase domain.IWebhookUsecase
+}
+
+func NewWebhookController(webhookUsecase domain.IWebhookUsecase) *WebhookController {
+	return &WebhookController{webhookUsecase: webhookUsecase}
+}
+
+type createWebhookRequest struct {
+	URL string `json:"url"`
+	// Secret is generated when left empty.
+	Secret      string   `json:"secret"`
+	Events      []string `json:"events"`
+	Description string   `json:"description"`
+}
+
+type updateWebhookRequest struct {
+	URL         *string  `json:"url"`
+	Secret      *string  `json:"secret"`
+	Events      []string `json:"events"`
+	Description *string  `json:"description"`
+	// Active re-enables a webhook that was disabled after failing.
+	Active *bool `json:"active"`
+}
+
+// Register a webhook; the response is the only time its secret is shown (admins)
+func (wc *WebhookController) CreateWebhookHandler(ctx *gin.Context) {
+	var req createWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	webhook, err := wc.webhookUsecase.CreateWebhook(ctx.Request.Context(), domain.WebhookInput{
+		URL:         req.URL,
+		Secret:      req.Secret,
+		Events:      req.Events,
+		Description: req.Description,
+	}, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"webhook": webhook, "secret": webhook.Secret})
+}
+
+// List every webhook (admins)
+func (wc *WebhookController) ListWebhooksHandler(ctx *gin.Context) {
+	webhooks, err := wc.webhookUsecase.ListWebhooks(ctx.Request.Context(), getViewer(ctx))
+	if err != nil {
+		ctx.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"webhooks": webhooks})
+}
+
+// Get a single webhook (admins)
+func (wc *WebhookController) GetWebhookHandler(ctx *gin.Context) {
+	webhook, err := wc.webhookUsecase.GetWebhook(ctx.Request.Context(), ctx.Param("id"), getViewer(ctx))
+	if err != nil {
+		ctx.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, webhook)
+}
+
+// Change a webhook's URL, secret, events, description or state (admins)
+func (wc *WebhookController) UpdateWebhookHandler(ctx *gin.Context) {
+	var req updateWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	webhook, err := wc.webhookUsecase.UpdateWebhook(ctx.Request.Context(), ctx.Param("id"), domain.WebhookUpdate{
+		URL:         req.URL,
+		Secret:      req.Secret,
+		Events:      req.Events,
+		Description: req.Description,
+		Active:      req.Active,
+	}, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, webhook)
+}
+
+// Delete a webhook and its delivery log (admins)
+func (wc *WebhookController) DeleteWebhookHandler(ctx *gin.Context) {
+	if err := wc.webhookUsecase.DeleteWebhook(ctx.Request.Context(), ctx.Param("id"), getViewer(ctx)); err != nil {
+		ctx.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+}
+
+// Send a ping to check a webhook's endpoint (admins)
+func (wc *WebhookController) PingWebhookHandler(ctx *gin.Context) {
+	delivery, err := wc.webhookUsecase.PingWebhook(ctx.Request.Context(), ctx.Param("id"), getViewer(ctx))
+	if err != nil {
+		ctx.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusAccepted, delivery)
+}
+
+// Get one page of a webhook's delivery log, newest first (admins)
+func (wc *WebhookController) ListDeliveriesHandler(ctx *gin.Context) {
+	page, err := intQuery(ctx, "page")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	limit, err := intQuery(ctx, "limit")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	status := domain.WebhookDeliveryStatus(ctx.Query("status"))
+	deliveries, err := wc.webhookUsecase.ListDeliveries(ctx.Request.Context(), ctx.Param("id"), status, page, limit, getViewer(ctx))
+	if err != nil {
+		ctx.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, deliveries)
+}
+
+// Get a single delivery with its payload (admins)
+func (wc *WebhookController) GetDeliveryHandler(ctx *gin.Context) {
+	delivery, err := wc.webhookUsecase.GetDelivery(ctx.Request.Context(), ctx.Param("deliveryID"), getViewer(ctx))
+	if err != nil {
+		ctx.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, delivery)
+}
+
+// Send a past delivery's payload again (admins)
+func (wc *WebhookController) ReplayDeliveryHandler(ctx *gin.Context) {
+	delivery, err := wc.webhookUsecase.ReplayDelivery(ctx.Request.Context(), ctx.Param("deliveryID"), getViewer(ctx))
+	if err != nil {
+		ctx.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusAccepted, delivery)
+}
+
+func webhookErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrWebhookNotFound), errors.Is(err, domain.ErrDeliveryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -14,6 +14,17 @@ func SetupRouter(
 	likeCtrl *controllers.LikeController,
 	authMiddleware *infrastructure.AuthMiddleware,
 	commentsController *controllers.CommentController,
+	feedController *controllers.FeedController,
+	tagController *controllers.TagController,
+	reportController *controllers.ReportController,
+	followController *controllers.FollowController,
+	bookmarkController *controllers.BookmarkController,
+	reactionController *controllers.ReactionController,
+	notificationController *controllers.NotificationController,
+	streamController *controllers.StreamController,
+	webhookController *controllers.WebhookController,
+	outboxController *controllers.OutboxController,
+	emailTemplateController *controllers.EmailTemplateController,
 ) *gin.Engine {
 	router := gin.Default()
 
@@ -21,7 +32,7 @@ func SetupRouter(
 	router.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "http://localhost:3000") // TODO: change to frontend domain in production
 		c.Header("Access-Control-Allow-Credentials", "true")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, Last-Event-ID")
 		c.Header("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
@@ -57,15 +68,34 @@ func SetupRouter(
 	// --- Blog routes ---
 	blogRoutes := router.Group("/blogs")
 	{
-		blogRoutes.GET("/", bc.GetBlogsHandler)       // Paginated blogs
-		blogRoutes.GET("/:id", bc.GetBlogByIDHandler) // Single blog
+		blogRoutes.GET("/", authMiddleware.OptionalMiddleware(), bc.GetBlogsHandler)       // Paginated blogs
+		blogRoutes.GET("/:id", authMiddleware.OptionalMiddleware(), bc.GetBlogByIDHandler) // Single blog
+		blogRoutes.GET("/by-slug/:slug", authMiddleware.OptionalMiddleware(), bc.GetBlogBySlugHandler)
+		blogRoutes.GET("/trending", authMiddleware.OptionalMiddleware(), bc.GetTrendingBlogsHandler)
 
 		blogRoutes.POST("/", authMiddleware.Middleware(), bc.CreateBlogHandler)
 		blogRoutes.PUT("/:id", authMiddleware.Middleware(), bc.UpdateBlogHandler)
 		blogRoutes.DELETE("/:id", authMiddleware.Middleware(), bc.DeleteBlog)
-		blogRoutes.GET("/filter", authMiddleware.Middleware(), bc.FilterBlogsHandler)
+		blogRoutes.POST("/:id/publish", authMiddleware.Middleware(), bc.PublishBlogHandler)
+		blogRoutes.POST("/:id/unpublish", authMiddleware.Middleware(), bc.UnpublishBlogHandler)
+		blogRoutes.POST("/:id/archive", authMiddleware.Middleware(), bc.ArchiveBlogHandler)
+		blogRoutes.POST("/:id/schedule", authMiddleware.Middleware(), bc.ScheduleBlogHandler)
+		blogRoutes.PUT("/:id/comment-settings", authMiddleware.Middleware(), bc.CommentSettingsHandler)
+		blogRoutes.POST("/:id/bookmark", authMiddleware.Middleware(), bookmarkController.AddBookmarkHandler)
+		blogRoutes.DELETE("/:id/bookmark", authMiddleware.Middleware(), bookmarkController.RemoveBookmarkHandler)
+
+		// Revisions
+		revisions := blogRoutes.Group("/:id/revisions", authMiddleware.Middleware())
+		{
+			revisions.GET("/", bc.ListRevisionsHandler)
+			revisions.GET("/diff", bc.DiffRevisionsHandler)
+			revisions.GET("/:version", bc.GetRevisionHandler)
+			revisions.POST("/:version/restore", bc.RestoreRevisionHandler)
+		}
+		// Older names for the listing above
+		blogRoutes.GET("/filter", authMiddleware.OptionalMiddleware(), bc.GetBlogsHandler)
 		blogRoutes.POST("/aisuggestion", authMiddleware.Middleware(), bc.AiSuggestion)
-		blogRoutes.GET("/search", authMiddleware.Middleware(), bc.SearchBlogs)
+		blogRoutes.GET("/search", authMiddleware.OptionalMiddleware(), bc.GetBlogsHandler)
 
 		// Likes
 		likes := blogRoutes.Group("/:id/likes", authMiddleware.Middleware())
@@ -76,16 +106,157 @@ func SetupRouter(
 			likes.GET("/is-liked", likeCtrl.IsBlogLikedHandler)
 		}
 
+		// Reactions
+		blogReactions := blogRoutes.Group("/:id/reactions")
+		{
+			blogReactions.PUT("/", authMiddleware.Middleware(), reactionController.ReactHandler)
+			blogReactions.DELETE("/", authMiddleware.Middleware(), reactionController.UnreactHandler)
+			blogReactions.GET("/", authMiddleware.OptionalMiddleware(), reactionController.GetReactionsHandler)
+			blogReactions.GET("/users", authMiddleware.OptionalMiddleware(), reactionController.ListReactorsHandler)
+		}
+
 		// Comments
 		comments := blogRoutes.Group("/:id/comments", authMiddleware.Middleware())
 		{
 			comments.POST("/", commentsController.CreateComment)
 			comments.GET("/", commentsController.GetComments)
+			comments.GET("/pending", commentsController.GetPendingComments)
+			comments.GET("/:commentID/replies", commentsController.GetReplies)
+			comments.GET("/:commentID/thread", commentsController.GetThread)
 		}
 	}
 
+	// --- Feeds (rss, atom or json) ---
+	feedRoutes := router.Group("/feeds")
+	{
+		feedRoutes.GET("/:format", feedController.SiteFeedHandler)
+		feedRoutes.GET("/authors/:userID/:format", feedController.AuthorFeedHandler)
+		feedRoutes.GET("/tags/:tag/:format", feedController.TagFeedHandler)
+	}
+
+	// --- Tags ---
+	tagRoutes := router.Group("/tags")
+	{
+		tagRoutes.GET("/", tagController.ListTagsHandler)
+		tagRoutes.GET("/:name", tagController.GetTagHandler)
+
+		// Admin only
+		tagRoutes.PUT("/:name", authMiddleware.Middleware(), tagController.UpdateTagHandler)
+		tagRoutes.DELETE("/:name", authMiddleware.Middleware(), tagController.DeleteTagHandler)
+		tagRoutes.POST("/:name/merge", authMiddleware.Middleware(), tagController.MergeTagHandler)
+		tagRoutes.POST("/:name/aliases", authMiddleware.Middleware(), tagController.AddAliasHandler)
+		tagRoutes.DELETE("/:name/aliases/:alias", authMiddleware.Middleware(), tagController.RemoveAliasHandler)
+
+		tagRoutes.POST("/:name/follow", authMiddleware.Middleware(), followController.FollowTagHandler)
+		tagRoutes.DELETE("/:name/follow", authMiddleware.Middleware(), followController.UnfollowTagHandler)
+	}
+
+	// --- Users and follows ---
+	userRoutes := router.Group("/users/:id")
+	{
+		userRoutes.POST("/follow", authMiddleware.Middleware(), followController.FollowUserHandler)
+		userRoutes.DELETE("/follow", authMiddleware.Middleware(), followController.UnfollowUserHandler)
+		userRoutes.GET("/followers", followController.ListFollowersHandler)
+		userRoutes.GET("/following", followController.ListFollowingHandler)
+		userRoutes.GET("/follow-counts", authMiddleware.OptionalMiddleware(), followController.FollowCountsHandler)
+		userRoutes.GET("/followed-tags", followController.ListFollowedTagsHandler)
+		userRoutes.GET("/reading-lists", authMiddleware.OptionalMiddleware(), bookmarkController.ListUserReadingListsHandler)
+	}
+
+	// --- Bookmarks and reading lists ---
+	router.GET("/bookmarks", authMiddleware.Middleware(), bookmarkController.ListBookmarksHandler)
+	listRoutes := router.Group("/reading-lists")
+	{
+		listRoutes.POST("/", authMiddleware.Middleware(), bookmarkController.CreateReadingListHandler)
+		listRoutes.GET("/", authMiddleware.Middleware(), bookmarkController.ListMyReadingListsHandler)
+		listRoutes.GET("/:listID", authMiddleware.OptionalMiddleware(), bookmarkController.GetReadingListHandler)
+		listRoutes.PUT("/:listID", authMiddleware.Middleware(), bookmarkController.UpdateReadingListHandler)
+		listRoutes.DELETE("/:listID", authMiddleware.Middleware(), bookmarkController.DeleteReadingListHandler)
+		listRoutes.POST("/:listID/items", authMiddleware.Middleware(), bookmarkController.AddReadingListItemHandler)
+		listRoutes.PUT("/:listID/items", authMiddleware.Middleware(), bookmarkController.ReorderReadingListHandler)
+		listRoutes.DELETE("/:listID/items/:blogID", authMiddleware.Middleware(), bookmarkController.RemoveReadingListItemHandler)
+	}
+
+	// --- Home feed ---
+	router.GET("/feed", authMiddleware.Middleware(), followController.FeedHandler)
+
 	// Comment deletion (separate for direct access)
 	router.DELETE("/comments/:commentID", authMiddleware.Middleware(), commentsController.DeleteComment)
+	router.PUT("/comments/:commentID", authMiddleware.Middleware(), commentsController.UpdateComment)
+	router.GET("/comments/:commentID/history", authMiddleware.Middleware(), commentsController.GetCommentHistory)
+	router.GET("/comments/pending", authMiddleware.Middleware(), commentsController.GetAllPendingComments)
+	router.POST("/comments/moderate", authMiddleware.Middleware(), commentsController.ModerateComments)
+
+	// --- Reactions to comments ---
+	router.GET("/reactions/types", reactionController.ListReactionTypesHandler)
+	commentReactions := router.Group("/comments/:commentID/reactions")
+	{
+		commentReactions.PUT("/", authMiddleware.Middleware(), reactionController.ReactHandler)
+		commentReactions.DELETE("/", authMiddleware.Middleware(), reactionController.UnreactHandler)
+		commentReactions.GET("/", authMiddleware.OptionalMiddleware(), reactionController.GetReactionsHandler)
+		commentReactions.GET("/users", authMiddleware.OptionalMiddleware(), reactionController.ListReactorsHandler)
+	}
+
+	// --- Notifications ---
+	notificationRoutes := router.Group("/notifications", authMiddleware.Middleware())
+	{
+		notificationRoutes.GET("/", notificationController.ListNotificationsHandler)
+		notificationRoutes.GET("/unread-count", notificationController.CountUnreadHandler)
+		notificationRoutes.POST("/read-all", notificationController.MarkAllReadHandler)
+		notificationRoutes.GET("/preferences", notificationController.GetPreferencesHandler)
+		notificationRoutes.PUT("/preferences", notificationController.UpdatePreferencesHandler)
+		notificationRoutes.POST("/:id/read", notificationController.MarkReadHandler)
+		notificationRoutes.DELETE("/:id", notificationController.DeleteNotificationHandler)
+	}
+
+	// --- Real-time updates (Server-Sent Events) ---
+	router.GET("/stream", authMiddleware.Middleware(), streamController.StreamHandler)
+
+	// --- Reports ---
+	router.POST("/reports", authMiddleware.Middleware(), reportController.CreateReportHandler)
+
+	// --- Moderation (admin only) ---
+	moderationRoutes := router.Group("/admin", authMiddleware.Middleware())
+	{
+		moderationRoutes.GET("/reports", reportController.ListReportsHandler)
+		moderationRoutes.GET("/reports/counts", reportController.CountReportsHandler)
+		moderationRoutes.GET("/reports/:id", reportController.GetReportHandler)
+		moderationRoutes.POST("/reports/:id/resolve", reportController.ResolveReportHandler)
+		moderationRoutes.POST("/blogs/:id/unhide", reportController.UnhideBlogHandler)
+		moderationRoutes.POST("/comments/:id/unhide", reportController.UnhideCommentHandler)
+		moderationRoutes.POST("/users/:id/unsuspend", reportController.LiftSuspensionHandler)
+		moderationRoutes.GET("/moderation-actions", reportController.ListModerationActionsHandler)
+		moderationRoutes.POST("/blogs/reconcile-counts", bc.ReconcileCountsHandler)
+	}
+
+	// --- Webhooks (admin only) ---
+	webhookRoutes := router.Group("/admin", authMiddleware.Middleware())
+	{
+		webhookRoutes.GET("/webhooks", webhookController.ListWebhooksHandler)
+		webhookRoutes.POST("/webhooks", webhookController.CreateWebhookHandler)
+		webhookRoutes.GET("/webhooks/:id", webhookController.GetWebhookHandler)
+		webhookRoutes.PUT("/webhooks/:id", webhookController.UpdateWebhookHandler)
+		webhookRoutes.DELETE("/webhooks/:id", webhookController.DeleteWebhookHandler)
+		webhookRoutes.POST("/webhooks/:id/ping", webhookController.PingWebhookHandler)
+		webhookRoutes.GET("/webhooks/:id/deliveries", webhookController.ListDeliveriesHandler)
+		webhookRoutes.GET("/webhook-deliveries/:deliveryID", webhookController.GetDeliveryHandler)
+		webhookRoutes.POST("/webhook-deliveries/:deliveryID/replay", webhookController.ReplayDeliveryHandler)
+	}
+
+	// --- Outbox (admin only) ---
+	outboxRoutes := router.Group("/admin/outbox", authMiddleware.Middleware())
+	{
+		outboxRoutes.GET("/", outboxController.ListMessagesHandler)
+		outboxRoutes.GET("/:id", outboxController.GetMessageHandler)
+		outboxRoutes.POST("/:id/retry", outboxController.RetryMessageHandler)
+	}
+
+	// --- Email previews (admin only) ---
+	emailRoutes := router.Group("/admin/email-templates", authMiddleware.Middleware())
+	{
+		emailRoutes.GET("/", emailTemplateController.ListTemplatesHandler)
+		emailRoutes.GET("/:name/preview", emailTemplateController.PreviewTemplateHandler)
+	}
 
 	return router
 }
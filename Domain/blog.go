@@ -5,38 +5,195 @@ import (
 	"time"
 )
 
+type BlogStatus string
+
+const (
+	BlogStatusDraft     BlogStatus = "draft"
+	BlogStatusScheduled BlogStatus = "scheduled"
+	BlogStatusPublished BlogStatus = "published"
+	BlogStatusArchived  BlogStatus = "archived"
+)
+
+func (s BlogStatus) IsValid() bool {
+	switch s {
+	case BlogStatusDraft, BlogStatusScheduled, BlogStatusPublished, BlogStatusArchived:
+		return true
+	}
+	return false
+}
+
+// CommentMode decides who may comment on a blog and whether comments wait
+// for approval by the blog's author or an admin.
+type CommentMode string
+
+const (
+	CommentModeOpen     CommentMode = "open"
+	CommentModeClosed   CommentMode = "closed"
+	CommentModeApproval CommentMode = "approval"
+)
+
+func (m CommentMode) IsValid() bool {
+	switch m {
+	case CommentModeOpen, CommentModeClosed, CommentModeApproval:
+		return true
+	}
+	return false
+}
+
 type Blog struct {
-	ID        string
-	Title     string
-	Content   string
-	UserID    string
-	Tags      []string
-	ViewCount int
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID       string
+	Title    string
+	Slug     string
+	OldSlugs []string // previous slugs, kept so old links keep resolving
+	// Content is the source as the author wrote it, unsanitized. It is sent
+	// as RawContent so clients edit it but never render it; readers get
+	// ContentHTML.
+	Content string `json:"RawContent"`
+	// ContentFormat says how Content is written; ContentHTML, Excerpt,
+	// WordCount and ReadingTime are derived from it on every save.
+	ContentFormat ContentFormat
+	ContentHTML   string
+	Excerpt       string
+	WordCount     int
+	ReadingTime   int // minutes
+	UserID        string
+	Tags          []string
+	ViewCount     int
+	LikeCount     int // "like" reactions, kept up to date on the document
+	CommentCount  int // approved comments that are not deleted
+	Status        BlogStatus
+	CommentMode   CommentMode
+	Hidden        bool       // taken down by moderation; only the author and admins see it
+	PublishAt     *time.Time // when a scheduled blog goes live
+	PublishedAt   *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time  // last change to the content or settings
+	LastViewedAt  *time.Time // reads are counted in batches, so this may lag
+
+	// Reactions counts the blog's reactions by type. It is filled in when
+	// the blog is read and not stored with it.
+	Reactions map[string]int64 `json:",omitempty"`
+}
+
+// Viewer is the user a blog listing is being built for. Anonymous readers
+// have an empty UserID. Only admins and a post's author can see it before
+// it is published.
+type Viewer struct {
+	UserID string
+	Role   Role
+}
+
+func (v Viewer) CanSee(blog *Blog) bool {
+	if blog.Status == BlogStatusPublished && !blog.Hidden {
+		return true
+	}
+	return v.CanManage(blog)
+}
+
+func (v Viewer) CanManage(blog *Blog) bool {
+	return v.Role == RoleAdmin || (v.UserID != "" && v.UserID == blog.UserID)
 }
 
 type IBlogRepository interface {
 	Create(ctx context.Context, blog *Blog) (*Blog, error)
 	FindByID(ctx context.Context, BlogID string) (*Blog, error)
+	// FindByIDs returns the blogs found among blogIDs, keyed by ID.
+	FindByIDs(ctx context.Context, blogIDs []string) (map[string]Blog, error)
+	// FindBySlug matches the current slug first, then any previous slug.
+	FindBySlug(ctx context.Context, slug string) (*Blog, error)
+	SlugTaken(ctx context.Context, slug, excludeBlogID string) (bool, error)
+	// FindWithoutSlug returns up to limit blogs stored before blogs had
+	// slugs, oldest first, starting after the blog afterID ("" to start).
+	FindWithoutSlug(ctx context.Context, afterID string, limit int) ([]Blog, error)
+	// SetSlug gives a blog without a slug its first one, telling whether it
+	// still had none. A slug already in use fails with ErrSlugTaken.
+	SetSlug(ctx context.Context, blogID, slug string) (bool, error)
 	// GetByUser(ctx context.Context, user *User) (*Blog, error)
 	DeleteBlog(ctx context.Context, blog *Blog) error
 	Update(ctx context.Context, blog *Blog) (*Blog, error)
-	Filter(ctx context.Context, tag string, date string, sort string) ([]Blog, error)
-	SearchBlogs(ctx context.Context, tag, date, sort, title, userID string) ([]Blog, error)
-	GetPaginated(ctx context.Context, page, limit int, sort, authorID string) ([]Blog, int64, error)
-    IncrementViewCount(ctx context.Context, blogID string) error
+	UpdateStatus(ctx context.Context, blogID string, status BlogStatus, publishedAt *time.Time) error
+	Schedule(ctx context.Context, blogID string, publishAt time.Time) error
+	SetCommentMode(ctx context.Context, blogID string, mode CommentMode) error
+	SetHidden(ctx context.Context, blogID string, hidden bool) error
+	// PublishNextDue atomically publishes one scheduled blog whose PublishAt
+	// has passed and returns it, or returns nil when none is due. The blog
+	// is left waiting to be announced.
+	PublishNextDue(ctx context.Context, now time.Time) (*Blog, error)
+	// ClaimUnannounced takes a blog published by schedule that is still
+	// waiting to be announced and holds it for lease, so no other publisher
+	// announces it meanwhile. It returns nil when none is waiting.
+	ClaimUnannounced(ctx context.Context, now time.Time, lease time.Duration) (*Blog, error)
+	// MarkAnnounced records that a blog's publication has been announced.
+	MarkAnnounced(ctx context.Context, blogID string) error
+	// FindBlogs returns the page of blogs matching query that viewer may see.
+	// query is expected to be validated already; Text must be in $text syntax.
+	FindBlogs(ctx context.Context, query BlogQuery, viewer Viewer) (*BlogQueryResult, error)
+	// ReplaceTags swaps every tag in from for to on all blogs, without
+	// duplicating to where a blog already had it. It returns the number of
+	// blogs changed.
+	ReplaceTags(ctx context.Context, from []string, to string) (int64, error)
+	RemoveTags(ctx context.Context, names []string) (int64, error)
+	// CountTags counts published blogs per stored tag.
+	CountTags(ctx context.Context) (map[string]int, error)
+	// AddViews adds buffered views to each blog's view count and moves its
+	// LastViewedAt forward; UpdatedAt is left alone. The views are also
+	// logged as activity for trending.
+	AddViews(ctx context.Context, views []BlogViews) error
+	// UpdateTrendingScores scores every blog for each trending window from
+	// its hourly activity as of now. Each hour's activity is weighted and
+	// then halved for every quarter of the window that has passed since;
+	// activity older than the window does not count, and blogs without any
+	// score 0.
+	UpdateTrendingScores(ctx context.Context, now time.Time, weights TrendingWeights) error
+	// IncrementCounts adds likes and comments, which may be negative, to the
+	// blog's stored like and comment counts. New likes and comments are also
+	// logged as activity for trending.
+	IncrementCounts(ctx context.Context, blogID string, likes, comments int) error
+	// ReconcileCounts recomputes blogs' like and comment counts from the
+	// reactions and comments and returns how many blogs it corrected. With
+	// a non-zero since it only looks at blogs with count changes, likes or
+	// comments from then on; otherwise it checks every blog.
+	ReconcileCounts(ctx context.Context, since time.Time) (int64, error)
 }
 type IBlogUsecase interface {
 	Create(ctx context.Context, blog *Blog) error
-	Update(ctx context.Context, input UpdateBlogInput) (*Blog, error)
-	DeleteBlog(ctx context.Context, blogID, userID, userRole string) error
-	FilterBlogs(ctx context.Context, tag string, date string, sort string) ([]Blog, error)
-	SearchBlogs(ctx context.Context, tag, date, sort, title, userID string) ([]Blog, error)
-	GetSuggestion(req AiSuggestionRequest)(string, error)
-	GetBlogs(ctx context.Context, page, limit int, sort, authorID string) (*PaginatedBlogsResponse, error)
-    GetByIDAndIncrementViews(ctx context.Context, blogID string) (*Blog, error)
+	// Update is open to the author and admins.
+	Update(ctx context.Context, input UpdateBlogInput, actor Viewer) (*Blog, error)
+	// DeleteBlog is open to the author and admins.
+	DeleteBlog(ctx context.Context, blogID string, actor Viewer) error
+	PublishBlog(ctx context.Context, blogID string, actor Viewer) (*Blog, error)
+	UnpublishBlog(ctx context.Context, blogID string, actor Viewer) (*Blog, error)
+	ArchiveBlog(ctx context.Context, blogID string, actor Viewer) (*Blog, error)
+	ScheduleBlog(ctx context.Context, blogID string, publishAt time.Time, actor Viewer) (*Blog, error)
+	SetCommentMode(ctx context.Context, blogID string, mode CommentMode, actor Viewer) (*Blog, error)
+	ListRevisions(ctx context.Context, blogID string, actor Viewer) ([]BlogRevision, error)
+	GetRevision(ctx context.Context, blogID string, version int, actor Viewer) (*BlogRevision, error)
+	DiffRevisions(ctx context.Context, blogID string, from, to int, actor Viewer) (*BlogRevisionDiff, error)
+	RestoreRevision(ctx context.Context, blogID string, version int, actor Viewer) (*Blog, error)
+	GetSuggestion(req AiSuggestionRequest) (string, error)
+	ListBlogs(ctx context.Context, query BlogQuery, viewer Viewer) (*BlogListResponse, error)
+	// GetByIDAndIncrementViews counts a view by viewKey, which identifies
+	// the reader; an empty viewKey (a bot, say) is not counted.
+	GetByIDAndIncrementViews(ctx context.Context, blogID string, viewer Viewer, viewKey string) (*Blog, error)
+	// GetBySlug also resolves previous slugs; callers compare the returned
+	// blog's Slug with the requested one to detect a redirect.
+	GetBySlug(ctx context.Context, slug string, viewer Viewer, viewKey string) (*Blog, error)
+	// ReconcileCounts runs the counter reconciliation now (admins only).
+	ReconcileCounts(ctx context.Context, actor Viewer) (int64, error)
 	// Search(ctx context.Context, blogid string) error
 	// Filtration(ctx context.Context) error
 	// PopulatityTracking(ctx context.Context) error
 }
+
+// IBlogPublisher promotes scheduled blogs to published once they are due.
+type IBlogPublisher interface {
+	Run(ctx context.Context)
+	PublishDue(ctx context.Context) (int, error)
+}
+
+// ICounterReconciler periodically corrects the counts stored on blogs, which
+// drift if a process dies between changing a reaction or comment and
+// updating its blog.
+type ICounterReconciler interface {
+	Run(ctx context.Context)
+}
@@ -1,28 +1,178 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
-type Comment struct{
-	ID 			string
-	BlogId 		string
-	UserId 		string
-	Content 	string
-	CreatedAt 	string
+// Comment is a comment on a blog or, when ParentID is set, a reply to
+// another comment. Ancestors lists the IDs from the top-level comment down
+// to the parent, and Depth is their count.
+//
+// A deleted comment that still has replies stays in place as a tombstone:
+// Deleted is set and its content and author are hidden.
+type Comment struct {
+	ID        string
+	BlogId    string
+	UserId    string
+	ParentID  string
+	Ancestors []string
+	Depth     int
+	Content   string
+	// Status is pending while a comment on a blog that requires approval
+	// waits for moderation.
+	Status      CommentStatus
+	ModeratedBy string     `json:",omitempty"`
+	ModeratedAt *time.Time `json:",omitempty"`
+	ReplyCount  int        // direct replies
+	Score       int        // replies anywhere beneath the comment; ranks the top sort
+	CreatedAt   time.Time
+	EditedAt    *time.Time `json:",omitempty"`
+	Deleted     bool       `json:",omitempty"`
+	DeletedAt   *time.Time `json:",omitempty"`
+	DeletedBy   string     `json:",omitempty"`
+	// Edits holds earlier versions of Content, oldest first. Only moderators
+	// get to see them.
+	Edits []CommentEdit `json:",omitempty"`
 }
 
+// CommentEdit is a version of a comment's content and when it was replaced.
+type CommentEdit struct {
+	Content    string
+	ReplacedAt time.Time
+}
+
+// DeletedCommentContent stands in for the content of a tombstone.
+const DeletedCommentContent = "[deleted]"
+
+type CommentStatus string
+
+const (
+	CommentStatusApproved CommentStatus = "approved"
+	CommentStatusPending  CommentStatus = "pending"
+	CommentStatusRejected CommentStatus = "rejected"
+)
+
+// CommentAudience is who a comment listing is for. Everyone sees approved
+// comments; authors also see their own pending ones, and moderators see
+// every pending comment.
+type CommentAudience struct {
+	UserID    string
+	Moderator bool
+}
+
+type CommentSort string
+
+const (
+	CommentSortOldest CommentSort = "oldest"
+	CommentSortNewest CommentSort = "newest"
+	CommentSortTop    CommentSort = "top"
+)
+
+func (s CommentSort) IsValid() bool {
+	switch s {
+	case CommentSortOldest, CommentSortNewest, CommentSortTop:
+		return true
+	}
+	return false
+}
+
+// CommentQuery selects one page of the comments directly under ParentID, or
+// the top-level comments of BlogID when ParentID is empty.
+type CommentQuery struct {
+	BlogID   string
+	ParentID string
+	Sort     CommentSort
+	Audience CommentAudience
+	Page     int
+	Limit    int
+}
+
+// ModerationResult reports which comments a bulk moderation action applied
+// to and why the others were skipped, keyed by comment ID.
+type ModerationResult struct {
+	Processed []string          `json:"processed"`
+	Skipped   map[string]string `json:"skipped,omitempty"`
+}
+
+type CommentPage struct {
+	Comments   []Comment `json:"comments"`
+	Page       int       `json:"page"`
+	Limit      int       `json:"limit"`
+	Total      int64     `json:"total"`
+	TotalPages int       `json:"total_pages"`
+	HasNext    bool      `json:"has_next"`
+	HasPrev    bool      `json:"has_prev"`
+}
+
+// CommentThread is a comment with its replies nested beneath it. Truncated
+// is set on the root when the thread was too large to return whole.
+type CommentThread struct {
+	Comment
+	Replies   []*CommentThread
+	Truncated bool `json:",omitempty"`
+}
 
 type ICommentUsecase interface {
-    CreateComment(ctx context.Context, blogID, userID, content string) (*Comment, error)
-    GetCommentsByBlogID(ctx context.Context, blogID string) ([]Comment, error)
-    DeleteComment(ctx context.Context, commentID, userID string) error
+	// CreateComment honors the blog's comment mode: it refuses comments on
+	// closed blogs and holds them as pending on blogs requiring approval.
+	CreateComment(ctx context.Context, blogID, parentID, content string, actor Viewer) (*Comment, error)
+	ListComments(ctx context.Context, query CommentQuery, viewer Viewer) (*CommentPage, error)
+	// GetReplies pages through the direct replies to a comment.
+	GetReplies(ctx context.Context, blogID, commentID string, query CommentQuery, viewer Viewer) (*CommentPage, error)
+	GetThread(ctx context.Context, blogID, commentID string, viewer Viewer) (*CommentThread, error)
+	// ListPending pages through the moderation queue of a blog, oldest
+	// first, or of every blog when blogID is empty (admins only).
+	ListPending(ctx context.Context, blogID string, page, limit int, actor Viewer) (*CommentPage, error)
+	// ModerateComments approves or rejects pending comments in bulk.
+	ModerateComments(ctx context.Context, commentIDs []string, approve bool, actor Viewer) (*ModerationResult, error)
+	// UpdateComment lets the author change a comment within the edit window.
+	UpdateComment(ctx context.Context, commentID, content string, actor Viewer) (*Comment, error)
+	// GetCommentHistory returns a comment with its earlier versions, for
+	// admins and the owner of the blog.
+	GetCommentHistory(ctx context.Context, commentID string, actor Viewer) (*Comment, error)
+	// DeleteComment is open to the author, the owner of the blog and admins.
+	DeleteComment(ctx context.Context, commentID string, actor Viewer) error
 }
 
 type ICommentRepository interface {
-    Create(ctx context.Context, comment *Comment) (*Comment, error)
-    GetByBlogID(ctx context.Context, blogID string) ([]Comment, error)
-    Delete(ctx context.Context, commentID, userID string) error
+	Create(ctx context.Context, comment *Comment) (*Comment, error)
+	FindByID(ctx context.Context, commentID string) (*Comment, error)
+	List(ctx context.Context, query CommentQuery) ([]Comment, int64, error)
+	// Descendants returns up to limit comments beneath commentID that
+	// audience may see, oldest first.
+	Descendants(ctx context.Context, commentID string, audience CommentAudience, limit int) ([]Comment, error)
+	// ListPending lists pending comments oldest first, across all blogs when
+	// blogID is empty.
+	ListPending(ctx context.Context, blogID string, page, limit int) ([]Comment, int64, error)
+	// SetStatus moves a pending comment to status. Approving a reply counts
+	// it on its parent and ancestors.
+	SetStatus(ctx context.Context, commentID string, status CommentStatus, moderatorID string, at time.Time) error
+	// UpdateContent replaces the content of a live comment, keeping the
+	// previous content in its edits.
+	UpdateContent(ctx context.Context, commentID, content string, editedAt time.Time) (*Comment, error)
+	// Tombstone marks a comment deleted, moving its content into its edits.
+	Tombstone(ctx context.Context, commentID, deletedBy string, deletedAt time.Time) error
+	// Untombstone undoes Tombstone, putting back the content it moved into
+	// the edits.
+	Untombstone(ctx context.Context, commentID string) (*Comment, error)
+	// Delete removes a comment and anything beneath it.
+	Delete(ctx context.Context, commentID string) error
+	// HasReplies reports whether commentID has a reply that is not rejected,
+	// counting the pending ones its ReplyCount leaves out.
+	HasReplies(ctx context.Context, commentID string) (bool, error)
+}
+
+type UpdateCommentRequest struct {
+	Content string `json:"content"`
+}
+
+type ModerateCommentsRequest struct {
+	Action     string   `json:"action"` // "approve" or "reject"
+	CommentIDs []string `json:"comment_ids"`
 }
 
 type CreateCommentRequest struct {
-    Content string `json:"content"`
-}
\ No newline at end of file
+	Content  string `json:"content"`
+	ParentID string `json:"parent_id"`
+}
@@ -1,23 +1,7 @@
 package domain
 
-import (
-	"time"
-)
-
-type Like struct {
-	ID        string
-	BlogID    string
-	UserID    string
-	CreatedAt time.Time
-}
-
-type IBlogLikeRepository interface {
-	AddLike(like *Like) error
-	RemoveLike(blogID, userID string) error
-	IsLiked(blogID, userID string) (bool, error)
-	CountLikes(blogID string) (int, error)
-}
-
+// IBlogLikeUsecase is the original like API, kept on top of reactions:
+// a like is the "like" reaction.
 type IBlogLikeUsecase interface {
 	LikeBlog(blogID, userID string) error
 	RemoveLikeBlog(blogID, userID string) error
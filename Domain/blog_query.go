@@ -0,0 +1,89 @@
+package domain
+
+import "time"
+
+type TagMatch string
+
+const (
+	TagMatchAny TagMatch = "any" // blogs with at least one of the tags
+	TagMatchAll TagMatch = "all" // blogs with every tag
+)
+
+type BlogSort string
+
+const (
+	BlogSortRecent    BlogSort = "recent"
+	BlogSortPublished BlogSort = "published" // newest publication first; published blogs only
+	BlogSortPopular   BlogSort = "popular"   // most viewed
+	BlogSortLikes     BlogSort = "likes"
+	BlogSortComments  BlogSort = "comments"
+	BlogSortTrending  BlogSort = "trending"  // recent activity, decayed over time
+	BlogSortRelevance BlogSort = "relevance" // text queries only
+)
+
+// BlogQuery describes which blogs to list and how. Zero values leave a
+// criterion out. Pagination is by Page, or by Cursor when UseCursor is set,
+// an empty Cursor meaning the first page.
+type BlogQuery struct {
+	Tags     []string
+	TagMatch TagMatch
+	// TagVariants[i] lists every spelling that counts as Tags[i] (its
+	// canonical name and aliases). The usecase fills it in.
+	TagVariants [][]string
+	AuthorID    string
+	From        *time.Time // CreatedAt >= From
+	To          *time.Time // CreatedAt < To
+	// Text is a full-text query over title, tags and content: plain words,
+	// "quoted phrases" that must appear as written and -words or -"phrases"
+	// that must not appear.
+	Text     string
+	Title    string // case-insensitive literal match inside the title
+	MinViews int
+	MinLikes int
+	Status   BlogStatus
+	Sort     BlogSort
+	// Following, when set, keeps only blogs that match it; the home feed
+	// uses it.
+	Following *FollowingFilter
+
+	// TrendingWindow picks the score sort=trending orders by; the usecase
+	// defaults it to 7d.
+	TrendingWindow TrendingWindow
+
+	Page      int
+	Limit     int
+	Cursor    string
+	UseCursor bool
+}
+
+// FollowingFilter matches blogs written by any of AuthorIDs or tagged with
+// any of Tags. Tags should list every stored spelling of a tag.
+type FollowingFilter struct {
+	AuthorIDs []string
+	Tags      []string
+}
+
+// BlogQueryResult is one page of blogs from the repository. Scores holds
+// the text score of each blog by ID when the query had Text. Total is only
+// computed in page mode and NextCursor only in cursor mode.
+type BlogQueryResult struct {
+	Blogs      []Blog
+	Scores     map[string]float64
+	Total      int64
+	NextCursor string
+}
+
+// BlogListResponse is a page of a blog listing. Page, Total and TotalPages
+// are set in page mode, NextCursor in cursor mode while more pages remain.
+type BlogListResponse struct {
+	Blogs []Blog `json:"blogs"`
+	// Matches explains text-query hits, keyed by blog ID.
+	Matches    map[string]BlogMatch `json:"matches,omitempty"`
+	Page       int                  `json:"page,omitempty"`
+	Limit      int                  `json:"limit"`
+	Total      *int64               `json:"total,omitempty"`
+	TotalPages *int                 `json:"total_pages,omitempty"`
+	HasNext    bool                 `json:"has_next"`
+	HasPrev    bool                 `json:"has_prev"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// BlogRevision is an immutable snapshot of a blog taken right before an
+// edit. EditorID and CreatedAt record who made that edit and when.
+type BlogRevision struct {
+	ID            string
+	BlogID        string
+	Version       int
+	EditorID      string
+	Title         string
+	Content       string
+	ContentFormat ContentFormat
+	Tags          []string
+	RestoredFrom  int // version restored by the edit, 0 for regular edits
+	CreatedAt     time.Time
+}
+
+type DiffOp string
+
+const (
+	DiffEqual  DiffOp = "equal"
+	DiffInsert DiffOp = "insert"
+	DiffDelete DiffOp = "delete"
+)
+
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+// BlogRevisionDiff compares two versions of a blog. A version of 0 stands for
+// the blog as it currently is.
+type BlogRevisionDiff struct {
+	BlogID      string
+	From        int
+	To          int
+	Title       []DiffLine
+	Content     []DiffLine
+	TagsAdded   []string
+	TagsRemoved []string
+}
+
+type IBlogRevisionRepository interface {
+	Create(ctx context.Context, revision *BlogRevision) (*BlogRevision, error)
+	ListByBlogID(ctx context.Context, blogID string) ([]BlogRevision, error)
+	FindByVersion(ctx context.Context, blogID string, version int) (*BlogRevision, error)
+	LatestVersion(ctx context.Context, blogID string) (int, error)
+	// Delete removes a revision recorded for an edit that did not go through.
+	Delete(ctx context.Context, revisionID string) error
+	DeleteByBlogID(ctx context.Context, blogID string) error
+}
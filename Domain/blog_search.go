@@ -0,0 +1,14 @@
+package domain
+
+// SearchHighlights are HTML-escaped snippets with the matched words wrapped
+// in <mark> tags.
+type SearchHighlights struct {
+	Title   string   `json:"title,omitempty"`
+	Content []string `json:"content"`
+}
+
+// BlogMatch is how well a blog matched a text query.
+type BlogMatch struct {
+	Score      float64          `json:"score"`
+	Highlights SearchHighlights `json:"highlights"`
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// BlogViews is a batch of views of one blog waiting to be stored.
+type BlogViews struct {
+	BlogID       string
+	Count        int
+	LastViewedAt time.Time
+}
+
+// IBlogViewRepository remembers who viewed a blog recently, so a reader
+// counts once per window however often they reload. viewerKey is a user ID
+// or a fingerprint of an anonymous reader. Views are forgotten once their
+// window is over, including those of deleted blogs.
+type IBlogViewRepository interface {
+	// Record notes a view at the given time and reports whether it is the
+	// first by viewerKey within window.
+	Record(ctx context.Context, blogID, viewerKey string, at time.Time, window time.Duration) (bool, error)
+}
+
+// IViewCounter buffers unique views in memory and writes them to the blogs
+// in batches.
+type IViewCounter interface {
+	// RecordView counts a view unless viewerKey is empty or already viewed
+	// the blog within the window. It reports whether the view counted.
+	RecordView(ctx context.Context, blogID, viewerKey string) (bool, error)
+	// Run flushes the buffer periodically until ctx is cancelled. It does
+	// not flush on the way out; call Flush once requests have stopped.
+	Run(ctx context.Context)
+	Flush(ctx context.Context) error
+}
@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// BlogSummary is the part of a blog shown in bookmark and reading lists.
+type BlogSummary struct {
+	ID          string
+	Title       string
+	Slug        string
+	Excerpt     string
+	UserID      string
+	Tags        []string
+	ReadingTime int // minutes
+	PublishedAt *time.Time
+}
+
+func (b *Blog) Summary() BlogSummary {
+	return BlogSummary{
+		ID:          b.ID,
+		Title:       b.Title,
+		Slug:        b.Slug,
+		Excerpt:     b.Excerpt,
+		UserID:      b.UserID,
+		Tags:        b.Tags,
+		ReadingTime: b.ReadingTime,
+		PublishedAt: b.PublishedAt,
+	}
+}
+
+// Bookmark is a blog a user saved for later. Blog is filled in when
+// bookmarks are listed.
+type Bookmark struct {
+	BlogID  string
+	SavedAt time.Time
+	Blog    *BlogSummary `json:",omitempty"`
+}
+
+type BookmarkPage struct {
+	Bookmarks  []Bookmark `json:"bookmarks"`
+	Page       int        `json:"page"`
+	Limit      int        `json:"limit"`
+	Total      int64      `json:"total"`
+	TotalPages int        `json:"total_pages"`
+	HasNext    bool       `json:"has_next"`
+	HasPrev    bool       `json:"has_prev"`
+}
+
+// ReadingList is a named, ordered collection of blogs. Only its owner can
+// see a private list.
+type ReadingList struct {
+	ID          string
+	UserID      string
+	Name        string
+	Description string
+	Public      bool
+	Items       []ReadingListItem
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// ReadingListItem is one entry of a reading list. Blog is filled in when a
+// single list is fetched.
+type ReadingListItem struct {
+	BlogID  string
+	AddedAt time.Time
+	Blog    *BlogSummary `json:",omitempty"`
+}
+
+type ReadingListInput struct {
+	Name        string
+	Description string
+	Public      bool
+}
+
+type IBookmarkRepository interface {
+	// Add returns false when the blog was already bookmarked.
+	Add(ctx context.Context, userID, blogID string, at time.Time) (bool, error)
+	Remove(ctx context.Context, userID, blogID string) (bool, error)
+	// List pages through a user's bookmarks, most recent first.
+	List(ctx context.Context, userID string, page, limit int) ([]Bookmark, int64, error)
+	// RemoveBlog drops a blog from everyone's bookmarks.
+	RemoveBlog(ctx context.Context, blogID string) error
+}
+
+type IReadingListRepository interface {
+	Create(ctx context.Context, list *ReadingList) error
+	FindByID(ctx context.Context, listID string) (*ReadingList, error)
+	// ListByUser returns a user's lists, newest first, leaving out private
+	// ones unless includePrivate is set.
+	ListByUser(ctx context.Context, userID string, includePrivate bool) ([]ReadingList, error)
+	CountByUser(ctx context.Context, userID string) (int64, error)
+	// Update saves the name, description and visibility of a list.
+	Update(ctx context.Context, list *ReadingList) error
+	Delete(ctx context.Context, listID string) error
+	// AddItem inserts an item at position, or appends it when position is
+	// negative or past the end. It returns false when the blog is already in
+	// the list.
+	AddItem(ctx context.Context, listID string, item ReadingListItem, position int) (bool, error)
+	RemoveItem(ctx context.Context, listID, blogID string) (bool, error)
+	// ReorderItems replaces the items of a list with items, provided it still
+	// holds the blogs of previous in that order. It tells whether it did.
+	ReorderItems(ctx context.Context, listID string, previous, items []ReadingListItem) (bool, error)
+	// RemoveBlog drops a blog from every reading list.
+	RemoveBlog(ctx context.Context, blogID string) error
+}
+
+type IBookmarkUsecase interface {
+	AddBookmark(ctx context.Context, blogID string, actor Viewer) error
+	RemoveBookmark(ctx context.Context, blogID string, actor Viewer) error
+	ListBookmarks(ctx context.Context, page, limit int, actor Viewer) (*BookmarkPage, error)
+
+	CreateReadingList(ctx context.Context, input ReadingListInput, actor Viewer) (*ReadingList, error)
+	// GetReadingList returns a list with the blogs viewer may see resolved
+	// into summaries.
+	GetReadingList(ctx context.Context, listID string, viewer Viewer) (*ReadingList, error)
+	// ListReadingLists returns a user's lists without their items' blogs;
+	// private lists only show up for their owner.
+	ListReadingLists(ctx context.Context, userID string, viewer Viewer) ([]ReadingList, error)
+	UpdateReadingList(ctx context.Context, listID string, input ReadingListInput, actor Viewer) (*ReadingList, error)
+	DeleteReadingList(ctx context.Context, listID string, actor Viewer) error
+	// AddToReadingList inserts a blog at position (zero-based), or at the
+	// end when position is nil.
+	AddToReadingList(ctx context.Context, listID, blogID string, position *int, actor Viewer) (*ReadingList, error)
+	RemoveFromReadingList(ctx context.Context, listID, blogID string, actor Viewer) (*ReadingList, error)
+	// ReorderReadingList puts the items in the order of blogIDs, which must
+	// name every item the actor can see exactly once. Items they cannot see
+	// keep their place.
+	ReorderReadingList(ctx context.Context, listID string, blogIDs []string, actor Viewer) (*ReadingList, error)
+}
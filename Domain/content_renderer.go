@@ -0,0 +1,31 @@
+package domain
+
+type ContentFormat string
+
+const (
+	ContentFormatPlain    ContentFormat = "plain"
+	ContentFormatMarkdown ContentFormat = "markdown"
+	ContentFormatHTML     ContentFormat = "html"
+)
+
+func (f ContentFormat) IsValid() bool {
+	switch f {
+	case ContentFormatPlain, ContentFormatMarkdown, ContentFormatHTML:
+		return true
+	}
+	return false
+}
+
+// RenderedContent is what readers get for a blog's source content: HTML that
+// is safe to embed as-is plus the derived summary fields.
+type RenderedContent struct {
+	HTML        string
+	Text        string // the visible text, whitespace collapsed
+	Excerpt     string
+	WordCount   int
+	ReadingTime int // minutes
+}
+
+type IContentRenderer interface {
+	Render(format ContentFormat, source string) (*RenderedContent, error)
+}
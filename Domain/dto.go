@@ -6,11 +6,11 @@ type TokenResponse struct {
 }
 
 type UpdateBlogInput struct {
-	BlogID  string
-	UserID  string
-	Title   string
-	Content string
-	Tags    []string
+	BlogID        string
+	Title         string
+	Content       string
+	ContentFormat ContentFormat
+	Tags          []string
 }
 type RequestPasswordResetInput struct {
 	Email string
@@ -20,13 +20,3 @@ type ResetPasswordInput struct {
 	Token       string
 	NewPassword string
 }
-
-type PaginatedBlogsResponse struct {
-    Blogs      []Blog `json:"blogs"`
-    Page       int    `json:"page"`
-    Limit      int    `json:"limit"`
-    Total      int64  `json:"total"`
-    TotalPages int    `json:"total_pages"`
-    HasNext    bool   `json:"has_next"`
-    HasPrev    bool   `json:"has_prev"`
-}
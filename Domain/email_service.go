@@ -6,3 +6,36 @@ type IEmailService interface {
 	SendPasswordResetEmail(ctx context.Context, toEmail string, resetToken string) error
 	SendVerificationEmail(ctx context.Context, toEmail string, otp string) error
 }
+
+// EmailMessage is a rendered email. Text is the plain-text alternative to
+// HTML.
+type EmailMessage struct {
+	From    string   `json:",omitempty"`
+	To      []string `json:",omitempty"`
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// IEmailTransport hands rendered emails over for delivery: to an SMTP
+// server, or somewhere local during development.
+type IEmailTransport interface {
+	Send(ctx context.Context, message *EmailMessage) error
+	// Close releases open connections.
+	Close() error
+}
+
+// IEmailTemplates renders the emails the service sends.
+type IEmailTemplates interface {
+	Names() []string
+	// Render returns ErrEmailTemplateNotFound for an unknown name.
+	Render(name string, data any) (*EmailMessage, error)
+	// Preview renders a template with made-up data.
+	Preview(name string) (*EmailMessage, error)
+}
+
+// IEmailTemplateUsecase lets admins preview emails.
+type IEmailTemplateUsecase interface {
+	ListTemplates(ctx context.Context, actor Viewer) ([]string, error)
+	PreviewTemplate(ctx context.Context, name string, actor Viewer) (*EmailMessage, error)
+}
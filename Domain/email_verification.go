@@ -17,6 +17,9 @@ type EmailVerification struct {
 type IEmailVerificationRepository interface {
 	Store(ctx context.Context, verification *EmailVerification) error
 	GetByEmail(ctx context.Context, email string) (*EmailVerification, error)
+	// GetByID fails with ErrVerificationNotFound when there is no such
+	// verification, used or not.
+	GetByID(ctx context.Context, id string) (*EmailVerification, error)
 	GetByOTP(ctx context.Context, otp, email string) (*EmailVerification, error)
 	MarkUsed(ctx context.Context, id string) error
 	DeleteExpired(ctx context.Context) error
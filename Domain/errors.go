@@ -3,10 +3,27 @@ package domain
 import "errors"
 
 var (
-	ErrInvalidInput  = errors.New("invalid input")
-	ErrEmailTaken    = errors.New("email is already registered")
-	ErrUsernameTaken = errors.New("username is already taken")
-	ErrUserNotFound  = errors.New("user not found")
-	ErrUnauthorized  = errors.New("incorrect email or password")
-	ErrInternal      = errors.New("internal error")
+	ErrInvalidInput     = errors.New("invalid input")
+	ErrEmailTaken       = errors.New("email is already registered")
+	ErrUsernameTaken    = errors.New("username is already taken")
+	ErrUserNotFound     = errors.New("user not found")
+	ErrUnauthorized     = errors.New("incorrect email or password")
+	ErrInternal         = errors.New("internal error")
+	ErrBlogNotFound     = errors.New("blog not found")
+	ErrRevisionNotFound = errors.New("revision not found")
+	ErrSlugTaken        = errors.New("slug is already taken")
+	ErrForbidden        = errors.New("you do not have permission to perform this action")
+	ErrTagNotFound      = errors.New("tag not found")
+	ErrTagExists        = errors.New("tag name is already in use")
+	ErrCommentNotFound  = errors.New("comment not found")
+	ErrReportNotFound   = errors.New("report not found")
+	ErrAccountSuspended = errors.New("account is suspended")
+	ErrListNotFound     = errors.New("reading list not found")
+
+	ErrVerificationNotFound  = errors.New("email verification not found")
+	ErrNotificationNotFound  = errors.New("notification not found")
+	ErrWebhookNotFound       = errors.New("webhook not found")
+	ErrDeliveryNotFound      = errors.New("webhook delivery not found")
+	ErrOutboxMessageNotFound = errors.New("outbox message not found")
+	ErrEmailTemplateNotFound = errors.New("email template not found")
 )
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type EventType string
+
+const (
+	// EventReactionAdded: ActorID reacted to UserID's blog, or to their
+	// comment when CommentID is set. Data["reaction"] is the type.
+	EventReactionAdded EventType = "reaction.added"
+	// EventReactionRemoved: ActorID took back a reaction, with the same
+	// fields as EventReactionAdded.
+	EventReactionRemoved EventType = "reaction.removed"
+	// EventCommentCreated: ActorID commented on UserID's blog. It fires once
+	// the comment is visible, which may be when it is approved.
+	EventCommentCreated EventType = "comment.created"
+	// EventCommentReplied: ActorID replied to UserID's comment CommentID.
+	// Data["reply_id"] is the reply.
+	EventCommentReplied EventType = "comment.replied"
+	// EventUserFollowed: ActorID started following UserID.
+	EventUserFollowed EventType = "user.followed"
+	// EventBlogPublished: blog BlogID of UserID went live, by ActorID or on
+	// schedule (no ActorID).
+	EventBlogPublished EventType = "blog.published"
+	// EventBlogUpdated: ActorID changed a live blog, or took it offline.
+	EventBlogUpdated EventType = "blog.updated"
+	// EventBlogDeleted: ActorID deleted a live blog. Data["title"] and
+	// Data["slug"] say which, as it can no longer be looked up.
+	EventBlogDeleted EventType = "blog.deleted"
+	// EventUserRegistered: UserID signed up. Data["username"] is theirs.
+	EventUserRegistered EventType = "user.registered"
+	// EventUserWarned: ActorID, a moderator, warned UserID about their blog
+	// BlogID, their comment CommentID or their account. Data["report_id"]
+	// is the report acted on and Data["note"] the moderator's note.
+	EventUserWarned EventType = "user.warned"
+	// EventNotificationAdded: a notification for UserID was created or
+	// gained an actor. Data["notification_id"] and Data["type"] describe it.
+	EventNotificationAdded EventType = "notification.added"
+)
+
+// Event is something a user did that others may want to hear about. UserID
+// is the user whose content or account it concerns.
+type Event struct {
+	ID         string
+	Type       EventType
+	ActorID    string
+	UserID     string
+	BlogID     string
+	CommentID  string
+	Data       map[string]string
+	OccurredAt time.Time
+}
+
+// EventHandler reacts to one event. Its error reaches the publisher, which
+// fails the operation behind the event, so a subscriber that records side
+// effects must not lose them silently. Subscribers doing best-effort work
+// log their own failures instead.
+type EventHandler func(ctx context.Context, event Event) error
+
+// IEventBus delivers published events to every subscriber in process.
+type IEventBus interface {
+	// Publish fills in the ID and OccurredAt of event when empty and hands
+	// it to the subscribers before returning. It returns the errors of the
+	// subscribers that failed.
+	Publish(ctx context.Context, event Event) error
+	Subscribe(handler EventHandler)
+}
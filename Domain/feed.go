@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type FeedFormat string
+
+const (
+	FeedFormatRSS  FeedFormat = "rss"
+	FeedFormatAtom FeedFormat = "atom"
+	FeedFormatJSON FeedFormat = "json"
+)
+
+func (f FeedFormat) IsValid() bool {
+	switch f {
+	case FeedFormatRSS, FeedFormatAtom, FeedFormatJSON:
+		return true
+	}
+	return false
+}
+
+// Feed is a format-independent syndication feed of published blogs.
+type Feed struct {
+	Title       string
+	Description string
+	Link        string // the site or listing the feed mirrors
+	SelfLink    string // where the feed is served, without the trailing /<format>
+	Updated     time.Time
+	Items       []FeedItem
+}
+
+type FeedItem struct {
+	ID          string // permanent, never changes with the slug
+	Title       string
+	Link        string
+	Summary     string
+	ContentHTML string
+	Author      string
+	Tags        []string
+	Published   time.Time
+	Updated     time.Time
+}
+
+type IFeedUsecase interface {
+	SiteFeed(ctx context.Context, limit int) (*Feed, error)
+	AuthorFeed(ctx context.Context, userID string, limit int) (*Feed, error)
+	TagFeed(ctx context.Context, tag string, limit int) (*Feed, error)
+}
+
+type IFeedEncoder interface {
+	Encode(feed *Feed, format FeedFormat) (body []byte, contentType string, err error)
+}
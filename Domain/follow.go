@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Follow is one user following another.
+type Follow struct {
+	FollowerID string
+	FolloweeID string
+	CreatedAt  time.Time
+}
+
+// FollowUser is a user in a followers or following list.
+type FollowUser struct {
+	ID             string    `json:"id"`
+	Username       string    `json:"username"`
+	Bio            string    `json:"bio,omitempty"`
+	ProfilePicture string    `json:"profile_picture,omitempty"`
+	FollowedAt     time.Time `json:"followed_at"`
+}
+
+type FollowPage struct {
+	Users      []FollowUser `json:"users"`
+	Page       int          `json:"page"`
+	Limit      int          `json:"limit"`
+	Total      int64        `json:"total"`
+	TotalPages int          `json:"total_pages"`
+	HasNext    bool         `json:"has_next"`
+	HasPrev    bool         `json:"has_prev"`
+}
+
+// FollowCounts summarizes a user's place in the social graph. Followed
+// tells whether the viewer follows the user; it is false for anonymous
+// viewers.
+type FollowCounts struct {
+	Followers int64 `json:"followers"`
+	Following int64 `json:"following"`
+	Followed  bool  `json:"followed"`
+}
+
+type IFollowRepository interface {
+	// Follow records that followerID follows followeeID. It returns false
+	// when the follow already existed.
+	Follow(ctx context.Context, followerID, followeeID string, at time.Time) (bool, error)
+	// Unfollow returns false when there was no follow to remove.
+	Unfollow(ctx context.Context, followerID, followeeID string) (bool, error)
+	IsFollowing(ctx context.Context, followerID, followeeID string) (bool, error)
+	// ListFollowers and ListFollowing page through a user's follows, most
+	// recent first.
+	ListFollowers(ctx context.Context, userID string, page, limit int) ([]Follow, int64, error)
+	ListFollowing(ctx context.Context, userID string, page, limit int) ([]Follow, int64, error)
+	CountFollowers(ctx context.Context, userID string) (int64, error)
+	CountFollowing(ctx context.Context, userID string) (int64, error)
+	// FolloweeIDs lists everyone followerID follows.
+	FolloweeIDs(ctx context.Context, followerID string) ([]string, error)
+
+	FollowTag(ctx context.Context, userID, tag string, at time.Time) (bool, error)
+	UnfollowTag(ctx context.Context, userID, tag string) (bool, error)
+	// FollowedTags lists the tags userID follows, as they were stored.
+	FollowedTags(ctx context.Context, userID string) ([]string, error)
+	// RemoveTagFollows drops every follow of the given tags and returns how
+	// many it removed.
+	RemoveTagFollows(ctx context.Context, tags []string) (int64, error)
+}
+
+type IFollowUsecase interface {
+	FollowUser(ctx context.Context, userID string, actor Viewer) error
+	UnfollowUser(ctx context.Context, userID string, actor Viewer) error
+	ListFollowers(ctx context.Context, userID string, page, limit int) (*FollowPage, error)
+	ListFollowing(ctx context.Context, userID string, page, limit int) (*FollowPage, error)
+	GetFollowCounts(ctx context.Context, userID string, viewer Viewer) (*FollowCounts, error)
+
+	// FollowTag returns the canonical name of the followed tag.
+	FollowTag(ctx context.Context, tag string, actor Viewer) (string, error)
+	UnfollowTag(ctx context.Context, tag string, actor Viewer) error
+	ListFollowedTags(ctx context.Context, userID string) ([]string, error)
+
+	// GetFeed lists the published blogs of the authors and tags actor
+	// follows, most recently published first, by cursor.
+	GetFeed(ctx context.Context, cursor string, limit int, actor Viewer) (*BlogListResponse, error)
+}
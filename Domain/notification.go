@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type NotificationType string
+
+const (
+	NotificationReaction NotificationType = "reaction"
+	NotificationComment  NotificationType = "comment"
+	NotificationReply    NotificationType = "reply"
+	NotificationFollow   NotificationType = "follow"
+	// NotificationWarning comes from a moderator. It cannot be muted, so it
+	// is not among NotificationTypes.
+	NotificationWarning NotificationType = "warning"
+)
+
+var NotificationTypes = []NotificationType{NotificationReaction, NotificationComment, NotificationReply, NotificationFollow}
+
+// NotificationEvents are the events that can notify someone.
+var NotificationEvents = []EventType{EventReactionAdded, EventCommentCreated, EventCommentReplied, EventUserFollowed, EventUserWarned}
+
+func (t NotificationType) IsValid() bool {
+	for _, known := range NotificationTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification tells a user that others acted on their content or account.
+// Events of one kind on one target are grouped into a single unread
+// notification; once it is read, the next event starts a new one.
+type Notification struct {
+	ID        string
+	UserID    string
+	Type      NotificationType
+	GroupKey  string `json:"-"`
+	BlogID    string `json:",omitempty"`
+	CommentID string `json:",omitempty"`
+	Reaction  string `json:",omitempty"`
+	// Note is the moderator's note on a warning.
+	Note string `json:",omitempty"`
+	// ActorIDs holds the most recent actors first; ActorCount counts all of
+	// them.
+	ActorIDs   []string `json:"-"`
+	ActorCount int
+	Read       bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+
+	// Filled in when notifications are listed.
+	Actors  []NotificationActor
+	Blog    *BlogSummary `json:",omitempty"`
+	Message string
+}
+
+type NotificationActor struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+}
+
+type NotificationPage struct {
+	Notifications []Notification `json:"notifications"`
+	Page          int            `json:"page"`
+	Limit         int            `json:"limit"`
+	Total         int64          `json:"total"`
+	TotalPages    int            `json:"total_pages"`
+	HasNext       bool           `json:"has_next"`
+	HasPrev       bool           `json:"has_prev"`
+}
+
+// NotificationPreferences says for each notification type whether the
+// user wants it.
+type NotificationPreferences map[NotificationType]bool
+
+type INotificationRepository interface {
+	// Add records actorID in the user's unread notification with the same
+	// group key, creating it from notification when there is none, and
+	// returns its ID.
+	Add(ctx context.Context, notification *Notification, actorID string, at time.Time) (string, error)
+	// List pages through a user's notifications, most recently updated first.
+	List(ctx context.Context, userID string, unreadOnly bool, page, limit int) ([]Notification, int64, error)
+	CountUnread(ctx context.Context, userID string) (int64, error)
+	MarkRead(ctx context.Context, userID, notificationID string) error
+	// MarkAllRead returns how many notifications it marked.
+	MarkAllRead(ctx context.Context, userID string) (int64, error)
+	Delete(ctx context.Context, userID, notificationID string) error
+	// MutedTypes lists the notification types the user turned off.
+	MutedTypes(ctx context.Context, userID string) ([]NotificationType, error)
+	SetMutedTypes(ctx context.Context, userID string, muted []NotificationType) error
+}
+
+type INotificationUsecase interface {
+	// HandleEvent turns a domain event into a notification for the user it
+	// concerns. It is meant to be subscribed to the event bus.
+	HandleEvent(ctx context.Context, event Event) error
+	List(ctx context.Context, unreadOnly bool, page, limit int, actor Viewer) (*NotificationPage, error)
+	CountUnread(ctx context.Context, actor Viewer) (int64, error)
+	MarkRead(ctx context.Context, notificationID string, actor Viewer) error
+	MarkAllRead(ctx context.Context, actor Viewer) (int64, error)
+	Delete(ctx context.Context, notificationID string, actor Viewer) error
+	GetPreferences(ctx context.Context, actor Viewer) (NotificationPreferences, error)
+	// UpdatePreferences changes only the types present in prefs.
+	UpdatePreferences(ctx context.Context, prefs NotificationPreferences, actor Viewer) (NotificationPreferences, error)
+}
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Outbox topics. Each names a side effect and the shape of its payload.
+const (
+	// OutboxVerificationEmail sends a VerificationEmail.
+	OutboxVerificationEmail = "email.verification"
+	// OutboxPasswordResetEmail sends a PasswordResetEmail.
+	OutboxPasswordResetEmail = "email.password_reset"
+	// OutboxEventPrefix starts the topics of durable event subscribers,
+	// whose payload is the Event.
+	OutboxEventPrefix = "event."
+)
+
+// VerificationEmail names the stored verification whose code it carries,
+// so the code itself never sits in the outbox.
+type VerificationEmail struct {
+	Email          string
+	VerificationID string
+}
+
+// PasswordResetEmail is for the user UserID. Its reset token is only created
+// when the email is sent, so the token never sits in the outbox.
+type PasswordResetEmail struct {
+	UserID string
+	Email  string
+}
+
+type OutboxStatus string
+
+const (
+	OutboxPending   OutboxStatus = "pending"
+	OutboxDelivered OutboxStatus = "delivered"
+	OutboxDead      OutboxStatus = "dead" // out of attempts, waiting for an admin
+)
+
+// OutboxMessage is a side effect recorded by the operation that caused it
+// and carried out later by the outbox dispatcher, at least once. Payload is
+// JSON. It holds references rather than secrets, is never shown, and is
+// dropped once the message is delivered or has expired.
+type OutboxMessage struct {
+	ID      string
+	Topic   string
+	Payload string `json:"-"`
+	// ExpiresAt is when the message stops being worth delivering, such as
+	// when the code in an email runs out. Expired messages are dead.
+	ExpiresAt     *time.Time `json:",omitempty"`
+	Status        OutboxStatus
+	Attempts      int
+	NextAttemptAt *time.Time `json:",omitempty"`
+	LastError     string     `json:",omitempty"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeliveredAt   *time.Time `json:",omitempty"`
+}
+
+func (m *OutboxMessage) Expired(now time.Time) bool {
+	return m.ExpiresAt != nil && !now.Before(*m.ExpiresAt)
+}
+
+type OutboxPage struct {
+	Messages   []OutboxMessage `json:"messages"`
+	Page       int             `json:"page"`
+	Limit      int             `json:"limit"`
+	Total      int64           `json:"total"`
+	TotalPages int             `json:"total_pages"`
+	HasNext    bool            `json:"has_next"`
+	HasPrev    bool            `json:"has_prev"`
+}
+
+type IOutboxRepository interface {
+	Add(ctx context.Context, message *OutboxMessage) error
+	FindByID(ctx context.Context, messageID string) (*OutboxMessage, error)
+	// List leaves out the payloads. An empty status or topic matches all.
+	List(ctx context.Context, status OutboxStatus, topic string, page, limit int) ([]OutboxMessage, int64, error)
+	// ClaimDue takes the pending message that has been due the longest and
+	// holds it for lease, so no other dispatcher handles it meanwhile. It
+	// returns nil when nothing is due.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*OutboxMessage, error)
+	// Save stores the outcome of an attempt.
+	Save(ctx context.Context, message *OutboxMessage) error
+	// ExpireDue dead-letters the undelivered messages whose ExpiresAt has
+	// passed and drops their payloads. It returns how many it changed.
+	ExpireDue(ctx context.Context, now time.Time) (int64, error)
+}
+
+// OutboxHandler carries out one message. As a message may be handled more
+// than once, handlers should be safe to repeat.
+type OutboxHandler func(ctx context.Context, payload []byte) error
+
+// IOutbox records side effects. Usecases enqueue them as part of the
+// operation that causes them and fail that operation when they cannot.
+type IOutbox interface {
+	Enqueue(ctx context.Context, topic string, payload any) error
+	// EnqueueUntil enqueues a side effect that is pointless after expiresAt.
+	EnqueueUntil(ctx context.Context, topic string, payload any, expiresAt time.Time) error
+}
+
+type IOutboxDispatcher interface {
+	IOutbox
+	// Handle sets the handler of a topic. Messages of a topic nobody handles
+	// are dead-lettered.
+	Handle(topic string, handler OutboxHandler)
+	// Durable wraps an event subscriber so that the events of the given
+	// types go through the outbox under name, and are retried until it
+	// succeeds. Events of other types are dropped.
+	Durable(name string, handler EventHandler, types ...EventType) EventHandler
+	Run(ctx context.Context)
+	// DispatchDue handles every message that is due and returns how many it
+	// attempted.
+	DispatchDue(ctx context.Context) (int, error)
+}
+
+// IOutboxUsecase lets admins inspect the outbox and retry dead letters.
+type IOutboxUsecase interface {
+	ListMessages(ctx context.Context, status OutboxStatus, topic string, page, limit int, actor Viewer) (*OutboxPage, error)
+	GetMessage(ctx context.Context, messageID string, actor Viewer) (*OutboxMessage, error)
+	// RetryMessage gives a dead message that has not expired a fresh set of
+	// attempts.
+	RetryMessage(ctx context.Context, messageID string, actor Viewer) (*OutboxMessage, error)
+}
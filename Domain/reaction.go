@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type ReactionTargetType string
+
+const (
+	ReactionTargetBlog    ReactionTargetType = "blog"
+	ReactionTargetComment ReactionTargetType = "comment"
+)
+
+// ReactionLike is always available; the like endpoints are built on it.
+const ReactionLike = "like"
+
+// Reaction is one user's reaction to a blog or comment. A user has at most
+// one reaction per target; reacting again switches its type.
+type Reaction struct {
+	TargetType ReactionTargetType
+	TargetID   string
+	UserID     string
+	Type       string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// ReactionSummary counts the reactions to a target by type. Mine is the
+// viewer's own reaction, if any.
+type ReactionSummary struct {
+	Counts map[string]int64 `json:"counts"`
+	Total  int64            `json:"total"`
+	Mine   string           `json:"mine,omitempty"`
+}
+
+// Reactor is a user in a "who reacted" listing.
+type Reactor struct {
+	UserID    string    `json:"user_id"`
+	Username  string    `json:"username"`
+	Type      string    `json:"type"`
+	ReactedAt time.Time `json:"reacted_at"`
+}
+
+type ReactorPage struct {
+	Reactors   []Reactor `json:"reactors"`
+	Page       int       `json:"page"`
+	Limit      int       `json:"limit"`
+	Total      int64     `json:"total"`
+	TotalPages int       `json:"total_pages"`
+	HasNext    bool      `json:"has_next"`
+	HasPrev    bool      `json:"has_prev"`
+}
+
+type IReactionRepository interface {
+	// Set records userID's reaction to a target, replacing any earlier one,
+	// and returns the type it replaced ("" when there was none).
+	Set(ctx context.Context, targetType ReactionTargetType, targetID, userID, reactionType string, at time.Time) (string, error)
+	// Remove deletes userID's reaction to a target, or only a reaction of
+	// onlyType when that is set. It returns the type removed, or "".
+	Remove(ctx context.Context, targetType ReactionTargetType, targetID, userID, onlyType string) (string, error)
+	// Find returns userID's reaction type on a target, or "".
+	Find(ctx context.Context, targetType ReactionTargetType, targetID, userID string) (string, error)
+	// Counts counts the reactions to each of targetIDs by type.
+	Counts(ctx context.Context, targetType ReactionTargetType, targetIDs []string) (map[string]map[string]int64, error)
+	// List pages through the reactions to a target, most recent first,
+	// optionally of one type only.
+	List(ctx context.Context, targetType ReactionTargetType, targetID, reactionType string, page, limit int) ([]Reaction, int64, error)
+	RemoveTarget(ctx context.Context, targetType ReactionTargetType, targetID string) error
+}
+
+type IReactionUsecase interface {
+	// Types lists the configured reaction types.
+	Types() []string
+	React(ctx context.Context, targetType ReactionTargetType, targetID, reactionType string, actor Viewer) (*ReactionSummary, error)
+	// Unreact removes actor's reaction, or only a reaction of onlyType when
+	// that is set.
+	Unreact(ctx context.Context, targetType ReactionTargetType, targetID, onlyType string, actor Viewer) (*ReactionSummary, error)
+	GetReactions(ctx context.Context, targetType ReactionTargetType, targetID string, viewer Viewer) (*ReactionSummary, error)
+	ListReactors(ctx context.Context, targetType ReactionTargetType, targetID, reactionType string, page, limit int, viewer Viewer) (*ReactorPage, error)
+}
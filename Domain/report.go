@@ -0,0 +1,214 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type ReportTargetType string
+
+const (
+	ReportTargetBlog    ReportTargetType = "blog"
+	ReportTargetComment ReportTargetType = "comment"
+	ReportTargetUser    ReportTargetType = "user"
+)
+
+func (t ReportTargetType) IsValid() bool {
+	switch t {
+	case ReportTargetBlog, ReportTargetComment, ReportTargetUser:
+		return true
+	}
+	return false
+}
+
+type ReportReason string
+
+const (
+	ReportReasonSpam           ReportReason = "spam"
+	ReportReasonHarassment     ReportReason = "harassment"
+	ReportReasonHate           ReportReason = "hate"
+	ReportReasonSexual         ReportReason = "sexual"
+	ReportReasonViolence       ReportReason = "violence"
+	ReportReasonMisinformation ReportReason = "misinformation"
+	ReportReasonOther          ReportReason = "other"
+)
+
+func (r ReportReason) IsValid() bool {
+	switch r {
+	case ReportReasonSpam, ReportReasonHarassment, ReportReasonHate, ReportReasonSexual,
+		ReportReasonViolence, ReportReasonMisinformation, ReportReasonOther:
+		return true
+	}
+	return false
+}
+
+type ReportStatus string
+
+const (
+	ReportStatusOpen      ReportStatus = "open"
+	ReportStatusResolved  ReportStatus = "resolved"
+	ReportStatusDismissed ReportStatus = "dismissed"
+)
+
+func (s ReportStatus) IsValid() bool {
+	switch s {
+	case ReportStatusOpen, ReportStatusResolved, ReportStatusDismissed:
+		return true
+	}
+	return false
+}
+
+// ModerationActionType is what an admin did about reported content.
+type ModerationActionType string
+
+const (
+	ModerationDismiss ModerationActionType = "dismiss" // no action needed
+	ModerationHide    ModerationActionType = "hide"    // hide the blog or comment
+	ModerationWarn    ModerationActionType = "warn"    // warn the author
+	ModerationSuspend ModerationActionType = "suspend" // suspend the author
+
+	// Reversals, taken outside of any report.
+	ModerationUnhide    ModerationActionType = "unhide"    // restore a hidden blog or comment
+	ModerationUnsuspend ModerationActionType = "unsuspend" // lift a suspension
+)
+
+// IsValid reports whether a report can be resolved with a.
+func (a ModerationActionType) IsValid() bool {
+	switch a {
+	case ModerationDismiss, ModerationHide, ModerationWarn, ModerationSuspend:
+		return true
+	}
+	return false
+}
+
+// Report flags a blog, comment or user. A user has at most one open report
+// per target; reporting the same target again updates that report.
+type Report struct {
+	ID           string
+	TargetType   ReportTargetType
+	TargetID     string
+	TargetUserID string // author of the reported content, or the reported user
+	ReporterID   string
+	Reason       ReportReason
+	Details      string
+	Status       ReportStatus
+	Resolution   ModerationActionType `json:",omitempty"`
+	ResolvedBy   string               `json:",omitempty"`
+	ResolvedAt   *time.Time           `json:",omitempty"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// ModerationAction is an entry in the audit log of moderation decisions.
+type ModerationAction struct {
+	ID           string
+	ActorID      string
+	Action       ModerationActionType
+	TargetType   ReportTargetType
+	TargetID     string
+	TargetUserID string
+	ReportID     string     `json:",omitempty"`
+	Note         string     `json:",omitempty"`
+	Until        *time.Time `json:",omitempty"` // end of a suspension
+	CreatedAt    time.Time
+}
+
+// ReportQuery filters the report queue; zero values leave a criterion out.
+type ReportQuery struct {
+	Status       ReportStatus
+	TargetType   ReportTargetType
+	TargetID     string
+	TargetUserID string
+	Reason       ReportReason
+	Page         int
+	Limit        int
+}
+
+type ReportPage struct {
+	Reports    []Report `json:"reports"`
+	Page       int      `json:"page"`
+	Limit      int      `json:"limit"`
+	Total      int64    `json:"total"`
+	TotalPages int      `json:"total_pages"`
+	HasNext    bool     `json:"has_next"`
+	HasPrev    bool     `json:"has_prev"`
+}
+
+// ReportCounts summarizes the queue: every report by status, and the open
+// ones by target type and reason.
+type ReportCounts struct {
+	ByStatus         map[ReportStatus]int64     `json:"by_status"`
+	OpenByTargetType map[ReportTargetType]int64 `json:"open_by_target_type"`
+	OpenByReason     map[ReportReason]int64     `json:"open_by_reason"`
+}
+
+type ModerationActionQuery struct {
+	TargetUserID string
+	Page         int
+	Limit        int
+}
+
+type ModerationActionPage struct {
+	Actions    []ModerationAction `json:"actions"`
+	Page       int                `json:"page"`
+	Limit      int                `json:"limit"`
+	Total      int64              `json:"total"`
+	TotalPages int                `json:"total_pages"`
+	HasNext    bool               `json:"has_next"`
+	HasPrev    bool               `json:"has_prev"`
+}
+
+type CreateReportInput struct {
+	TargetType ReportTargetType
+	TargetID   string
+	Reason     ReportReason
+	Details    string
+}
+
+type ResolveReportInput struct {
+	Action ModerationActionType
+	Note   string
+	// SuspendFor is how long a suspension lasts; zero suspends until an
+	// admin lifts it.
+	SuspendFor time.Duration
+}
+
+type IReportRepository interface {
+	// Upsert files report, or updates the reason and details of the
+	// reporter's open report on the same target. created tells which.
+	Upsert(ctx context.Context, report *Report) (created bool, err error)
+	FindByID(ctx context.Context, reportID string) (*Report, error)
+	List(ctx context.Context, query ReportQuery) ([]Report, int64, error)
+	Counts(ctx context.Context) (*ReportCounts, error)
+	// Close closes the report if it is still open, telling whether it was;
+	// of two moderators resolving the same report, only one gets true.
+	Close(ctx context.Context, reportID string, status ReportStatus, resolution ModerationActionType,
+		resolvedBy string, resolvedAt time.Time) (bool, error)
+	// Reopen undoes Close when the action taken on the report failed.
+	Reopen(ctx context.Context, reportID string) error
+	// CloseTarget closes every open report on a target with the given
+	// status and resolution.
+	CloseTarget(ctx context.Context, targetType ReportTargetType, targetID string, status ReportStatus,
+		resolution ModerationActionType, resolvedBy string, resolvedAt time.Time) (int64, error)
+}
+
+type IModerationActionRepository interface {
+	Create(ctx context.Context, action *ModerationAction) error
+	List(ctx context.Context, query ModerationActionQuery) ([]ModerationAction, int64, error)
+}
+
+type IReportUsecase interface {
+	CreateReport(ctx context.Context, input CreateReportInput, actor Viewer) (*Report, bool, error)
+	// The rest are for admins only.
+	ListReports(ctx context.Context, query ReportQuery, actor Viewer) (*ReportPage, error)
+	GetReport(ctx context.Context, reportID string, actor Viewer) (*Report, error)
+	CountReports(ctx context.Context, actor Viewer) (*ReportCounts, error)
+	// ResolveReport applies input.Action to the reported target and closes
+	// every open report on it.
+	ResolveReport(ctx context.Context, reportID string, input ResolveReportInput, actor Viewer) (*Report, error)
+	// Unhide puts a hidden blog or comment back in public view.
+	Unhide(ctx context.Context, targetType ReportTargetType, targetID, note string, actor Viewer) (*ModerationAction, error)
+	// LiftSuspension lets a suspended user sign in again.
+	LiftSuspension(ctx context.Context, userID, note string, actor Viewer) (*ModerationAction, error)
+	ListModerationActions(ctx context.Context, query ModerationActionQuery, actor Viewer) (*ModerationActionPage, error)
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Stream event types sent to real-time clients.
+const (
+	StreamCommentCreated   = "comment.created"
+	StreamReactionsChanged = "reactions.changed"
+	StreamNotification     = "notification"
+)
+
+// BlogTopic carries the comments and reaction counts of a blog.
+func BlogTopic(blogID string) string { return "blog:" + blogID }
+
+// UserTopic carries a user's notifications. Only the user may follow it.
+func UserTopic(userID string) string { return "user:" + userID }
+
+// StreamEvent is one message to the clients following Topic. Data must
+// marshal to JSON; a broker that crosses process boundaries hands it back
+// as raw JSON.
+type StreamEvent struct {
+	ID    string
+	Topic string
+	Type  string
+	Data  any
+	At    time.Time
+}
+
+// IStreamBroker carries stream events between the instances of the API. The
+// in-process broker only reaches its own instance; a shared one (Redis,
+// NATS, ...) lets every instance fan out what any of them publishes.
+type IStreamBroker interface {
+	Publish(ctx context.Context, event StreamEvent) error
+	// Subscribe hands every published event, from any instance, to handler
+	// until ctx is done.
+	Subscribe(ctx context.Context, handler func(StreamEvent)) error
+}
+
+// StreamSubscription is one client's view of the stream. Replay holds the
+// buffered events after the client's Last-Event-ID; Reset is set when that
+// ID is no longer buffered and the client should refetch instead. Events is
+// closed when the subscription ends, including when the client falls too
+// far behind.
+type StreamSubscription struct {
+	Replay []StreamEvent
+	Reset  bool
+	Events <-chan StreamEvent
+	Close  func()
+}
+
+// IStreamHub fans the events of this instance's broker subscription out to
+// connected clients and keeps a short buffer for reconnects.
+type IStreamHub interface {
+	Subscribe(topics []string, lastEventID string) *StreamSubscription
+	Run(ctx context.Context)
+	// Close ends every subscription, so streams finish on shutdown.
+	Close()
+}
+
+type IStreamUsecase interface {
+	// HandleEvent turns a domain event into stream events. It is meant to be
+	// subscribed to the event bus. Live updates are best-effort, so failures
+	// are logged rather than returned.
+	HandleEvent(ctx context.Context, event Event) error
+	// Subscribe follows the given blogs and the actor's own notifications.
+	Subscribe(ctx context.Context, blogIDs []string, lastEventID string, actor Viewer) (*StreamSubscription, error)
+}
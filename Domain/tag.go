@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const MaxTagLength = 50
+
+// Tag is the canonical form of a blog tag. Blogs always store canonical
+// names; any alias written to a blog is replaced by the tag it belongs to.
+type Tag struct {
+	ID          string
+	Name        string
+	Aliases     []string
+	Description string
+	PostCount   int // published posts; only filled in by listings
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NormalizeTag lowercases raw, drops a leading '#', and turns runs of
+// whitespace into single dashes, so " Go ", "#go" and "GO" are all "go".
+func NormalizeTag(raw string) string {
+	raw = strings.TrimPrefix(strings.TrimSpace(raw), "#")
+	return strings.Join(strings.FieldsFunc(strings.ToLower(raw), unicode.IsSpace), "-")
+}
+
+type ITagRepository interface {
+	// FindByName matches a canonical name or an alias.
+	FindByName(ctx context.Context, name string) (*Tag, error)
+	// FindByNames returns the tags any of names belongs to, keyed by each
+	// name that matched (canonical or alias).
+	FindByNames(ctx context.Context, names []string) (map[string]*Tag, error)
+	List(ctx context.Context) ([]Tag, error)
+	// EnsureExists creates tags for names that are not yet known.
+	EnsureExists(ctx context.Context, names []string) error
+	Create(ctx context.Context, tag *Tag) error
+	Update(ctx context.Context, tag *Tag) error
+	Delete(ctx context.Context, tagID string) error
+}
+
+type ITagUsecase interface {
+	ListTags(ctx context.Context) ([]Tag, error)
+	GetTag(ctx context.Context, name string) (*Tag, error)
+	// UpdateTag renames a tag and/or changes its description. The old name
+	// becomes an alias so existing links keep working.
+	UpdateTag(ctx context.Context, name string, input UpdateTagInput, actor Viewer) (*Tag, error)
+	MergeTag(ctx context.Context, source, target string, actor Viewer) (*Tag, error)
+	DeleteTag(ctx context.Context, name string, actor Viewer) error
+	AddAlias(ctx context.Context, name, alias string, actor Viewer) (*Tag, error)
+	RemoveAlias(ctx context.Context, name, alias string, actor Viewer) (*Tag, error)
+}
+
+type UpdateTagInput struct {
+	Name        *string
+	Description *string
+}
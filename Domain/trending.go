@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// TrendingWindow is a period trending scores are computed over.
+type TrendingWindow string
+
+const (
+	TrendingDay   TrendingWindow = "24h"
+	TrendingWeek  TrendingWindow = "7d"
+	TrendingMonth TrendingWindow = "30d"
+)
+
+// TrendingWindows lists every window, longest last.
+var TrendingWindows = []TrendingWindow{TrendingDay, TrendingWeek, TrendingMonth}
+
+func (w TrendingWindow) IsValid() bool {
+	switch w {
+	case TrendingDay, TrendingWeek, TrendingMonth:
+		return true
+	}
+	return false
+}
+
+func (w TrendingWindow) Duration() time.Duration {
+	switch w {
+	case TrendingDay:
+		return 24 * time.Hour
+	case TrendingWeek:
+		return 7 * 24 * time.Hour
+	case TrendingMonth:
+		return 30 * 24 * time.Hour
+	}
+	return 0
+}
+
+// TrendingWeights is how much one view, like and comment adds to a trending
+// score before decay.
+type TrendingWeights struct {
+	View    float64
+	Like    float64
+	Comment float64
+}
+
+// BlogActivity is what happened to a blog during one hour: new views, likes
+// and comments. Trending scores are computed from it.
+type BlogActivity struct {
+	BlogID   string
+	Hour     time.Time
+	Views    int
+	Likes    int
+	Comments int
+}
+
+// ITrendingUpdater recomputes the trending scores stored on blogs.
+type ITrendingUpdater interface {
+	Run(ctx context.Context)
+	Update(ctx context.Context) error
+}
@@ -24,6 +24,14 @@ type User struct {
 	Bio            string
 	ProfilePicture string
 	ContactInfo    string
+	// Suspended users cannot sign in; SuspendedUntil is nil for a
+	// suspension without an end.
+	Suspended      bool
+	SuspendedUntil *time.Time
+}
+
+func (u *User) IsSuspended(now time.Time) bool {
+	return u.Suspended && (u.SuspendedUntil == nil || now.Before(*u.SuspendedUntil))
 }
 
 type IUserRepository interface {
@@ -32,11 +40,16 @@ type IUserRepository interface {
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
 	GetByUsername(ctx context.Context, username string) (*User, error)
 	GetByID(ctx context.Context, id string) (*User, error)
+	// GetByIDs returns the users found among ids, keyed by ID.
+	GetByIDs(ctx context.Context, ids []string) (map[string]User, error)
 	ExistsByUsername(ctx context.Context, username string) (bool, error)
 	Promote(ctx context.Context, user *User) error
 	Update(ctx context.Context, user *User) error
 	UpdatePassword(ctx context.Context, userID string, hashedPassword string) error
 	VerifyUser(ctx context.Context, userID string) error
+	// SetSuspension suspends a user until the given time (nil for no end),
+	// or lifts the suspension when suspended is false.
+	SetSuspension(ctx context.Context, userID string, suspended bool, until *time.Time) error
 }
 
 type IUserUsecase interface {
@@ -46,6 +59,6 @@ type IUserUsecase interface {
 	Promote(ctx context.Context, username string) error
 	Logout(ctx context.Context, userID string) error
 	UpdateProfile(ctx context.Context, userID, bio, profilePicture, contactInfo string) error
-	RequestPasswordReset(ctx context.Context, input RequestPasswordResetInput) (token string, err error)
+	RequestPasswordReset(ctx context.Context, input RequestPasswordResetInput) error
 	ResetPassword(ctx context.Context, input ResetPasswordInput) error
 }
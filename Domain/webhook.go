@@ -0,0 +1,182 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Events that can be delivered to webhooks. They share their names with the
+// domain events they come from.
+const (
+	WebhookBlogPublished  = "blog.published"
+	WebhookBlogUpdated    = "blog.updated"
+	WebhookBlogDeleted    = "blog.deleted"
+	WebhookCommentCreated = "comment.created"
+	WebhookUserRegistered = "user.registered"
+	// WebhookPing is only sent on request, to test an endpoint.
+	WebhookPing = "ping"
+)
+
+var WebhookEventTypes = []string{
+	WebhookBlogPublished,
+	WebhookBlogUpdated,
+	WebhookBlogDeleted,
+	WebhookCommentCreated,
+	WebhookUserRegistered,
+}
+
+// WebhookEvents are the domain events behind WebhookEventTypes.
+var WebhookEvents = []EventType{EventBlogPublished, EventBlogUpdated, EventBlogDeleted, EventCommentCreated, EventUserRegistered}
+
+// Webhook is an admin-managed endpoint that receives signed JSON payloads for
+// the event types it subscribes to. It is disabled after too many failed
+// attempts in a row.
+type Webhook struct {
+	ID          string
+	URL         string
+	Secret      string `json:"-"`
+	Events      []string
+	Description string `json:",omitempty"`
+	Active      bool
+	// ConsecutiveFailures counts failed attempts since the last success.
+	ConsecutiveFailures int
+	DisabledAt          *time.Time `json:",omitempty"`
+	DisabledReason      string     `json:",omitempty"`
+	CreatedBy           string
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+func (w *Webhook) Subscribes(eventType string) bool {
+	for _, e := range w.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookInput struct {
+	URL         string
+	Secret      string
+	Events      []string
+	Description string
+}
+
+// WebhookUpdate changes only the fields that are set. Setting Active
+// re-enables a disabled webhook and clears its failures.
+type WebhookUpdate struct {
+	URL         *string
+	Secret      *string
+	Events      []string
+	Description *string
+	Active      *bool
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	DeliveryPending   WebhookDeliveryStatus = "pending"
+	DeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	DeliveryFailed    WebhookDeliveryStatus = "failed" // gave up
+)
+
+// WebhookDelivery is one payload for one webhook, with the outcome of its
+// latest attempt. A replay is a new delivery of the same payload.
+type WebhookDelivery struct {
+	ID             string
+	WebhookID      string
+	EventID        string
+	EventType      string
+	Payload        string `json:",omitempty"`
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	NextAttemptAt  *time.Time `json:",omitempty"`
+	LastStatusCode int        `json:",omitempty"`
+	LastError      string     `json:",omitempty"`
+	LastResponse   string     `json:",omitempty"` // truncated
+	ReplayOf       string     `json:",omitempty"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeliveredAt    *time.Time `json:",omitempty"`
+}
+
+type WebhookDeliveryPage struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+	Page       int               `json:"page"`
+	Limit      int               `json:"limit"`
+	Total      int64             `json:"total"`
+	TotalPages int               `json:"total_pages"`
+	HasNext    bool              `json:"has_next"`
+	HasPrev    bool              `json:"has_prev"`
+}
+
+// WebhookResult is the outcome of sending a payload once. Err is set when
+// there was no HTTP response at all.
+type WebhookResult struct {
+	StatusCode int
+	Body       string
+	Err        error
+}
+
+func (r WebhookResult) OK() bool {
+	return r.Err == nil && r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+type IWebhookRepository interface {
+	Create(ctx context.Context, webhook *Webhook) error
+	FindByID(ctx context.Context, webhookID string) (*Webhook, error)
+	List(ctx context.Context) ([]Webhook, error)
+	Update(ctx context.Context, webhook *Webhook) error
+	Delete(ctx context.Context, webhookID string) error
+	// ListActive returns the enabled webhooks subscribed to eventType.
+	ListActive(ctx context.Context, eventType string) ([]Webhook, error)
+	// RecordSuccess clears the webhook's failures.
+	RecordSuccess(ctx context.Context, webhookID string) error
+	// RecordFailure counts a failed attempt and disables the webhook once it
+	// has failed disableAfter times in a row. It reports whether this call
+	// disabled it.
+	RecordFailure(ctx context.Context, webhookID string, disableAfter int, reason string, at time.Time) (bool, error)
+}
+
+type IWebhookDeliveryRepository interface {
+	CreateMany(ctx context.Context, deliveries []WebhookDelivery) error
+	FindByID(ctx context.Context, deliveryID string) (*WebhookDelivery, error)
+	ListByWebhook(ctx context.Context, webhookID string, status WebhookDeliveryStatus, page, limit int) ([]WebhookDelivery, int64, error)
+	// ClaimDue takes the pending delivery that has been due the longest and
+	// holds it for lease, so no other dispatcher sends it meanwhile. It
+	// returns nil when nothing is due.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*WebhookDelivery, error)
+	// Save stores the outcome of an attempt.
+	Save(ctx context.Context, delivery *WebhookDelivery) error
+	DeleteByWebhook(ctx context.Context, webhookID string) error
+}
+
+// IWebhookSender posts a signed payload to an endpoint.
+type IWebhookSender interface {
+	Send(ctx context.Context, webhook *Webhook, delivery *WebhookDelivery) WebhookResult
+}
+
+type IWebhookUsecase interface {
+	// HandleEvent queues a delivery for every webhook subscribed to the
+	// event. It is meant to be subscribed to the event bus.
+	HandleEvent(ctx context.Context, event Event) error
+	// The rest are for admins only.
+	CreateWebhook(ctx context.Context, input WebhookInput, actor Viewer) (*Webhook, error)
+	ListWebhooks(ctx context.Context, actor Viewer) ([]Webhook, error)
+	GetWebhook(ctx context.Context, webhookID string, actor Viewer) (*Webhook, error)
+	UpdateWebhook(ctx context.Context, webhookID string, update WebhookUpdate, actor Viewer) (*Webhook, error)
+	DeleteWebhook(ctx context.Context, webhookID string, actor Viewer) error
+	// PingWebhook queues a ping delivery, even to a disabled webhook.
+	PingWebhook(ctx context.Context, webhookID string, actor Viewer) (*WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, webhookID string, status WebhookDeliveryStatus, page, limit int, actor Viewer) (*WebhookDeliveryPage, error)
+	GetDelivery(ctx context.Context, deliveryID string, actor Viewer) (*WebhookDelivery, error)
+	// ReplayDelivery queues the payload of a past delivery again.
+	ReplayDelivery(ctx context.Context, deliveryID string, actor Viewer) (*WebhookDelivery, error)
+}
+
+// IWebhookDispatcher sends due deliveries in the background.
+type IWebhookDispatcher interface {
+	Run(ctx context.Context)
+	DispatchDue(ctx context.Context) (int, error)
+}
@@ -2,8 +2,10 @@ package infrastructure
 
 import (
 	domain "blog-api/Domain"
+	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,12 +14,15 @@ const (
 	authHeader = "Authorization"
 )
 
+// AuthMiddleware checks bearer tokens. Access tokens outlive a suspension,
+// so every request also looks the user up and turns suspended users away.
 type AuthMiddleware struct {
-	JWTService domain.IJWTService
+	JWTService     domain.IJWTService
+	UserRepository domain.IUserRepository
 }
 
-func NewAuthMiddleware(JWTService domain.IJWTService) *AuthMiddleware {
-	return &AuthMiddleware{JWTService: JWTService}
+func NewAuthMiddleware(JWTService domain.IJWTService, userRepository domain.IUserRepository) *AuthMiddleware {
+	return &AuthMiddleware{JWTService: JWTService, UserRepository: userRepository}
 }
 
 func (a *AuthMiddleware) Middleware() gin.HandlerFunc {
@@ -41,6 +46,51 @@ func (a *AuthMiddleware) Middleware() gin.HandlerFunc {
 			return
 		}
 
+		user, err := a.UserRepository.GetByID(c.Request.Context(), claims.UserID)
+		if errors.Is(err, domain.ErrUserNotFound) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "unauthorized"})
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": "failed to check account"})
+			return
+		}
+		if user.IsSuspended(time.Now()) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": domain.ErrAccountSuspended.Error()})
+			return
+		}
+
+		c.Set("email", claims.Email)
+		c.Set("role", claims.Role)
+		c.Set("user_id", claims.UserID)
+		c.Next()
+	}
+}
+
+// OptionalMiddleware identifies the caller when a valid bearer token is sent
+// but lets anonymous requests through, so public endpoints can tailor their
+// response to the signed-in user.
+func (a *AuthMiddleware) OptionalMiddleware() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+		authParts := strings.Split(c.GetHeader(authHeader), " ")
+		if len(authParts) != 2 || strings.ToLower(authParts[0]) != "bearer" {
+			c.Next()
+			return
+		}
+
+		claims, err := a.JWTService.ValidateAccessToken(authParts[1])
+		if err != nil {
+			c.Next()
+			return
+		}
+		// Suspended users browse as anonymous readers.
+		user, err := a.UserRepository.GetByID(c.Request.Context(), claims.UserID)
+		if err != nil || user.IsSuspended(time.Now()) {
+			c.Next()
+			return
+		}
+
 		c.Set("email", claims.Email)
 		c.Set("role", claims.Role)
 		c.Set("user_id", claims.UserID)
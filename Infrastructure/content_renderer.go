@@ -0,0 +1,103 @@
+package infrastructure
+
+import (
+	domain "blog-api/Domain"
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+const (
+	excerptLength  = 200 // characters
+	wordsPerMinute = 200
+)
+
+// blockBreak matches the tags that end a line of text, so that stripping
+// them does not glue the words on either side together.
+var blockBreak = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|h[1-6]|li|blockquote|pre|tr|td|th|figcaption)>`)
+
+type contentRenderer struct {
+	markdown  goldmark.Markdown
+	sanitizer *bluemonday.Policy
+	stripper  *bluemonday.Policy
+}
+
+func NewContentRenderer() domain.IContentRenderer {
+	return &contentRenderer{
+		// Raw HTML inside markdown is dropped by goldmark unless WithUnsafe is
+		// set; the sanitizer below is the actual safety net either way.
+		markdown:  goldmark.New(goldmark.WithExtensions(extension.GFM)),
+		sanitizer: bluemonday.UGCPolicy(),
+		stripper:  bluemonday.StrictPolicy(),
+	}
+}
+
+// Render converts source to HTML according to format and sanitizes the result,
+// removing scripts, event handlers and javascript: URLs whatever the format.
+func (r *contentRenderer) Render(format domain.ContentFormat, source string) (*domain.RenderedContent, error) {
+	var unsafeHTML string
+
+	switch format {
+	case domain.ContentFormatMarkdown:
+		var buf bytes.Buffer
+		if err := r.markdown.Convert([]byte(source), &buf); err != nil {
+			return nil, fmt.Errorf("failed to render markdown: %w", err)
+		}
+		unsafeHTML = buf.String()
+	case domain.ContentFormatHTML:
+		unsafeHTML = source
+	case domain.ContentFormatPlain, "":
+		unsafeHTML = plainToHTML(source)
+	default:
+		return nil, fmt.Errorf("unsupported content format %q", format)
+	}
+
+	safeHTML := r.sanitizer.Sanitize(unsafeHTML)
+	text := strings.Join(strings.Fields(html.UnescapeString(r.stripper.Sanitize(blockBreak.ReplaceAllString(safeHTML, "$0 ")))), " ")
+	words := len(strings.Fields(text))
+
+	return &domain.RenderedContent{
+		HTML:        safeHTML,
+		Text:        text,
+		Excerpt:     excerpt(text, excerptLength),
+		WordCount:   words,
+		ReadingTime: max(1, (words+wordsPerMinute-1)/wordsPerMinute),
+	}, nil
+}
+
+// plainToHTML escapes text and turns blank-line separated blocks into
+// paragraphs, keeping single line breaks.
+func plainToHTML(text string) string {
+	var b strings.Builder
+	for _, para := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		para = strings.TrimSpace(para)
+		if para == "" {
+			continue
+		}
+		b.WriteString("<p>")
+		b.WriteString(strings.ReplaceAll(html.EscapeString(para), "\n", "<br>"))
+		b.WriteString("</p>\n")
+	}
+	return b.String()
+}
+
+// excerpt shortens text to at most limit characters, cutting at a word
+// boundary and marking the cut with an ellipsis.
+func excerpt(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	runes := []rune(text)[:limit]
+	cut := string(runes)
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:") + "…"
+}
@@ -0,0 +1,121 @@
+package infrastructure
+
+import (
+	domain "blog-api/Domain"
+	"strings"
+	"testing"
+)
+
+func TestContentRendererSanitizes(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      domain.ContentFormat
+		source      string
+		wantHTML    string
+		wantExcerpt string
+	}{
+		{
+			name:        "html script",
+			format:      domain.ContentFormatHTML,
+			source:      `<p>Hi<script>alert(1)</script> there</p>`,
+			wantHTML:    `<p>Hi there</p>`,
+			wantExcerpt: "Hi there",
+		},
+		{
+			name:        "html event handlers",
+			format:      domain.ContentFormatHTML,
+			source:      `<p onclick="alert(1)" onmouseover="x()">Hi</p><img src="x.png" onerror="alert(1)" alt="pic">`,
+			wantHTML:    `<p>Hi</p><img src="x.png" alt="pic">`,
+			wantExcerpt: "Hi",
+		},
+		{
+			name:        "html javascript link",
+			format:      domain.ContentFormatHTML,
+			source:      `<a href="javascript:alert(1)">click</a> <a href="JaVaScRiPt:alert(1)">me</a> <a href="https://example.com">ok</a>`,
+			wantHTML:    `click me <a href="https://example.com" rel="nofollow">ok</a>`,
+			wantExcerpt: "click me ok",
+		},
+		{
+			name:        "html data urls",
+			format:      domain.ContentFormatHTML,
+			source:      `<img src="data:image/svg+xml;base64,PHN2Zz4=" alt="d"><a href="data:text/html,<script>alert(1)</script>">d</a>`,
+			wantHTML:    `<img alt="d">d`,
+			wantExcerpt: "d",
+		},
+		{
+			name:        "html frames and styles",
+			format:      domain.ContentFormatHTML,
+			source:      `<iframe src="https://example.com"></iframe><style>p{display:none}</style><p>x</p>`,
+			wantHTML:    `<p>x</p>`,
+			wantExcerpt: "x",
+		},
+		{
+			name:        "html line breaks separate words",
+			format:      domain.ContentFormatHTML,
+			source:      `<h2>one</h2><p>two<br>three</p><ul><li>four</li><li><em>fi</em>ve</li></ul>`,
+			wantHTML:    `<h2>one</h2><p>two<br>three</p><ul><li>four</li><li><em>fi</em>ve</li></ul>`,
+			wantExcerpt: "one two three four five",
+		},
+		{
+			name:        "markdown inline script",
+			format:      domain.ContentFormatMarkdown,
+			source:      "# Title\n\nHello <script>alert(1)</script> **bold**",
+			wantHTML:    "<h1>Title</h1>\n<p>Hello alert(1) <strong>bold</strong></p>\n",
+			wantExcerpt: "Title Hello alert(1) bold",
+		},
+		{
+			name:        "markdown raw html block",
+			format:      domain.ContentFormatMarkdown,
+			source:      "<div onclick=\"alert(1)\">raw</div>\n\ntext\n\n<img src=x onerror=alert(1)>",
+			wantHTML:    "\n<p>text</p>\n\n",
+			wantExcerpt: "text",
+		},
+		{
+			name:        "markdown javascript and data urls",
+			format:      domain.ContentFormatMarkdown,
+			source:      "[click](javascript:alert(1)) ![img](data:image/png;base64,AAAA) [ok](https://example.com)",
+			wantHTML:    "<p>click <img alt=\"img\"> <a href=\"https://example.com\" rel=\"nofollow\">ok</a></p>\n",
+			wantExcerpt: "click ok",
+		},
+		{
+			name:        "plain text is escaped",
+			format:      domain.ContentFormatPlain,
+			source:      "a <script>b</script>\nc\n\nd",
+			wantHTML:    "<p>a &lt;script&gt;b&lt;/script&gt;<br>c</p>\n<p>d</p>\n",
+			wantExcerpt: "a <script>b</script> c d",
+		},
+	}
+	r := NewContentRenderer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := r.Render(tt.format, tt.source)
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			if rendered.HTML != tt.wantHTML {
+				t.Errorf("HTML = %q, want %q", rendered.HTML, tt.wantHTML)
+			}
+			if rendered.Excerpt != tt.wantExcerpt {
+				t.Errorf("excerpt = %q, want %q", rendered.Excerpt, tt.wantExcerpt)
+			}
+		})
+	}
+}
+
+func TestContentRendererSummary(t *testing.T) {
+	r := NewContentRenderer()
+	rendered, err := r.Render(domain.ContentFormatMarkdown, strings.Repeat("word ", 450))
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if rendered.WordCount != 450 || rendered.ReadingTime != 3 {
+		t.Errorf("%d words, %d minutes, want 450 and 3", rendered.WordCount, rendered.ReadingTime)
+	}
+	if !strings.HasSuffix(rendered.Excerpt, "word…") || len([]rune(rendered.Excerpt)) > excerptLength+1 {
+		t.Errorf("excerpt = %q, want at most %d characters cut at a word", rendered.Excerpt, excerptLength)
+	}
+
+	if _, err := r.Render("rtf", "x"); err == nil {
+		t.Error("Render of an unknown format succeeded")
+	}
+}
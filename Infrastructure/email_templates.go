@@ -0,0 +1,94 @@
+package infrastructure
+
+import (
+	domain "blog-api/Domain"
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Each email is a pair of templates: <name>.html for the HTML body and
+// <name>.txt for the plain-text body, which also defines "subject".
+//
+//go:embed templates/*.html templates/*.txt
+var templateFiles embed.FS
+
+type verificationEmailData struct {
+	OTP string
+}
+
+type passwordResetEmailData struct {
+	ResetLink string
+}
+
+// emailSamples lists the emails there are templates for, with the data
+// their previews are rendered with.
+var emailSamples = map[string]any{
+	"verification":   verificationEmailData{OTP: "123456"},
+	"password_reset": passwordResetEmailData{ResetLink: resetLink("sample-reset-token")},
+}
+
+type emailTemplate struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+type EmailTemplates struct {
+	templates map[string]emailTemplate
+}
+
+// NewEmailTemplates parses the embedded templates. A broken template is a
+// programming error, so it panics.
+func NewEmailTemplates() domain.IEmailTemplates {
+	templates := make(map[string]emailTemplate, len(emailSamples))
+	for name := range emailSamples {
+		templates[name] = emailTemplate{
+			html: htmltemplate.Must(htmltemplate.ParseFS(templateFiles, "templates/"+name+".html")),
+			text: texttemplate.Must(texttemplate.ParseFS(templateFiles, "templates/"+name+".txt")),
+		}
+	}
+	return &EmailTemplates{templates: templates}
+}
+
+func (t *EmailTemplates) Names() []string {
+	names := make([]string, 0, len(t.templates))
+	for name := range t.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (t *EmailTemplates) Render(name string, data any) (*domain.EmailMessage, error) {
+	tmpl, ok := t.templates[name]
+	if !ok {
+		return nil, domain.ErrEmailTemplateNotFound
+	}
+	var subject, html, text bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, fmt.Errorf("failed to render %s subject: %w", name, err)
+	}
+	if err := tmpl.text.Execute(&text, data); err != nil {
+		return nil, fmt.Errorf("failed to render %s text: %w", name, err)
+	}
+	if err := tmpl.html.Execute(&html, data); err != nil {
+		return nil, fmt.Errorf("failed to render %s html: %w", name, err)
+	}
+	return &domain.EmailMessage{
+		Subject: strings.TrimSpace(subject.String()),
+		HTML:    html.String(),
+		Text:    text.String(),
+	}, nil
+}
+
+func (t *EmailTemplates) Preview(name string) (*domain.EmailMessage, error) {
+	sample, ok := emailSamples[name]
+	if !ok {
+		return nil, domain.ErrEmailTemplateNotFound
+	}
+	return t.Render(name, sample)
+}
@@ -0,0 +1,145 @@
+package infrastructure
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/gomail.v2"
+)
+
+// EmailTransportConfig holds the settings of every transport; each reads
+// the ones it needs.
+type EmailTransportConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// Security is "tls" (implicit TLS), "starttls" or "none". Empty means
+	// tls on port 465 and starttls elsewhere.
+	Security string
+	// PoolSize is how many SMTP connections are kept open.
+	PoolSize int
+	// Dir is where the file transport writes .eml files.
+	Dir string
+}
+
+type EmailTransportFactory func(config EmailTransportConfig) (domain.IEmailTransport, error)
+
+var (
+	emailTransportsMu sync.RWMutex
+	emailTransports   = map[string]EmailTransportFactory{
+		"smtp": NewSMTPTransport,
+		"file": NewFileTransport,
+		"log":  NewLogTransport,
+	}
+)
+
+// RegisterEmailTransport makes a transport selectable by name through
+// EMAIL_TRANSPORT.
+func RegisterEmailTransport(name string, factory EmailTransportFactory) {
+	emailTransportsMu.Lock()
+	defer emailTransportsMu.Unlock()
+	emailTransports[name] = factory
+}
+
+// NewEmailTransport builds the transport registered under name.
+func NewEmailTransport(name string, config EmailTransportConfig) (domain.IEmailTransport, error) {
+	emailTransportsMu.RLock()
+	factory, ok := emailTransports[name]
+	names := make([]string, 0, len(emailTransports))
+	for known := range emailTransports {
+		names = append(names, known)
+	}
+	emailTransportsMu.RUnlock()
+	if !ok {
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown email transport %q, expected one of %s", name, strings.Join(names, ", "))
+	}
+	return factory(config)
+}
+
+// writeMIME writes message as a multipart/alternative MIME message.
+func writeMIME(w io.Writer, message *domain.EmailMessage) error {
+	m := gomail.NewMessage()
+	m.SetHeader("From", message.From)
+	m.SetHeader("To", message.To...)
+	m.SetHeader("Subject", message.Subject)
+	m.SetBody("text/plain", message.Text)
+	m.AddAlternative("text/html", message.HTML)
+	_, err := m.WriteTo(w)
+	return err
+}
+
+// FileTransport writes each email to its own .eml file, which mail clients
+// open as is.
+type FileTransport struct {
+	dir string
+}
+
+func NewFileTransport(config EmailTransportConfig) (domain.IEmailTransport, error) {
+	dir := config.Dir
+	if dir == "" {
+		dir = "mail"
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %w", err)
+	}
+	log.Printf("email: writing emails to %s instead of sending them", dir)
+	return &FileTransport{dir: dir}, nil
+}
+
+func (t *FileTransport) Send(ctx context.Context, message *domain.EmailMessage) error {
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	name := time.Now().UTC().Format("20060102T150405.000000000") + "-" + hex.EncodeToString(suffix) + ".eml"
+
+	// Write to a temporary name first so nothing picks up half an email.
+	tmp, err := os.CreateTemp(t.dir, ".email-*")
+	if err != nil {
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := writeMIME(tmp, message); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(t.dir, name)); err != nil {
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+	return nil
+}
+
+func (t *FileTransport) Close() error {
+	return nil
+}
+
+// LogTransport writes the plain-text version of each email to the log.
+type LogTransport struct{}
+
+func NewLogTransport(config EmailTransportConfig) (domain.IEmailTransport, error) {
+	log.Printf("email: logging emails instead of sending them")
+	return &LogTransport{}, nil
+}
+
+func (t *LogTransport) Send(ctx context.Context, message *domain.EmailMessage) error {
+	log.Printf("email to %s: %s\n%s", strings.Join(message.To, ", "), message.Subject, message.Text)
+	return nil
+}
+
+func (t *LogTransport) Close() error {
+	return nil
+}
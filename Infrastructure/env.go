@@ -3,21 +3,47 @@ package infrastructure
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type EnvStruct struct {
-	MONGODB_URI    string
-	DB_NAME        string
-	JWT_SECRET     string
-	PORT           string
-	EMAIL_FROM     string
-	EMAIL_PORT     string
-	EMAIL_HOST     string
-	EMAIL_USERNAME string
-	EMAIL_PASSWORD string
-	API_Key        string
+	MONGODB_URI         string
+	DB_NAME             string
+	JWT_SECRET          string
+	PORT                string
+	EMAIL_FROM          string
+	EMAIL_PORT          string
+	EMAIL_HOST          string
+	EMAIL_USERNAME      string
+	EMAIL_PASSWORD      string
+	EMAIL_TRANSPORT     string
+	EMAIL_SECURITY      string
+	EMAIL_POOL_SIZE     string
+	EMAIL_DIR           string
+	API_Key             string
+	PUBLISH_INTERVAL    string
+	SITE_URL            string
+	SITE_NAME           string
+	FEED_ITEMS          string
+	FEED_MAX_ITEMS      string
+	COMMENT_MAX_DEPTH   string
+	COMMENT_EDIT_WINDOW string
+	REACTION_TYPES      string
+
+	COUNTER_RECONCILE_INTERVAL string
+	VIEW_WINDOW                string
+	VIEW_FLUSH_INTERVAL        string
+	TRENDING_INTERVAL          string
+	STREAM_REPLAY_BUFFER       string
+	WEBHOOK_POLL_INTERVAL      string
+	WEBHOOK_MAX_ATTEMPTS       string
+	WEBHOOK_DISABLE_AFTER      string
+	OUTBOX_POLL_INTERVAL       string
+	OUTBOX_MAX_ATTEMPTS        string
 }
 
 var Env EnvStruct
@@ -29,19 +55,91 @@ func LoadEnv() {
 	}
 
 	Env = EnvStruct{
-		MONGODB_URI:    os.Getenv("MONGODB_URI"),
-		DB_NAME:        os.Getenv("DB_NAME"),
-		JWT_SECRET:     os.Getenv("JWT_SECRET"),
-		PORT:           os.Getenv("PORT"),
-		EMAIL_FROM:     os.Getenv("EMAIL_FROM"),
-		EMAIL_HOST:     os.Getenv("EMAIL_HOST"),
-		EMAIL_PORT:     os.Getenv("EMAIL_PORT"),
-		EMAIL_USERNAME: os.Getenv("EMAIL_USERNAME"),
-		EMAIL_PASSWORD: os.Getenv("EMAIL_PASSWORD"),
-		API_Key:        os.Getenv("API_Key"),
+		MONGODB_URI:         os.Getenv("MONGODB_URI"),
+		DB_NAME:             os.Getenv("DB_NAME"),
+		JWT_SECRET:          os.Getenv("JWT_SECRET"),
+		PORT:                os.Getenv("PORT"),
+		EMAIL_FROM:          os.Getenv("EMAIL_FROM"),
+		EMAIL_HOST:          os.Getenv("EMAIL_HOST"),
+		EMAIL_PORT:          os.Getenv("EMAIL_PORT"),
+		EMAIL_USERNAME:      os.Getenv("EMAIL_USERNAME"),
+		EMAIL_PASSWORD:      os.Getenv("EMAIL_PASSWORD"),
+		EMAIL_TRANSPORT:     os.Getenv("EMAIL_TRANSPORT"),
+		EMAIL_SECURITY:      os.Getenv("EMAIL_SECURITY"),
+		EMAIL_POOL_SIZE:     os.Getenv("EMAIL_POOL_SIZE"),
+		EMAIL_DIR:           os.Getenv("EMAIL_DIR"),
+		API_Key:             os.Getenv("API_Key"),
+		PUBLISH_INTERVAL:    os.Getenv("PUBLISH_INTERVAL"),
+		SITE_URL:            os.Getenv("SITE_URL"),
+		SITE_NAME:           os.Getenv("SITE_NAME"),
+		FEED_ITEMS:          os.Getenv("FEED_ITEMS"),
+		FEED_MAX_ITEMS:      os.Getenv("FEED_MAX_ITEMS"),
+		COMMENT_MAX_DEPTH:   os.Getenv("COMMENT_MAX_DEPTH"),
+		COMMENT_EDIT_WINDOW: os.Getenv("COMMENT_EDIT_WINDOW"),
+		REACTION_TYPES:      os.Getenv("REACTION_TYPES"),
+
+		COUNTER_RECONCILE_INTERVAL: os.Getenv("COUNTER_RECONCILE_INTERVAL"),
+		VIEW_WINDOW:                os.Getenv("VIEW_WINDOW"),
+		VIEW_FLUSH_INTERVAL:        os.Getenv("VIEW_FLUSH_INTERVAL"),
+		TRENDING_INTERVAL:          os.Getenv("TRENDING_INTERVAL"),
+		STREAM_REPLAY_BUFFER:       os.Getenv("STREAM_REPLAY_BUFFER"),
+		WEBHOOK_POLL_INTERVAL:      os.Getenv("WEBHOOK_POLL_INTERVAL"),
+		WEBHOOK_MAX_ATTEMPTS:       os.Getenv("WEBHOOK_MAX_ATTEMPTS"),
+		WEBHOOK_DISABLE_AFTER:      os.Getenv("WEBHOOK_DISABLE_AFTER"),
+		OUTBOX_POLL_INTERVAL:       os.Getenv("OUTBOX_POLL_INTERVAL"),
+		OUTBOX_MAX_ATTEMPTS:        os.Getenv("OUTBOX_MAX_ATTEMPTS"),
+	}
+
+	if Env.SITE_URL == "" {
+		port := Env.PORT
+		if port == "" {
+			port = "8080"
+		}
+		Env.SITE_URL = "http://localhost:" + port
+	}
+	if Env.SITE_NAME == "" {
+		Env.SITE_NAME = "Blog"
 	}
 
 	if Env.MONGODB_URI == "" || Env.JWT_SECRET == "" || Env.DB_NAME == "" {
 		log.Fatal("Missing required environment variables")
 	}
 }
+
+func ParseDuration(value string, defaultDuration time.Duration) time.Duration {
+	if value == "" {
+		return defaultDuration
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("Warning: invalid duration '%s', defaulting to %s\n", value, defaultDuration)
+		return defaultDuration
+	}
+	return d
+}
+
+func ParsePositiveInt(value string, defaultValue int) int {
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		log.Printf("Warning: invalid number '%s', defaulting to %d\n", value, defaultValue)
+		return defaultValue
+	}
+	return n
+}
+
+// ParseList splits a comma-separated value, dropping blank entries.
+func ParseList(value string, defaultValue []string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	if len(list) == 0 {
+		return defaultValue
+	}
+	return list
+}
@@ -0,0 +1,66 @@
+package infrastructure
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+type EventBus struct {
+	mu       sync.RWMutex
+	handlers []domain.EventHandler
+}
+
+func NewEventBus() domain.IEventBus {
+	return &EventBus{}
+}
+
+func (b *EventBus) Subscribe(handler domain.EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish runs the handlers one after another. A handler that fails or
+// panics does not stop the others; its error is returned with theirs.
+func (b *EventBus) Publish(ctx context.Context, event domain.Event) error {
+	if event.ID == "" {
+		event.ID = newEventID()
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, handler := range handlers {
+		if err := dispatch(ctx, handler, event); err != nil {
+			log.Printf("event bus: handler failed on %s %s: %v", event.Type, event.ID, err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func dispatch(ctx context.Context, handler domain.EventHandler, event domain.Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+	return handler(ctx, event)
+}
+
+func newEventID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
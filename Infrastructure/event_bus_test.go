@@ -0,0 +1,68 @@
+package infrastructure
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestEventBusPublishReturnsHandlerErrors(t *testing.T) {
+	queueDown := errors.New("outbox unavailable")
+	tests := []struct {
+		name     string
+		handlers []domain.EventHandler
+		wantErr  error
+		wantRuns int
+	}{
+		{
+			name:     "all succeed",
+			handlers: []domain.EventHandler{nil, nil},
+			wantRuns: 2,
+		},
+		{
+			name: "failure does not stop the others",
+			handlers: []domain.EventHandler{
+				func(context.Context, domain.Event) error { return queueDown },
+				nil,
+			},
+			wantErr:  queueDown,
+			wantRuns: 2,
+		},
+		{
+			name: "panic becomes an error",
+			handlers: []domain.EventHandler{
+				func(context.Context, domain.Event) error { panic("boom") },
+				nil,
+			},
+			wantErr:  errors.New("handler panicked: boom"),
+			wantRuns: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := NewEventBus()
+			runs := 0
+			for _, handler := range tt.handlers {
+				bus.Subscribe(func(ctx context.Context, event domain.Event) error {
+					runs++
+					if handler == nil {
+						return nil
+					}
+					return handler(ctx, event)
+				})
+			}
+
+			err := bus.Publish(context.Background(), domain.Event{Type: domain.EventUserFollowed})
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Errorf("Publish error = %v, want nil", err)
+			case tt.wantErr != nil && (err == nil || !errors.Is(err, tt.wantErr) && err.Error() != tt.wantErr.Error()):
+				t.Errorf("Publish error = %v, want %v", err, tt.wantErr)
+			}
+			if runs != tt.wantRuns {
+				t.Errorf("handlers run = %d, want %d", runs, tt.wantRuns)
+			}
+		})
+	}
+}
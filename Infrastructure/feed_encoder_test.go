@@ -0,0 +1,140 @@
+package infrastructure
+
+import (
+	domain "blog-api/Domain"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleFeed() *domain.Feed {
+	published := time.Date(2024, 2, 10, 8, 0, 0, 0, time.UTC)
+	return &domain.Feed{
+		Title:       "Field Notes - #go",
+		Description: "Latest posts tagged go on Field Notes",
+		Link:        "https://blog.example.org/blogs?tags=go",
+		SelfLink:    "https://blog.example.org/feeds/tags/go",
+		Updated:     published.Add(48 * time.Hour),
+		Items: []domain.FeedItem{
+			{
+				ID: "https://blog.example.org/blogs/b1", Title: "Channels & you", Link: "https://blog.example.org/blogs/by-slug/channels",
+				Summary: "A tour", ContentHTML: `<p>Use <code>select</code> & friends</p>`, Author: "margaret",
+				Tags: []string{"go", "concurrency"}, Published: published, Updated: published.Add(48 * time.Hour),
+			},
+			{
+				ID: "https://blog.example.org/blogs/b2", Title: "Unsigned", Link: "https://blog.example.org/blogs/b2",
+				Published: published, Updated: published,
+			},
+		},
+	}
+}
+
+func TestEncodeRSS(t *testing.T) {
+	body, contentType, err := NewFeedEncoder().Encode(sampleFeed(), domain.FeedFormatRSS)
+	if err != nil || !strings.HasPrefix(contentType, "application/rss+xml") {
+		t.Fatalf("Encode = %q, %v", contentType, err)
+	}
+	var doc struct {
+		Channel struct {
+			LastBuildDate string `xml:"lastBuildDate"`
+			Items         []struct {
+				GUID       string   `xml:"guid"`
+				Content    string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+				Creator    string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+				Categories []string `xml:"category"`
+				PubDate    string   `xml:"pubDate"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("feed does not parse: %v\n%s", err, body)
+	}
+	if doc.Channel.LastBuildDate != "Mon, 12 Feb 2024 08:00:00 +0000" || len(doc.Channel.Items) != 2 {
+		t.Fatalf("channel built %q with %d items", doc.Channel.LastBuildDate, len(doc.Channel.Items))
+	}
+	item := doc.Channel.Items[0]
+	if item.GUID != "https://blog.example.org/blogs/b1" || item.Creator != "margaret" || item.PubDate != "Sat, 10 Feb 2024 08:00:00 +0000" {
+		t.Errorf("item %+v", item)
+	}
+	if item.Content != `<p>Use <code>select</code> & friends</p>` || len(item.Categories) != 2 {
+		t.Errorf("item content %q, categories %v", item.Content, item.Categories)
+	}
+}
+
+func TestEncodeAtom(t *testing.T) {
+	body, contentType, err := NewFeedEncoder().Encode(sampleFeed(), domain.FeedFormatAtom)
+	if err != nil || !strings.HasPrefix(contentType, "application/atom+xml") {
+		t.Fatalf("Encode = %q, %v", contentType, err)
+	}
+	var doc struct {
+		ID      string `xml:"id"`
+		Updated string `xml:"updated"`
+		Links   []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Entries []struct {
+			Updated string `xml:"updated"`
+			Author  string `xml:"author>name"`
+			Content *struct {
+				Type  string `xml:"type,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"content"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("feed does not parse: %v\n%s", err, body)
+	}
+	if doc.ID != "https://blog.example.org/feeds/tags/go/atom" || doc.Updated != "2024-02-12T08:00:00Z" {
+		t.Errorf("feed id %q, updated %q", doc.ID, doc.Updated)
+	}
+	if len(doc.Links) != 2 || doc.Links[0].Rel != "self" || doc.Links[0].Href != doc.ID {
+		t.Errorf("links %+v, want self first", doc.Links)
+	}
+	if len(doc.Entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(doc.Entries))
+	}
+	if doc.Entries[0].Updated != "2024-02-12T08:00:00Z" || doc.Entries[0].Content == nil || doc.Entries[0].Content.Type != "html" {
+		t.Errorf("first entry %+v", doc.Entries[0])
+	}
+	// Atom needs an author on every entry; unnamed ones take the feed title.
+	if doc.Entries[1].Author != "Field Notes - #go" || doc.Entries[1].Content != nil {
+		t.Errorf("unsigned entry author %q, content %+v", doc.Entries[1].Author, doc.Entries[1].Content)
+	}
+}
+
+func TestEncodeJSONFeed(t *testing.T) {
+	body, contentType, err := NewFeedEncoder().Encode(sampleFeed(), domain.FeedFormatJSON)
+	if err != nil || !strings.HasPrefix(contentType, "application/feed+json") {
+		t.Fatalf("Encode = %q, %v", contentType, err)
+	}
+	if !strings.Contains(string(body), `<code>select</code> & friends`) {
+		t.Errorf("content_html is escaped:\n%s", body)
+	}
+	var doc jsonFeedDocument
+	if err := json.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("feed does not parse: %v", err)
+	}
+	if doc.FeedURL != "https://blog.example.org/feeds/tags/go/json" || len(doc.Items) != 2 {
+		t.Fatalf("feed url %q with %d items", doc.FeedURL, len(doc.Items))
+	}
+	if len(doc.Items[0].Authors) != 1 || doc.Items[1].Authors != nil || doc.Items[0].DateModified != "2024-02-12T08:00:00Z" {
+		t.Errorf("items %+v", doc.Items)
+	}
+}
+
+func TestEncodeEmptyJSONFeed(t *testing.T) {
+	feed := &domain.Feed{Title: "Field Notes", SelfLink: "https://blog.example.org/feeds"}
+	body, _, err := NewFeedEncoder().Encode(feed, domain.FeedFormatJSON)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if !strings.Contains(string(body), `"items": []`) {
+		t.Errorf("empty feed has no items array:\n%s", body)
+	}
+	if _, _, err := NewFeedEncoder().Encode(feed, "yaml"); err == nil {
+		t.Error("encoded an unknown format")
+	}
+}
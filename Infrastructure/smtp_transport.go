@@ -0,0 +1,211 @@
+package infrastructure
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// smtpTimeout bounds dialing and each send.
+	smtpTimeout = 30 * time.Second
+	// smtpIdleTimeout is how long an unused connection is kept. Servers
+	// tend to hang up on idle clients after a minute or so.
+	smtpIdleTimeout = 30 * time.Second
+)
+
+type smtpConn struct {
+	conn     net.Conn
+	client   *smtp.Client
+	lastUsed time.Time
+}
+
+func (c *smtpConn) close() {
+	c.client.Close()
+}
+
+// SMTPTransport sends through an SMTP server over implicit TLS or
+// STARTTLS, keeping up to PoolSize connections open between emails.
+type SMTPTransport struct {
+	host     string
+	addr     string
+	username string
+	password string
+	security string
+	slots    chan struct{} // one per connection in use
+	idle     chan *smtpConn
+	closed   atomic.Bool
+}
+
+func NewSMTPTransport(config EmailTransportConfig) (domain.IEmailTransport, error) {
+	if config.Host == "" {
+		return nil, errors.New("EMAIL_HOST is required by the smtp email transport")
+	}
+	security := config.Security
+	if security == "" {
+		security = "starttls"
+		if config.Port == 465 {
+			security = "tls"
+		}
+	}
+	if security != "tls" && security != "starttls" && security != "none" {
+		return nil, fmt.Errorf("unknown email security %q, expected tls, starttls or none", security)
+	}
+	poolSize := max(config.PoolSize, 1)
+	return &SMTPTransport{
+		host:     config.Host,
+		addr:     net.JoinHostPort(config.Host, strconv.Itoa(config.Port)),
+		username: config.Username,
+		password: config.Password,
+		security: security,
+		slots:    make(chan struct{}, poolSize),
+		idle:     make(chan *smtpConn, poolSize),
+	}, nil
+}
+
+func (t *SMTPTransport) Send(ctx context.Context, message *domain.EmailMessage) error {
+	select {
+	case t.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-t.slots }()
+
+	c, err := t.get(ctx)
+	if err != nil {
+		return err
+	}
+	if err := t.deliver(ctx, c, message); err != nil {
+		c.close()
+		return err
+	}
+	t.put(c)
+	return nil
+}
+
+// get takes an idle connection that still answers, or dials a new one.
+func (t *SMTPTransport) get(ctx context.Context) (*smtpConn, error) {
+	for {
+		select {
+		case c := <-t.idle:
+			if time.Since(c.lastUsed) > smtpIdleTimeout {
+				c.close()
+				continue
+			}
+			c.conn.SetDeadline(time.Now().Add(smtpTimeout))
+			if err := c.client.Noop(); err != nil {
+				c.close()
+				continue
+			}
+			return c, nil
+		default:
+			return t.dial(ctx)
+		}
+	}
+}
+
+func (t *SMTPTransport) put(c *smtpConn) {
+	c.lastUsed = time.Now()
+	if t.closed.Load() {
+		c.client.Quit()
+		return
+	}
+	select {
+	case t.idle <- c:
+	default:
+		c.client.Quit()
+	}
+}
+
+func (t *SMTPTransport) dial(ctx context.Context) (*smtpConn, error) {
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+	tlsConfig := &tls.Config{ServerName: t.host}
+
+	var conn net.Conn
+	var err error
+	if t.security == "tls" {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", t.addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", t.addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", t.addr, err)
+	}
+	conn.SetDeadline(time.Now().Add(smtpTimeout))
+
+	client, err := smtp.NewClient(conn, t.host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to greet %s: %w", t.addr, err)
+	}
+	c := &smtpConn{conn: conn, client: client}
+	if t.security == "starttls" {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			c.close()
+			return nil, fmt.Errorf("%s does not support STARTTLS", t.addr)
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			c.close()
+			return nil, fmt.Errorf("failed to start TLS with %s: %w", t.addr, err)
+		}
+	}
+	if t.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", t.username, t.password, t.host)); err != nil {
+			c.close()
+			return nil, fmt.Errorf("failed to authenticate with %s: %w", t.addr, err)
+		}
+	}
+	return c, nil
+}
+
+func (t *SMTPTransport) deliver(ctx context.Context, c *smtpConn, message *domain.EmailMessage) error {
+	deadline := time.Now().Add(smtpTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	c.conn.SetDeadline(deadline)
+
+	from, err := mail.ParseAddress(message.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender %q: %w", message.From, err)
+	}
+	if err := c.client.Mail(from.Address); err != nil {
+		return err
+	}
+	for _, to := range message.To {
+		if err := c.client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.client.Data()
+	if err != nil {
+		return err
+	}
+	if err := writeMIME(w, message); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// Close hangs up the idle connections, and the ones in use once their
+// email is sent.
+func (t *SMTPTransport) Close() error {
+	t.closed.Store(true)
+	for {
+		select {
+		case c := <-t.idle:
+			c.client.Quit()
+		default:
+			return nil
+		}
+	}
+}
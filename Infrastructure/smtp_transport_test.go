@@ -0,0 +1,183 @@
+package infrastructure
+
+import (
+	domain "blog-api/Domain"
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// smtpServer is a plain-text SMTP server that accepts every email and
+// counts connections and messages.
+type smtpServer struct {
+	listener net.Listener
+
+	mu          sync.Mutex
+	conns       []net.Conn
+	connections int
+	messages    int
+}
+
+func newSMTPServer(t *testing.T) *smtpServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpServer{listener: listener}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.conns = append(s.conns, conn)
+			s.connections++
+			s.mu.Unlock()
+			go s.serve(conn)
+		}
+	}()
+	t.Cleanup(func() {
+		listener.Close()
+		s.hangUp()
+	})
+	return s
+}
+
+func (s *smtpServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(lines ...string) {
+		for _, line := range lines {
+			conn.Write([]byte(line + "\r\n"))
+		}
+	}
+	reply("220 test ready")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		switch strings.ToUpper(strings.Fields(line + " x")[0]) {
+		case "EHLO":
+			reply("250-test", "250 8BITMIME")
+		case "DATA":
+			reply("354 go ahead")
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+			}
+			s.mu.Lock()
+			s.messages++
+			s.mu.Unlock()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+// hangUp drops every open connection, like a server timing out idle clients.
+func (s *smtpServer) hangUp() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+	s.conns = nil
+}
+
+func (s *smtpServer) counts() (connections, messages int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connections, s.messages
+}
+
+func newTestSMTPTransport(t *testing.T, s *smtpServer) domain.IEmailTransport {
+	host, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	transport, err := NewSMTPTransport(EmailTransportConfig{Host: host, Port: portNumber, Security: "none", PoolSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { transport.Close() })
+	return transport
+}
+
+var testEmail = &domain.EmailMessage{
+	From:    "Blog <noreply@example.com>",
+	To:      []string{"alice@example.com"},
+	Subject: "Hello",
+	Text:    "Hello",
+	HTML:    "<p>Hello</p>",
+}
+
+func TestSMTPTransportReusesConnections(t *testing.T) {
+	s := newSMTPServer(t)
+	transport := newTestSMTPTransport(t, s)
+	for i := 0; i < 3; i++ {
+		if err := transport.Send(context.Background(), testEmail); err != nil {
+			t.Fatalf("send %d: %v", i, err)
+		}
+	}
+	if connections, messages := s.counts(); connections != 1 || messages != 3 {
+		t.Errorf("connections = %d, messages = %d, want 1 and 3", connections, messages)
+	}
+}
+
+func TestSMTPTransportRedialsDroppedConnection(t *testing.T) {
+	s := newSMTPServer(t)
+	transport := newTestSMTPTransport(t, s)
+	if err := transport.Send(context.Background(), testEmail); err != nil {
+		t.Fatalf("first send: %v", err)
+	}
+	s.hangUp()
+	if err := transport.Send(context.Background(), testEmail); err != nil {
+		t.Fatalf("send after hang-up: %v", err)
+	}
+	if connections, messages := s.counts(); connections != 2 || messages != 2 {
+		t.Errorf("connections = %d, messages = %d, want 2 and 2", connections, messages)
+	}
+}
+
+func TestSMTPTransportSecurity(t *testing.T) {
+	tests := []struct {
+		port     int
+		security string
+		want     string
+		wantErr  bool
+	}{
+		{port: 465, want: "tls"},
+		{port: 587, want: "starttls"},
+		{port: 25, security: "none", want: "none"},
+		{port: 465, security: "starttls", want: "starttls"},
+		{port: 25, security: "ssl", wantErr: true},
+	}
+	for _, tt := range tests {
+		transport, err := NewSMTPTransport(EmailTransportConfig{Host: "mail.example.com", Port: tt.port, Security: tt.security})
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("port %d, security %q: want an error", tt.port, tt.security)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("port %d, security %q: %v", tt.port, tt.security, err)
+		}
+		if got := transport.(*SMTPTransport).security; got != tt.want {
+			t.Errorf("port %d, security %q: got %q, want %q", tt.port, tt.security, got, tt.want)
+		}
+	}
+}
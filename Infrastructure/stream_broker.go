@@ -0,0 +1,43 @@
+package infrastructure
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"sync"
+)
+
+// MemoryBroker delivers stream events within this process only. Running
+// several instances needs a shared broker behind domain.IStreamBroker.
+type MemoryBroker struct {
+	mu       sync.RWMutex
+	handlers map[int]func(domain.StreamEvent)
+	next     int
+}
+
+func NewMemoryBroker() domain.IStreamBroker {
+	return &MemoryBroker{handlers: map[int]func(domain.StreamEvent){}}
+}
+
+func (b *MemoryBroker) Publish(ctx context.Context, event domain.StreamEvent) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, handler := range b.handlers {
+		handler(event)
+	}
+	return nil
+}
+
+func (b *MemoryBroker) Subscribe(ctx context.Context, handler func(domain.StreamEvent)) error {
+	b.mu.Lock()
+	id := b.next
+	b.next++
+	b.handlers[id] = handler
+	b.mu.Unlock()
+
+	<-ctx.Done()
+
+	b.mu.Lock()
+	delete(b.handlers, id)
+	b.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,136 @@
+package infrastructure
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// streamClientQueue is how many events a client may fall behind before it
+// is dropped. It reconnects and catches up from the replay buffer.
+const streamClientQueue = 64
+
+type streamClient struct {
+	topics map[string]bool
+	events chan domain.StreamEvent
+}
+
+type StreamHub struct {
+	broker     domain.IStreamBroker
+	bufferSize int
+
+	mu      sync.Mutex
+	buffer  []domain.StreamEvent // oldest first
+	clients map[*streamClient]struct{}
+	closed  bool
+}
+
+// NewStreamHub keeps the last bufferSize events for clients that reconnect.
+func NewStreamHub(broker domain.IStreamBroker, bufferSize int) domain.IStreamHub {
+	return &StreamHub{
+		broker:     broker,
+		bufferSize: bufferSize,
+		clients:    map[*streamClient]struct{}{},
+	}
+}
+
+// Run receives events from the broker until ctx is done, subscribing again
+// when the broker drops the subscription.
+func (h *StreamHub) Run(ctx context.Context) {
+	for {
+		err := h.broker.Subscribe(ctx, h.deliver)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("stream hub: broker subscription ended: %v", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (h *StreamHub) deliver(event domain.StreamEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.buffer) == h.bufferSize {
+		copy(h.buffer, h.buffer[1:])
+		h.buffer = h.buffer[:len(h.buffer)-1]
+	}
+	h.buffer = append(h.buffer, event)
+
+	for client := range h.clients {
+		if !client.topics[event.Topic] {
+			continue
+		}
+		select {
+		case client.events <- event:
+		default:
+			h.drop(client)
+		}
+	}
+}
+
+// Subscribe registers the client and collects its replay under one lock, so
+// no event is missed or sent twice between the two.
+func (h *StreamHub) Subscribe(topics []string, lastEventID string) *domain.StreamSubscription {
+	client := &streamClient{topics: map[string]bool{}, events: make(chan domain.StreamEvent, streamClientQueue)}
+	for _, topic := range topics {
+		client.topics[topic] = true
+	}
+	sub := &domain.StreamSubscription{
+		Events: client.events,
+		Close: func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			h.drop(client)
+		},
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(client.events)
+		sub.Close = func() {}
+		return sub
+	}
+	if lastEventID != "" {
+		sub.Reset = true
+		for i, event := range h.buffer {
+			if event.ID != lastEventID {
+				continue
+			}
+			sub.Reset = false
+			for _, missed := range h.buffer[i+1:] {
+				if client.topics[missed.Topic] {
+					sub.Replay = append(sub.Replay, missed)
+				}
+			}
+			break
+		}
+	}
+	h.clients[client] = struct{}{}
+	return sub
+}
+
+func (h *StreamHub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for client := range h.clients {
+		h.drop(client)
+	}
+}
+
+// drop ends a client's subscription. The caller holds h.mu.
+func (h *StreamHub) drop(client *streamClient) {
+	if _, ok := h.clients[client]; !ok {
+		return
+	}
+	delete(h.clients, client)
+	close(client.events)
+}
@@ -0,0 +1,96 @@
+package infrastructure
+
+import (
+	domain "blog-api/Domain"
+	"slices"
+	"testing"
+)
+
+func TestStreamHubSubscribeReplay(t *testing.T) {
+	blog, other := domain.BlogTopic("b1"), domain.BlogTopic("b2")
+	// The buffer holds 4 events, so e1 has already been pushed out.
+	published := []domain.StreamEvent{
+		{ID: "e1", Topic: blog},
+		{ID: "e2", Topic: blog},
+		{ID: "e3", Topic: other},
+		{ID: "e4", Topic: blog},
+		{ID: "e5", Topic: blog},
+	}
+	tests := []struct {
+		name        string
+		lastEventID string
+		wantReplay  []string
+		wantReset   bool
+	}{
+		{name: "first connection", lastEventID: ""},
+		{name: "missed events of followed topics", lastEventID: "e2", wantReplay: []string{"e4", "e5"}},
+		{name: "missed nothing", lastEventID: "e5"},
+		{name: "last event of another topic", lastEventID: "e3", wantReplay: []string{"e4", "e5"}},
+		{name: "fell out of the buffer", lastEventID: "e1", wantReset: true},
+		{name: "unknown event", lastEventID: "nope", wantReset: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := NewStreamHub(nil, 4).(*StreamHub)
+			for _, event := range published {
+				hub.deliver(event)
+			}
+
+			sub := hub.Subscribe([]string{blog}, tt.lastEventID)
+			defer sub.Close()
+			var replay []string
+			for _, event := range sub.Replay {
+				replay = append(replay, event.ID)
+			}
+			if !slices.Equal(replay, tt.wantReplay) {
+				t.Errorf("replay = %v, want %v", replay, tt.wantReplay)
+			}
+			if sub.Reset != tt.wantReset {
+				t.Errorf("reset = %v, want %v", sub.Reset, tt.wantReset)
+			}
+		})
+	}
+}
+
+func TestStreamHubDelivers(t *testing.T) {
+	hub := NewStreamHub(nil, 4).(*StreamHub)
+	sub := hub.Subscribe([]string{domain.UserTopic("u1")}, "")
+
+	hub.deliver(domain.StreamEvent{ID: "e1", Topic: domain.UserTopic("u2")})
+	hub.deliver(domain.StreamEvent{ID: "e2", Topic: domain.UserTopic("u1")})
+	if event := <-sub.Events; event.ID != "e2" {
+		t.Errorf("got event %s, want e2", event.ID)
+	}
+
+	sub.Close()
+	if _, open := <-sub.Events; open {
+		t.Error("events still open after Close")
+	}
+	sub.Close() // closing twice is harmless
+}
+
+func TestStreamHubDropsSlowClient(t *testing.T) {
+	hub := NewStreamHub(nil, streamClientQueue+1).(*StreamHub)
+	sub := hub.Subscribe([]string{domain.BlogTopic("b1")}, "")
+	for i := 0; i <= streamClientQueue; i++ {
+		hub.deliver(domain.StreamEvent{ID: "e", Topic: domain.BlogTopic("b1")})
+	}
+
+	received := 0
+	for range sub.Events {
+		received++
+	}
+	if received != streamClientQueue {
+		t.Errorf("received %d events before the drop, want %d", received, streamClientQueue)
+	}
+}
+
+func TestStreamHubSubscribeAfterClose(t *testing.T) {
+	hub := NewStreamHub(nil, 4)
+	hub.Close()
+	sub := hub.Subscribe([]string{domain.BlogTopic("b1")}, "")
+	if _, open := <-sub.Events; open {
+		t.Error("subscription after Close is open")
+	}
+	sub.Close()
+}
@@ -0,0 +1,62 @@
+package infrastructure
+
+import (
+	domain "blog-api/Domain"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxWebhookResponse is how much of a response body is kept for the
+// delivery log.
+const maxWebhookResponse = 1024
+
+type WebhookSender struct {
+	client *http.Client
+}
+
+func NewWebhookSender(timeout time.Duration) domain.IWebhookSender {
+	return &WebhookSender{client: &http.Client{Timeout: timeout}}
+}
+
+// Send posts the payload with its signature: X-Webhook-Signature is
+// "sha256=" and the hex HMAC-SHA256, keyed with the webhook's secret, of
+// the X-Webhook-Timestamp value, a dot and the body.
+func (s *WebhookSender) Send(ctx context.Context, webhook *domain.Webhook, delivery *domain.WebhookDelivery) domain.WebhookResult {
+	body := []byte(delivery.Payload)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return domain.WebhookResult{Err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "blog-api-webhooks/1")
+	req.Header.Set("X-Webhook-Event", delivery.EventType)
+	req.Header.Set("X-Webhook-Delivery", delivery.ID)
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+SignWebhook(webhook.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return domain.WebhookResult{Err: err}
+	}
+	defer resp.Body.Close()
+	excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookResponse))
+	return domain.WebhookResult{StatusCode: resp.StatusCode, Body: string(excerpt)}
+}
+
+// SignWebhook is what receivers compute to check X-Webhook-Signature.
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
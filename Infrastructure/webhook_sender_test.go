@@ -0,0 +1,80 @@
+package infrastructure
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhookSenderSignsRequest(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+		io.WriteString(w, "thanks")
+	}))
+	defer server.Close()
+
+	webhook := &domain.Webhook{ID: "w1", URL: server.URL, Secret: "s3cret"}
+	delivery := &domain.WebhookDelivery{ID: "d1", EventType: domain.WebhookBlogPublished, Payload: `{"id":"b1"}`}
+	result := NewWebhookSender(time.Second).Send(context.Background(), webhook, delivery)
+
+	if !result.OK() || result.StatusCode != http.StatusAccepted || result.Body != "thanks" {
+		t.Fatalf("result = %+v, want 202 with the response body", result)
+	}
+	if string(body) != delivery.Payload {
+		t.Errorf("body = %q, want %q", body, delivery.Payload)
+	}
+	if e := got.Header.Get("X-Webhook-Event"); e != domain.WebhookBlogPublished {
+		t.Errorf("X-Webhook-Event = %q", e)
+	}
+	if d := got.Header.Get("X-Webhook-Delivery"); d != "d1" {
+		t.Errorf("X-Webhook-Delivery = %q", d)
+	}
+
+	// Check the signature the way a receiver would.
+	timestamp := got.Header.Get("X-Webhook-Timestamp")
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte(timestamp + "." + string(body)))
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if sig := got.Header.Get("X-Webhook-Signature"); !hmac.Equal([]byte(sig), []byte(want)) {
+		t.Errorf("X-Webhook-Signature = %q, want %q", sig, want)
+	}
+	if sig := SignWebhook("other", timestamp, body); "sha256="+sig == want {
+		t.Error("signature does not depend on the secret")
+	}
+}
+
+func TestWebhookSenderResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		io.WriteString(w, strings.Repeat("x", 2*maxWebhookResponse))
+	}))
+	defer server.Close()
+	sender := NewWebhookSender(time.Second)
+	delivery := &domain.WebhookDelivery{ID: "d1", Payload: "{}"}
+
+	result := sender.Send(context.Background(), &domain.Webhook{URL: server.URL}, delivery)
+	if result.OK() || result.StatusCode != http.StatusInternalServerError || result.Err != nil {
+		t.Errorf("result = %+v, want a failed 500", result)
+	}
+	if len(result.Body) != maxWebhookResponse {
+		t.Errorf("kept %d bytes of the response, want %d", len(result.Body), maxWebhookResponse)
+	}
+
+	server.Close()
+	result = sender.Send(context.Background(), &domain.Webhook{URL: server.URL}, delivery)
+	if result.OK() || result.Err == nil {
+		t.Errorf("result = %+v, want a connection error", result)
+	}
+}
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	domain "blog-api/Domain"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// blogCursor is the position after the last blog of a page: the value of
+// the sort field plus the _id that breaks ties between equal values. It is
+// handed to clients as base64-encoded JSON and should be treated as opaque.
+type blogCursor struct {
+	Sort         domain.BlogSort       `json:"s"`
+	Window       domain.TrendingWindow `json:"w,omitempty"`
+	CreatedAt    *time.Time            `json:"t,omitempty"`
+	PublishedAt  *time.Time            `json:"p,omitempty"`
+	ViewCount    *int                  `json:"v,omitempty"`
+	LikeCount    *int                  `json:"l,omitempty"`
+	CommentCount *int                  `json:"c,omitempty"`
+	Trending     *float64              `json:"tr,omitempty"`
+	ID           string                `json:"id"`
+}
+
+// cursorSortField is the field a keyset-paginated listing is ordered by.
+// window only matters to the trending sort.
+func cursorSortField(sort domain.BlogSort, window domain.TrendingWindow) string {
+	switch sort {
+	case domain.BlogSortPublished:
+		return "published_at"
+	case domain.BlogSortPopular:
+		return "view_count"
+	case domain.BlogSortLikes:
+		return "like_count"
+	case domain.BlogSortComments:
+		return "comment_count"
+	case domain.BlogSortTrending:
+		return "trending." + string(window)
+	default:
+		return "createdAt"
+	}
+}
+
+// cursorSort orders documents the way cursors expect: by the sort field and
+// then by _id, both descending.
+func cursorSort(sort domain.BlogSort, window domain.TrendingWindow) bson.D {
+	return bson.D{{Key: cursorSortField(sort, window), Value: -1}, {Key: "_id", Value: -1}}
+}
+
+func encodeBlogCursor(sort domain.BlogSort, window domain.TrendingWindow, last blogQueryRow) string {
+	c := blogCursor{Sort: sort, ID: last.ID.Hex()}
+	switch cursorSortField(sort, window) {
+	case "view_count":
+		c.ViewCount = &last.ViewCount
+	case "like_count":
+		c.LikeCount = &last.LikeCount
+	case "comment_count":
+		c.CommentCount = &last.CommentCount
+	case "createdAt":
+		c.CreatedAt = &last.CreatedAt
+	case "published_at":
+		c.PublishedAt = last.PublishedAt
+	default: // trending.<window>
+		score := last.Trending[window]
+		c.Window, c.Trending = window, &score
+	}
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// cursorFilter decodes cursor into a filter matching the documents that come
+// after it under sort. An empty cursor matches everything.
+func cursorFilter(sort domain.BlogSort, window domain.TrendingWindow, cursor string) (bson.M, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	invalid := fmt.Errorf("%w: invalid cursor", domain.ErrInvalidInput)
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, invalid
+	}
+	var c blogCursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, invalid
+	}
+	id, err := primitive.ObjectIDFromHex(c.ID)
+	if err != nil {
+		return nil, invalid
+	}
+	if c.Sort != sort || (sort == domain.BlogSortTrending && c.Window != window) {
+		return nil, fmt.Errorf("%w: cursor was issued for a different sort order", domain.ErrInvalidInput)
+	}
+
+	field := cursorSortField(sort, window)
+	var value interface{}
+	switch {
+	case field == "view_count" && c.ViewCount != nil:
+		value = *c.ViewCount
+	case field == "like_count" && c.LikeCount != nil:
+		value = *c.LikeCount
+	case field == "comment_count" && c.CommentCount != nil:
+		value = *c.CommentCount
+	case sort == domain.BlogSortTrending && c.Trending != nil:
+		value = *c.Trending
+	case field == "createdAt" && c.CreatedAt != nil:
+		value = *c.CreatedAt
+	case field == "published_at" && c.PublishedAt != nil:
+		value = *c.PublishedAt
+	default:
+		return nil, invalid
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{field: bson.M{"$lt": value}},
+		bson.M{field: value, "_id": bson.M{"$lt": id}},
+	}}, nil
+}
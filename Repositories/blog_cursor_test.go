@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	domain "blog-api/Domain"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestBlogCursorRoundTrip(t *testing.T) {
+	id := primitive.NewObjectID()
+	created := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	published := time.Date(2024, 6, 2, 8, 0, 0, 0, time.UTC)
+	last := blogQueryRow{blogModel: blogModel{
+		ID:           id,
+		CreatedAt:    created,
+		PublishedAt:  &published,
+		ViewCount:    120,
+		LikeCount:    7,
+		CommentCount: 3,
+		Trending:     map[domain.TrendingWindow]float64{domain.TrendingWeek: 4.5},
+	}}
+
+	tests := []struct {
+		sort   domain.BlogSort
+		window domain.TrendingWindow
+		field  string
+		value  interface{}
+	}{
+		{domain.BlogSortRecent, "", "createdAt", created},
+		{domain.BlogSortPublished, "", "published_at", published},
+		{domain.BlogSortPopular, "", "view_count", 120},
+		{domain.BlogSortLikes, "", "like_count", 7},
+		{domain.BlogSortComments, "", "comment_count", 3},
+		{domain.BlogSortTrending, domain.TrendingWeek, "trending.7d", 4.5},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.sort), func(t *testing.T) {
+			cursor := encodeBlogCursor(tt.sort, tt.window, last)
+			got, err := cursorFilter(tt.sort, tt.window, cursor)
+			if err != nil {
+				t.Fatalf("cursorFilter: %v", err)
+			}
+			want := bson.M{"$or": bson.A{
+				bson.M{tt.field: bson.M{"$lt": tt.value}},
+				bson.M{tt.field: tt.value, "_id": bson.M{"$lt": id}},
+			}}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("cursorFilter = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestCursorFilterRejects(t *testing.T) {
+	last := blogQueryRow{blogModel: blogModel{ID: primitive.NewObjectID(), ViewCount: 1}}
+	popular := encodeBlogCursor(domain.BlogSortPopular, "", last)
+	trendingDay := encodeBlogCursor(domain.BlogSortTrending, domain.TrendingDay, last)
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name   string
+		sort   domain.BlogSort
+		window domain.TrendingWindow
+		cursor string
+	}{
+		{"not base64", domain.BlogSortPopular, "", "%%%"},
+		{"not json", domain.BlogSortPopular, "", encode("nope")},
+		{"bad id", domain.BlogSortPopular, "", encode(`{"s":"popular","v":1,"id":"xyz"}`)},
+		{"missing value", domain.BlogSortPopular, "", encode(`{"s":"popular","id":"` + last.ID.Hex() + `"}`)},
+		{"other sort", domain.BlogSortLikes, "", popular},
+		{"other window", domain.BlogSortTrending, domain.TrendingWeek, trendingDay},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := cursorFilter(tt.sort, tt.window, tt.cursor)
+			if !errors.Is(err, domain.ErrInvalidInput) {
+				t.Errorf("cursorFilter error = %v, want ErrInvalidInput", err)
+			}
+		})
+	}
+}
+
+func TestCursorFilterEmpty(t *testing.T) {
+	filter, err := cursorFilter(domain.BlogSortRecent, "", "")
+	if err != nil || filter != nil {
+		t.Errorf("cursorFilter(\"\") = %v, %v; want nil, nil", filter, err)
+	}
+}
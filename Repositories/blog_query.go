@@ -0,0 +1,182 @@
+package repositories
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"fmt"
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// blogQueryRow is a blog as it comes out of the listing pipeline, with the
+// text score alongside the stored fields.
+type blogQueryRow struct {
+	blogModel `bson:",inline"`
+	Score     float64 `bson:"score"`
+}
+
+// blogQueryFilter turns the criteria of query into a $match document.
+func blogQueryFilter(query domain.BlogQuery, viewer domain.Viewer) bson.M {
+	var and bson.A
+	if query.Text != "" {
+		// $text has to be in the first $match of the pipeline.
+		and = append(and, bson.M{"$text": bson.M{"$search": query.Text}})
+	}
+	if len(query.Tags) > 0 {
+		variants := query.TagVariants
+		if len(variants) != len(query.Tags) {
+			variants = make([][]string, len(query.Tags))
+			for i, tag := range query.Tags {
+				variants[i] = []string{tag}
+			}
+		}
+		if query.TagMatch == domain.TagMatchAll {
+			for _, spellings := range variants {
+				and = append(and, bson.M{"tags": bson.M{"$in": spellings}})
+			}
+		} else {
+			var names []string
+			for _, spellings := range variants {
+				names = append(names, spellings...)
+			}
+			and = append(and, bson.M{"tags": bson.M{"$in": names}})
+		}
+	}
+	if query.AuthorID != "" {
+		and = append(and, bson.M{"user_id": query.AuthorID})
+	}
+	if query.Following != nil {
+		// Nil slices would encode as null, which $in rejects.
+		authorIDs := append([]string{}, query.Following.AuthorIDs...)
+		tags := append([]string{}, query.Following.Tags...)
+		and = append(and, bson.M{"$or": bson.A{
+			bson.M{"user_id": bson.M{"$in": authorIDs}},
+			bson.M{"tags": bson.M{"$in": tags}},
+		}})
+	}
+	if query.From != nil || query.To != nil {
+		created := bson.M{}
+		if query.From != nil {
+			created["$gte"] = *query.From
+		}
+		if query.To != nil {
+			created["$lt"] = *query.To
+		}
+		and = append(and, bson.M{"createdAt": created})
+	}
+	if query.Title != "" {
+		// Quote the input so it is matched literally rather than run as a pattern.
+		and = append(and, bson.M{"title": bson.M{"$regex": regexp.QuoteMeta(query.Title), "$options": "i"}})
+	}
+	if query.MinViews > 0 {
+		and = append(and, bson.M{"view_count": bson.M{"$gte": query.MinViews}})
+	}
+	if query.MinLikes > 0 {
+		and = append(and, bson.M{"like_count": bson.M{"$gte": query.MinLikes}})
+	}
+	if query.Status == domain.BlogStatusPublished {
+		and = append(and, bson.M{"status": bson.M{"$in": bson.A{domain.BlogStatusPublished, nil}}})
+	} else if query.Status != "" {
+		and = append(and, bson.M{"status": query.Status})
+	}
+	if visibility := visibilityFilter(viewer); visibility != nil {
+		and = append(and, visibility)
+	}
+
+	if len(and) == 0 {
+		return bson.M{}
+	}
+	return bson.M{"$and": and}
+}
+
+func (r *blogRepository) FindBlogs(ctx context.Context, query domain.BlogQuery, viewer domain.Viewer) (*domain.BlogQueryResult, error) {
+	pipeline := bson.A{bson.M{"$match": blogQueryFilter(query, viewer)}}
+	if query.Text != "" {
+		pipeline = append(pipeline, bson.M{"$addFields": bson.M{"score": bson.M{"$meta": "textScore"}}})
+	}
+
+	result := &domain.BlogQueryResult{}
+	if !query.UseCursor {
+		total, err := r.countPipeline(ctx, pipeline)
+		if err != nil {
+			return nil, err
+		}
+		result.Total = total
+	}
+
+	if query.UseCursor {
+		after, err := cursorFilter(query.Sort, query.TrendingWindow, query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if after != nil {
+			pipeline = append(pipeline, bson.M{"$match": after})
+		}
+	}
+
+	sort := cursorSort(query.Sort, query.TrendingWindow)
+	if query.Sort == domain.BlogSortRelevance {
+		sort = bson.D{{Key: "score", Value: -1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}
+	}
+	pipeline = append(pipeline, bson.M{"$sort": sort})
+	if query.UseCursor {
+		// One extra document tells us whether another page exists.
+		pipeline = append(pipeline, bson.M{"$limit": query.Limit + 1})
+	} else {
+		pipeline = append(pipeline,
+			bson.M{"$skip": (query.Page - 1) * query.Limit},
+			bson.M{"$limit": query.Limit},
+		)
+	}
+
+	cursor, err := r.blogCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch blogs: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []blogQueryRow
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode blogs: %w", err)
+	}
+
+	if query.UseCursor && len(rows) > query.Limit {
+		rows = rows[:query.Limit]
+		result.NextCursor = encodeBlogCursor(query.Sort, query.TrendingWindow, rows[len(rows)-1])
+	}
+	result.Blogs = make([]domain.Blog, 0, len(rows))
+	if query.Text != "" {
+		result.Scores = make(map[string]float64, len(rows))
+	}
+	for _, row := range rows {
+		blog := toDomainBlog(row.blogModel)
+		result.Blogs = append(result.Blogs, blog)
+		if result.Scores != nil {
+			result.Scores[blog.ID] = row.Score
+		}
+	}
+	return result, nil
+}
+
+// countPipeline counts the documents the filtering stages of pipeline let
+// through.
+func (r *blogRepository) countPipeline(ctx context.Context, pipeline bson.A) (int64, error) {
+	counting := append(append(bson.A{}, pipeline...), bson.M{"$count": "total"})
+	cursor, err := r.blogCollection.Aggregate(ctx, counting)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count blogs: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var counts []struct {
+		Total int64 `bson:"total"`
+	}
+	if err := cursor.All(ctx, &counts); err != nil {
+		return 0, fmt.Errorf("failed to count blogs: %w", err)
+	}
+	if len(counts) == 0 {
+		return 0, nil
+	}
+	return counts[0].Total, nil
+}
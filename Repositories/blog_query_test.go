@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	domain "blog-api/Domain"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestBlogQueryFilterFollowing(t *testing.T) {
+	tests := []struct {
+		name        string
+		following   domain.FollowingFilter
+		wantAuthors int
+		wantTags    int
+	}{
+		{name: "authors only", following: domain.FollowingFilter{AuthorIDs: []string{"u1", "u2"}}, wantAuthors: 2},
+		{name: "tags only", following: domain.FollowingFilter{Tags: []string{"javascript", "js"}}, wantTags: 2},
+		{name: "both", following: domain.FollowingFilter{AuthorIDs: []string{"u1"}, Tags: []string{"go"}}, wantAuthors: 1, wantTags: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			following := tt.following
+			filter := blogQueryFilter(domain.BlogQuery{Following: &following, Status: domain.BlogStatusPublished}, domain.Viewer{})
+
+			raw, err := bson.Marshal(filter)
+			if err != nil {
+				t.Fatalf("filter does not encode: %v", err)
+			}
+			var either bson.Raw
+			clauses, _ := bson.Raw(raw).Lookup("$and").Array().Values()
+			for _, clause := range clauses {
+				if or, ok := clause.Document().Lookup("$or").ArrayOK(); ok {
+					either = or
+				}
+			}
+			if either == nil {
+				t.Fatalf("filter %v has no author-or-tag clause", filter)
+			}
+			// An unfollowed side must still be an array: $in rejects null.
+			authors, ok := either.Index(0).Value().Document().Lookup("user_id", "$in").ArrayOK()
+			if !ok {
+				t.Fatalf("authors are not an array in %v", either)
+			}
+			tags, ok := either.Index(1).Value().Document().Lookup("tags", "$in").ArrayOK()
+			if !ok {
+				t.Fatalf("tags are not an array in %v", either)
+			}
+			authorValues, _ := authors.Values()
+			tagValues, _ := tags.Values()
+			if len(authorValues) != tt.wantAuthors || len(tagValues) != tt.wantTags {
+				t.Errorf("matches %d authors and %d tags, want %d and %d", len(authorValues), len(tagValues), tt.wantAuthors, tt.wantTags)
+			}
+		})
+	}
+}
@@ -13,37 +13,195 @@ import (
 )
 
 type blogModel struct {
-	ID        primitive.ObjectID `bson:"_id"`
-	Title     string             `bson:"title"`
-	Content   string             `bson:"content"`
-	UserID    string             `bson:"user_id"`
-	Tags      []string           `bson:"tags"`
-	ViewCount int                `bson:"view_count"`
-	CreatedAt time.Time          `bson:"createdAt"`
-	UpdatedAt time.Time          `bson:"updatedAt"`
+	ID            primitive.ObjectID   `bson:"_id"`
+	Title         string               `bson:"title"`
+	Slug          string               `bson:"slug,omitempty"`
+	OldSlugs      []string             `bson:"old_slugs,omitempty"`
+	Content       string               `bson:"content"`
+	ContentFormat domain.ContentFormat `bson:"content_format,omitempty"`
+	ContentHTML   string               `bson:"content_html,omitempty"`
+	Excerpt       string               `bson:"excerpt,omitempty"`
+	WordCount     int                  `bson:"word_count"`
+	ReadingTime   int                  `bson:"reading_time"`
+	UserID        string               `bson:"user_id"`
+	Tags          []string             `bson:"tags"`
+	ViewCount     int                  `bson:"view_count"`
+	LikeCount     int                  `bson:"like_count"`
+	CommentCount  int                  `bson:"comment_count"`
+	Status        domain.BlogStatus    `bson:"status"`
+	CommentMode   domain.CommentMode   `bson:"comment_mode,omitempty"`
+	Hidden        bool                 `bson:"hidden,omitempty"`
+	PublishAt     *time.Time           `bson:"publish_at,omitempty"`
+	PublishedAt   *time.Time           `bson:"published_at,omitempty"`
+	CreatedAt     time.Time            `bson:"createdAt"`
+	UpdatedAt     time.Time            `bson:"updatedAt"`
+	LastViewedAt  *time.Time           `bson:"last_viewed_at,omitempty"`
+
+	// Trending holds a score per trending window, e.g. "7d".
+	Trending map[domain.TrendingWindow]float64 `bson:"trending,omitempty"`
 }
 
 func toDomainBlog(m blogModel) domain.Blog {
+	status := m.Status
+	if status == "" {
+		// Blogs stored before the status field existed were always public.
+		status = domain.BlogStatusPublished
+	}
+	format := m.ContentFormat
+	if format == "" {
+		format = domain.ContentFormatPlain
+	}
+	commentMode := m.CommentMode
+	if commentMode == "" {
+		commentMode = domain.CommentModeOpen
+	}
 	return domain.Blog{
-		ID:        m.ID.Hex(),
-		Title:     m.Title,
-		Content:   m.Content,
-		Tags:      m.Tags,
-		UserID:    m.UserID,
-		ViewCount: m.ViewCount,
-		CreatedAt: m.CreatedAt,
-		UpdatedAt: m.UpdatedAt,
+		ID:            m.ID.Hex(),
+		Title:         m.Title,
+		Slug:          m.Slug,
+		OldSlugs:      m.OldSlugs,
+		Content:       m.Content,
+		ContentFormat: format,
+		ContentHTML:   m.ContentHTML,
+		Excerpt:       m.Excerpt,
+		WordCount:     m.WordCount,
+		ReadingTime:   m.ReadingTime,
+		Tags:          m.Tags,
+		UserID:        m.UserID,
+		ViewCount:     m.ViewCount,
+		LikeCount:     m.LikeCount,
+		CommentCount:  m.CommentCount,
+		Status:        status,
+		CommentMode:   commentMode,
+		Hidden:        m.Hidden,
+		PublishAt:     m.PublishAt,
+		PublishedAt:   m.PublishedAt,
+		CreatedAt:     m.CreatedAt,
+		UpdatedAt:     m.UpdatedAt,
+		LastViewedAt:  m.LastViewedAt,
 	}
 }
 
+// visibilityFilter restricts a query to the blogs the viewer may see:
+// published posts (including legacy documents without a status) that
+// moderation has not hidden plus, for signed-in users, their own posts.
+// Admins see everything.
+func visibilityFilter(viewer domain.Viewer) bson.M {
+	if viewer.Role == domain.RoleAdmin {
+		return nil
+	}
+	published := bson.M{
+		"status": bson.M{"$in": bson.A{domain.BlogStatusPublished, nil}},
+		"hidden": bson.M{"$ne": true},
+	}
+	if viewer.UserID == "" {
+		return published
+	}
+	return bson.M{"$or": bson.A{published, bson.M{"user_id": viewer.UserID}}}
+}
+
+func withVisibility(filter bson.M, viewer domain.Viewer) bson.M {
+	visibility := visibilityFilter(viewer)
+	if visibility == nil {
+		return filter
+	}
+	if len(filter) == 0 {
+		return visibility
+	}
+	return bson.M{"$and": bson.A{filter, visibility}}
+}
+
 type blogRepository struct {
-	blogCollection *mongo.Collection
+	blogCollection     *mongo.Collection
+	activityCollection *mongo.Collection
+	// The reactions and comments that ReconcileCounts recounts.
+	reactionCollection *mongo.Collection
+	commentCollection  *mongo.Collection
 }
 
 func NewBlogRepository(db *mongo.Database) domain.IBlogRepository {
 
 	collection := db.Collection("blogs")
-	return &blogRepository{blogCollection: collection}
+	indexModels := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "publish_at", Value: 1}}},
+		{Keys: bson.D{{Key: "announce_at", Value: 1}}, Options: options.Index().SetSparse(true)},
+		// Keyset pagination orders (see cursorSort).
+		{Keys: bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "published_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "view_count", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "like_count", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "comment_count", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "trending.24h", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "trending.7d", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "trending.30d", Value: -1}, {Key: "_id", Value: -1}}},
+		{
+			Keys: bson.D{{Key: "slug", Value: 1}},
+			// Blogs created before slugs existed have none; leave them out.
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"slug": bson.M{"$type": "string"}}),
+		},
+		{Keys: bson.D{{Key: "old_slugs", Value: 1}}},
+		{Keys: bson.D{{Key: "tags", Value: 1}}},
+		{Keys: bson.D{{Key: "counts_changed_at", Value: 1}}, Options: options.Index().SetSparse(true)},
+		{
+			Keys: bson.D{{Key: "title", Value: "text"}, {Key: "tags", Value: "text"}, {Key: "content", Value: "text"}},
+			Options: options.Index().SetName("blog_text").
+				SetWeights(bson.D{{Key: "title", Value: 10}, {Key: "tags", Value: 5}, {Key: "content", Value: 1}}).
+				SetDefaultLanguage("english"),
+		},
+	}
+	ensureIndexes(collection, indexModels)
+
+	// Blogs published before published_at was recorded count as published
+	// when they were created, so the published order includes them.
+	Migrate(db, "blogs.published_at", func(ctx context.Context) error {
+		_, err := collection.UpdateMany(ctx,
+			bson.M{
+				"published_at": bson.M{"$exists": false},
+				"status":       bson.M{"$in": bson.A{domain.BlogStatusPublished, nil}},
+			},
+			mongo.Pipeline{{{Key: "$set", Value: bson.M{"published_at": "$createdAt"}}}},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to backfill published_at: %w", err)
+		}
+		return nil
+	})
+
+	repository := &blogRepository{
+		blogCollection:     collection,
+		activityCollection: newBlogActivityCollection(db),
+		reactionCollection: db.Collection(reactionsCollection),
+		commentCollection:  db.Collection(commentsCollection),
+	}
+	Migrate(db, "blogs.sort_counters", repository.backfillSortCounters)
+	return repository
+}
+
+// backfillSortCounters sets the counters and trending scores that blogs
+// stored by older versions lack to 0. Cursors page with $lt on these
+// fields, which never matches a missing one, so such blogs would otherwise
+// drop out of every page after the first.
+func (r *blogRepository) backfillSortCounters(ctx context.Context) error {
+	fields := []string{"view_count", "like_count", "comment_count"}
+	for _, window := range domain.TrendingWindows {
+		fields = append(fields, "trending."+string(window))
+	}
+	missing := bson.A{}
+	set := bson.M{}
+	for _, field := range fields {
+		missing = append(missing, bson.M{field: bson.M{"$exists": false}})
+		set[field] = bson.M{"$ifNull": bson.A{"$" + field, 0}}
+	}
+	_, err := r.blogCollection.UpdateMany(ctx,
+		bson.M{"$or": missing},
+		mongo.Pipeline{{{Key: "$set", Value: set}}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to backfill sort counters: %w", err)
+	}
+	return nil
 }
 
 func (r *blogRepository) Create(ctx context.Context, blog *domain.Blog) (*domain.Blog, error) {
@@ -51,17 +209,41 @@ func (r *blogRepository) Create(ctx context.Context, blog *domain.Blog) (*domain
 	blog.ID = blogObjectID.Hex()
 
 	doc := bson.M{
-		"_id":        blogObjectID,
-		"title":      blog.Title,
-		"content":    blog.Content,
-		"tags":       blog.Tags,
-		"view_count": blog.ViewCount,
-		"user_id":    blog.UserID,
-		"createdAt":  blog.CreatedAt,
-		"updatedAt":  blog.UpdatedAt,
+		"_id":            blogObjectID,
+		"title":          blog.Title,
+		"slug":           blog.Slug,
+		"content":        blog.Content,
+		"content_format": blog.ContentFormat,
+		"content_html":   blog.ContentHTML,
+		"excerpt":        blog.Excerpt,
+		"word_count":     blog.WordCount,
+		"reading_time":   blog.ReadingTime,
+		"tags":           blog.Tags,
+		"view_count":     blog.ViewCount,
+		"like_count":     0,
+		"comment_count":  0,
+		"user_id":        blog.UserID,
+		"status":         blog.Status,
+		"comment_mode":   blog.CommentMode,
+		"createdAt":      blog.CreatedAt,
+		"updatedAt":      blog.UpdatedAt,
+	}
+	trending := bson.M{}
+	for _, window := range domain.TrendingWindows {
+		trending[string(window)] = 0.0
+	}
+	doc["trending"] = trending
+	if blog.PublishAt != nil {
+		doc["publish_at"] = blog.PublishAt
+	}
+	if blog.PublishedAt != nil {
+		doc["published_at"] = blog.PublishedAt
 	}
 
 	_, err := r.blogCollection.InsertOne(ctx, doc)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, domain.ErrSlugTaken
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to insert blog: %w", err)
 	}
@@ -72,14 +254,14 @@ func (r *blogRepository) Create(ctx context.Context, blog *domain.Blog) (*domain
 func (r *blogRepository) FindByID(ctx context.Context, blogID string) (*domain.Blog, error) {
 	objID, err := primitive.ObjectIDFromHex(blogID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid blog ID format: %w", err)
+		return nil, fmt.Errorf("%w: invalid blog ID format", domain.ErrBlogNotFound)
 	}
 
 	var blogDoc blogModel
 	err = r.blogCollection.FindOne(ctx, bson.M{"_id": objID}).Decode(&blogDoc)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("blog not found")
+			return nil, domain.ErrBlogNotFound
 		}
 		return nil, fmt.Errorf("failed to find blog: %w", err)
 	}
@@ -88,6 +270,119 @@ func (r *blogRepository) FindByID(ctx context.Context, blogID string) (*domain.B
 	return &domainBlog, nil
 }
 
+func (r *blogRepository) FindByIDs(ctx context.Context, blogIDs []string) (map[string]domain.Blog, error) {
+	objIDs := make([]primitive.ObjectID, 0, len(blogIDs))
+	for _, id := range blogIDs {
+		if objID, err := primitive.ObjectIDFromHex(id); err == nil {
+			objIDs = append(objIDs, objID)
+		}
+	}
+	blogs := make(map[string]domain.Blog, len(objIDs))
+	if len(objIDs) == 0 {
+		return blogs, nil
+	}
+
+	cursor, err := r.blogCollection.Find(ctx, bson.M{"_id": bson.M{"$in": objIDs}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find blogs: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []blogModel
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode blogs: %w", err)
+	}
+	for _, doc := range docs {
+		blogs[doc.ID.Hex()] = toDomainBlog(doc)
+	}
+	return blogs, nil
+}
+
+func (r *blogRepository) FindBySlug(ctx context.Context, slug string) (*domain.Blog, error) {
+	var blogDoc blogModel
+	err := r.blogCollection.FindOne(ctx, bson.M{"slug": slug}).Decode(&blogDoc)
+	if err == mongo.ErrNoDocuments {
+		err = r.blogCollection.FindOne(ctx, bson.M{"old_slugs": slug}).Decode(&blogDoc)
+	}
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrBlogNotFound
+		}
+		return nil, fmt.Errorf("failed to find blog by slug: %w", err)
+	}
+
+	domainBlog := toDomainBlog(blogDoc)
+	return &domainBlog, nil
+}
+
+// SlugTaken reports whether slug is the current or a previous slug of any blog
+// other than excludeBlogID, so retired slugs keep redirecting to their post.
+func (r *blogRepository) SlugTaken(ctx context.Context, slug, excludeBlogID string) (bool, error) {
+	filter := bson.M{"$or": bson.A{bson.M{"slug": slug}, bson.M{"old_slugs": slug}}}
+	if excludeBlogID != "" {
+		objID, err := primitive.ObjectIDFromHex(excludeBlogID)
+		if err != nil {
+			return false, fmt.Errorf("invalid blog ID: %w", err)
+		}
+		filter["_id"] = bson.M{"$ne": objID}
+	}
+
+	count, err := r.blogCollection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		return false, fmt.Errorf("failed to check slug: %w", err)
+	}
+	return count > 0, nil
+}
+
+// withoutSlug matches blogs whose slug is missing, null or empty.
+var withoutSlug = bson.M{"$in": bson.A{nil, ""}}
+
+func (r *blogRepository) FindWithoutSlug(ctx context.Context, afterID string, limit int) ([]domain.Blog, error) {
+	filter := bson.M{"slug": withoutSlug}
+	if afterID != "" {
+		objID, err := primitive.ObjectIDFromHex(afterID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blog ID: %w", err)
+		}
+		filter["_id"] = bson.M{"$gt": objID}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(limit))
+	cursor, err := r.blogCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find blogs without a slug: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []blogModel
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode blogs: %w", err)
+	}
+	blogs := make([]domain.Blog, 0, len(docs))
+	for _, doc := range docs {
+		blogs = append(blogs, toDomainBlog(doc))
+	}
+	return blogs, nil
+}
+
+func (r *blogRepository) SetSlug(ctx context.Context, blogID, slug string) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(blogID)
+	if err != nil {
+		return false, fmt.Errorf("invalid blog ID: %w", err)
+	}
+	// Leaves alone a blog that an edit gave a slug in the meantime.
+	result, err := r.blogCollection.UpdateOne(ctx,
+		bson.M{"_id": objID, "slug": withoutSlug},
+		bson.M{"$set": bson.M{"slug": slug}},
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, domain.ErrSlugTaken
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to set slug: %w", err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
 func (r *blogRepository) Update(ctx context.Context, blog *domain.Blog) (*domain.Blog, error) {
 	objID, err := primitive.ObjectIDFromHex(blog.ID)
 	if err != nil {
@@ -96,14 +391,24 @@ func (r *blogRepository) Update(ctx context.Context, blog *domain.Blog) (*domain
 
 	update := bson.M{
 		"$set": bson.M{
-			"title":     blog.Title,
-			"content":   blog.Content,
-			"tags":      blog.Tags,
-			"updatedAt": blog.UpdatedAt,
+			"title":          blog.Title,
+			"slug":           blog.Slug,
+			"old_slugs":      blog.OldSlugs,
+			"content":        blog.Content,
+			"content_format": blog.ContentFormat,
+			"content_html":   blog.ContentHTML,
+			"excerpt":        blog.Excerpt,
+			"word_count":     blog.WordCount,
+			"reading_time":   blog.ReadingTime,
+			"tags":           blog.Tags,
+			"updatedAt":      blog.UpdatedAt,
 		},
 	}
 
 	_, err = r.blogCollection.UpdateByID(ctx, objID, update)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, domain.ErrSlugTaken
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to update blog: %w", err)
 	}
@@ -111,49 +416,162 @@ func (r *blogRepository) Update(ctx context.Context, blog *domain.Blog) (*domain
 	return blog, nil
 }
 
-func (r *blogRepository) Filter(ctx context.Context, tag string, date string, sort string) ([]domain.Blog, error) {
-    filter := bson.M{}
-
-    if tag != "" {
-        filter["tags"] = tag
-    }
-
-    if date != "" {
-        parsedDate, err := time.Parse("2006-01-02", date)
-        if err != nil {
-            return nil, fmt.Errorf("repository: invalid date format '%s', expected YYYY-MM-DD", date)
-        }
-        start := parsedDate
-        end := parsedDate.Add(24 * time.Hour)
-        filter["createdAt"] = bson.M{
-            "$gte": start,
-            "$lt":  end,
-        }
-    }
-
-    findOptions := options.Find()
-    if sort == "popular" {
-        findOptions.SetSort(bson.D{{Key: "view_count", Value: -1}})
-    } else {
-        findOptions.SetSort(bson.D{{Key: "createdAt", Value: -1}})
-    }
-
-    cursor, err := r.blogCollection.Find(ctx, filter, findOptions)
-    if err != nil {
-        return nil, fmt.Errorf("repository: failed to fetch blogs: %w", err)
-    }
-    defer cursor.Close(ctx)
-
-    var results []blogModel
-    if err := cursor.All(ctx, &results); err != nil {
-        return nil, fmt.Errorf("repository: failed to decode blogs: %w", err)
-    }
-	blogs := make([]domain.Blog, 0)
-
-    for _, b := range results {
-        blogs = append(blogs, toDomainBlog(b))
-    }
-    return blogs, nil
+func (r *blogRepository) UpdateStatus(ctx context.Context, blogID string, status domain.BlogStatus, publishedAt *time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(blogID)
+	if err != nil {
+		return fmt.Errorf("invalid blog ID: %w", err)
+	}
+
+	set := bson.M{
+		"status":    status,
+		"updatedAt": time.Now(),
+	}
+	unset := bson.M{"publish_at": ""}
+	if publishedAt != nil {
+		set["published_at"] = publishedAt
+	} else {
+		unset["published_at"] = ""
+	}
+	update := bson.M{"$set": set, "$unset": unset}
+
+	result, err := r.blogCollection.UpdateByID(ctx, objID, update)
+	if err != nil {
+		return fmt.Errorf("failed to update blog status: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrBlogNotFound
+	}
+	return nil
+}
+
+func (r *blogRepository) Schedule(ctx context.Context, blogID string, publishAt time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(blogID)
+	if err != nil {
+		return fmt.Errorf("invalid blog ID: %w", err)
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":     domain.BlogStatusScheduled,
+			"publish_at": publishAt,
+			"updatedAt":  time.Now(),
+		},
+		"$unset": bson.M{"published_at": ""},
+	}
+
+	result, err := r.blogCollection.UpdateByID(ctx, objID, update)
+	if err != nil {
+		return fmt.Errorf("failed to schedule blog: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrBlogNotFound
+	}
+	return nil
+}
+
+func (r *blogRepository) SetCommentMode(ctx context.Context, blogID string, mode domain.CommentMode) error {
+	objID, err := primitive.ObjectIDFromHex(blogID)
+	if err != nil {
+		return fmt.Errorf("invalid blog ID: %w", err)
+	}
+
+	result, err := r.blogCollection.UpdateByID(ctx, objID, bson.M{"$set": bson.M{"comment_mode": mode}})
+	if err != nil {
+		return fmt.Errorf("failed to update comment mode: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrBlogNotFound
+	}
+	return nil
+}
+
+func (r *blogRepository) SetHidden(ctx context.Context, blogID string, hidden bool) error {
+	objID, err := primitive.ObjectIDFromHex(blogID)
+	if err != nil {
+		return fmt.Errorf("invalid blog ID: %w", err)
+	}
+
+	update := bson.M{"$set": bson.M{"hidden": true}}
+	if !hidden {
+		update = bson.M{"$unset": bson.M{"hidden": ""}}
+	}
+	result, err := r.blogCollection.UpdateByID(ctx, objID, update)
+	if err != nil {
+		return fmt.Errorf("failed to update blog visibility: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrBlogNotFound
+	}
+	return nil
+}
+
+func (r *blogRepository) PublishNextDue(ctx context.Context, now time.Time) (*domain.Blog, error) {
+	filter := bson.M{
+		"status":     domain.BlogStatusScheduled,
+		"publish_at": bson.M{"$lte": now},
+	}
+	// The status check in the filter makes the promotion a compare-and-set, so
+	// several API instances can run the publisher against the same collection
+	// without publishing a post twice.
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"status":       domain.BlogStatusPublished,
+			"published_at": "$publish_at",
+			"updatedAt":    now,
+			"announce_at":  now,
+		}}},
+		{{Key: "$unset", Value: "publish_at"}},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "publish_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var blogDoc blogModel
+	err := r.blogCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&blogDoc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to publish scheduled blog: %w", err)
+	}
+
+	blog := toDomainBlog(blogDoc)
+	return &blog, nil
+}
+
+func (r *blogRepository) ClaimUnannounced(ctx context.Context, now time.Time, lease time.Duration) (*domain.Blog, error) {
+	filter := bson.M{
+		"announce_at": bson.M{"$lte": now},
+		"status":      domain.BlogStatusPublished,
+	}
+	update := bson.M{"$set": bson.M{"announce_at": now.Add(lease)}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "announce_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var blogDoc blogModel
+	err := r.blogCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&blogDoc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim published blog: %w", err)
+	}
+
+	blog := toDomainBlog(blogDoc)
+	return &blog, nil
+}
+
+func (r *blogRepository) MarkAnnounced(ctx context.Context, blogID string) error {
+	objID, err := primitive.ObjectIDFromHex(blogID)
+	if err != nil {
+		return fmt.Errorf("invalid blog ID: %w", err)
+	}
+	_, err = r.blogCollection.UpdateOne(ctx, bson.M{"_id": objID}, bson.M{"$unset": bson.M{"announce_at": ""}})
+	if err != nil {
+		return fmt.Errorf("failed to mark blog announced: %w", err)
+	}
+	return nil
 }
 
 func (r *blogRepository) DeleteBlog(ctx context.Context, blog *domain.Blog) error {
@@ -168,120 +586,264 @@ func (r *blogRepository) DeleteBlog(ctx context.Context, blog *domain.Blog) erro
 	return nil
 }
 
-func (r *blogRepository) SearchBlogs(ctx context.Context, tag, date, sort, title, userID string) ([]domain.Blog, error) {
-    var andFilters []bson.M
-
-    if tag != "" {
-        andFilters = append(andFilters, bson.M{"tags": tag})
-    }
-    if title != "" {
-        andFilters = append(andFilters, bson.M{"title": bson.M{"$regex": title, "$options": "i"}})
-    }
-    if userID != "" {
-        andFilters = append(andFilters, bson.M{"user_id": userID})
-    }
-    if date != "" {
-        if parsedDate, err := time.Parse("2006-01-02", date); err == nil {
-            start := parsedDate
-            end := parsedDate.Add(24 * time.Hour)
-            andFilters = append(andFilters, bson.M{"createdAt": bson.M{"$gte": start, "$lt": end}})
-        }
-    }
-
-    filter := bson.M{}
-    if len(andFilters) > 0 {
-        filter["$and"] = andFilters
-    }
-
-    findOptions := options.Find()
-    if sort == "popular" {
-        findOptions.SetSort(bson.D{{Key: "view_count", Value: -1}})
-    } else {
-        findOptions.SetSort(bson.D{{Key: "createdAt", Value: -1}})
-    }
-
-    cursor, err := r.blogCollection.Find(ctx, filter, findOptions)
-    if err != nil {
-        return nil, fmt.Errorf("failed to fetch blogs: %w", err)
-    }
-    defer cursor.Close(ctx)
-
-    var results []blogModel
-    if err := cursor.All(ctx, &results); err != nil {
-        return nil, fmt.Errorf("failed to decode blogs: %w", err)
-    }
-
-    var blogs []domain.Blog
-    for _, b := range results {
-        blogs = append(blogs, toDomainBlog(b))
-    }
-    return blogs, nil
+func (r *blogRepository) ReplaceTags(ctx context.Context, from []string, to string) (int64, error) {
+	// Map each tag onto its replacement, then drop repeats while keeping the
+	// original order.
+	replaced := bson.M{"$map": bson.M{
+		"input": "$tags",
+		"in":    bson.M{"$cond": bson.A{bson.M{"$in": bson.A{"$$this", from}}, to, "$$this"}},
+	}}
+	deduped := bson.M{"$reduce": bson.M{
+		"input":        replaced,
+		"initialValue": bson.A{},
+		"in": bson.M{"$cond": bson.A{
+			bson.M{"$in": bson.A{"$$this", "$$value"}},
+			"$$value",
+			bson.M{"$concatArrays": bson.A{"$$value", bson.A{"$$this"}}},
+		}},
+	}}
+
+	result, err := r.blogCollection.UpdateMany(ctx,
+		bson.M{"tags": bson.M{"$in": from}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"tags": deduped}}}},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to replace tags: %w", err)
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *blogRepository) RemoveTags(ctx context.Context, names []string) (int64, error) {
+	result, err := r.blogCollection.UpdateMany(ctx,
+		bson.M{"tags": bson.M{"$in": names}},
+		bson.M{"$pull": bson.M{"tags": bson.M{"$in": names}}},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove tags: %w", err)
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *blogRepository) CountTags(ctx context.Context) (map[string]int, error) {
+	pipeline := bson.A{
+		bson.M{"$match": visibilityFilter(domain.Viewer{})},
+		bson.M{"$unwind": "$tags"},
+		bson.M{"$group": bson.M{"_id": "$tags", "count": bson.M{"$sum": 1}}},
+	}
+	cursor, err := r.blogCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tags: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		Tag   string `bson:"_id"`
+		Count int    `bson:"count"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode tag counts: %w", err)
+	}
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Tag] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *blogRepository) AddViews(ctx context.Context, views []domain.BlogViews) error {
+	writes := make([]mongo.WriteModel, 0, len(views))
+	activity := make([]domain.BlogActivity, 0, len(views))
+	for _, v := range views {
+		objID, err := primitive.ObjectIDFromHex(v.BlogID)
+		if err != nil {
+			continue
+		}
+		activity = append(activity, domain.BlogActivity{BlogID: v.BlogID, Hour: v.LastViewedAt, Views: v.Count})
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": objID}).
+			SetUpdate(bson.M{
+				"$inc": bson.M{"view_count": v.Count},
+				"$max": bson.M{"last_viewed_at": v.LastViewedAt},
+			}))
+	}
+	if len(writes) == 0 {
+		return nil
+	}
+	if _, err := r.blogCollection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		return fmt.Errorf("failed to add views: %w", err)
+	}
+	return r.logActivity(ctx, activity)
 }
 
-func (r *blogRepository) GetPaginated(ctx context.Context, page, limit int, sort, authorID string) ([]domain.Blog, int64, error) {
-    filter := bson.M{}
-    
-    if authorID != "" {
-        filter["user_id"] = authorID
-    }
-
-    // Count total documents
-    total, err := r.blogCollection.CountDocuments(ctx, filter)
-    if err != nil {
-        return nil, 0, fmt.Errorf("failed to count blogs: %w", err)
-    }
-
-    // Calculate skip
-    skip := (page - 1) * limit
-
-    // Set sort options
-    findOptions := options.Find()
-    findOptions.SetSkip(int64(skip))
-    findOptions.SetLimit(int64(limit))
-    
-    switch sort {
-    case "popular", "views":
-        findOptions.SetSort(bson.D{{Key: "view_count", Value: -1}})
-    case "recent":
-        findOptions.SetSort(bson.D{{Key: "createdAt", Value: -1}})
-    default:
-        findOptions.SetSort(bson.D{{Key: "createdAt", Value: -1}})
-    }
-
-    cursor, err := r.blogCollection.Find(ctx, filter, findOptions)
-    if err != nil {
-        return nil, 0, fmt.Errorf("failed to fetch blogs: %w", err)
-    }
-    defer cursor.Close(ctx)
-
-    var results []blogModel
-    if err := cursor.All(ctx, &results); err != nil {
-        return nil, 0, fmt.Errorf("failed to decode blogs: %w", err)
-    }
-
-    var blogs []domain.Blog
-    for _, b := range results {
-        blogs = append(blogs, toDomainBlog(b))
-    }
-    
-    return blogs, total, nil
+func (r *blogRepository) IncrementCounts(ctx context.Context, blogID string, likes, comments int) error {
+	objID, err := primitive.ObjectIDFromHex(blogID)
+	if err != nil {
+		return fmt.Errorf("invalid blog ID: %w", err)
+	}
+	inc := bson.M{}
+	if likes != 0 {
+		inc["like_count"] = likes
+	}
+	if comments != 0 {
+		inc["comment_count"] = comments
+	}
+	if len(inc) == 0 {
+		return nil
+	}
+	update := bson.M{"$inc": inc, "$set": bson.M{"counts_changed_at": time.Now()}}
+	if _, err := r.blogCollection.UpdateByID(ctx, objID, update); err != nil {
+		return fmt.Errorf("failed to update blog counts: %w", err)
+	}
+	if likes > 0 || comments > 0 {
+		activity := domain.BlogActivity{BlogID: blogID, Hour: time.Now(), Likes: max(likes, 0), Comments: max(comments, 0)}
+		return r.logActivity(ctx, []domain.BlogActivity{activity})
+	}
+	return nil
 }
 
-func (r *blogRepository) IncrementViewCount(ctx context.Context, blogID string) error {
-    objID, err := primitive.ObjectIDFromHex(blogID)
-    if err != nil {
-        return fmt.Errorf("invalid blog ID: %w", err)
-    }
+// ReconcileCounts counts "like" reactions and live approved comments per
+// blog and rewrites the stored counts that disagree. Comments stored before
+// moderation existed have no status and count as approved.
+func (r *blogRepository) ReconcileCounts(ctx context.Context, since time.Time) (int64, error) {
+	var pipeline bson.A
+	if !since.IsZero() {
+		ids, err := r.recentlyCountedBlogs(ctx, since)
+		if err != nil {
+			return 0, err
+		}
+		if len(ids) == 0 {
+			return 0, nil
+		}
+		pipeline = append(pipeline, bson.M{"$match": bson.M{"_id": bson.M{"$in": ids}}})
+	}
+	pipeline = append(pipeline,
+		bson.M{"$project": bson.M{"like_count": 1, "comment_count": 1}},
+		bson.M{"$lookup": bson.M{
+			"from": r.reactionCollection.Name(),
+			"let":  bson.M{"blog_id": bson.M{"$toString": "$_id"}},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{
+					"target_type": domain.ReactionTargetBlog,
+					"type":        domain.ReactionLike,
+					"$expr":       bson.M{"$eq": bson.A{"$target_id", "$$blog_id"}},
+				}},
+				bson.M{"$count": "n"},
+			},
+			"as": "likes",
+		}},
+		bson.M{"$lookup": bson.M{
+			"from": r.commentCollection.Name(),
+			"let":  bson.M{"blog_id": bson.M{"$toString": "$_id"}},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{
+					"status":  bson.M{"$in": bson.A{domain.CommentStatusApproved, nil}},
+					"deleted": bson.M{"$ne": true},
+					"$expr":   bson.M{"$eq": bson.A{"$blog_id", "$$blog_id"}},
+				}},
+				bson.M{"$count": "n"},
+			},
+			"as": "comments",
+		}},
+		bson.M{"$set": bson.M{
+			"likes":    bson.M{"$ifNull": bson.A{bson.M{"$arrayElemAt": bson.A{"$likes.n", 0}}, 0}},
+			"comments": bson.M{"$ifNull": bson.A{bson.M{"$arrayElemAt": bson.A{"$comments.n", 0}}, 0}},
+		}},
+		// Blogs stored before the counts existed have neither field, which
+		// never equals a number, so they are filled in here too.
+		bson.M{"$match": bson.M{"$expr": bson.M{"$or": bson.A{
+			bson.M{"$ne": bson.A{"$like_count", "$likes"}},
+			bson.M{"$ne": bson.A{"$comment_count", "$comments"}},
+		}}}},
+	)
+	cursor, err := r.blogCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, fmt.Errorf("failed to recount blogs: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		ID           primitive.ObjectID `bson:"_id"`
+		LikeCount    *int               `bson:"like_count"`
+		CommentCount *int               `bson:"comment_count"`
+		Likes        int                `bson:"likes"`
+		Comments     int                `bson:"comments"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return 0, fmt.Errorf("failed to decode blog counts: %w", err)
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
 
-    update := bson.M{
-        "$inc": bson.M{"view_count": 1},
-        "$set": bson.M{"updatedAt": time.Now()},
-    }
+	// Only overwrite the counts that were read: a like or comment counted
+	// with $inc since then makes the filter miss, and the next run picks
+	// the blog up again.
+	unchanged := func(count *int) interface{} {
+		if count == nil {
+			return bson.M{"$exists": false}
+		}
+		return *count
+	}
+	writes := make([]mongo.WriteModel, 0, len(rows))
+	for _, row := range rows {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{
+				"_id":           row.ID,
+				"like_count":    unchanged(row.LikeCount),
+				"comment_count": unchanged(row.CommentCount),
+			}).
+			SetUpdate(bson.M{"$set": bson.M{"like_count": row.Likes, "comment_count": row.Comments}}))
+	}
+	res, err := r.blogCollection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, fmt.Errorf("failed to update blog counts: %w", err)
+	}
+	return res.ModifiedCount, nil
+}
 
-    _, err = r.blogCollection.UpdateByID(ctx, objID, update)
-    if err != nil {
-        return fmt.Errorf("failed to increment view count: %w", err)
-    }
+// recentlyCountedBlogs returns the IDs of blogs whose counts were changed,
+// or that were liked or commented on, from since on.
+func (r *blogRepository) recentlyCountedBlogs(ctx context.Context, since time.Time) (bson.A, error) {
+	seen := map[string]bool{}
+	var ids bson.A
+	add := func(values []interface{}) {
+		for _, v := range values {
+			var objID primitive.ObjectID
+			switch v := v.(type) {
+			case primitive.ObjectID:
+				objID = v
+			case string:
+				var err error
+				if objID, err = primitive.ObjectIDFromHex(v); err != nil {
+					continue
+				}
+			default:
+				continue
+			}
+			if !seen[objID.Hex()] {
+				seen[objID.Hex()] = true
+				ids = append(ids, objID)
+			}
+		}
+	}
 
-    return nil
+	changed, err := r.blogCollection.Distinct(ctx, "_id", bson.M{"counts_changed_at": bson.M{"$gte": since}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find recently counted blogs: %w", err)
+	}
+	add(changed)
+	liked, err := r.reactionCollection.Distinct(ctx, "target_id", bson.M{
+		"target_type": domain.ReactionTargetBlog,
+		"updatedAt":   bson.M{"$gte": since},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find recently liked blogs: %w", err)
+	}
+	add(liked)
+	commented, err := r.commentCollection.Distinct(ctx, "blog_id", bson.M{"created_at": bson.M{"$gte": since}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find recently commented blogs: %w", err)
+	}
+	add(commented)
+	return ids, nil
 }
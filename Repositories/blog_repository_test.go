@@ -0,0 +1,292 @@
+package repositories
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// findAndModifyReply answers a findAndModify with doc, or with no match
+// when doc is nil.
+func findAndModifyReply(doc bson.D) bson.D {
+	if doc == nil {
+		return bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}}
+	}
+	return bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: doc}}
+}
+
+func TestPublishNextDue(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	now := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	id := primitive.NewObjectID()
+
+	mt.Run("publishes the blog that has been due longest", func(mt *mtest.T) {
+		r := &blogRepository{blogCollection: mt.Coll}
+		mt.AddMockResponses(findAndModifyReply(bson.D{
+			{Key: "_id", Value: id},
+			{Key: "title", Value: "Monday"},
+			{Key: "status", Value: domain.BlogStatusPublished},
+			{Key: "published_at", Value: now.Add(-time.Minute)},
+		}))
+
+		blog, err := r.PublishNextDue(context.Background(), now)
+		if err != nil {
+			mt.Fatalf("PublishNextDue: %v", err)
+		}
+		if blog == nil || blog.ID != id.Hex() || blog.Status != domain.BlogStatusPublished {
+			mt.Fatalf("blog = %+v, want %s published", blog, id.Hex())
+		}
+
+		cmd := mt.GetStartedEvent().Command
+		if status := cmd.Lookup("query", "status").StringValue(); status != string(domain.BlogStatusScheduled) {
+			mt.Errorf("publishes %s blogs, want scheduled", status)
+		}
+		if due := cmd.Lookup("query", "publish_at", "$lte").Time(); !due.Equal(now) {
+			mt.Errorf("publishes blogs due by %v, want %v", due, now)
+		}
+		if sort := cmd.Lookup("sort", "publish_at").Int32(); sort != 1 {
+			mt.Errorf("sort on publish_at = %d, want 1", sort)
+		}
+		set := cmd.Lookup("update").Array().Index(0).Value().Document().Lookup("$set")
+		if status := set.Document().Lookup("status").StringValue(); status != string(domain.BlogStatusPublished) {
+			mt.Errorf("status set to %q, want published", status)
+		}
+		if announceAt := set.Document().Lookup("announce_at").Time(); !announceAt.Equal(now) {
+			mt.Errorf("announce_at = %v, want %v", announceAt, now)
+		}
+	})
+
+	mt.Run("nothing due", func(mt *mtest.T) {
+		r := &blogRepository{blogCollection: mt.Coll}
+		mt.AddMockResponses(findAndModifyReply(nil))
+		blog, err := r.PublishNextDue(context.Background(), now)
+		if err != nil || blog != nil {
+			mt.Errorf("PublishNextDue = %+v, %v, want nil", blog, err)
+		}
+	})
+}
+
+func TestClaimUnannounced(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	now := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+
+	mt.Run("holds the blog for the lease", func(mt *mtest.T) {
+		r := &blogRepository{blogCollection: mt.Coll}
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(findAndModifyReply(bson.D{{Key: "_id", Value: id}, {Key: "status", Value: domain.BlogStatusPublished}}))
+
+		blog, err := r.ClaimUnannounced(context.Background(), now, time.Minute)
+		if err != nil || blog == nil || blog.ID != id.Hex() {
+			mt.Fatalf("ClaimUnannounced = %+v, %v, want %s", blog, err, id.Hex())
+		}
+		cmd := mt.GetStartedEvent().Command
+		if due := cmd.Lookup("query", "announce_at", "$lte").Time(); !due.Equal(now) {
+			mt.Errorf("claims blogs due by %v, want %v", due, now)
+		}
+		if status := cmd.Lookup("query", "status").StringValue(); status != string(domain.BlogStatusPublished) {
+			mt.Errorf("claims %s blogs, want published", status)
+		}
+		if until := cmd.Lookup("update", "$set", "announce_at").Time(); !until.Equal(now.Add(time.Minute)) {
+			mt.Errorf("claimed until %v, want %v", until, now.Add(time.Minute))
+		}
+	})
+
+	mt.Run("none waiting", func(mt *mtest.T) {
+		r := &blogRepository{blogCollection: mt.Coll}
+		mt.AddMockResponses(findAndModifyReply(nil))
+		blog, err := r.ClaimUnannounced(context.Background(), now, time.Minute)
+		if err != nil || blog != nil {
+			mt.Errorf("ClaimUnannounced = %+v, %v, want nil", blog, err)
+		}
+	})
+}
+
+func TestSetSlug(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	id := primitive.NewObjectID()
+
+	mt.Run("only fills in a missing slug", func(mt *mtest.T) {
+		r := &blogRepository{blogCollection: mt.Coll}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+
+		set, err := r.SetSlug(context.Background(), id.Hex(), "first-post")
+		if err != nil || !set {
+			mt.Fatalf("SetSlug = %v, %v, want true", set, err)
+		}
+		filter := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("q").Document()
+		missing, _ := filter.Lookup("slug", "$in").Array().Values()
+		if len(missing) != 2 || missing[0].Type != bson.TypeNull || missing[1].StringValue() != "" {
+			mt.Errorf("matches slugs %v, want null and empty", missing)
+		}
+	})
+
+	mt.Run("slug in use", func(mt *mtest.T) {
+		r := &blogRepository{blogCollection: mt.Coll}
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "duplicate key"}))
+
+		if _, err := r.SetSlug(context.Background(), id.Hex(), "first-post"); !errors.Is(err, domain.ErrSlugTaken) {
+			mt.Errorf("SetSlug: %v, want %v", err, domain.ErrSlugTaken)
+		}
+	})
+}
+
+func TestBackfillSortCounters(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("zeroes only missing counters", func(mt *mtest.T) {
+		r := &blogRepository{blogCollection: mt.Coll}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 4}, {Key: "nModified", Value: 4}})
+
+		if err := r.backfillSortCounters(context.Background()); err != nil {
+			mt.Fatalf("backfillSortCounters: %v", err)
+		}
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		if !update.Lookup("multi").Boolean() {
+			mt.Errorf("update %v, want it to cover every blog", update)
+		}
+		missing, _ := update.Lookup("q", "$or").Array().Values()
+		set := update.Lookup("u").Array().Index(0).Value().Document().Lookup("$set").Document()
+		for _, field := range []string{"view_count", "like_count", "comment_count", "trending.24h", "trending.7d", "trending.30d"} {
+			found := false
+			for _, clause := range missing {
+				if exists, ok := clause.Document().Lookup(field, "$exists").BooleanOK(); ok && !exists {
+					found = true
+				}
+			}
+			if !found {
+				mt.Errorf("filter %v does not match blogs without %s", missing, field)
+			}
+			ifNull, _ := set.Lookup(field, "$ifNull").Array().Values()
+			if len(ifNull) != 2 || ifNull[0].StringValue() != "$"+field || ifNull[1].Int32() != 0 {
+				mt.Errorf("sets %s to %v, want it kept or 0", field, set.Lookup(field))
+			}
+		}
+	})
+
+	mt.Run("update fails", func(mt *mtest.T) {
+		r := &blogRepository{blogCollection: mt.Coll}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}, {Key: "code", Value: 91}, {Key: "errmsg", Value: "shutting down"}})
+
+		if err := r.backfillSortCounters(context.Background()); err == nil {
+			mt.Error("backfillSortCounters succeeded, want the error so the migration is retried")
+		}
+	})
+}
+
+func TestReconcileCounts(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	cursorReply := func(ns string, docs ...bson.D) bson.D {
+		batch := bson.A{}
+		for _, doc := range docs {
+			batch = append(batch, doc)
+		}
+		return bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "cursor", Value: bson.D{{Key: "id", Value: int64(0)}, {Key: "ns", Value: ns}, {Key: "firstBatch", Value: batch}}},
+		}
+	}
+	distinctReply := func(values ...interface{}) bson.D {
+		return bson.D{{Key: "ok", Value: 1}, {Key: "values", Value: append(bson.A{}, values...)}}
+	}
+	repository := func(mt *mtest.T) *blogRepository {
+		return &blogRepository{
+			blogCollection:     mt.Coll,
+			reactionCollection: mt.DB.Collection("reactions_v2"),
+			commentCollection:  mt.DB.Collection("threaded_comments"),
+		}
+	}
+
+	mt.Run("full pass rewrites the counts that are off", func(mt *mtest.T) {
+		r := repository(mt)
+		drifted := primitive.NewObjectID()
+		mt.AddMockResponses(
+			cursorReply("test.blogs", bson.D{
+				{Key: "_id", Value: drifted}, {Key: "like_count", Value: 3},
+				{Key: "likes", Value: 5}, {Key: "comments", Value: 2},
+			}),
+			bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}},
+		)
+
+		fixed, err := r.ReconcileCounts(context.Background(), time.Time{})
+		if err != nil || fixed != 1 {
+			mt.Fatalf("ReconcileCounts = %d, %v, want 1", fixed, err)
+		}
+		events := mt.GetAllStartedEvents()
+		if len(events) != 2 {
+			mt.Fatalf("sent %d commands, want an aggregate and an update", len(events))
+		}
+		stages, _ := events[0].Command.Lookup("pipeline").Array().Values()
+		var from []string
+		for _, stage := range stages {
+			if lookup, ok := stage.Document().Lookup("$lookup").DocumentOK(); ok {
+				from = append(from, lookup.Lookup("from").StringValue())
+			}
+		}
+		if len(from) != 2 || from[0] != "reactions_v2" || from[1] != "threaded_comments" {
+			mt.Errorf("counts from %v, want the reaction and comment collections it was given", from)
+		}
+		if _, ok := stages[0].Document().Lookup("$match").DocumentOK(); ok {
+			mt.Errorf("full pass starts with %v, want every blog", stages[0])
+		}
+
+		update := events[1].Command.Lookup("updates").Array().Index(0).Value().Document()
+		filter := update.Lookup("q").Document()
+		exists, ok := filter.Lookup("comment_count", "$exists").BooleanOK()
+		if filter.Lookup("_id").ObjectID() != drifted || filter.Lookup("like_count").AsInt64() != 3 || !ok || exists {
+			mt.Errorf("update filter = %v, want the counts as read", filter)
+		}
+		set := update.Lookup("u", "$set").Document()
+		if set.Lookup("like_count").AsInt64() != 5 || set.Lookup("comment_count").AsInt64() != 2 {
+			mt.Errorf("update sets %v, want 5 likes and 2 comments", set)
+		}
+	})
+
+	mt.Run("incremental pass looks at recent activity only", func(mt *mtest.T) {
+		r := repository(mt)
+		bumped, liked := primitive.NewObjectID(), primitive.NewObjectID()
+		mt.AddMockResponses(
+			distinctReply(bumped),
+			distinctReply(liked.Hex(), bumped.Hex(), "not-an-id"),
+			distinctReply(liked.Hex()),
+			cursorReply("test.blogs"),
+		)
+
+		fixed, err := r.ReconcileCounts(context.Background(), time.Now().Add(-time.Hour))
+		if err != nil || fixed != 0 {
+			mt.Fatalf("ReconcileCounts = %d, %v, want 0", fixed, err)
+		}
+		events := mt.GetAllStartedEvents()
+		if len(events) != 4 {
+			mt.Fatalf("sent %d commands, want three distincts and an aggregate", len(events))
+		}
+		if got := events[1].Command.Lookup("distinct").StringValue(); got != "reactions_v2" {
+			mt.Errorf("looks for recent likes in %q", got)
+		}
+		if got := events[2].Command.Lookup("distinct").StringValue(); got != "threaded_comments" {
+			mt.Errorf("looks for recent comments in %q", got)
+		}
+		stage := events[3].Command.Lookup("pipeline").Array().Index(0).Value().Document()
+		ids, _ := stage.Lookup("$match", "_id", "$in").Array().Values()
+		if len(ids) != 2 || ids[0].ObjectID() != bumped || ids[1].ObjectID() != liked {
+			mt.Errorf("recounts %v, want %s and %s once each", ids, bumped.Hex(), liked.Hex())
+		}
+	})
+
+	mt.Run("quiet period skips the recount", func(mt *mtest.T) {
+		r := repository(mt)
+		mt.AddMockResponses(distinctReply(), distinctReply(), distinctReply())
+
+		if fixed, err := r.ReconcileCounts(context.Background(), time.Now().Add(-time.Hour)); err != nil || fixed != 0 {
+			mt.Fatalf("ReconcileCounts = %d, %v, want 0", fixed, err)
+		}
+		if n := len(mt.GetAllStartedEvents()); n != 3 {
+			mt.Errorf("sent %d commands, want only the three distincts", n)
+		}
+	})
+}
@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type blogRevisionModel struct {
+	ID            primitive.ObjectID   `bson:"_id"`
+	BlogID        string               `bson:"blog_id"`
+	Version       int                  `bson:"version"`
+	EditorID      string               `bson:"editor_id"`
+	Title         string               `bson:"title"`
+	Content       string               `bson:"content"`
+	ContentFormat domain.ContentFormat `bson:"content_format,omitempty"`
+	Tags          []string             `bson:"tags"`
+	RestoredFrom  int                  `bson:"restored_from,omitempty"`
+	CreatedAt     time.Time            `bson:"created_at"`
+}
+
+func toDomainBlogRevision(m blogRevisionModel) domain.BlogRevision {
+	return domain.BlogRevision{
+		ID:            m.ID.Hex(),
+		BlogID:        m.BlogID,
+		Version:       m.Version,
+		EditorID:      m.EditorID,
+		Title:         m.Title,
+		Content:       m.Content,
+		ContentFormat: m.ContentFormat,
+		Tags:          m.Tags,
+		RestoredFrom:  m.RestoredFrom,
+		CreatedAt:     m.CreatedAt,
+	}
+}
+
+type blogRevisionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewBlogRevisionRepository(db *mongo.Database) domain.IBlogRevisionRepository {
+	collection := db.Collection("blog_revisions")
+	indexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "blog_id", Value: 1},
+			{Key: "version", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	}
+	ensureIndexes(collection, []mongo.IndexModel{indexModel})
+
+	return &blogRevisionRepository{collection: collection}
+}
+
+// Create stores a revision under the next free version number for its blog.
+// Revisions are never updated afterwards.
+func (r *blogRevisionRepository) Create(ctx context.Context, revision *domain.BlogRevision) (*domain.BlogRevision, error) {
+	const maxAttempts = 5
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		latest, err := r.LatestVersion(ctx, revision.BlogID)
+		if err != nil {
+			return nil, err
+		}
+
+		doc := blogRevisionModel{
+			ID:            primitive.NewObjectID(),
+			BlogID:        revision.BlogID,
+			Version:       latest + 1,
+			EditorID:      revision.EditorID,
+			Title:         revision.Title,
+			Content:       revision.Content,
+			ContentFormat: revision.ContentFormat,
+			Tags:          revision.Tags,
+			RestoredFrom:  revision.RestoredFrom,
+			CreatedAt:     revision.CreatedAt,
+		}
+
+		_, err = r.collection.InsertOne(ctx, doc)
+		if mongo.IsDuplicateKeyError(err) {
+			// Another edit took this version number first; try the next one.
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to insert blog revision: %w", err)
+		}
+
+		revision.ID = doc.ID.Hex()
+		revision.Version = doc.Version
+		return revision, nil
+	}
+
+	return nil, fmt.Errorf("failed to insert blog revision: too many concurrent edits")
+}
+
+func (r *blogRevisionRepository) ListByBlogID(ctx context.Context, blogID string) ([]domain.BlogRevision, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "version", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"blog_id": blogID}, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch blog revisions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []blogRevisionModel
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode blog revisions: %w", err)
+	}
+
+	revisions := make([]domain.BlogRevision, 0, len(results))
+	for _, m := range results {
+		revisions = append(revisions, toDomainBlogRevision(m))
+	}
+	return revisions, nil
+}
+
+func (r *blogRevisionRepository) FindByVersion(ctx context.Context, blogID string, version int) (*domain.BlogRevision, error) {
+	var doc blogRevisionModel
+	err := r.collection.FindOne(ctx, bson.M{"blog_id": blogID, "version": version}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, domain.ErrRevisionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find blog revision: %w", err)
+	}
+
+	revision := toDomainBlogRevision(doc)
+	return &revision, nil
+}
+
+func (r *blogRevisionRepository) LatestVersion(ctx context.Context, blogID string) (int, error) {
+	findOptions := options.FindOne().
+		SetSort(bson.D{{Key: "version", Value: -1}}).
+		SetProjection(bson.M{"version": 1})
+
+	var doc blogRevisionModel
+	err := r.collection.FindOne(ctx, bson.M{"blog_id": blogID}, findOptions).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to find latest blog revision: %w", err)
+	}
+	return doc.Version, nil
+}
+
+func (r *blogRevisionRepository) Delete(ctx context.Context, revisionID string) error {
+	objID, err := primitive.ObjectIDFromHex(revisionID)
+	if err != nil {
+		return domain.ErrRevisionNotFound
+	}
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"_id": objID}); err != nil {
+		return fmt.Errorf("failed to delete blog revision: %w", err)
+	}
+	return nil
+}
+
+func (r *blogRevisionRepository) DeleteByBlogID(ctx context.Context, blogID string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"blog_id": blogID})
+	if err != nil {
+		return fmt.Errorf("failed to delete blog revisions: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// activityRetention is how long hourly activity is kept: a little over the
+// longest trending window.
+const activityRetention = 31 * 24 * time.Hour
+
+// newBlogActivityCollection holds one document per blog and hour with the
+// blog_id, the hour and that hour's views, likes and comments.
+func newBlogActivityCollection(db *mongo.Database) *mongo.Collection {
+	collection := db.Collection("blog_activity")
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "blog_id", Value: 1}, {Key: "hour", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "hour", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(activityRetention.Seconds())),
+		},
+	}
+	ensureIndexes(collection, indexModels)
+	return collection
+}
+
+// logActivity adds activity to the hourly buckets of each blog.
+func (r *blogRepository) logActivity(ctx context.Context, activity []domain.BlogActivity) error {
+	if len(activity) == 0 {
+		return nil
+	}
+	writes := make([]mongo.WriteModel, 0, len(activity))
+	for _, a := range activity {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"blog_id": a.BlogID, "hour": a.Hour.UTC().Truncate(time.Hour)}).
+			SetUpdate(bson.M{"$inc": bson.M{"views": a.Views, "likes": a.Likes, "comments": a.Comments}}).
+			SetUpsert(true))
+	}
+	if _, err := r.activityCollection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		return fmt.Errorf("failed to log blog activity: %w", err)
+	}
+	return nil
+}
+
+func (r *blogRepository) UpdateTrendingScores(ctx context.Context, now time.Time, weights domain.TrendingWeights) error {
+	// Stamped on every scored blog so the ones left unscored can be found.
+	now = now.UTC().Truncate(time.Millisecond)
+	longest := domain.TrendingWindows[len(domain.TrendingWindows)-1]
+
+	sums := bson.M{"_id": "$blog_id"}
+	scores := bson.M{}
+	for i, window := range domain.TrendingWindows {
+		hours := window.Duration().Hours()
+		sum := fmt.Sprintf("w%d", i)
+		// weight * 2^(-age / halfLife), with the half-life a quarter of the window
+		decayed := bson.M{"$multiply": bson.A{"$weight", bson.M{"$exp": bson.M{"$multiply": bson.A{-math.Ln2 / (hours / 4), "$age"}}}}}
+		sums[sum] = bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$lt": bson.A{"$age", hours}}, decayed, 0}}}
+		scores[string(window)] = "$" + sum
+	}
+
+	pipeline := bson.A{
+		// Activity is measured from the middle of the hour it is logged under.
+		bson.M{"$match": bson.M{"hour": bson.M{"$gt": now.Add(-longest.Duration() - 30*time.Minute)}}},
+		bson.M{"$set": bson.M{
+			"age": bson.M{"$max": bson.A{0, bson.M{"$subtract": bson.A{
+				bson.M{"$divide": bson.A{bson.M{"$subtract": bson.A{now, "$hour"}}, float64(time.Hour / time.Millisecond)}},
+				0.5,
+			}}}},
+			"weight": bson.M{"$add": bson.A{
+				bson.M{"$multiply": bson.A{"$views", weights.View}},
+				bson.M{"$multiply": bson.A{"$likes", weights.Like}},
+				bson.M{"$multiply": bson.A{"$comments", weights.Comment}},
+			}},
+		}},
+		bson.M{"$group": sums},
+		bson.M{"$project": bson.M{
+			"_id":         bson.M{"$convert": bson.M{"input": "$_id", "to": "objectId", "onError": nil, "onNull": nil}},
+			"trending":    scores,
+			"trending_at": now,
+		}},
+		bson.M{"$match": bson.M{"_id": bson.M{"$ne": nil}}},
+		bson.M{"$merge": bson.M{
+			"into": r.blogCollection.Name(),
+			"on":   "_id",
+			"whenMatched": bson.A{bson.M{"$set": bson.M{
+				"trending":    "$$new.trending",
+				"trending_at": "$$new.trending_at",
+			}}},
+			"whenNotMatched": "discard",
+		}},
+	}
+	cursor, err := r.activityCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return fmt.Errorf("failed to compute trending scores: %w", err)
+	}
+	cursor.Close(ctx)
+
+	// Blogs whose activity has left every window drop back to zero. Blogs
+	// without a score get one too, as cursors cannot page past a missing
+	// value.
+	stale := bson.A{}
+	zero := bson.M{}
+	for _, window := range domain.TrendingWindows {
+		field := "trending." + string(window)
+		stale = append(stale, bson.M{field: bson.M{"$gt": 0}}, bson.M{field: bson.M{"$exists": false}})
+		zero[field] = 0
+	}
+	_, err = r.blogCollection.UpdateMany(ctx,
+		bson.M{"$or": stale, "trending_at": bson.M{"$ne": now}},
+		bson.M{"$set": zero},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reset trending scores: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestUpdateTrendingScores(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	now := time.Date(2024, 9, 30, 14, 20, 5, 123456789, time.UTC)
+	weights := domain.TrendingWeights{View: 1, Like: 4, Comment: 6}
+	emptyAggregate := bson.D{
+		{Key: "ok", Value: 1},
+		{Key: "cursor", Value: bson.D{{Key: "id", Value: int64(0)}, {Key: "ns", Value: "test.blog_activity"}, {Key: "firstBatch", Value: bson.A{}}}},
+	}
+
+	mt.Run("merges scores into blogs and zeroes the rest", func(mt *mtest.T) {
+		r := &blogRepository{blogCollection: mt.DB.Collection("blogs"), activityCollection: mt.DB.Collection("blog_activity")}
+		mt.AddMockResponses(emptyAggregate, bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 3}, {Key: "nModified", Value: 3}})
+
+		if err := r.UpdateTrendingScores(context.Background(), now, weights); err != nil {
+			mt.Fatalf("UpdateTrendingScores: %v", err)
+		}
+		events := mt.GetAllStartedEvents()
+		if len(events) != 2 || events[0].CommandName != "aggregate" || events[1].CommandName != "update" {
+			mt.Fatalf("ran %d commands, want an aggregate and an update", len(events))
+		}
+
+		aggregate := events[0].Command
+		if aggregate.Lookup("aggregate").StringValue() != "blog_activity" {
+			mt.Errorf("aggregated %v, want the activity log", aggregate.Lookup("aggregate"))
+		}
+		stages, _ := aggregate.Lookup("pipeline").Array().Values()
+		merge := stages[len(stages)-1].Document().Lookup("$merge").Document()
+		if merge.Lookup("into").StringValue() != "blogs" || merge.Lookup("whenNotMatched").StringValue() != "discard" {
+			mt.Errorf("merge %v, want existing blogs only", merge)
+		}
+		weight := stages[1].Document().Lookup("$set", "weight", "$add").Array()
+		for i, want := range []float64{weights.View, weights.Like, weights.Comment} {
+			factor := weight.Index(uint(i)).Value().Document().Lookup("$multiply").Array().Index(1).Value().Double()
+			if factor != want {
+				mt.Errorf("weight %d = %v, want %v", i, factor, want)
+			}
+		}
+
+		reset := events[1].Command.Lookup("updates").Array().Index(0).Value().Document()
+		stamp := reset.Lookup("q", "trending_at", "$ne").Time()
+		if !stamp.Equal(now.Truncate(time.Millisecond)) {
+			mt.Errorf("reset skips blogs stamped %v, want %v", stamp, now.Truncate(time.Millisecond))
+		}
+		zeroed := reset.Lookup("u", "$set").Document()
+		for _, window := range domain.TrendingWindows {
+			if v, ok := zeroed.Lookup("trending." + string(window)).Int32OK(); !ok || v != 0 {
+				mt.Errorf("reset leaves the %s score alone", window)
+			}
+		}
+	})
+
+	mt.Run("no reset when scoring fails", func(mt *mtest.T) {
+		r := &blogRepository{blogCollection: mt.DB.Collection("blogs"), activityCollection: mt.DB.Collection("blog_activity")}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}, {Key: "code", Value: 292}, {Key: "errmsg", Value: "exceeded memory limit"}})
+
+		if err := r.UpdateTrendingScores(context.Background(), now, weights); err == nil {
+			mt.Fatal("UpdateTrendingScores succeeded, want the aggregation error")
+		}
+		if events := mt.GetAllStartedEvents(); len(events) != 1 {
+			mt.Errorf("ran %d commands, want only the failed aggregate", len(events))
+		}
+	})
+}
+
+func TestIncrementCountsLogsActivity(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	blogID := primitive.NewObjectID().Hex()
+	tests := []struct {
+		name         string
+		likes        int
+		comments     int
+		wantActivity bool
+	}{
+		{name: "new like", likes: 1, wantActivity: true},
+		{name: "new comment", comments: 1, wantActivity: true},
+		{name: "removed like", likes: -1},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			r := &blogRepository{blogCollection: mt.DB.Collection("blogs"), activityCollection: mt.DB.Collection("blog_activity")}
+			mt.AddMockResponses(
+				bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}},
+				bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}},
+			)
+
+			if err := r.IncrementCounts(context.Background(), blogID, tt.likes, tt.comments); err != nil {
+				mt.Fatalf("IncrementCounts: %v", err)
+			}
+			events := mt.GetAllStartedEvents()
+			if logged := len(events) == 2; logged != tt.wantActivity {
+				mt.Fatalf("logged activity = %v, want %v", logged, tt.wantActivity)
+			}
+			if !tt.wantActivity {
+				return
+			}
+			if events[1].Command.Lookup("update").StringValue() != "blog_activity" {
+				mt.Errorf("logged to %v, want blog_activity", events[1].Command.Lookup("update"))
+			}
+			upsert := events[1].Command.Lookup("updates").Array().Index(0).Value().Document()
+			if hour := upsert.Lookup("q", "hour").Time(); !hour.Equal(hour.Truncate(time.Hour)) {
+				mt.Errorf("bucket %v is not a whole hour", hour)
+			}
+			inc := upsert.Lookup("u", "$inc").Document()
+			if inc.Lookup("likes").Int32() != int32(tt.likes) || inc.Lookup("comments").Int32() != int32(tt.comments) {
+				mt.Errorf("incremented %v, want %d likes and %d comments", inc, tt.likes, tt.comments)
+			}
+			if !upsert.Lookup("upsert").Boolean() {
+				mt.Error("activity bucket is not upserted")
+			}
+		})
+	}
+}
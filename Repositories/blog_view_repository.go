@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type blogViewRepository struct {
+	viewCollection *mongo.Collection
+}
+
+func NewBlogViewRepository(db *mongo.Database) domain.IBlogViewRepository {
+	collection := db.Collection("blog_views")
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "blog_id", Value: 1}, {Key: "viewer", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			// MongoDB removes a view once its window is over.
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+	ensureIndexes(collection, indexModels)
+
+	return &blogViewRepository{viewCollection: collection}
+}
+
+// Record only matches an expired view of the same viewer, so the upsert
+// either renews one the TTL monitor has not removed yet or inserts a new
+// one. A live view makes the insert fail on the unique index instead.
+func (r *blogViewRepository) Record(ctx context.Context, blogID, viewerKey string, at time.Time, window time.Duration) (bool, error) {
+	filter := bson.M{"blog_id": blogID, "viewer": viewerKey, "expires_at": bson.M{"$lte": at}}
+	update := bson.M{"$set": bson.M{"viewed_at": at, "expires_at": at.Add(window)}}
+	_, err := r.viewCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to record view: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type bookmarkModel struct {
+	UserID    string    `bson:"user_id"`
+	BlogID    string    `bson:"blog_id"`
+	CreatedAt time.Time `bson:"createdAt"`
+}
+
+type bookmarkRepository struct {
+	bookmarkCollection *mongo.Collection
+}
+
+func NewBookmarkRepository(db *mongo.Database) domain.IBookmarkRepository {
+	collection := db.Collection("bookmarks")
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "blog_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "blog_id", Value: 1}}},
+	}
+	ensureIndexes(collection, indexModels)
+
+	return &bookmarkRepository{bookmarkCollection: collection}
+}
+
+func (r *bookmarkRepository) Add(ctx context.Context, userID, blogID string, at time.Time) (bool, error) {
+	result, err := r.bookmarkCollection.UpdateOne(ctx,
+		bson.M{"user_id": userID, "blog_id": blogID},
+		bson.M{"$setOnInsert": bookmarkModel{UserID: userID, BlogID: blogID, CreatedAt: at}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil // a concurrent request got there first
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to add bookmark: %w", err)
+	}
+	return result.UpsertedCount > 0, nil
+}
+
+func (r *bookmarkRepository) Remove(ctx context.Context, userID, blogID string) (bool, error) {
+	result, err := r.bookmarkCollection.DeleteOne(ctx, bson.M{"user_id": userID, "blog_id": blogID})
+	if err != nil {
+		return false, fmt.Errorf("failed to remove bookmark: %w", err)
+	}
+	return result.DeletedCount > 0, nil
+}
+
+func (r *bookmarkRepository) List(ctx context.Context, userID string, page, limit int) ([]domain.Bookmark, int64, error) {
+	filter := bson.M{"user_id": userID}
+	total, err := r.bookmarkCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count bookmarks: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := r.bookmarkCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list bookmarks: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var models []bookmarkModel
+	if err := cursor.All(ctx, &models); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode bookmarks: %w", err)
+	}
+	bookmarks := make([]domain.Bookmark, 0, len(models))
+	for _, m := range models {
+		bookmarks = append(bookmarks, domain.Bookmark{BlogID: m.BlogID, SavedAt: m.CreatedAt})
+	}
+	return bookmarks, total, nil
+}
+
+func (r *bookmarkRepository) RemoveBlog(ctx context.Context, blogID string) error {
+	if _, err := r.bookmarkCollection.DeleteMany(ctx, bson.M{"blog_id": blogID}); err != nil {
+		return fmt.Errorf("failed to remove bookmarks: %w", err)
+	}
+	return nil
+}
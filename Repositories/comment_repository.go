@@ -1,91 +1,436 @@
 package repositories
 
 import (
-    "blog-api/Domain"
-    "context"
-    "fmt"
-    "time"
-
-    "go.mongodb.org/mongo-driver/bson"
-    "go.mongodb.org/mongo-driver/bson/primitive"
-    "go.mongodb.org/mongo-driver/mongo"
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type commentModel struct {
-    ID        primitive.ObjectID `bson:"_id"`
-    BlogID    string             `bson:"blog_id"`
-    UserID    string             `bson:"user_id"`
-    Content   string             `bson:"content"`
-    CreatedAt time.Time          `bson:"created_at"`
+	ID          primitive.ObjectID   `bson:"_id"`
+	BlogID      string               `bson:"blog_id"`
+	UserID      string               `bson:"user_id"`
+	ParentID    string               `bson:"parent_id,omitempty"`
+	Ancestors   []string             `bson:"ancestors,omitempty"`
+	Depth       int                  `bson:"depth"`
+	Content     string               `bson:"content"`
+	Status      domain.CommentStatus `bson:"status,omitempty"`
+	ModeratedBy string               `bson:"moderated_by,omitempty"`
+	ModeratedAt *time.Time           `bson:"moderated_at,omitempty"`
+	ReplyCount  int                  `bson:"reply_count"`
+	Score       int                  `bson:"score"`
+	CreatedAt   time.Time            `bson:"created_at"`
+	EditedAt    *time.Time           `bson:"edited_at,omitempty"`
+	Deleted     bool                 `bson:"deleted,omitempty"`
+	DeletedAt   *time.Time           `bson:"deleted_at,omitempty"`
+	DeletedBy   string               `bson:"deleted_by,omitempty"`
+	Edits       []commentEditModel   `bson:"edits,omitempty"`
+}
+
+type commentEditModel struct {
+	Content    string    `bson:"content"`
+	ReplacedAt time.Time `bson:"replaced_at"`
 }
 
 func toDomainComment(m commentModel) domain.Comment {
-    return domain.Comment{
-        ID:        m.ID.Hex(),
-        BlogId:    m.BlogID,
-        UserId:    m.UserID,
-        Content:   m.Content,
-        CreatedAt: m.CreatedAt.Format(time.RFC3339),
-    }
+	ancestors := m.Ancestors
+	if ancestors == nil {
+		ancestors = []string{}
+	}
+	status := m.Status
+	if status == "" {
+		// Comments stored before moderation existed were all public.
+		status = domain.CommentStatusApproved
+	}
+	var edits []domain.CommentEdit
+	for _, e := range m.Edits {
+		edits = append(edits, domain.CommentEdit{Content: e.Content, ReplacedAt: e.ReplacedAt})
+	}
+	return domain.Comment{
+		ID:          m.ID.Hex(),
+		BlogId:      m.BlogID,
+		UserId:      m.UserID,
+		ParentID:    m.ParentID,
+		Ancestors:   ancestors,
+		Depth:       m.Depth,
+		Content:     m.Content,
+		Status:      status,
+		ModeratedBy: m.ModeratedBy,
+		ModeratedAt: m.ModeratedAt,
+		ReplyCount:  m.ReplyCount,
+		Score:       m.Score,
+		CreatedAt:   m.CreatedAt,
+		EditedAt:    m.EditedAt,
+		Deleted:     m.Deleted,
+		DeletedAt:   m.DeletedAt,
+		DeletedBy:   m.DeletedBy,
+		Edits:       edits,
+	}
 }
 
+// commentsCollection is also read by the blog repository's count
+// reconciliation.
+const commentsCollection = "comments"
+
 type CommentRepository struct {
-    collection *mongo.Collection
+	collection *mongo.Collection
 }
 
 func NewCommentRepository(db *mongo.Database) domain.ICommentRepository {
-    return &CommentRepository{collection: db.Collection("comments")}
+	collection := db.Collection(commentsCollection)
+	indexModels := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "blog_id", Value: 1}, {Key: "parent_id", Value: 1}, {Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "blog_id", Value: 1}, {Key: "parent_id", Value: 1}, {Key: "score", Value: -1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "ancestors", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "blog_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		// Count reconciliation looks for recently commented blogs.
+		{Keys: bson.D{{Key: "created_at", Value: 1}}},
+	}
+	ensureIndexes(collection, indexModels)
+
+	// Comments used to be saved with created_at as an RFC 3339 string.
+	Migrate(db, "comments.created_at_date", func(ctx context.Context) error {
+		_, err := collection.UpdateMany(ctx,
+			bson.M{"created_at": bson.M{"$type": "string"}},
+			mongo.Pipeline{{{Key: "$set", Value: bson.M{"created_at": bson.M{"$toDate": "$created_at"}}}}},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to convert created_at to dates: %w", err)
+		}
+		return nil
+	})
+
+	return &CommentRepository{collection: collection}
 }
 
+// Create stores comment and, for an approved reply, counts it on its parent
+// and on the score of every ancestor.
 func (r *CommentRepository) Create(ctx context.Context, comment *domain.Comment) (*domain.Comment, error) {
-    objID := primitive.NewObjectID()
-    comment.ID = objID.Hex()
-    doc := bson.M{
-        "_id":        objID,
-        "blog_id":    comment.BlogId,
-        "user_id":    comment.UserId,
-        "content":    comment.Content,
-        "created_at": comment.CreatedAt,
-    }
-    _, err := r.collection.InsertOne(ctx, doc)
-    if err != nil {
-        return nil, fmt.Errorf("failed to insert comment: %w", err)
-    }
-    return comment, nil
-}
-
-func (r *CommentRepository) GetByBlogID(ctx context.Context, blogID string) ([]domain.Comment, error) {
-    filter := bson.M{"blog_id": blogID}
-    cursor, err := r.collection.Find(ctx, filter)
-    if err != nil {
-        return nil, fmt.Errorf("failed to fetch comments: %w", err)
-    }
-    defer cursor.Close(ctx)
-
-    var results []commentModel
-    if err := cursor.All(ctx, &results); err != nil {
-        return nil, fmt.Errorf("failed to decode comments: %w", err)
-    }
-
-    var comments []domain.Comment
-    for _, c := range results {
-        comments = append(comments, toDomainComment(c))
-    }
-    return comments, nil
-}
-
-func (r *CommentRepository) Delete(ctx context.Context, commentID, userID string) error {
-    objID, err := primitive.ObjectIDFromHex(commentID)
-    if err != nil {
-        return fmt.Errorf("invalid comment ID: %w", err)
-    }
-    filter := bson.M{"_id": objID, "user_id": userID}
-    res, err := r.collection.DeleteOne(ctx, filter)
-    if err != nil {
-        return fmt.Errorf("failed to delete comment: %w", err)
-    }
-    if res.DeletedCount == 0 {
-        return fmt.Errorf("comment not found or not owned by user")
-    }
-    return nil
-}
\ No newline at end of file
+	objID := primitive.NewObjectID()
+	_, err := r.collection.InsertOne(ctx, commentModel{
+		ID:        objID,
+		BlogID:    comment.BlogId,
+		UserID:    comment.UserId,
+		ParentID:  comment.ParentID,
+		Ancestors: comment.Ancestors,
+		Depth:     comment.Depth,
+		Content:   comment.Content,
+		Status:    comment.Status,
+		CreatedAt: comment.CreatedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert comment: %w", err)
+	}
+	comment.ID = objID.Hex()
+
+	if comment.ParentID != "" && comment.Status == domain.CommentStatusApproved {
+		if err := r.adjustAncestors(ctx, comment.ParentID, comment.Ancestors, 1, 1); err != nil {
+			return nil, err
+		}
+	}
+	return comment, nil
+}
+
+func (r *CommentRepository) FindByID(ctx context.Context, commentID string) (*domain.Comment, error) {
+	objID, err := primitive.ObjectIDFromHex(commentID)
+	if err != nil {
+		return nil, domain.ErrCommentNotFound
+	}
+	var model commentModel
+	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&model)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrCommentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find comment: %w", err)
+	}
+	comment := toDomainComment(model)
+	return &comment, nil
+}
+
+func (r *CommentRepository) List(ctx context.Context, query domain.CommentQuery) ([]domain.Comment, int64, error) {
+	filter := audienceFilter(query.Audience)
+	filter["blog_id"] = query.BlogID
+	filter["parent_id"] = nil
+	if query.ParentID != "" {
+		filter["parent_id"] = query.ParentID
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count comments: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(commentSort(query.Sort)).
+		SetSkip(int64((query.Page - 1) * query.Limit)).
+		SetLimit(int64(query.Limit))
+	comments, err := r.find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+func (r *CommentRepository) Descendants(ctx context.Context, commentID string, audience domain.CommentAudience, limit int) ([]domain.Comment, error) {
+	filter := audienceFilter(audience)
+	filter["ancestors"] = commentID
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+	return r.find(ctx, filter, opts)
+}
+
+func (r *CommentRepository) ListPending(ctx context.Context, blogID string, page, limit int) ([]domain.Comment, int64, error) {
+	filter := bson.M{"status": domain.CommentStatusPending}
+	if blogID != "" {
+		filter["blog_id"] = blogID
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count pending comments: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	comments, err := r.find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+func (r *CommentRepository) SetStatus(ctx context.Context, commentID string, status domain.CommentStatus, moderatorID string, at time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(commentID)
+	if err != nil {
+		return domain.ErrCommentNotFound
+	}
+	var model commentModel
+	err = r.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": objID, "status": domain.CommentStatusPending},
+		bson.M{"$set": bson.M{"status": status, "moderated_by": moderatorID, "moderated_at": at}},
+	).Decode(&model)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.ErrCommentNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to moderate comment: %w", err)
+	}
+
+	if status == domain.CommentStatusApproved && model.ParentID != "" {
+		return r.adjustAncestors(ctx, model.ParentID, model.Ancestors, 1, 1)
+	}
+	return nil
+}
+
+func (r *CommentRepository) UpdateContent(ctx context.Context, commentID, content string, editedAt time.Time) (*domain.Comment, error) {
+	objID, err := primitive.ObjectIDFromHex(commentID)
+	if err != nil {
+		return nil, domain.ErrCommentNotFound
+	}
+	// $literal keeps content starting with "$" from reading as a field path.
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"edits":     appendEdit(editedAt),
+		"content":   bson.M{"$literal": content},
+		"edited_at": editedAt,
+	}}}}
+	var model commentModel
+	err = r.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": objID, "deleted": bson.M{"$ne": true}},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&model)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrCommentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+	comment := toDomainComment(model)
+	return &comment, nil
+}
+
+func (r *CommentRepository) Tombstone(ctx context.Context, commentID, deletedBy string, deletedAt time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(commentID)
+	if err != nil {
+		return domain.ErrCommentNotFound
+	}
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"edits":      appendEdit(deletedAt),
+		"content":    "",
+		"deleted":    true,
+		"deleted_at": deletedAt,
+		"deleted_by": bson.M{"$literal": deletedBy},
+	}}}}
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": objID, "deleted": bson.M{"$ne": true}}, update)
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrCommentNotFound
+	}
+	return nil
+}
+
+func (r *CommentRepository) Untombstone(ctx context.Context, commentID string) (*domain.Comment, error) {
+	objID, err := primitive.ObjectIDFromHex(commentID)
+	if err != nil {
+		return nil, domain.ErrCommentNotFound
+	}
+	remaining := bson.M{"$subtract": bson.A{bson.M{"$size": "$edits"}, 1}}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"content": bson.M{"$arrayElemAt": bson.A{"$edits.content", -1}},
+			"edits": bson.M{"$cond": bson.A{
+				bson.M{"$gt": bson.A{remaining, 0}},
+				bson.M{"$slice": bson.A{"$edits", remaining}},
+				"$$REMOVE",
+			}},
+		}}},
+		{{Key: "$unset", Value: bson.A{"deleted", "deleted_at", "deleted_by"}}},
+	}
+	filter := bson.M{"_id": objID, "deleted": true, "edits.0": bson.M{"$exists": true}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var model commentModel
+	err = r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&model)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrCommentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore comment: %w", err)
+	}
+	comment := toDomainComment(model)
+	return &comment, nil
+}
+
+// appendEdit is an aggregation expression for the edits array with the
+// current content added, replaced at the given time.
+func appendEdit(replacedAt time.Time) bson.M {
+	return bson.M{"$concatArrays": bson.A{
+		bson.M{"$ifNull": bson.A{"$edits", bson.A{}}},
+		bson.A{bson.M{"content": "$content", "replaced_at": replacedAt}},
+	}}
+}
+
+func (r *CommentRepository) Delete(ctx context.Context, commentID string) error {
+	objID, err := primitive.ObjectIDFromHex(commentID)
+	if err != nil {
+		return domain.ErrCommentNotFound
+	}
+	var model commentModel
+	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&model)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.ErrCommentNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find comment: %w", err)
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"$or": bson.A{
+		bson.M{"_id": objID},
+		bson.M{"ancestors": commentID},
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+	// Only approved comments are counted, and the score of the comment
+	// already counts the approved replies going with it.
+	if model.ParentID != "" && (model.Status == "" || model.Status == domain.CommentStatusApproved) {
+		return r.adjustAncestors(ctx, model.ParentID, model.Ancestors, -1, -(model.Score + 1))
+	}
+	return nil
+}
+
+func (r *CommentRepository) HasReplies(ctx context.Context, commentID string) (bool, error) {
+	err := r.collection.FindOne(ctx,
+		bson.M{"parent_id": commentID, "status": bson.M{"$ne": domain.CommentStatusRejected}},
+		options.FindOne().SetProjection(bson.M{"_id": 1}),
+	).Err()
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to look up replies: %w", err)
+	}
+	return true, nil
+}
+
+// adjustAncestors changes the reply count of parentID by replies and the
+// score of every ancestor by score.
+func (r *CommentRepository) adjustAncestors(ctx context.Context, parentID string, ancestors []string, replies, score int) error {
+	parentObjID, err := primitive.ObjectIDFromHex(parentID)
+	if err != nil {
+		return fmt.Errorf("invalid parent comment ID: %w", err)
+	}
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": parentObjID}, bson.M{"$inc": bson.M{"reply_count": replies}}); err != nil {
+		return fmt.Errorf("failed to update reply count: %w", err)
+	}
+
+	ids := make(bson.A, 0, len(ancestors))
+	for _, id := range ancestors {
+		if objID, err := primitive.ObjectIDFromHex(id); err == nil {
+			ids = append(ids, objID)
+		}
+	}
+	if _, err := r.collection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, bson.M{"$inc": bson.M{"score": score}}); err != nil {
+		return fmt.Errorf("failed to update comment scores: %w", err)
+	}
+	return nil
+}
+
+func (r *CommentRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]domain.Comment, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch comments: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []commentModel
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode comments: %w", err)
+	}
+
+	comments := make([]domain.Comment, 0, len(results))
+	for _, c := range results {
+		comments = append(comments, toDomainComment(c))
+	}
+	return comments, nil
+}
+
+// audienceFilter restricts a query to the comments audience may see.
+func audienceFilter(audience domain.CommentAudience) bson.M {
+	if audience.Moderator {
+		return bson.M{"status": bson.M{"$ne": domain.CommentStatusRejected}}
+	}
+	approved := bson.M{"status": bson.M{"$in": bson.A{domain.CommentStatusApproved, nil}}}
+	if audience.UserID == "" {
+		return approved
+	}
+	return bson.M{"$or": bson.A{
+		approved,
+		bson.M{"status": domain.CommentStatusPending, "user_id": audience.UserID},
+	}}
+}
+
+// commentSort orders comments with _id as the final tie-breaker, so pages
+// never overlap.
+func commentSort(sort domain.CommentSort) bson.D {
+	switch sort {
+	case domain.CommentSortNewest:
+		return bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}
+	case domain.CommentSortTop:
+		return bson.D{{Key: "score", Value: -1}, {Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}
+	default:
+		return bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}
+	}
+}
@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestHasReplies(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	tests := []struct {
+		name  string
+		reply bson.D
+		want  bool
+	}{
+		{name: "pending reply", reply: bson.D{{Key: "_id", Value: primitive.NewObjectID()}}, want: true},
+		{name: "no reply"},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			r := &CommentRepository{collection: mt.Coll}
+			batch := bson.A{}
+			if tt.reply != nil {
+				batch = append(batch, tt.reply)
+			}
+			mt.AddMockResponses(bson.D{
+				{Key: "ok", Value: 1},
+				{Key: "cursor", Value: bson.D{{Key: "id", Value: int64(0)}, {Key: "ns", Value: "test.comments"}, {Key: "firstBatch", Value: batch}}},
+			})
+
+			got, err := r.HasReplies(context.Background(), "c1")
+			if err != nil || got != tt.want {
+				mt.Fatalf("HasReplies = %v, %v, want %v", got, err, tt.want)
+			}
+			filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+			if parent := filter.Lookup("parent_id").StringValue(); parent != "c1" {
+				mt.Errorf("looks for replies to %q, want c1", parent)
+			}
+			if excluded := filter.Lookup("status", "$ne").StringValue(); excluded != string(domain.CommentStatusRejected) {
+				mt.Errorf("leaves out %q replies, want rejected", excluded)
+			}
+		})
+	}
+}
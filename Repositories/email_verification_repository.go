@@ -86,6 +86,31 @@ func (r *emailVerificationRepo) GetByEmail(ctx context.Context, email string) (*
 	}, nil
 }
 
+func (r *emailVerificationRepo) GetByID(ctx context.Context, id string) (*domain.EmailVerification, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.ErrVerificationNotFound
+	}
+
+	var doc emailVerificationModel
+	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrVerificationNotFound
+		}
+		return nil, fmt.Errorf("failed to find email verification: %w", err)
+	}
+
+	return &domain.EmailVerification{
+		ID:        doc.ID.Hex(),
+		Email:     doc.Email,
+		OTP:       doc.OTP,
+		ExpiresAt: doc.ExpiresAt,
+		CreatedAt: doc.CreatedAt,
+		Used:      doc.Used,
+	}, nil
+}
+
 func (r *emailVerificationRepo) GetByOTP(ctx context.Context, otp, email string) (*domain.EmailVerification, error) {
 	var doc emailVerificationModel
 	
@@ -0,0 +1,188 @@
+package repositories
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type followModel struct {
+	FollowerID string    `bson:"follower_id"`
+	FolloweeID string    `bson:"followee_id"`
+	CreatedAt  time.Time `bson:"createdAt"`
+}
+
+type tagFollowModel struct {
+	UserID    string    `bson:"user_id"`
+	Tag       string    `bson:"tag"`
+	CreatedAt time.Time `bson:"createdAt"`
+}
+
+type followRepository struct {
+	followCollection    *mongo.Collection
+	tagFollowCollection *mongo.Collection
+}
+
+func NewFollowRepository(db *mongo.Database) domain.IFollowRepository {
+	follows := db.Collection("follows")
+	ensureIndexes(follows, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "follower_id", Value: 1}, {Key: "followee_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "followee_id", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "follower_id", Value: 1}, {Key: "createdAt", Value: -1}}},
+	})
+
+	tagFollows := db.Collection("tag_follows")
+	ensureIndexes(tagFollows, []mongo.IndexModel{{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "tag", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}})
+
+	return &followRepository{followCollection: follows, tagFollowCollection: tagFollows}
+}
+
+func (r *followRepository) Follow(ctx context.Context, followerID, followeeID string, at time.Time) (bool, error) {
+	result, err := r.followCollection.UpdateOne(ctx,
+		bson.M{"follower_id": followerID, "followee_id": followeeID},
+		bson.M{"$setOnInsert": followModel{FollowerID: followerID, FolloweeID: followeeID, CreatedAt: at}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil // a concurrent request got there first
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to follow user: %w", err)
+	}
+	return result.UpsertedCount > 0, nil
+}
+
+func (r *followRepository) Unfollow(ctx context.Context, followerID, followeeID string) (bool, error) {
+	result, err := r.followCollection.DeleteOne(ctx, bson.M{"follower_id": followerID, "followee_id": followeeID})
+	if err != nil {
+		return false, fmt.Errorf("failed to unfollow user: %w", err)
+	}
+	return result.DeletedCount > 0, nil
+}
+
+func (r *followRepository) IsFollowing(ctx context.Context, followerID, followeeID string) (bool, error) {
+	count, err := r.followCollection.CountDocuments(ctx, bson.M{"follower_id": followerID, "followee_id": followeeID})
+	if err != nil {
+		return false, fmt.Errorf("failed to check follow: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (r *followRepository) ListFollowers(ctx context.Context, userID string, page, limit int) ([]domain.Follow, int64, error) {
+	return r.list(ctx, bson.M{"followee_id": userID}, page, limit)
+}
+
+func (r *followRepository) ListFollowing(ctx context.Context, userID string, page, limit int) ([]domain.Follow, int64, error) {
+	return r.list(ctx, bson.M{"follower_id": userID}, page, limit)
+}
+
+func (r *followRepository) list(ctx context.Context, filter bson.M, page, limit int) ([]domain.Follow, int64, error) {
+	total, err := r.followCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count follows: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := r.followCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list follows: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var models []followModel
+	if err := cursor.All(ctx, &models); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode follows: %w", err)
+	}
+	follows := make([]domain.Follow, 0, len(models))
+	for _, m := range models {
+		follows = append(follows, domain.Follow{FollowerID: m.FollowerID, FolloweeID: m.FolloweeID, CreatedAt: m.CreatedAt})
+	}
+	return follows, total, nil
+}
+
+func (r *followRepository) CountFollowers(ctx context.Context, userID string) (int64, error) {
+	count, err := r.followCollection.CountDocuments(ctx, bson.M{"followee_id": userID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count followers: %w", err)
+	}
+	return count, nil
+}
+
+func (r *followRepository) CountFollowing(ctx context.Context, userID string) (int64, error) {
+	count, err := r.followCollection.CountDocuments(ctx, bson.M{"follower_id": userID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count following: %w", err)
+	}
+	return count, nil
+}
+
+func (r *followRepository) FolloweeIDs(ctx context.Context, followerID string) ([]string, error) {
+	values, err := r.followCollection.Distinct(ctx, "followee_id", bson.M{"follower_id": followerID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list followed users: %w", err)
+	}
+	return stringValues(values), nil
+}
+
+func (r *followRepository) FollowTag(ctx context.Context, userID, tag string, at time.Time) (bool, error) {
+	result, err := r.tagFollowCollection.UpdateOne(ctx,
+		bson.M{"user_id": userID, "tag": tag},
+		bson.M{"$setOnInsert": tagFollowModel{UserID: userID, Tag: tag, CreatedAt: at}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to follow tag: %w", err)
+	}
+	return result.UpsertedCount > 0, nil
+}
+
+func (r *followRepository) UnfollowTag(ctx context.Context, userID, tag string) (bool, error) {
+	result, err := r.tagFollowCollection.DeleteOne(ctx, bson.M{"user_id": userID, "tag": tag})
+	if err != nil {
+		return false, fmt.Errorf("failed to unfollow tag: %w", err)
+	}
+	return result.DeletedCount > 0, nil
+}
+
+func (r *followRepository) FollowedTags(ctx context.Context, userID string) ([]string, error) {
+	values, err := r.tagFollowCollection.Distinct(ctx, "tag", bson.M{"user_id": userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list followed tags: %w", err)
+	}
+	return stringValues(values), nil
+}
+
+func (r *followRepository) RemoveTagFollows(ctx context.Context, tags []string) (int64, error) {
+	result, err := r.tagFollowCollection.DeleteMany(ctx, bson.M{"tag": bson.M{"$in": tags}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove tag follows: %w", err)
+	}
+	return result.DeletedCount, nil
+}
+
+func stringValues(values []interface{}) []string {
+	strs := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// migrationsCollection records the data migrations that have been applied.
+const migrationsCollection = "migrations"
+
+// ensureIndexes creates the indexes of a collection at startup. A failure is
+// logged rather than fatal: the queries still work, if slowly, and the next
+// start tries again.
+func ensureIndexes(collection *mongo.Collection, models []mongo.IndexModel) {
+	if _, err := collection.Indexes().CreateMany(context.Background(), models); err != nil {
+		log.Printf("warning: failed to create indexes on %s: %v", collection.Name(), err)
+	}
+}
+
+// Migrate runs the data migration called name unless it was applied before,
+// and records it once run succeeds. A failed migration is logged and tried
+// again on the next start. Instances starting together may both run it, so
+// run must be safe to repeat. Migrations that need more than a repository,
+// such as the slug backfill, are run from main.
+func Migrate(db *mongo.Database, name string, run func(ctx context.Context) error) {
+	if err := applyMigration(context.Background(), db, name, run); err != nil {
+		log.Printf("warning: migration %s: %v", name, err)
+	}
+}
+
+func applyMigration(ctx context.Context, db *mongo.Database, name string, run func(ctx context.Context) error) error {
+	migrations := db.Collection(migrationsCollection)
+	err := migrations.FindOne(ctx, bson.M{"_id": name}).Err()
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("failed to look up migration: %w", err)
+	}
+
+	if err := run(ctx); err != nil {
+		return err
+	}
+	_, err = migrations.InsertOne(ctx, bson.M{"_id": name, "applied_at": time.Now()})
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("failed to record migration: %w", err)
+	}
+	log.Printf("applied migration %s", name)
+	return nil
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestApplyMigration(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	found := func(docs ...bson.D) bson.D {
+		batch := bson.A{}
+		for _, doc := range docs {
+			batch = append(batch, doc)
+		}
+		return bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "cursor", Value: bson.D{{Key: "id", Value: int64(0)}, {Key: "ns", Value: "test.migrations"}, {Key: "firstBatch", Value: batch}}},
+		}
+	}
+	tests := []struct {
+		name         string
+		responses    []bson.D
+		runErr       error
+		wantRun      bool
+		wantErr      bool
+		wantRecorded bool
+	}{
+		{
+			name:      "already applied",
+			responses: []bson.D{found(bson.D{{Key: "_id", Value: "blogs.published_at"}})},
+		},
+		{
+			name:         "first start",
+			responses:    []bson.D{found(), {{Key: "ok", Value: 1}, {Key: "n", Value: 1}}},
+			wantRun:      true,
+			wantRecorded: true,
+		},
+		{
+			name:      "migration fails",
+			responses: []bson.D{found()},
+			runErr:    errors.New("disk full"),
+			wantRun:   true,
+			wantErr:   true,
+		},
+		{
+			name:      "lookup fails",
+			responses: []bson.D{{{Key: "ok", Value: 0}, {Key: "code", Value: 13}, {Key: "errmsg", Value: "unauthorized"}}},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			mt.AddMockResponses(tt.responses...)
+			ran := false
+			err := applyMigration(context.Background(), mt.DB, "blogs.published_at", func(context.Context) error {
+				ran = true
+				return tt.runErr
+			})
+			if (err != nil) != tt.wantErr {
+				mt.Fatalf("applyMigration: %v, want error %v", err, tt.wantErr)
+			}
+			if ran != tt.wantRun {
+				mt.Errorf("ran = %v, want %v", ran, tt.wantRun)
+			}
+			recorded := false
+			for _, event := range mt.GetAllStartedEvents() {
+				if event.CommandName == "insert" && event.Command.Lookup("insert").StringValue() == migrationsCollection {
+					recorded = true
+				}
+			}
+			if recorded != tt.wantRecorded {
+				mt.Errorf("recorded = %v, want %v", recorded, tt.wantRecorded)
+			}
+		})
+	}
+}
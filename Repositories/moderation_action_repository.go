@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type moderationActionModel struct {
+	ID           primitive.ObjectID          `bson:"_id"`
+	ActorID      string                      `bson:"actor_id"`
+	Action       domain.ModerationActionType `bson:"action"`
+	TargetType   domain.ReportTargetType     `bson:"target_type"`
+	TargetID     string                      `bson:"target_id"`
+	TargetUserID string                      `bson:"target_user_id"`
+	ReportID     string                      `bson:"report_id,omitempty"`
+	Note         string                      `bson:"note,omitempty"`
+	Until        *time.Time                  `bson:"until,omitempty"`
+	CreatedAt    time.Time                   `bson:"createdAt"`
+}
+
+func toDomainModerationAction(m moderationActionModel) domain.ModerationAction {
+	return domain.ModerationAction{
+		ID:           m.ID.Hex(),
+		ActorID:      m.ActorID,
+		Action:       m.Action,
+		TargetType:   m.TargetType,
+		TargetID:     m.TargetID,
+		TargetUserID: m.TargetUserID,
+		ReportID:     m.ReportID,
+		Note:         m.Note,
+		Until:        m.Until,
+		CreatedAt:    m.CreatedAt,
+	}
+}
+
+type moderationActionRepository struct {
+	actionCollection *mongo.Collection
+}
+
+func NewModerationActionRepository(db *mongo.Database) domain.IModerationActionRepository {
+	collection := db.Collection("moderation_actions")
+	indexModels := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "target_user_id", Value: 1}, {Key: "createdAt", Value: -1}}},
+	}
+	ensureIndexes(collection, indexModels)
+
+	return &moderationActionRepository{actionCollection: collection}
+}
+
+func (r *moderationActionRepository) Create(ctx context.Context, action *domain.ModerationAction) error {
+	id := primitive.NewObjectID()
+	_, err := r.actionCollection.InsertOne(ctx, moderationActionModel{
+		ID:           id,
+		ActorID:      action.ActorID,
+		Action:       action.Action,
+		TargetType:   action.TargetType,
+		TargetID:     action.TargetID,
+		TargetUserID: action.TargetUserID,
+		ReportID:     action.ReportID,
+		Note:         action.Note,
+		Until:        action.Until,
+		CreatedAt:    action.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record moderation action: %w", err)
+	}
+	action.ID = id.Hex()
+	return nil
+}
+
+func (r *moderationActionRepository) List(ctx context.Context, query domain.ModerationActionQuery) ([]domain.ModerationAction, int64, error) {
+	filter := bson.M{}
+	if query.TargetUserID != "" {
+		filter["target_user_id"] = query.TargetUserID
+	}
+
+	total, err := r.actionCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count moderation actions: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((query.Page - 1) * query.Limit)).
+		SetLimit(int64(query.Limit))
+	cursor, err := r.actionCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list moderation actions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var models []moderationActionModel
+	if err := cursor.All(ctx, &models); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode moderation actions: %w", err)
+	}
+	actions := make([]domain.ModerationAction, 0, len(models))
+	for _, m := range models {
+		actions = append(actions, toDomainModerationAction(m))
+	}
+	return actions, total, nil
+}
@@ -0,0 +1,235 @@
+package repositories
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxNotificationActors caps the actor IDs kept on a notification. An actor
+// who drops off the end and acts again is counted a second time.
+const maxNotificationActors = 100
+
+type notificationModel struct {
+	ID         primitive.ObjectID      `bson:"_id"`
+	UserID     string                  `bson:"user_id"`
+	Type       domain.NotificationType `bson:"type"`
+	GroupKey   string                  `bson:"group_key"`
+	BlogID     string                  `bson:"blog_id,omitempty"`
+	CommentID  string                  `bson:"comment_id,omitempty"`
+	Reaction   string                  `bson:"reaction,omitempty"`
+	Note       string                  `bson:"note,omitempty"`
+	ActorIDs   []string                `bson:"actor_ids"`
+	ActorCount int                     `bson:"actor_count"`
+	Read       bool                    `bson:"read"`
+	CreatedAt  time.Time               `bson:"createdAt"`
+	UpdatedAt  time.Time               `bson:"updatedAt"`
+}
+
+func toDomainNotification(m notificationModel) domain.Notification {
+	return domain.Notification{
+		ID:         m.ID.Hex(),
+		UserID:     m.UserID,
+		Type:       m.Type,
+		GroupKey:   m.GroupKey,
+		BlogID:     m.BlogID,
+		CommentID:  m.CommentID,
+		Reaction:   m.Reaction,
+		Note:       m.Note,
+		ActorIDs:   m.ActorIDs,
+		ActorCount: m.ActorCount,
+		Read:       m.Read,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
+
+type notificationPreferenceModel struct {
+	UserID string                    `bson:"_id"`
+	Muted  []domain.NotificationType `bson:"muted"`
+}
+
+type notificationRepository struct {
+	notificationCollection *mongo.Collection
+	preferenceCollection   *mongo.Collection
+}
+
+func NewNotificationRepository(db *mongo.Database) domain.INotificationRepository {
+	notifications := db.Collection("notifications")
+	ensureIndexes(notifications, []mongo.IndexModel{
+		{
+			// At most one unread notification per group.
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "group_key", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"read": false}),
+		},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "read", Value: 1}, {Key: "updatedAt", Value: -1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "updatedAt", Value: -1}}},
+	})
+
+	return &notificationRepository{
+		notificationCollection: notifications,
+		preferenceCollection:   db.Collection("notification_preferences"),
+	}
+}
+
+func (r *notificationRepository) Add(ctx context.Context, notification *domain.Notification, actorID string, at time.Time) (string, error) {
+	filter := bson.M{"user_id": notification.UserID, "group_key": notification.GroupKey, "read": false}
+	known := bson.M{"$in": bson.A{actorID, bson.M{"$ifNull": bson.A{"$actor_ids", bson.A{}}}}}
+	others := bson.M{"$filter": bson.M{
+		"input": bson.M{"$ifNull": bson.A{"$actor_ids", bson.A{}}},
+		"cond":  bson.M{"$ne": bson.A{"$$this", actorID}},
+	}}
+	// A pipeline update, so the actor can be moved to the front of the list
+	// and counted only when new.
+	update := bson.A{bson.M{"$set": bson.M{
+		"type":       notification.Type,
+		"blog_id":    notification.BlogID,
+		"comment_id": notification.CommentID,
+		"reaction":   notification.Reaction,
+		"note":       notification.Note,
+		"actor_ids":  bson.M{"$slice": bson.A{bson.M{"$concatArrays": bson.A{bson.A{actorID}, others}}, maxNotificationActors}},
+		"actor_count": bson.M{"$cond": bson.A{
+			known,
+			"$actor_count",
+			bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$actor_count", 0}}, 1}},
+		}},
+		"createdAt": bson.M{"$ifNull": bson.A{"$createdAt", at}},
+		"updatedAt": at,
+	}}}
+
+	opts := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.After).
+		SetProjection(bson.M{"_id": 1})
+
+	var saved struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	err := r.notificationCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&saved)
+	// Two events for the same group racing on the unique index: the loser
+	// joins the notification the winner created.
+	if mongo.IsDuplicateKeyError(err) {
+		err = r.notificationCollection.FindOneAndUpdate(ctx, filter, update, opts.SetUpsert(false)).Decode(&saved)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to save notification: %w", err)
+	}
+	return saved.ID.Hex(), nil
+}
+
+func (r *notificationRepository) List(ctx context.Context, userID string, unreadOnly bool, page, limit int) ([]domain.Notification, int64, error) {
+	filter := bson.M{"user_id": userID}
+	if unreadOnly {
+		filter["read"] = false
+	}
+	total, err := r.notificationCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count notifications: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "updatedAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := r.notificationCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var models []notificationModel
+	if err := cursor.All(ctx, &models); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode notifications: %w", err)
+	}
+	notifications := make([]domain.Notification, 0, len(models))
+	for _, m := range models {
+		notifications = append(notifications, toDomainNotification(m))
+	}
+	return notifications, total, nil
+}
+
+func (r *notificationRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
+	count, err := r.notificationCollection.CountDocuments(ctx, bson.M{"user_id": userID, "read": false})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count notifications: %w", err)
+	}
+	return count, nil
+}
+
+func (r *notificationRepository) MarkRead(ctx context.Context, userID, notificationID string) error {
+	objID, err := primitive.ObjectIDFromHex(notificationID)
+	if err != nil {
+		return domain.ErrNotificationNotFound
+	}
+	result, err := r.notificationCollection.UpdateOne(ctx,
+		bson.M{"_id": objID, "user_id": userID},
+		bson.M{"$set": bson.M{"read": true}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification read: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	result, err := r.notificationCollection.UpdateMany(ctx,
+		bson.M{"user_id": userID, "read": false},
+		bson.M{"$set": bson.M{"read": true}},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *notificationRepository) Delete(ctx context.Context, userID, notificationID string) error {
+	objID, err := primitive.ObjectIDFromHex(notificationID)
+	if err != nil {
+		return domain.ErrNotificationNotFound
+	}
+	result, err := r.notificationCollection.DeleteOne(ctx, bson.M{"_id": objID, "user_id": userID})
+	if err != nil {
+		return fmt.Errorf("failed to delete notification: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (r *notificationRepository) MutedTypes(ctx context.Context, userID string) ([]domain.NotificationType, error) {
+	var model notificationPreferenceModel
+	err := r.preferenceCollection.FindOne(ctx, bson.M{"_id": userID}).Decode(&model)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notification preferences: %w", err)
+	}
+	return model.Muted, nil
+}
+
+func (r *notificationRepository) SetMutedTypes(ctx context.Context, userID string, muted []domain.NotificationType) error {
+	if muted == nil {
+		muted = []domain.NotificationType{}
+	}
+	_, err := r.preferenceCollection.UpdateOne(ctx,
+		bson.M{"_id": userID},
+		bson.M{"$set": bson.M{"muted": muted}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package repositories
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// outboxRetention is how long delivered messages are kept. Dead ones stay
+// until an admin retries them.
+const outboxRetention = 7 * 24 * time.Hour
+
+type outboxModel struct {
+	ID            primitive.ObjectID  `bson:"_id"`
+	Topic         string              `bson:"topic"`
+	Payload       string              `bson:"payload,omitempty"`
+	ExpiresAt     *time.Time          `bson:"expires_at,omitempty"`
+	Status        domain.OutboxStatus `bson:"status"`
+	Attempts      int                 `bson:"attempts"`
+	NextAttemptAt *time.Time          `bson:"next_attempt_at,omitempty"`
+	LastError     string              `bson:"last_error,omitempty"`
+	CreatedAt     time.Time           `bson:"createdAt"`
+	UpdatedAt     time.Time           `bson:"updatedAt"`
+	DeliveredAt   *time.Time          `bson:"delivered_at,omitempty"`
+}
+
+func toDomainOutboxMessage(m outboxModel) domain.OutboxMessage {
+	return domain.OutboxMessage{
+		ID:            m.ID.Hex(),
+		Topic:         m.Topic,
+		Payload:       m.Payload,
+		ExpiresAt:     m.ExpiresAt,
+		Status:        m.Status,
+		Attempts:      m.Attempts,
+		NextAttemptAt: m.NextAttemptAt,
+		LastError:     m.LastError,
+		CreatedAt:     m.CreatedAt,
+		UpdatedAt:     m.UpdatedAt,
+		DeliveredAt:   m.DeliveredAt,
+	}
+}
+
+type outboxRepository struct {
+	outboxCollection *mongo.Collection
+}
+
+func NewOutboxRepository(db *mongo.Database) domain.IOutboxRepository {
+	collection := db.Collection("outbox")
+	ensureIndexes(collection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "topic", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{
+			// Only delivered messages have delivered_at, so only they expire.
+			Keys:    bson.D{{Key: "delivered_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(outboxRetention.Seconds())),
+		},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetSparse(true)},
+	})
+	return &outboxRepository{outboxCollection: collection}
+}
+
+func (r *outboxRepository) Add(ctx context.Context, message *domain.OutboxMessage) error {
+	model := outboxModel{
+		ID:            primitive.NewObjectID(),
+		Topic:         message.Topic,
+		Payload:       message.Payload,
+		ExpiresAt:     message.ExpiresAt,
+		Status:        message.Status,
+		NextAttemptAt: message.NextAttemptAt,
+		CreatedAt:     message.CreatedAt,
+		UpdatedAt:     message.UpdatedAt,
+	}
+	if _, err := r.outboxCollection.InsertOne(ctx, model); err != nil {
+		return fmt.Errorf("failed to add outbox message: %w", err)
+	}
+	message.ID = model.ID.Hex()
+	return nil
+}
+
+func (r *outboxRepository) FindByID(ctx context.Context, messageID string) (*domain.OutboxMessage, error) {
+	objID, err := primitive.ObjectIDFromHex(messageID)
+	if err != nil {
+		return nil, domain.ErrOutboxMessageNotFound
+	}
+	var model outboxModel
+	err = r.outboxCollection.FindOne(ctx, bson.M{"_id": objID}).Decode(&model)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrOutboxMessageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find outbox message: %w", err)
+	}
+	message := toDomainOutboxMessage(model)
+	return &message, nil
+}
+
+func (r *outboxRepository) List(ctx context.Context, status domain.OutboxStatus, topic string, page, limit int) ([]domain.OutboxMessage, int64, error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	if topic != "" {
+		filter["topic"] = topic
+	}
+	total, err := r.outboxCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count outbox messages: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"payload": 0})
+	cursor, err := r.outboxCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list outbox messages: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var models []outboxModel
+	if err := cursor.All(ctx, &models); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode outbox messages: %w", err)
+	}
+	messages := make([]domain.OutboxMessage, 0, len(models))
+	for _, m := range models {
+		messages = append(messages, toDomainOutboxMessage(m))
+	}
+	return messages, total, nil
+}
+
+func (r *outboxRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*domain.OutboxMessage, error) {
+	var model outboxModel
+	err := r.outboxCollection.FindOneAndUpdate(ctx,
+		bson.M{"status": domain.OutboxPending, "next_attempt_at": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"next_attempt_at": now.Add(lease)}},
+		options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+			SetReturnDocument(options.After),
+	).Decode(&model)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox message: %w", err)
+	}
+	message := toDomainOutboxMessage(model)
+	return &message, nil
+}
+
+func (r *outboxRepository) Save(ctx context.Context, message *domain.OutboxMessage) error {
+	objID, err := primitive.ObjectIDFromHex(message.ID)
+	if err != nil {
+		return domain.ErrOutboxMessageNotFound
+	}
+	set := bson.M{
+		"status":     message.Status,
+		"attempts":   message.Attempts,
+		"last_error": message.LastError,
+		"updatedAt":  message.UpdatedAt,
+	}
+	unset := bson.M{}
+	if message.Payload != "" {
+		set["payload"] = message.Payload
+	} else {
+		unset["payload"] = ""
+	}
+	if message.NextAttemptAt != nil {
+		set["next_attempt_at"] = message.NextAttemptAt
+	} else {
+		unset["next_attempt_at"] = ""
+	}
+	if message.DeliveredAt != nil {
+		set["delivered_at"] = message.DeliveredAt
+	} else {
+		unset["delivered_at"] = ""
+	}
+	update := bson.M{"$set": set, "$unset": unset}
+	result, err := r.outboxCollection.UpdateOne(ctx, bson.M{"_id": objID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to save outbox message: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrOutboxMessageNotFound
+	}
+	return nil
+}
+
+func (r *outboxRepository) ExpireDue(ctx context.Context, now time.Time) (int64, error) {
+	filter := bson.M{
+		"status":     bson.M{"$ne": domain.OutboxDelivered},
+		"expires_at": bson.M{"$lte": now},
+		"payload":    bson.M{"$exists": true},
+	}
+	// Dead messages keep the error that killed them.
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"status": domain.OutboxDead,
+			"last_error": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{"$status", domain.OutboxPending}},
+				"expired before it could be delivered",
+				"$last_error",
+			}},
+			"updatedAt": now,
+		}}},
+		{{Key: "$unset", Value: bson.A{"payload", "next_attempt_at"}}},
+	}
+	result, err := r.outboxCollection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire outbox messages: %w", err)
+	}
+	return result.ModifiedCount, nil
+}
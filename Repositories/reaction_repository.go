@@ -0,0 +1,233 @@
+package repositories
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type reactionModel struct {
+	TargetType domain.ReactionTargetType `bson:"target_type"`
+	TargetID   string                    `bson:"target_id"`
+	UserID     string                    `bson:"user_id"`
+	Type       string                    `bson:"type"`
+	CreatedAt  time.Time                 `bson:"createdAt"`
+	UpdatedAt  time.Time                 `bson:"updatedAt"`
+}
+
+// reactionsCollection is also read by the blog repository's count
+// reconciliation.
+const reactionsCollection = "reactions"
+
+type reactionRepository struct {
+	reactionCollection *mongo.Collection
+}
+
+func NewReactionRepository(db *mongo.Database) domain.IReactionRepository {
+	collection := db.Collection(reactionsCollection)
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}, {Key: "type", Value: 1}, {Key: "updatedAt", Value: -1}}},
+		// Count reconciliation looks for recently liked blogs.
+		{Keys: bson.D{{Key: "target_type", Value: 1}, {Key: "updatedAt", Value: 1}}},
+	}
+	ensureIndexes(collection, indexModels)
+
+	r := &reactionRepository{reactionCollection: collection}
+	r.migrateLikes(db.Collection("likes"))
+	return r
+}
+
+// migrateLikes moves blog likes from the old likes collection into
+// reactions, then drops it. A reaction the user has made since wins over
+// their old like.
+func (r *reactionRepository) migrateLikes(likes *mongo.Collection) {
+	ctx := context.Background()
+	cursor, err := likes.Find(ctx, bson.M{})
+	if err != nil {
+		log.Printf("warning: failed to read legacy likes: %v", err)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var legacy []struct {
+		BlogID    primitive.ObjectID `bson:"blogId"`
+		UserID    primitive.ObjectID `bson:"userId"`
+		CreatedAt time.Time          `bson:"createdAt"`
+	}
+	if err := cursor.All(ctx, &legacy); err != nil {
+		log.Printf("warning: failed to read legacy likes: %v", err)
+		return
+	}
+	if len(legacy) == 0 {
+		return
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(legacy))
+	for _, like := range legacy {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"target_type": domain.ReactionTargetBlog, "target_id": like.BlogID.Hex(), "user_id": like.UserID.Hex()}).
+			SetUpdate(bson.M{"$setOnInsert": reactionModel{
+				TargetType: domain.ReactionTargetBlog,
+				TargetID:   like.BlogID.Hex(),
+				UserID:     like.UserID.Hex(),
+				Type:       domain.ReactionLike,
+				CreatedAt:  like.CreatedAt,
+				UpdatedAt:  like.CreatedAt,
+			}}).
+			SetUpsert(true))
+	}
+	if _, err := r.reactionCollection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		log.Printf("warning: failed to migrate legacy likes: %v", err)
+		return
+	}
+	if err := likes.Drop(ctx); err != nil {
+		log.Printf("warning: failed to drop legacy likes: %v", err)
+		return
+	}
+	log.Printf("migrated %d likes to reactions", len(legacy))
+}
+
+func (r *reactionRepository) Set(ctx context.Context, targetType domain.ReactionTargetType, targetID, userID, reactionType string, at time.Time) (string, error) {
+	filter := bson.M{"target_type": targetType, "target_id": targetID, "user_id": userID}
+	update := bson.M{
+		"$set":         bson.M{"type": reactionType, "updatedAt": at},
+		"$setOnInsert": bson.M{"createdAt": at},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before)
+
+	var previous reactionModel
+	err := r.reactionCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&previous)
+	if mongo.IsDuplicateKeyError(err) {
+		// Lost an insert race with the same user; the document exists now.
+		err = r.reactionCollection.FindOneAndUpdate(ctx, filter, update, opts.SetUpsert(false)).Decode(&previous)
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to save reaction: %w", err)
+	}
+	return previous.Type, nil
+}
+
+func (r *reactionRepository) Remove(ctx context.Context, targetType domain.ReactionTargetType, targetID, userID, onlyType string) (string, error) {
+	filter := bson.M{"target_type": targetType, "target_id": targetID, "user_id": userID}
+	if onlyType != "" {
+		filter["type"] = onlyType
+	}
+	var removed reactionModel
+	err := r.reactionCollection.FindOneAndDelete(ctx, filter).Decode(&removed)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to remove reaction: %w", err)
+	}
+	return removed.Type, nil
+}
+
+func (r *reactionRepository) Find(ctx context.Context, targetType domain.ReactionTargetType, targetID, userID string) (string, error) {
+	var model reactionModel
+	err := r.reactionCollection.FindOne(ctx, bson.M{"target_type": targetType, "target_id": targetID, "user_id": userID}).Decode(&model)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to find reaction: %w", err)
+	}
+	return model.Type, nil
+}
+
+func (r *reactionRepository) Counts(ctx context.Context, targetType domain.ReactionTargetType, targetIDs []string) (map[string]map[string]int64, error) {
+	counts := make(map[string]map[string]int64, len(targetIDs))
+	if len(targetIDs) == 0 {
+		return counts, nil
+	}
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{"target_type": targetType, "target_id": bson.M{"$in": targetIDs}}},
+		bson.M{"$group": bson.M{
+			"_id":   bson.M{"target_id": "$target_id", "type": "$type"},
+			"count": bson.M{"$sum": 1},
+		}},
+	}
+	cursor, err := r.reactionCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count reactions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		ID struct {
+			TargetID string `bson:"target_id"`
+			Type     string `bson:"type"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode reaction counts: %w", err)
+	}
+	for _, row := range rows {
+		if counts[row.ID.TargetID] == nil {
+			counts[row.ID.TargetID] = map[string]int64{}
+		}
+		counts[row.ID.TargetID][row.ID.Type] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *reactionRepository) List(ctx context.Context, targetType domain.ReactionTargetType, targetID, reactionType string, page, limit int) ([]domain.Reaction, int64, error) {
+	filter := bson.M{"target_type": targetType, "target_id": targetID}
+	if reactionType != "" {
+		filter["type"] = reactionType
+	}
+	total, err := r.reactionCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count reactions: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "updatedAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := r.reactionCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list reactions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var models []reactionModel
+	if err := cursor.All(ctx, &models); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode reactions: %w", err)
+	}
+	reactions := make([]domain.Reaction, 0, len(models))
+	for _, m := range models {
+		reactions = append(reactions, domain.Reaction{
+			TargetType: m.TargetType,
+			TargetID:   m.TargetID,
+			UserID:     m.UserID,
+			Type:       m.Type,
+			CreatedAt:  m.CreatedAt,
+			UpdatedAt:  m.UpdatedAt,
+		})
+	}
+	return reactions, total, nil
+}
+
+func (r *reactionRepository) RemoveTarget(ctx context.Context, targetType domain.ReactionTargetType, targetID string) error {
+	if _, err := r.reactionCollection.DeleteMany(ctx, bson.M{"target_type": targetType, "target_id": targetID}); err != nil {
+		return fmt.Errorf("failed to remove reactions: %w", err)
+	}
+	return nil
+}
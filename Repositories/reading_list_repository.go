@@ -0,0 +1,253 @@
+package repositories
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type readingListModel struct {
+	ID          primitive.ObjectID     `bson:"_id"`
+	UserID      string                 `bson:"user_id"`
+	Name        string                 `bson:"name"`
+	Description string                 `bson:"description,omitempty"`
+	Public      bool                   `bson:"public"`
+	Items       []readingListItemModel `bson:"items"`
+	CreatedAt   time.Time              `bson:"createdAt"`
+	UpdatedAt   time.Time              `bson:"updatedAt"`
+}
+
+type readingListItemModel struct {
+	BlogID  string    `bson:"blog_id"`
+	AddedAt time.Time `bson:"added_at"`
+}
+
+func toDomainReadingList(m readingListModel) domain.ReadingList {
+	items := make([]domain.ReadingListItem, 0, len(m.Items))
+	for _, item := range m.Items {
+		items = append(items, domain.ReadingListItem{BlogID: item.BlogID, AddedAt: item.AddedAt})
+	}
+	return domain.ReadingList{
+		ID:          m.ID.Hex(),
+		UserID:      m.UserID,
+		Name:        m.Name,
+		Description: m.Description,
+		Public:      m.Public,
+		Items:       items,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+func toReadingListItemModels(items []domain.ReadingListItem) []readingListItemModel {
+	models := make([]readingListItemModel, 0, len(items))
+	for _, item := range items {
+		models = append(models, readingListItemModel{BlogID: item.BlogID, AddedAt: item.AddedAt})
+	}
+	return models
+}
+
+type readingListRepository struct {
+	listCollection *mongo.Collection
+}
+
+func NewReadingListRepository(db *mongo.Database) domain.IReadingListRepository {
+	collection := db.Collection("reading_lists")
+	indexModels := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "items.blog_id", Value: 1}}},
+	}
+	ensureIndexes(collection, indexModels)
+
+	return &readingListRepository{listCollection: collection}
+}
+
+func (r *readingListRepository) Create(ctx context.Context, list *domain.ReadingList) error {
+	id := primitive.NewObjectID()
+	_, err := r.listCollection.InsertOne(ctx, readingListModel{
+		ID:          id,
+		UserID:      list.UserID,
+		Name:        list.Name,
+		Description: list.Description,
+		Public:      list.Public,
+		Items:       toReadingListItemModels(list.Items),
+		CreatedAt:   list.CreatedAt,
+		UpdatedAt:   list.UpdatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create reading list: %w", err)
+	}
+	list.ID = id.Hex()
+	return nil
+}
+
+func (r *readingListRepository) FindByID(ctx context.Context, listID string) (*domain.ReadingList, error) {
+	objID, err := primitive.ObjectIDFromHex(listID)
+	if err != nil {
+		return nil, domain.ErrListNotFound
+	}
+	var model readingListModel
+	err = r.listCollection.FindOne(ctx, bson.M{"_id": objID}).Decode(&model)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrListNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find reading list: %w", err)
+	}
+	list := toDomainReadingList(model)
+	return &list, nil
+}
+
+func (r *readingListRepository) ListByUser(ctx context.Context, userID string, includePrivate bool) ([]domain.ReadingList, error) {
+	filter := bson.M{"user_id": userID}
+	if !includePrivate {
+		filter["public"] = true
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}})
+	cursor, err := r.listCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reading lists: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var models []readingListModel
+	if err := cursor.All(ctx, &models); err != nil {
+		return nil, fmt.Errorf("failed to decode reading lists: %w", err)
+	}
+	lists := make([]domain.ReadingList, 0, len(models))
+	for _, m := range models {
+		lists = append(lists, toDomainReadingList(m))
+	}
+	return lists, nil
+}
+
+func (r *readingListRepository) CountByUser(ctx context.Context, userID string) (int64, error) {
+	count, err := r.listCollection.CountDocuments(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count reading lists: %w", err)
+	}
+	return count, nil
+}
+
+func (r *readingListRepository) Update(ctx context.Context, list *domain.ReadingList) error {
+	return r.updateOne(ctx, list.ID, bson.M{"$set": bson.M{
+		"name":        list.Name,
+		"description": list.Description,
+		"public":      list.Public,
+		"updatedAt":   list.UpdatedAt,
+	}})
+}
+
+func (r *readingListRepository) Delete(ctx context.Context, listID string) error {
+	objID, err := primitive.ObjectIDFromHex(listID)
+	if err != nil {
+		return domain.ErrListNotFound
+	}
+	result, err := r.listCollection.DeleteOne(ctx, bson.M{"_id": objID})
+	if err != nil {
+		return fmt.Errorf("failed to delete reading list: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrListNotFound
+	}
+	return nil
+}
+
+func (r *readingListRepository) AddItem(ctx context.Context, listID string, item domain.ReadingListItem, position int) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(listID)
+	if err != nil {
+		return false, domain.ErrListNotFound
+	}
+	push := bson.M{"$each": bson.A{readingListItemModel{BlogID: item.BlogID, AddedAt: item.AddedAt}}}
+	if position >= 0 {
+		push["$position"] = position
+	}
+	// Matching on the blog not being listed yet keeps duplicates out even
+	// when two adds race.
+	result, err := r.listCollection.UpdateOne(ctx,
+		bson.M{"_id": objID, "items.blog_id": bson.M{"$ne": item.BlogID}},
+		bson.M{
+			"$push": bson.M{"items": push},
+			"$set":  bson.M{"updatedAt": item.AddedAt},
+		},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to add to reading list: %w", err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (r *readingListRepository) RemoveItem(ctx context.Context, listID, blogID string) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(listID)
+	if err != nil {
+		return false, domain.ErrListNotFound
+	}
+	result, err := r.listCollection.UpdateOne(ctx,
+		bson.M{"_id": objID, "items.blog_id": blogID},
+		bson.M{
+			"$pull": bson.M{"items": bson.M{"blog_id": blogID}},
+			"$set":  bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove from reading list: %w", err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+func (r *readingListRepository) ReorderItems(ctx context.Context, listID string, previous, items []domain.ReadingListItem) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(listID)
+	if err != nil {
+		return false, domain.ErrListNotFound
+	}
+	blogIDs := make([]string, len(previous))
+	for i, item := range previous {
+		blogIDs[i] = item.BlogID
+	}
+	// Matching on the current order keeps a reorder from undoing an add or
+	// remove that happened since the list was read.
+	result, err := r.listCollection.UpdateOne(ctx,
+		bson.M{"_id": objID, "$expr": bson.M{"$eq": bson.A{"$items.blog_id", blogIDs}}},
+		bson.M{"$set": bson.M{
+			"items":     toReadingListItemModels(items),
+			"updatedAt": time.Now(),
+		}},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to reorder reading list: %w", err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (r *readingListRepository) RemoveBlog(ctx context.Context, blogID string) error {
+	_, err := r.listCollection.UpdateMany(ctx,
+		bson.M{"items.blog_id": blogID},
+		bson.M{"$pull": bson.M{"items": bson.M{"blog_id": blogID}}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove blog from reading lists: %w", err)
+	}
+	return nil
+}
+
+func (r *readingListRepository) updateOne(ctx context.Context, listID string, update bson.M) error {
+	objID, err := primitive.ObjectIDFromHex(listID)
+	if err != nil {
+		return domain.ErrListNotFound
+	}
+	result, err := r.listCollection.UpdateOne(ctx, bson.M{"_id": objID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update reading list: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrListNotFound
+	}
+	return nil
+}
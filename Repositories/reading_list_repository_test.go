@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestReorderItems(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	added := time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC)
+	previous := []domain.ReadingListItem{{BlogID: "a", AddedAt: added}, {BlogID: "b", AddedAt: added}}
+	reordered := []domain.ReadingListItem{previous[1], previous[0]}
+	tests := []struct {
+		name    string
+		matched int32
+		want    bool
+	}{
+		{name: "list as it was read", matched: 1, want: true},
+		{name: "list changed since", matched: 0, want: false},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			r := &readingListRepository{listCollection: mt.Coll}
+			mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: tt.matched}, {Key: "nModified", Value: tt.matched}})
+
+			got, err := r.ReorderItems(context.Background(), primitive.NewObjectID().Hex(), previous, reordered)
+			if err != nil || got != tt.want {
+				mt.Fatalf("ReorderItems = %v, %v, want %v", got, err, tt.want)
+			}
+			update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+			expected := update.Lookup("q", "$expr", "$eq").Array()
+			if field := expected.Index(0).Value().StringValue(); field != "$items.blog_id" {
+				mt.Errorf("compares %q, want the blog IDs of the items", field)
+			}
+			order, _ := expected.Index(1).Value().Array().Values()
+			if len(order) != 2 || order[0].StringValue() != "a" || order[1].StringValue() != "b" {
+				mt.Errorf("expects order %v, want [a b]", order)
+			}
+			items, _ := update.Lookup("u", "$set", "items").Array().Values()
+			if len(items) != 2 || items[0].Document().Lookup("blog_id").StringValue() != "b" {
+				mt.Errorf("sets items %v, want b first", items)
+			}
+		})
+	}
+}
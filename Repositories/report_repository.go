@@ -0,0 +1,276 @@
+package repositories
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type reportModel struct {
+	ID           primitive.ObjectID          `bson:"_id"`
+	TargetType   domain.ReportTargetType     `bson:"target_type"`
+	TargetID     string                      `bson:"target_id"`
+	TargetUserID string                      `bson:"target_user_id"`
+	ReporterID   string                      `bson:"reporter_id"`
+	Reason       domain.ReportReason         `bson:"reason"`
+	Details      string                      `bson:"details,omitempty"`
+	Status       domain.ReportStatus         `bson:"status"`
+	Resolution   domain.ModerationActionType `bson:"resolution,omitempty"`
+	ResolvedBy   string                      `bson:"resolved_by,omitempty"`
+	ResolvedAt   *time.Time                  `bson:"resolved_at,omitempty"`
+	CreatedAt    time.Time                   `bson:"createdAt"`
+	UpdatedAt    time.Time                   `bson:"updatedAt"`
+}
+
+func toDomainReport(m reportModel) domain.Report {
+	return domain.Report{
+		ID:           m.ID.Hex(),
+		TargetType:   m.TargetType,
+		TargetID:     m.TargetID,
+		TargetUserID: m.TargetUserID,
+		ReporterID:   m.ReporterID,
+		Reason:       m.Reason,
+		Details:      m.Details,
+		Status:       m.Status,
+		Resolution:   m.Resolution,
+		ResolvedBy:   m.ResolvedBy,
+		ResolvedAt:   m.ResolvedAt,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	}
+}
+
+type reportRepository struct {
+	reportCollection *mongo.Collection
+}
+
+func NewReportRepository(db *mongo.Database) domain.IReportRepository {
+	collection := db.Collection("reports")
+	indexModels := []mongo.IndexModel{
+		{
+			// One open report per reporter and target.
+			Keys: bson.D{{Key: "reporter_id", Value: 1}, {Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"status": domain.ReportStatusOpen}),
+		},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}, {Key: "status", Value: 1}}},
+		{Keys: bson.D{{Key: "target_user_id", Value: 1}, {Key: "createdAt", Value: -1}}},
+	}
+	ensureIndexes(collection, indexModels)
+
+	return &reportRepository{reportCollection: collection}
+}
+
+func (r *reportRepository) Upsert(ctx context.Context, report *domain.Report) (bool, error) {
+	filter := bson.M{
+		"reporter_id": report.ReporterID,
+		"target_type": report.TargetType,
+		"target_id":   report.TargetID,
+		"status":      domain.ReportStatusOpen,
+	}
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"reason":    report.Reason,
+			"details":   report.Details,
+			"updatedAt": now,
+		},
+		"$setOnInsert": bson.M{
+			"_id":            primitive.NewObjectID(),
+			"target_user_id": report.TargetUserID,
+			"createdAt":      now,
+		},
+	}
+
+	result, err := r.reportCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	// Two identical reports racing on the unique index: the loser's report
+	// now exists, so update it instead.
+	if mongo.IsDuplicateKeyError(err) {
+		result, err = r.reportCollection.UpdateOne(ctx, filter, update)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to save report: %w", err)
+	}
+
+	var model reportModel
+	if err := r.reportCollection.FindOne(ctx, filter).Decode(&model); err != nil {
+		return false, fmt.Errorf("failed to load report: %w", err)
+	}
+	*report = toDomainReport(model)
+	return result.UpsertedCount > 0, nil
+}
+
+func (r *reportRepository) FindByID(ctx context.Context, reportID string) (*domain.Report, error) {
+	objID, err := primitive.ObjectIDFromHex(reportID)
+	if err != nil {
+		return nil, domain.ErrReportNotFound
+	}
+	var model reportModel
+	err = r.reportCollection.FindOne(ctx, bson.M{"_id": objID}).Decode(&model)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrReportNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find report: %w", err)
+	}
+	report := toDomainReport(model)
+	return &report, nil
+}
+
+func (r *reportRepository) List(ctx context.Context, query domain.ReportQuery) ([]domain.Report, int64, error) {
+	filter := bson.M{}
+	if query.Status != "" {
+		filter["status"] = query.Status
+	}
+	if query.TargetType != "" {
+		filter["target_type"] = query.TargetType
+	}
+	if query.TargetID != "" {
+		filter["target_id"] = query.TargetID
+	}
+	if query.TargetUserID != "" {
+		filter["target_user_id"] = query.TargetUserID
+	}
+	if query.Reason != "" {
+		filter["reason"] = query.Reason
+	}
+
+	total, err := r.reportCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count reports: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((query.Page - 1) * query.Limit)).
+		SetLimit(int64(query.Limit))
+	cursor, err := r.reportCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list reports: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var models []reportModel
+	if err := cursor.All(ctx, &models); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode reports: %w", err)
+	}
+	reports := make([]domain.Report, 0, len(models))
+	for _, m := range models {
+		reports = append(reports, toDomainReport(m))
+	}
+	return reports, total, nil
+}
+
+func (r *reportRepository) Counts(ctx context.Context) (*domain.ReportCounts, error) {
+	open := bson.M{"$match": bson.M{"status": domain.ReportStatusOpen}}
+	pipeline := bson.A{
+		bson.M{"$facet": bson.M{
+			"by_status":      bson.A{bson.M{"$group": bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}}},
+			"by_target_type": bson.A{open, bson.M{"$group": bson.M{"_id": "$target_type", "count": bson.M{"$sum": 1}}}},
+			"by_reason":      bson.A{open, bson.M{"$group": bson.M{"_id": "$reason", "count": bson.M{"$sum": 1}}}},
+		}},
+	}
+	cursor, err := r.reportCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count reports: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	type bucket struct {
+		ID    string `bson:"_id"`
+		Count int64  `bson:"count"`
+	}
+	var facets []struct {
+		ByStatus     []bucket `bson:"by_status"`
+		ByTargetType []bucket `bson:"by_target_type"`
+		ByReason     []bucket `bson:"by_reason"`
+	}
+	if err := cursor.All(ctx, &facets); err != nil {
+		return nil, fmt.Errorf("failed to decode report counts: %w", err)
+	}
+
+	counts := &domain.ReportCounts{
+		ByStatus:         map[domain.ReportStatus]int64{},
+		OpenByTargetType: map[domain.ReportTargetType]int64{},
+		OpenByReason:     map[domain.ReportReason]int64{},
+	}
+	if len(facets) == 0 {
+		return counts, nil
+	}
+	for _, b := range facets[0].ByStatus {
+		counts.ByStatus[domain.ReportStatus(b.ID)] = b.Count
+	}
+	for _, b := range facets[0].ByTargetType {
+		counts.OpenByTargetType[domain.ReportTargetType(b.ID)] = b.Count
+	}
+	for _, b := range facets[0].ByReason {
+		counts.OpenByReason[domain.ReportReason(b.ID)] = b.Count
+	}
+	return counts, nil
+}
+
+func (r *reportRepository) Close(ctx context.Context, reportID string, status domain.ReportStatus,
+	resolution domain.ModerationActionType, resolvedBy string, resolvedAt time.Time) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(reportID)
+	if err != nil {
+		return false, domain.ErrReportNotFound
+	}
+	result, err := r.reportCollection.UpdateOne(ctx,
+		bson.M{"_id": objID, "status": domain.ReportStatusOpen},
+		bson.M{"$set": bson.M{
+			"status":      status,
+			"resolution":  resolution,
+			"resolved_by": resolvedBy,
+			"resolved_at": resolvedAt,
+			"updatedAt":   resolvedAt,
+		}},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to close report: %w", err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+func (r *reportRepository) Reopen(ctx context.Context, reportID string) error {
+	objID, err := primitive.ObjectIDFromHex(reportID)
+	if err != nil {
+		return domain.ErrReportNotFound
+	}
+	_, err = r.reportCollection.UpdateOne(ctx,
+		bson.M{"_id": objID},
+		bson.M{
+			"$set":   bson.M{"status": domain.ReportStatusOpen, "updatedAt": time.Now()},
+			"$unset": bson.M{"resolution": "", "resolved_by": "", "resolved_at": ""},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reopen report: %w", err)
+	}
+	return nil
+}
+
+func (r *reportRepository) CloseTarget(ctx context.Context, targetType domain.ReportTargetType, targetID string, status domain.ReportStatus,
+	resolution domain.ModerationActionType, resolvedBy string, resolvedAt time.Time) (int64, error) {
+	result, err := r.reportCollection.UpdateMany(ctx,
+		bson.M{"target_type": targetType, "target_id": targetID, "status": domain.ReportStatusOpen},
+		bson.M{"$set": bson.M{
+			"status":      status,
+			"resolution":  resolution,
+			"resolved_by": resolvedBy,
+			"resolved_at": resolvedAt,
+			"updatedAt":   resolvedAt,
+		}},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to close reports: %w", err)
+	}
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestCloseReport(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	tests := []struct {
+		name     string
+		modified int32
+		want     bool
+	}{
+		{name: "open report", modified: 1, want: true},
+		{name: "closed by someone else", modified: 0, want: false},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			r := &reportRepository{reportCollection: mt.Coll}
+			mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: tt.modified}, {Key: "nModified", Value: tt.modified}})
+
+			id := primitive.NewObjectID()
+			got, err := r.Close(context.Background(), id.Hex(), domain.ReportStatusResolved, domain.ModerationWarn, "admin", time.Now())
+			if err != nil || got != tt.want {
+				mt.Fatalf("Close = %v, %v, want %v", got, err, tt.want)
+			}
+			filter := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("q").Document()
+			if status := filter.Lookup("status").StringValue(); status != string(domain.ReportStatusOpen) {
+				mt.Errorf("closes reports that are %q, want only open ones", status)
+			}
+			if got := filter.Lookup("_id").ObjectID(); got != id {
+				mt.Errorf("closes report %s, want %s", got.Hex(), id.Hex())
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package repositories
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type tagModel struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	Name        string             `bson:"name"`
+	Aliases     []string           `bson:"aliases"`
+	Description string             `bson:"description"`
+	CreatedAt   time.Time          `bson:"createdAt"`
+	UpdatedAt   time.Time          `bson:"updatedAt"`
+}
+
+func toDomainTag(m tagModel) domain.Tag {
+	aliases := m.Aliases
+	if aliases == nil {
+		aliases = []string{}
+	}
+	return domain.Tag{
+		ID:          m.ID.Hex(),
+		Name:        m.Name,
+		Aliases:     aliases,
+		Description: m.Description,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+type tagRepository struct {
+	tagCollection *mongo.Collection
+}
+
+func NewTagRepository(db *mongo.Database) domain.ITagRepository {
+	collection := db.Collection("tags")
+	indexModels := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "name", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "aliases", Value: 1}}},
+	}
+	ensureIndexes(collection, indexModels)
+
+	return &tagRepository{tagCollection: collection}
+}
+
+func (r *tagRepository) FindByName(ctx context.Context, name string) (*domain.Tag, error) {
+	var model tagModel
+	err := r.tagCollection.FindOne(ctx, bson.M{"$or": bson.A{
+		bson.M{"name": name},
+		bson.M{"aliases": name},
+	}}).Decode(&model)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrTagNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tag: %w", err)
+	}
+	tag := toDomainTag(model)
+	return &tag, nil
+}
+
+func (r *tagRepository) FindByNames(ctx context.Context, names []string) (map[string]*domain.Tag, error) {
+	found := map[string]*domain.Tag{}
+	if len(names) == 0 {
+		return found, nil
+	}
+
+	cursor, err := r.tagCollection.Find(ctx, bson.M{"$or": bson.A{
+		bson.M{"name": bson.M{"$in": names}},
+		bson.M{"aliases": bson.M{"$in": names}},
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tags: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var models []tagModel
+	if err := cursor.All(ctx, &models); err != nil {
+		return nil, fmt.Errorf("failed to decode tags: %w", err)
+	}
+
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+	for _, m := range models {
+		tag := toDomainTag(m)
+		for _, name := range append([]string{tag.Name}, tag.Aliases...) {
+			if wanted[name] {
+				found[name] = &tag
+			}
+		}
+	}
+	return found, nil
+}
+
+func (r *tagRepository) List(ctx context.Context) ([]domain.Tag, error) {
+	cursor, err := r.tagCollection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var models []tagModel
+	if err := cursor.All(ctx, &models); err != nil {
+		return nil, fmt.Errorf("failed to decode tags: %w", err)
+	}
+	tags := make([]domain.Tag, 0, len(models))
+	for _, m := range models {
+		tags = append(tags, toDomainTag(m))
+	}
+	return tags, nil
+}
+
+func (r *tagRepository) EnsureExists(ctx context.Context, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	now := time.Now()
+	writes := make([]mongo.WriteModel, 0, len(names))
+	for _, name := range names {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"name": name}).
+			SetUpdate(bson.M{"$setOnInsert": bson.M{
+				"name":        name,
+				"aliases":     bson.A{},
+				"description": "",
+				"createdAt":   now,
+				"updatedAt":   now,
+			}}).
+			SetUpsert(true))
+	}
+	_, err := r.tagCollection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	// Two writers creating the same new tag race on the unique index; the
+	// loser's tag exists either way.
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("failed to create tags: %w", err)
+	}
+	return nil
+}
+
+func (r *tagRepository) Create(ctx context.Context, tag *domain.Tag) error {
+	id := primitive.NewObjectID()
+	now := time.Now()
+	_, err := r.tagCollection.InsertOne(ctx, tagModel{
+		ID:          id,
+		Name:        tag.Name,
+		Aliases:     tag.Aliases,
+		Description: tag.Description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrTagExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create tag: %w", err)
+	}
+	tag.ID = id.Hex()
+	tag.CreatedAt, tag.UpdatedAt = now, now
+	return nil
+}
+
+func (r *tagRepository) Update(ctx context.Context, tag *domain.Tag) error {
+	objID, err := primitive.ObjectIDFromHex(tag.ID)
+	if err != nil {
+		return domain.ErrTagNotFound
+	}
+	aliases := tag.Aliases
+	if aliases == nil {
+		aliases = []string{}
+	}
+	tag.UpdatedAt = time.Now()
+	result, err := r.tagCollection.UpdateOne(ctx, bson.M{"_id": objID}, bson.M{"$set": bson.M{
+		"name":        tag.Name,
+		"aliases":     aliases,
+		"description": tag.Description,
+		"updatedAt":   tag.UpdatedAt,
+	}})
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrTagExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update tag: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrTagNotFound
+	}
+	return nil
+}
+
+func (r *tagRepository) Delete(ctx context.Context, tagID string) error {
+	objID, err := primitive.ObjectIDFromHex(tagID)
+	if err != nil {
+		return domain.ErrTagNotFound
+	}
+	result, err := r.tagCollection.DeleteOne(ctx, bson.M{"_id": objID})
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrTagNotFound
+	}
+	return nil
+}
@@ -23,6 +23,8 @@ type userModel struct {
 	Bio            string             `bson:"bio,omitempty"`
 	ProfilePicture string             `bson:"profile_picture,omitempty"`
 	ContactInfo    string             `bson:"contact_info,omitempty"`
+	Suspended      bool               `bson:"suspended,omitempty"`
+	SuspendedUntil *time.Time         `bson:"suspended_until,omitempty"`
 }
 
 func toDomainUser(m userModel) domain.User {
@@ -38,6 +40,8 @@ func toDomainUser(m userModel) domain.User {
 		Bio:            m.Bio,
 		ProfilePicture: m.ProfilePicture,
 		ContactInfo:    m.ContactInfo,
+		Suspended:      m.Suspended,
+		SuspendedUntil: m.SuspendedUntil,
 	}
 }
 
@@ -101,7 +105,7 @@ func (r *userRepository) GetByUsername(ctx context.Context, username string) (*d
 func (r *userRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, fmt.Errorf("invalid user ID format: %w", err)
+		return nil, fmt.Errorf("%w: invalid user ID format", domain.ErrUserNotFound)
 	}
 
 	var model userModel
@@ -117,6 +121,34 @@ func (r *userRepository) GetByID(ctx context.Context, id string) (*domain.User,
 	return &user, nil
 }
 
+func (r *userRepository) GetByIDs(ctx context.Context, ids []string) (map[string]domain.User, error) {
+	objectIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+			objectIDs = append(objectIDs, objectID)
+		}
+	}
+	users := make(map[string]domain.User, len(objectIDs))
+	if len(objectIDs) == 0 {
+		return users, nil
+	}
+
+	cursor, err := r.userCollection.Find(ctx, bson.M{"_id": bson.M{"$in": objectIDs}})
+	if err != nil {
+		return nil, fmt.Errorf("error finding users by ID: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var models []userModel
+	if err := cursor.All(ctx, &models); err != nil {
+		return nil, fmt.Errorf("error decoding users: %w", err)
+	}
+	for _, m := range models {
+		users[m.ID.Hex()] = toDomainUser(m)
+	}
+	return users, nil
+}
+
 func (r *userRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
 	count, err := r.userCollection.CountDocuments(ctx, bson.M{"email": email})
 	if err != nil {
@@ -211,3 +243,35 @@ func (r *userRepository) VerifyUser(ctx context.Context, userID string) error {
 
 	return nil
 }
+
+func (r *userRepository) SetSuspension(ctx context.Context, userID string, suspended bool, until *time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+
+	set := bson.M{"updatedAt": time.Now()}
+	unset := bson.M{}
+	switch {
+	case !suspended:
+		unset["suspended"], unset["suspended_until"] = "", ""
+	case until == nil:
+		set["suspended"] = true
+		unset["suspended_until"] = ""
+	default:
+		set["suspended"], set["suspended_until"] = true, until
+	}
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	result, err := r.userCollection.UpdateByID(ctx, objID, update)
+	if err != nil {
+		return fmt.Errorf("failed to update suspension: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package repositories
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// deliveryRetention is how long the delivery log keeps a delivery.
+const deliveryRetention = 30 * 24 * time.Hour
+
+type webhookDeliveryModel struct {
+	ID             primitive.ObjectID           `bson:"_id"`
+	WebhookID      string                       `bson:"webhook_id"`
+	EventID        string                       `bson:"event_id"`
+	EventType      string                       `bson:"event_type"`
+	Payload        string                       `bson:"payload"`
+	Status         domain.WebhookDeliveryStatus `bson:"status"`
+	Attempts       int                          `bson:"attempts"`
+	NextAttemptAt  *time.Time                   `bson:"next_attempt_at,omitempty"`
+	LastStatusCode int                          `bson:"last_status_code,omitempty"`
+	LastError      string                       `bson:"last_error,omitempty"`
+	LastResponse   string                       `bson:"last_response,omitempty"`
+	ReplayOf       string                       `bson:"replay_of,omitempty"`
+	CreatedAt      time.Time                    `bson:"createdAt"`
+	UpdatedAt      time.Time                    `bson:"updatedAt"`
+	DeliveredAt    *time.Time                   `bson:"delivered_at,omitempty"`
+}
+
+func toDomainWebhookDelivery(m webhookDeliveryModel) domain.WebhookDelivery {
+	return domain.WebhookDelivery{
+		ID:             m.ID.Hex(),
+		WebhookID:      m.WebhookID,
+		EventID:        m.EventID,
+		EventType:      m.EventType,
+		Payload:        m.Payload,
+		Status:         m.Status,
+		Attempts:       m.Attempts,
+		NextAttemptAt:  m.NextAttemptAt,
+		LastStatusCode: m.LastStatusCode,
+		LastError:      m.LastError,
+		LastResponse:   m.LastResponse,
+		ReplayOf:       m.ReplayOf,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+		DeliveredAt:    m.DeliveredAt,
+	}
+}
+
+type webhookDeliveryRepository struct {
+	deliveryCollection *mongo.Collection
+}
+
+func NewWebhookDeliveryRepository(db *mongo.Database) domain.IWebhookDeliveryRepository {
+	collection := db.Collection("webhook_deliveries")
+	ensureIndexes(collection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+		{Keys: bson.D{{Key: "webhook_id", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{
+			Keys:    bson.D{{Key: "createdAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(deliveryRetention.Seconds())),
+		},
+	})
+	return &webhookDeliveryRepository{deliveryCollection: collection}
+}
+
+func (r *webhookDeliveryRepository) CreateMany(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, len(deliveries))
+	for i := range deliveries {
+		d := &deliveries[i]
+		id := primitive.NewObjectID()
+		docs[i] = webhookDeliveryModel{
+			ID:            id,
+			WebhookID:     d.WebhookID,
+			EventID:       d.EventID,
+			EventType:     d.EventType,
+			Payload:       d.Payload,
+			Status:        d.Status,
+			NextAttemptAt: d.NextAttemptAt,
+			ReplayOf:      d.ReplayOf,
+			CreatedAt:     d.CreatedAt,
+			UpdatedAt:     d.UpdatedAt,
+		}
+		d.ID = id.Hex()
+	}
+	if _, err := r.deliveryCollection.InsertMany(ctx, docs); err != nil {
+		return fmt.Errorf("failed to queue webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+func (r *webhookDeliveryRepository) FindByID(ctx context.Context, deliveryID string) (*domain.WebhookDelivery, error) {
+	objID, err := primitive.ObjectIDFromHex(deliveryID)
+	if err != nil {
+		return nil, domain.ErrDeliveryNotFound
+	}
+	var model webhookDeliveryModel
+	err = r.deliveryCollection.FindOne(ctx, bson.M{"_id": objID}).Decode(&model)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrDeliveryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find webhook delivery: %w", err)
+	}
+	delivery := toDomainWebhookDelivery(model)
+	return &delivery, nil
+}
+
+func (r *webhookDeliveryRepository) ListByWebhook(ctx context.Context, webhookID string, status domain.WebhookDeliveryStatus, page, limit int) ([]domain.WebhookDelivery, int64, error) {
+	filter := bson.M{"webhook_id": webhookID}
+	if status != "" {
+		filter["status"] = status
+	}
+	total, err := r.deliveryCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"payload": 0})
+	cursor, err := r.deliveryCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var models []webhookDeliveryModel
+	if err := cursor.All(ctx, &models); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode webhook deliveries: %w", err)
+	}
+	deliveries := make([]domain.WebhookDelivery, 0, len(models))
+	for _, m := range models {
+		deliveries = append(deliveries, toDomainWebhookDelivery(m))
+	}
+	return deliveries, total, nil
+}
+
+func (r *webhookDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*domain.WebhookDelivery, error) {
+	var model webhookDeliveryModel
+	err := r.deliveryCollection.FindOneAndUpdate(ctx,
+		bson.M{"status": domain.DeliveryPending, "next_attempt_at": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"next_attempt_at": now.Add(lease)}},
+		options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+			SetReturnDocument(options.After),
+	).Decode(&model)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook delivery: %w", err)
+	}
+	delivery := toDomainWebhookDelivery(model)
+	return &delivery, nil
+}
+
+func (r *webhookDeliveryRepository) Save(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	objID, err := primitive.ObjectIDFromHex(delivery.ID)
+	if err != nil {
+		return domain.ErrDeliveryNotFound
+	}
+	set := bson.M{
+		"status":           delivery.Status,
+		"attempts":         delivery.Attempts,
+		"last_status_code": delivery.LastStatusCode,
+		"last_error":       delivery.LastError,
+		"last_response":    delivery.LastResponse,
+		"updatedAt":        delivery.UpdatedAt,
+	}
+	unset := bson.M{}
+	if delivery.NextAttemptAt != nil {
+		set["next_attempt_at"] = delivery.NextAttemptAt
+	} else {
+		unset["next_attempt_at"] = ""
+	}
+	if delivery.DeliveredAt != nil {
+		set["delivered_at"] = delivery.DeliveredAt
+	}
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	if _, err := r.deliveryCollection.UpdateOne(ctx, bson.M{"_id": objID}, update); err != nil {
+		return fmt.Errorf("failed to save webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *webhookDeliveryRepository) DeleteByWebhook(ctx context.Context, webhookID string) error {
+	if _, err := r.deliveryCollection.DeleteMany(ctx, bson.M{"webhook_id": webhookID}); err != nil {
+		return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+	}
+	return nil
+}
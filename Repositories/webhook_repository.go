@@ -0,0 +1,213 @@
+package repositories
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type webhookModel struct {
+	ID                  primitive.ObjectID `bson:"_id"`
+	URL                 string             `bson:"url"`
+	Secret              string             `bson:"secret"`
+	Events              []string           `bson:"events"`
+	Description         string             `bson:"description,omitempty"`
+	Active              bool               `bson:"active"`
+	ConsecutiveFailures int                `bson:"consecutive_failures"`
+	DisabledAt          *time.Time         `bson:"disabled_at,omitempty"`
+	DisabledReason      string             `bson:"disabled_reason,omitempty"`
+	CreatedBy           string             `bson:"created_by"`
+	CreatedAt           time.Time          `bson:"createdAt"`
+	UpdatedAt           time.Time          `bson:"updatedAt"`
+}
+
+func toDomainWebhook(m webhookModel) domain.Webhook {
+	return domain.Webhook{
+		ID:                  m.ID.Hex(),
+		URL:                 m.URL,
+		Secret:              m.Secret,
+		Events:              m.Events,
+		Description:         m.Description,
+		Active:              m.Active,
+		ConsecutiveFailures: m.ConsecutiveFailures,
+		DisabledAt:          m.DisabledAt,
+		DisabledReason:      m.DisabledReason,
+		CreatedBy:           m.CreatedBy,
+		CreatedAt:           m.CreatedAt,
+		UpdatedAt:           m.UpdatedAt,
+	}
+}
+
+type webhookRepository struct {
+	webhookCollection *mongo.Collection
+}
+
+func NewWebhookRepository(db *mongo.Database) domain.IWebhookRepository {
+	collection := db.Collection("webhooks")
+	ensureIndexes(collection, []mongo.IndexModel{{
+		Keys: bson.D{{Key: "events", Value: 1}, {Key: "active", Value: 1}},
+	}})
+	return &webhookRepository{webhookCollection: collection}
+}
+
+func (r *webhookRepository) Create(ctx context.Context, webhook *domain.Webhook) error {
+	model := webhookModel{
+		ID:          primitive.NewObjectID(),
+		URL:         webhook.URL,
+		Secret:      webhook.Secret,
+		Events:      webhook.Events,
+		Description: webhook.Description,
+		Active:      webhook.Active,
+		CreatedBy:   webhook.CreatedBy,
+		CreatedAt:   webhook.CreatedAt,
+		UpdatedAt:   webhook.UpdatedAt,
+	}
+	if _, err := r.webhookCollection.InsertOne(ctx, model); err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+	webhook.ID = model.ID.Hex()
+	return nil
+}
+
+func (r *webhookRepository) FindByID(ctx context.Context, webhookID string) (*domain.Webhook, error) {
+	objID, err := primitive.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return nil, domain.ErrWebhookNotFound
+	}
+	var model webhookModel
+	err = r.webhookCollection.FindOne(ctx, bson.M{"_id": objID}).Decode(&model)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find webhook: %w", err)
+	}
+	webhook := toDomainWebhook(model)
+	return &webhook, nil
+}
+
+func (r *webhookRepository) List(ctx context.Context) ([]domain.Webhook, error) {
+	return r.find(ctx, bson.M{})
+}
+
+func (r *webhookRepository) ListActive(ctx context.Context, eventType string) ([]domain.Webhook, error) {
+	return r.find(ctx, bson.M{"events": eventType, "active": true})
+}
+
+func (r *webhookRepository) find(ctx context.Context, filter bson.M) ([]domain.Webhook, error) {
+	cursor, err := r.webhookCollection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var models []webhookModel
+	if err := cursor.All(ctx, &models); err != nil {
+		return nil, fmt.Errorf("failed to decode webhooks: %w", err)
+	}
+	webhooks := make([]domain.Webhook, 0, len(models))
+	for _, m := range models {
+		webhooks = append(webhooks, toDomainWebhook(m))
+	}
+	return webhooks, nil
+}
+
+func (r *webhookRepository) Update(ctx context.Context, webhook *domain.Webhook) error {
+	objID, err := primitive.ObjectIDFromHex(webhook.ID)
+	if err != nil {
+		return domain.ErrWebhookNotFound
+	}
+	set := bson.M{
+		"url":                  webhook.URL,
+		"secret":               webhook.Secret,
+		"events":               webhook.Events,
+		"description":          webhook.Description,
+		"active":               webhook.Active,
+		"consecutive_failures": webhook.ConsecutiveFailures,
+		"updatedAt":            webhook.UpdatedAt,
+	}
+	update := bson.M{"$set": set}
+	if webhook.DisabledAt == nil {
+		update["$unset"] = bson.M{"disabled_at": "", "disabled_reason": ""}
+	} else {
+		set["disabled_at"] = webhook.DisabledAt
+		set["disabled_reason"] = webhook.DisabledReason
+	}
+	result, err := r.webhookCollection.UpdateOne(ctx, bson.M{"_id": objID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, webhookID string) error {
+	objID, err := primitive.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return domain.ErrWebhookNotFound
+	}
+	result, err := r.webhookCollection.DeleteOne(ctx, bson.M{"_id": objID})
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (r *webhookRepository) RecordSuccess(ctx context.Context, webhookID string) error {
+	objID, err := primitive.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return domain.ErrWebhookNotFound
+	}
+	_, err = r.webhookCollection.UpdateOne(ctx,
+		bson.M{"_id": objID, "consecutive_failures": bson.M{"$ne": 0}},
+		bson.M{"$set": bson.M{"consecutive_failures": 0}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+	return nil
+}
+
+func (r *webhookRepository) RecordFailure(ctx context.Context, webhookID string, disableAfter int, reason string, at time.Time) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return false, domain.ErrWebhookNotFound
+	}
+	var model webhookModel
+	err = r.webhookCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": objID},
+		bson.M{"$inc": bson.M{"consecutive_failures": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&model)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, domain.ErrWebhookNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to update webhook: %w", err)
+	}
+	if !model.Active || model.ConsecutiveFailures < disableAfter {
+		return false, nil
+	}
+
+	// Only the update that flips active reports the webhook disabled.
+	result, err := r.webhookCollection.UpdateOne(ctx,
+		bson.M{"_id": objID, "active": true},
+		bson.M{"$set": bson.M{"active": false, "disabled_at": at, "disabled_reason": reason, "updatedAt": at}},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to disable webhook: %w", err)
+	}
+	return result.ModifiedCount == 1, nil
+}
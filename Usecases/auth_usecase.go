@@ -58,6 +58,9 @@ func (uc *AuthUsecase) RefreshToken(ctx context.Context, token string) (*domain.
 	if err != nil || user == nil {
 		return nil, errors.New("user not found")
 	}
+	if user.IsSuspended(time.Now()) {
+		return nil, domain.ErrAccountSuspended
+	}
 
 	newAccessToken, err := uc.jwtService.CreateAccessToken(user)
 	if err != nil {
@@ -0,0 +1,89 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+type BlogPublisher struct {
+	blogRepository domain.IBlogRepository
+	events         domain.IEventBus
+	interval       time.Duration
+}
+
+func NewBlogPublisher(blogRepo domain.IBlogRepository, events domain.IEventBus, interval time.Duration) domain.IBlogPublisher {
+	return &BlogPublisher{
+		blogRepository: blogRepo,
+		events:         events,
+		interval:       interval,
+	}
+}
+
+// Run polls for due scheduled blogs until ctx is cancelled. Schedules live in
+// MongoDB, so anything that fell due while the API was down is published on
+// the first tick after a restart.
+func (p *BlogPublisher) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := p.PublishDue(ctx); err != nil {
+			log.Printf("scheduled publisher: %v", err)
+		} else if n > 0 {
+			log.Printf("scheduled publisher: published %d blog(s)", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// announceLease is how long a publisher has to announce a blog before
+// another may try.
+const announceLease = time.Minute
+
+// PublishDue publishes every scheduled blog whose publish time has passed,
+// announces the blogs published by schedule that have not been announced
+// yet and returns how many it promoted.
+func (p *BlogPublisher) PublishDue(ctx context.Context) (int, error) {
+	published := 0
+	for {
+		blog, err := p.blogRepository.PublishNextDue(ctx, time.Now())
+		if err != nil {
+			return published, err
+		}
+		if blog == nil {
+			break
+		}
+		published++
+	}
+	return published, p.announceDue(ctx)
+}
+
+// announceDue publishes EventBlogPublished for each blog waiting to be
+// announced. A blog is only marked announced once its event is out, so one
+// whose announcement failed, or whose publisher died first, is announced
+// again when its claim runs out.
+func (p *BlogPublisher) announceDue(ctx context.Context) error {
+	for {
+		blog, err := p.blogRepository.ClaimUnannounced(ctx, time.Now(), announceLease)
+		if err != nil {
+			return err
+		}
+		if blog == nil {
+			return nil
+		}
+		if err := p.events.Publish(ctx, domain.Event{Type: domain.EventBlogPublished, UserID: blog.UserID, BlogID: blog.ID}); err != nil {
+			return fmt.Errorf("failed to announce blog %s: %w", blog.ID, err)
+		}
+		if err := p.blogRepository.MarkAnnounced(ctx, blog.ID); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// calendar stores blogs and publishes and claims them the way the Mongo
+// repository does, comparing times with the ones it is given.
+type calendar struct {
+	domain.IBlogRepository
+	blogs      map[string]*domain.Blog
+	announceAt map[string]time.Time // blogs waiting to be announced
+}
+
+func newCalendar() *calendar {
+	return &calendar{blogs: map[string]*domain.Blog{}, announceAt: map[string]time.Time{}}
+}
+
+func (c *calendar) SlugTaken(context.Context, string, string) (bool, error) { return false, nil }
+
+func (c *calendar) Create(_ context.Context, blog *domain.Blog) (*domain.Blog, error) {
+	blog.ID = "b1"
+	stored := *blog
+	c.blogs[blog.ID] = &stored
+	return blog, nil
+}
+
+func (c *calendar) PublishNextDue(_ context.Context, now time.Time) (*domain.Blog, error) {
+	for _, blog := range c.blogs {
+		if blog.Status == domain.BlogStatusScheduled && !blog.PublishAt.After(now) {
+			blog.Status, blog.PublishedAt, blog.PublishAt = domain.BlogStatusPublished, blog.PublishAt, nil
+			c.announceAt[blog.ID] = now
+			published := *blog
+			return &published, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *calendar) ClaimUnannounced(_ context.Context, now time.Time, lease time.Duration) (*domain.Blog, error) {
+	for id, at := range c.announceAt {
+		if !at.After(now) {
+			c.announceAt[id] = now.Add(lease)
+			claimed := *c.blogs[id]
+			return &claimed, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *calendar) MarkAnnounced(_ context.Context, blogID string) error {
+	delete(c.announceAt, blogID)
+	return nil
+}
+
+// expireClaims stands in for the announce lease running out.
+func (c *calendar) expireClaims() {
+	for id := range c.announceAt {
+		c.announceAt[id] = time.Time{}
+	}
+}
+
+type sourceRenderer struct{}
+
+func (sourceRenderer) Render(_ domain.ContentFormat, source string) (*domain.RenderedContent, error) {
+	return &domain.RenderedContent{HTML: source, Text: source, Excerpt: source, WordCount: 1, ReadingTime: 1}, nil
+}
+
+// flakyEventBus fails the first failures publishes and keeps the rest.
+type flakyEventBus struct {
+	domain.IEventBus
+	failures  int
+	published []domain.Event
+}
+
+func (b *flakyEventBus) Publish(_ context.Context, event domain.Event) error {
+	if b.failures > 0 {
+		b.failures--
+		return errors.New("outbox unavailable")
+	}
+	b.published = append(b.published, event)
+	return nil
+}
+
+func TestScheduledBlogGoesLive(t *testing.T) {
+	ctx := context.Background()
+	blogs := newCalendar()
+	events := &flakyEventBus{}
+	bu := &BlogUsecase{blogRepository: blogs, tagRepository: knownTagRepository{}, contentRenderer: sourceRenderer{}, events: events}
+	publisher := NewBlogPublisher(blogs, events, time.Minute)
+
+	publishAt := time.Now().Add(time.Hour)
+	blog := &domain.Blog{Title: "Monday", Content: "Good morning", Tags: []string{"news"}, UserID: "alice", PublishAt: &publishAt}
+	if err := bu.Create(ctx, blog); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if blog.Status != domain.BlogStatusScheduled || len(events.published) != 0 {
+		t.Fatalf("status %s with %d events, want scheduled and none", blog.Status, len(events.published))
+	}
+	if n, err := publisher.PublishDue(ctx); err != nil || n != 0 {
+		t.Fatalf("PublishDue before the publish time = %d, %v, want nothing published", n, err)
+	}
+
+	// The hour passes.
+	past := time.Now().Add(-time.Minute)
+	blogs.blogs["b1"].PublishAt = &past
+	if n, err := publisher.PublishDue(ctx); err != nil || n != 1 {
+		t.Fatalf("PublishDue = %d, %v, want 1 published", n, err)
+	}
+	stored := blogs.blogs["b1"]
+	if stored.Status != domain.BlogStatusPublished || stored.PublishedAt == nil || !stored.PublishedAt.Equal(past) {
+		t.Errorf("blog = %s published at %v, want published at %v", stored.Status, stored.PublishedAt, past)
+	}
+	if len(events.published) != 1 || events.published[0].Type != domain.EventBlogPublished ||
+		events.published[0].BlogID != "b1" || events.published[0].UserID != "alice" {
+		t.Errorf("events = %+v, want one blog.published for b1 by alice", events.published)
+	}
+
+	if n, err := publisher.PublishDue(ctx); err != nil || n != 0 || len(events.published) != 1 {
+		t.Errorf("second PublishDue = %d, %v with %d events, want nothing more", n, err, len(events.published))
+	}
+}
+
+func TestBlogPublisherRetriesAnnouncement(t *testing.T) {
+	ctx := context.Background()
+	blogs := newCalendar()
+	due := time.Now().Add(-time.Minute)
+	blogs.blogs["b1"] = &domain.Blog{ID: "b1", UserID: "alice", Status: domain.BlogStatusScheduled, PublishAt: &due}
+	events := &flakyEventBus{failures: 1}
+	publisher := NewBlogPublisher(blogs, events, time.Minute)
+
+	n, err := publisher.PublishDue(ctx)
+	if err == nil || n != 1 {
+		t.Fatalf("PublishDue = %d, %v, want 1 published and the announcement error", n, err)
+	}
+	if blogs.blogs["b1"].Status != domain.BlogStatusPublished {
+		t.Fatalf("status = %s, want published", blogs.blogs["b1"].Status)
+	}
+
+	// Still claimed by the failed attempt.
+	if _, err := publisher.PublishDue(ctx); err != nil || len(events.published) != 0 {
+		t.Fatalf("PublishDue within the lease = %v with %d events, want no announcement yet", err, len(events.published))
+	}
+
+	blogs.expireClaims()
+	if _, err := publisher.PublishDue(ctx); err != nil {
+		t.Fatalf("PublishDue after the lease: %v", err)
+	}
+	if len(events.published) != 1 || events.published[0].BlogID != "b1" {
+		t.Errorf("events = %+v, want b1 announced once", events.published)
+	}
+	if _, waiting := blogs.announceAt["b1"]; waiting {
+		t.Error("b1 still waiting to be announced")
+	}
+}
@@ -0,0 +1,167 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	defaultBlogLimit = 10
+	maxBlogLimit     = 100
+	maxQueryTags     = 20
+)
+
+func (bu *BlogUsecase) ListBlogs(ctx context.Context, query domain.BlogQuery, viewer domain.Viewer) (*domain.BlogListResponse, error) {
+	text, err := normalizeBlogQuery(&query)
+	if err != nil {
+		return nil, err
+	}
+	if len(query.Tags) > 0 {
+		query.Tags, query.TagVariants, err = tagVariants(ctx, bu.tagRepository, query.Tags)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve tags: %w", err)
+		}
+	}
+
+	result, err := bu.blogRepository.FindBlogs(ctx, query, viewer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blogs: %w", err)
+	}
+	blogs := make([]*domain.Blog, len(result.Blogs))
+	for i := range result.Blogs {
+		blogs[i] = &result.Blogs[i]
+	}
+	bu.fillReactions(ctx, blogs...)
+
+	response := &domain.BlogListResponse{
+		Blogs: result.Blogs,
+		Limit: query.Limit,
+	}
+	if result.Scores != nil {
+		response.Matches = make(map[string]domain.BlogMatch, len(result.Blogs))
+		for i := range result.Blogs {
+			blog := &result.Blogs[i]
+			response.Matches[blog.ID] = domain.BlogMatch{
+				Score:      result.Scores[blog.ID],
+				Highlights: bu.highlights(blog, text),
+			}
+		}
+	}
+
+	if query.UseCursor {
+		response.NextCursor = result.NextCursor
+		response.HasNext = result.NextCursor != ""
+		return response, nil
+	}
+
+	totalPages := int((result.Total + int64(query.Limit) - 1) / int64(query.Limit)) // Ceiling division
+	response.Page = query.Page
+	response.Total = &result.Total
+	response.TotalPages = &totalPages
+	response.HasNext = query.Page < totalPages
+	response.HasPrev = query.Page > 1
+	return response, nil
+}
+
+// normalizeBlogQuery validates query, fills in defaults and rewrites Text
+// into $text syntax. It returns the parsed text query for highlighting.
+func normalizeBlogQuery(query *domain.BlogQuery) (searchQuery, error) {
+	tags := make([]string, 0, len(query.Tags))
+	for _, tag := range query.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > maxQueryTags {
+		return searchQuery{}, fmt.Errorf("%w: at most %d tags can be combined", domain.ErrInvalidInput, maxQueryTags)
+	}
+	query.Tags = tags
+	switch query.TagMatch {
+	case "":
+		query.TagMatch = domain.TagMatchAny
+	case domain.TagMatchAny, domain.TagMatchAll:
+	default:
+		return searchQuery{}, fmt.Errorf("%w: tag_match must be %q or %q", domain.ErrInvalidInput, domain.TagMatchAny, domain.TagMatchAll)
+	}
+
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		return searchQuery{}, fmt.Errorf("%w: from must be before to", domain.ErrInvalidInput)
+	}
+	if query.MinViews < 0 || query.MinLikes < 0 {
+		return searchQuery{}, fmt.Errorf("%w: min_views and min_likes cannot be negative", domain.ErrInvalidInput)
+	}
+	if query.Status != "" && !query.Status.IsValid() {
+		return searchQuery{}, fmt.Errorf("%w: unknown status %q", domain.ErrInvalidInput, query.Status)
+	}
+
+	var text searchQuery
+	if query.Text != "" {
+		if utf8.RuneCountInString(query.Text) > maxSearchQueryLength {
+			return searchQuery{}, fmt.Errorf("%w: search query is longer than %d characters", domain.ErrInvalidInput, maxSearchQueryLength)
+		}
+		text = parseSearchQuery(query.Text)
+		if text.empty() {
+			return searchQuery{}, fmt.Errorf("%w: search query needs at least one word that is not excluded", domain.ErrInvalidInput)
+		}
+		query.Text = text.mongoSearch()
+	}
+
+	switch query.Sort {
+	case "":
+		query.Sort = domain.BlogSortRecent
+		if query.Text != "" {
+			query.Sort = domain.BlogSortRelevance
+		}
+	case "views":
+		query.Sort = domain.BlogSortPopular
+	case domain.BlogSortRecent, domain.BlogSortPopular, domain.BlogSortLikes, domain.BlogSortComments:
+	case domain.BlogSortPublished:
+		// Drafts and scheduled blogs have no publication date to order by.
+		if query.Status == "" {
+			query.Status = domain.BlogStatusPublished
+		}
+		if query.Status != domain.BlogStatusPublished {
+			return searchQuery{}, fmt.Errorf("%w: sort=published only lists published blogs", domain.ErrInvalidInput)
+		}
+	case domain.BlogSortTrending:
+		if query.TrendingWindow == "" {
+			query.TrendingWindow = domain.TrendingWeek
+		}
+	case domain.BlogSortRelevance:
+		if query.Text == "" {
+			return searchQuery{}, fmt.Errorf("%w: sort=relevance needs a text query", domain.ErrInvalidInput)
+		}
+	default:
+		return searchQuery{}, fmt.Errorf("%w: unknown sort %q; use recent, published, popular, likes, comments, trending or relevance", domain.ErrInvalidInput, query.Sort)
+	}
+
+	if query.TrendingWindow != "" && !query.TrendingWindow.IsValid() {
+		return searchQuery{}, fmt.Errorf("%w: window must be 24h, 7d or 30d", domain.ErrInvalidInput)
+	}
+
+	if query.Limit == 0 {
+		query.Limit = defaultBlogLimit
+	}
+	if query.Limit < 1 || query.Limit > maxBlogLimit {
+		return searchQuery{}, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidInput, maxBlogLimit)
+	}
+	if query.UseCursor {
+		if query.Sort == domain.BlogSortRelevance {
+			return searchQuery{}, fmt.Errorf("%w: cursor pagination is not available with relevance sort; use page, or sort by recent, published, popular, likes, comments or trending", domain.ErrInvalidInput)
+		}
+		if query.Page != 0 {
+			return searchQuery{}, fmt.Errorf("%w: page and cursor cannot be combined", domain.ErrInvalidInput)
+		}
+		return text, nil
+	}
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.Page < 1 {
+		return searchQuery{}, fmt.Errorf("%w: page must be 1 or more", domain.ErrInvalidInput)
+	}
+	return text, nil
+}
@@ -0,0 +1,100 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizeBlogQuery(t *testing.T) {
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+	manyTags := make([]string, maxQueryTags+1)
+	for i := range manyTags {
+		manyTags[i] = "tag"
+	}
+	tests := []struct {
+		name    string
+		query   domain.BlogQuery
+		want    domain.BlogQuery
+		wantErr bool
+	}{
+		{
+			name:  "defaults",
+			query: domain.BlogQuery{},
+			want:  domain.BlogQuery{Tags: []string{}, TagMatch: domain.TagMatchAny, Sort: domain.BlogSortRecent, Limit: defaultBlogLimit, Page: 1},
+		},
+		{
+			name:  "blank tags are dropped",
+			query: domain.BlogQuery{Tags: []string{" go ", "", "  "}, TagMatch: domain.TagMatchAll},
+			want:  domain.BlogQuery{Tags: []string{"go"}, TagMatch: domain.TagMatchAll, Sort: domain.BlogSortRecent, Limit: defaultBlogLimit, Page: 1},
+		},
+		{
+			name:  "text sorts by relevance",
+			query: domain.BlogQuery{Text: "Go -java"},
+			want: domain.BlogQuery{Tags: []string{}, TagMatch: domain.TagMatchAny, Text: "go -java", Sort: domain.BlogSortRelevance,
+				Limit: defaultBlogLimit, Page: 1},
+		},
+		{
+			name:  "views is an alias of popular",
+			query: domain.BlogQuery{Sort: "views"},
+			want:  domain.BlogQuery{Tags: []string{}, TagMatch: domain.TagMatchAny, Sort: domain.BlogSortPopular, Limit: defaultBlogLimit, Page: 1},
+		},
+		{
+			name:  "published sort lists published blogs",
+			query: domain.BlogQuery{Sort: domain.BlogSortPublished},
+			want: domain.BlogQuery{Tags: []string{}, TagMatch: domain.TagMatchAny, Status: domain.BlogStatusPublished,
+				Sort: domain.BlogSortPublished, Limit: defaultBlogLimit, Page: 1},
+		},
+		{
+			name:  "trending defaults to a week",
+			query: domain.BlogQuery{Sort: domain.BlogSortTrending},
+			want: domain.BlogQuery{Tags: []string{}, TagMatch: domain.TagMatchAny, Sort: domain.BlogSortTrending,
+				TrendingWindow: domain.TrendingWeek, Limit: defaultBlogLimit, Page: 1},
+		},
+		{
+			name:  "cursor leaves page unset",
+			query: domain.BlogQuery{UseCursor: true, Limit: 5},
+			want:  domain.BlogQuery{Tags: []string{}, TagMatch: domain.TagMatchAny, Sort: domain.BlogSortRecent, Limit: 5, UseCursor: true},
+		},
+		{name: "too many tags", query: domain.BlogQuery{Tags: manyTags}, wantErr: true},
+		{name: "unknown tag match", query: domain.BlogQuery{TagMatch: "some"}, wantErr: true},
+		{name: "from after to", query: domain.BlogQuery{From: &now, To: &earlier}, wantErr: true},
+		{name: "from equals to", query: domain.BlogQuery{From: &now, To: &now}, wantErr: true},
+		{name: "negative min views", query: domain.BlogQuery{MinViews: -1}, wantErr: true},
+		{name: "negative min likes", query: domain.BlogQuery{MinLikes: -1}, wantErr: true},
+		{name: "unknown status", query: domain.BlogQuery{Status: "deleted"}, wantErr: true},
+		{name: "text too long", query: domain.BlogQuery{Text: strings.Repeat("a", maxSearchQueryLength+1)}, wantErr: true},
+		{name: "text with only exclusions", query: domain.BlogQuery{Text: "-java"}, wantErr: true},
+		{name: "unknown sort", query: domain.BlogQuery{Sort: "oldest"}, wantErr: true},
+		{name: "published sort of drafts", query: domain.BlogQuery{Sort: domain.BlogSortPublished, Status: domain.BlogStatusDraft}, wantErr: true},
+		{name: "relevance without text", query: domain.BlogQuery{Sort: domain.BlogSortRelevance}, wantErr: true},
+		{name: "unknown trending window", query: domain.BlogQuery{Sort: domain.BlogSortTrending, TrendingWindow: "1y"}, wantErr: true},
+		{name: "limit too high", query: domain.BlogQuery{Limit: maxBlogLimit + 1}, wantErr: true},
+		{name: "negative limit", query: domain.BlogQuery{Limit: -1}, wantErr: true},
+		{name: "negative page", query: domain.BlogQuery{Page: -1}, wantErr: true},
+		{name: "cursor with relevance", query: domain.BlogQuery{Text: "go", UseCursor: true}, wantErr: true},
+		{name: "cursor with page", query: domain.BlogQuery{UseCursor: true, Page: 2}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := tt.query
+			_, err := normalizeBlogQuery(&query)
+			if tt.wantErr {
+				if !errors.Is(err, domain.ErrInvalidInput) {
+					t.Fatalf("error = %v, want ErrInvalidInput", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("normalizeBlogQuery: %v", err)
+			}
+			if !reflect.DeepEqual(query, tt.want) {
+				t.Errorf("query = %+v, want %+v", query, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"fmt"
+)
+
+func (bu *BlogUsecase) ListRevisions(ctx context.Context, blogID string, actor domain.Viewer) ([]domain.BlogRevision, error) {
+	if _, err := bu.findManageable(ctx, blogID, actor); err != nil {
+		return nil, err
+	}
+	return bu.revisionRepository.ListByBlogID(ctx, blogID)
+}
+
+func (bu *BlogUsecase) GetRevision(ctx context.Context, blogID string, version int, actor domain.Viewer) (*domain.BlogRevision, error) {
+	if _, err := bu.findManageable(ctx, blogID, actor); err != nil {
+		return nil, err
+	}
+	return bu.revisionRepository.FindByVersion(ctx, blogID, version)
+}
+
+// DiffRevisions compares two versions of a blog line by line. Version 0 is the
+// current content, so from=3&to=0 shows everything changed since revision 3.
+func (bu *BlogUsecase) DiffRevisions(ctx context.Context, blogID string, from, to int, actor domain.Viewer) (*domain.BlogRevisionDiff, error) {
+	if from < 0 || to < 0 {
+		return nil, fmt.Errorf("%w: versions must not be negative", domain.ErrInvalidInput)
+	}
+
+	blog, err := bu.findManageable(ctx, blogID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	older, err := bu.snapshot(ctx, blog, from)
+	if err != nil {
+		return nil, err
+	}
+	newer, err := bu.snapshot(ctx, blog, to)
+	if err != nil {
+		return nil, err
+	}
+
+	added, removed := diffTags(older.Tags, newer.Tags)
+	return &domain.BlogRevisionDiff{
+		BlogID:      blog.ID,
+		From:        from,
+		To:          to,
+		Title:       diffLines(older.Title, newer.Title),
+		Content:     diffLines(older.Content, newer.Content),
+		TagsAdded:   added,
+		TagsRemoved: removed,
+	}, nil
+}
+
+// RestoreRevision makes an old revision the current content. The content being
+// replaced is kept as a new revision, so a restore can itself be undone. Like
+// an edit, only the author may restore; admins can read the history.
+func (bu *BlogUsecase) RestoreRevision(ctx context.Context, blogID string, version int, actor domain.Viewer) (*domain.Blog, error) {
+	blog, err := bu.findManageable(ctx, blogID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if blog.UserID != actor.UserID {
+		return nil, domain.ErrForbidden
+	}
+
+	revision, err := bu.revisionRepository.FindByVersion(ctx, blogID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := *blog
+	blog.Title = revision.Title
+	blog.Content = revision.Content
+	if revision.ContentFormat != "" {
+		blog.ContentFormat = revision.ContentFormat
+	}
+	blog.Tags = revision.Tags
+
+	return bu.saveWithRevision(ctx, &previous, blog, actor.UserID, revision.Version)
+}
+
+func (bu *BlogUsecase) findManageable(ctx context.Context, blogID string, actor domain.Viewer) (*domain.Blog, error) {
+	blog, err := bu.blogRepository.FindByID(ctx, blogID)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.CanManage(blog) {
+		return nil, domain.ErrForbidden
+	}
+	return blog, nil
+}
+
+// snapshot returns the title, content and tags of a blog at the given
+// version, with version 0 meaning the blog as it is now.
+func (bu *BlogUsecase) snapshot(ctx context.Context, blog *domain.Blog, version int) (*domain.BlogRevision, error) {
+	if version == 0 {
+		return &domain.BlogRevision{
+			BlogID:  blog.ID,
+			Title:   blog.Title,
+			Content: blog.Content,
+			Tags:    blog.Tags,
+		}, nil
+	}
+	return bu.revisionRepository.FindByVersion(ctx, blog.ID, version)
+}
+
+func sameTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func diffTags(older, newer []string) (added, removed []string) {
+	inOlder := make(map[string]bool, len(older))
+	for _, t := range older {
+		inOlder[t] = true
+	}
+	inNewer := make(map[string]bool, len(newer))
+	for _, t := range newer {
+		inNewer[t] = true
+		if !inOlder[t] {
+			added = append(added, t)
+		}
+	}
+	for _, t := range older {
+		if !inNewer[t] {
+			removed = append(removed, t)
+		}
+	}
+	return added, removed
+}
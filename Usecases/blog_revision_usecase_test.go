@@ -0,0 +1,104 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+type knownTagRepository struct {
+	domain.ITagRepository
+}
+
+func (knownTagRepository) FindByNames(_ context.Context, names []string) (map[string]*domain.Tag, error) {
+	return map[string]*domain.Tag{}, nil
+}
+
+func (knownTagRepository) EnsureExists(context.Context, []string) error {
+	return nil
+}
+
+// revisionStore keeps the revisions recorded so far.
+type revisionStore struct {
+	domain.IBlogRevisionRepository
+	revisions map[string]domain.BlogRevision
+	next      int
+}
+
+func (r *revisionStore) Create(_ context.Context, revision *domain.BlogRevision) (*domain.BlogRevision, error) {
+	r.next++
+	revision.ID = strconv.Itoa(r.next)
+	revision.Version = r.next
+	r.revisions[revision.ID] = *revision
+	return revision, nil
+}
+
+func (r *revisionStore) Delete(_ context.Context, revisionID string) error {
+	delete(r.revisions, revisionID)
+	return nil
+}
+
+// updateFailingBlogRepository fails the first len(errs) updates with errs.
+type updateFailingBlogRepository struct {
+	domain.IBlogRepository
+	errs    []error
+	updates []domain.Blog
+}
+
+func (r *updateFailingBlogRepository) SlugTaken(context.Context, string, string) (bool, error) {
+	return false, nil
+}
+
+func (r *updateFailingBlogRepository) Update(_ context.Context, blog *domain.Blog) (*domain.Blog, error) {
+	r.updates = append(r.updates, *blog)
+	if len(r.updates) <= len(r.errs) {
+		return nil, r.errs[len(r.updates)-1]
+	}
+	return blog, nil
+}
+
+func TestSaveWithRevision(t *testing.T) {
+	failed := errors.New("connection reset")
+	tests := []struct {
+		name          string
+		errs          []error
+		wantErr       bool
+		wantUpdates   int
+		wantRevisions int
+	}{
+		{name: "saved", wantUpdates: 1, wantRevisions: 1},
+		{name: "slug taken is retried", errs: []error{domain.ErrSlugTaken}, wantUpdates: 2, wantRevisions: 1},
+		{name: "failure drops the revision", errs: []error{failed}, wantErr: true, wantUpdates: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blogs := &updateFailingBlogRepository{errs: tt.errs}
+			revisions := &revisionStore{revisions: map[string]domain.BlogRevision{}}
+			bu := &BlogUsecase{blogRepository: blogs, revisionRepository: revisions, tagRepository: knownTagRepository{}}
+
+			previous := domain.Blog{
+				ID: "b1", UserID: "alice", Title: "Old title", Slug: "old-title", Content: "content",
+				ContentHTML: "<p>content</p>", Tags: []string{"go"}, Status: domain.BlogStatusDraft,
+			}
+			blog := previous
+			blog.Title = "New title"
+
+			_, err := bu.saveWithRevision(context.Background(), &previous, &blog, "alice", 0)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("saveWithRevision error = %v, want error %v", err, tt.wantErr)
+			}
+			if len(blogs.updates) != tt.wantUpdates {
+				t.Errorf("updates = %d, want %d", len(blogs.updates), tt.wantUpdates)
+			}
+			if len(revisions.revisions) != tt.wantRevisions {
+				t.Errorf("revisions = %d, want %d", len(revisions.revisions), tt.wantRevisions)
+			}
+			last := blogs.updates[len(blogs.updates)-1]
+			if last.Slug != "new-title" || len(last.OldSlugs) != 1 || last.OldSlugs[0] != "old-title" {
+				t.Errorf("slug = %q, old slugs = %v, want new-title and [old-title]", last.Slug, last.OldSlugs)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	maxSearchQueryLength = 256
+	maxContentHighlights = 3
+	highlightContext     = 8 // words kept on each side of a match
+)
+
+// searchQuery is a parsed text-search string. Terms and phrases are
+// lowercased; phrases are stored as their individual words.
+type searchQuery struct {
+	terms    []string
+	phrases  [][]string
+	excluded []string // raw excluded terms and phrases, for the Mongo query
+}
+
+// parseSearchQuery splits q into plain terms, "quoted phrases" and -excluded
+// terms or phrases. An unterminated quote runs to the end of the input.
+func parseSearchQuery(q string) searchQuery {
+	var parsed searchQuery
+	for rest := strings.TrimSpace(q); rest != ""; rest = strings.TrimSpace(rest) {
+		negate := false
+		if rest[0] == '-' {
+			negate = true
+			rest = rest[1:]
+		}
+
+		var token string
+		phrase := strings.HasPrefix(rest, `"`)
+		if phrase {
+			rest = rest[1:]
+			end := strings.IndexByte(rest, '"')
+			if end < 0 {
+				end = len(rest)
+			}
+			token, rest = rest[:end], rest[min(end+1, len(rest)):]
+		} else {
+			end := strings.IndexFunc(rest, unicode.IsSpace)
+			if end < 0 {
+				end = len(rest)
+			}
+			token, rest = rest[:end], rest[end:]
+		}
+
+		words := searchWords(token)
+		if len(words) == 0 {
+			continue
+		}
+		switch {
+		case negate && (phrase || len(words) > 1):
+			parsed.excluded = append(parsed.excluded, `"`+strings.Join(words, " ")+`"`)
+		case negate:
+			parsed.excluded = append(parsed.excluded, words[0])
+		case phrase && len(words) > 1:
+			parsed.phrases = append(parsed.phrases, words)
+		default:
+			// Punctuation inside a bare token ("e-mail") splits it into words.
+			parsed.terms = append(parsed.terms, words...)
+		}
+	}
+	return parsed
+}
+
+func (q searchQuery) empty() bool {
+	return len(q.terms) == 0 && len(q.phrases) == 0
+}
+
+// mongoSearch rebuilds the query in $text syntax from the parsed parts, so
+// stray quotes or dashes in user input cannot change its meaning.
+func (q searchQuery) mongoSearch() string {
+	parts := make([]string, 0, len(q.terms)+len(q.phrases)+len(q.excluded))
+	for _, phrase := range q.phrases {
+		parts = append(parts, `"`+strings.Join(phrase, " ")+`"`)
+	}
+	parts = append(parts, q.terms...)
+	for _, excluded := range q.excluded {
+		parts = append(parts, "-"+excluded)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (bu *BlogUsecase) highlights(blog *domain.Blog, q searchQuery) domain.SearchHighlights {
+	result := domain.SearchHighlights{Content: []string{}}
+	if snippets := highlightText(blog.Title, q, 1, -1); len(snippets) > 0 {
+		result.Title = snippets[0]
+	}
+
+	text := blog.Content
+	if rendered, err := bu.contentRenderer.Render(blog.ContentFormat, blog.Content); err == nil {
+		text = rendered.Text
+	}
+	if snippets := highlightText(text, q, maxContentHighlights, highlightContext); snippets != nil {
+		result.Content = snippets
+	}
+	return result
+}
+
+// word is one run of letters or digits in a text, with its byte offsets.
+type word struct {
+	start, end int
+	norm       string
+}
+
+func splitWords(text string) []word {
+	var words []word
+	start := -1
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWord && start < 0 {
+			start = i
+		} else if !isWord && start >= 0 {
+			words = append(words, word{start, i, strings.ToLower(text[start:i])})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, word{start, len(text), strings.ToLower(text[start:])})
+	}
+	return words
+}
+
+func searchWords(text string) []string {
+	words := splitWords(text)
+	norms := make([]string, len(words))
+	for i, w := range words {
+		norms[i] = w.norm
+	}
+	return norms
+}
+
+// sameStem is a rough stand-in for the stemming the text index does, so a
+// search for "run" also highlights "running" and "studies" matches "study".
+func sameStem(a, b string) bool {
+	if a == b {
+		return true
+	}
+	shorter := min(len(a), len(b))
+	common := 0
+	for common < shorter && a[common] == b[common] {
+		common++
+	}
+	if common == shorter {
+		return shorter >= 3
+	}
+	return common >= 4 && common >= shorter-2
+}
+
+// highlightText returns up to limit snippets of text around the query's
+// matches. radius is the number of words kept either side of a match; a
+// negative radius keeps the whole text as a single snippet.
+func highlightText(text string, q searchQuery, limit, radius int) []string {
+	words := splitWords(text)
+	matched := make([]bool, len(words))
+	found := false
+	for i := range words {
+		for _, term := range q.terms {
+			if sameStem(words[i].norm, term) {
+				matched[i], found = true, true
+			}
+		}
+		for _, phrase := range q.phrases {
+			if i+len(phrase) > len(words) {
+				continue
+			}
+			ok := true
+			for j, pw := range phrase {
+				if !sameStem(words[i+j].norm, pw) {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				for j := range phrase {
+					matched[i+j] = true
+				}
+				found = true
+			}
+		}
+	}
+	if !found {
+		return nil
+	}
+	if radius < 0 {
+		return []string{markWords(text, words, matched, 0, len(words), true, true)}
+	}
+
+	var snippets []string
+	for i := 0; i < len(words) && len(snippets) < limit; i++ {
+		if !matched[i] {
+			continue
+		}
+		from := max(0, i-radius)
+		// Extend the window while further matches fall inside it.
+		to := min(len(words), i+radius+1)
+		for j := i + 1; j < to; j++ {
+			if matched[j] {
+				to = min(len(words), j+radius+1)
+			}
+		}
+		snippets = append(snippets, markWords(text, words, matched, from, to, from == 0, to == len(words)))
+		i = to - 1
+	}
+	return snippets
+}
+
+// markWords renders words[from:to] of text with matched words wrapped in
+// <mark>, escaping everything else. Cut ends get an ellipsis.
+func markWords(text string, words []word, matched []bool, from, to int, atStart, atEnd bool) string {
+	var b strings.Builder
+	if !atStart {
+		b.WriteString("…")
+	}
+	start := words[from].start
+	if atStart {
+		start = 0
+	}
+	pos := start
+	for i := from; i < to; i++ {
+		if !matched[i] {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos:words[i].start]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[words[i].start:words[i].end]))
+		b.WriteString("</mark>")
+		pos = words[i].end
+	}
+	end := words[to-1].end
+	if atEnd {
+		end = len(text)
+	}
+	b.WriteString(html.EscapeString(text[pos:end]))
+	if !atEnd {
+		b.WriteString("…")
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,168 @@
+package usecases
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		q     string
+		want  searchQuery
+		mongo string
+	}{
+		{
+			name: "empty",
+			q:    "   ",
+		},
+		{
+			name:  "terms are lowercased",
+			q:     "Go  Generics",
+			want:  searchQuery{terms: []string{"go", "generics"}},
+			mongo: "go generics",
+		},
+		{
+			name:  "phrase",
+			q:     `"error handling" go`,
+			want:  searchQuery{terms: []string{"go"}, phrases: [][]string{{"error", "handling"}}},
+			mongo: `"error handling" go`,
+		},
+		{
+			name:  "single-word phrase is a term",
+			q:     `"go"`,
+			want:  searchQuery{terms: []string{"go"}},
+			mongo: "go",
+		},
+		{
+			name:  "excluded term and phrase",
+			q:     `go -java -"spring boot"`,
+			want:  searchQuery{terms: []string{"go"}, excluded: []string{"java", `"spring boot"`}},
+			mongo: `go -java -"spring boot"`,
+		},
+		{
+			name:  "excluded punctuated term becomes a phrase",
+			q:     "go -e-mail",
+			want:  searchQuery{terms: []string{"go"}, excluded: []string{`"e mail"`}},
+			mongo: `go -"e mail"`,
+		},
+		{
+			name:  "punctuation splits a bare term",
+			q:     "e-mail",
+			want:  searchQuery{terms: []string{"e", "mail"}},
+			mongo: "e mail",
+		},
+		{
+			name:  "unterminated quote runs to the end",
+			q:     `go "open ended`,
+			want:  searchQuery{terms: []string{"go"}, phrases: [][]string{{"open", "ended"}}},
+			mongo: `"open ended" go`,
+		},
+		{
+			name: "stray quotes and dashes are dropped",
+			q:    `- "" -"" --`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseSearchQuery(tt.q)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSearchQuery(%q) = %+v, want %+v", tt.q, got, tt.want)
+			}
+			if m := got.mongoSearch(); m != tt.mongo {
+				t.Errorf("mongoSearch() = %q, want %q", m, tt.mongo)
+			}
+		})
+	}
+}
+
+func TestHighlightText(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		q      string
+		limit  int
+		radius int
+		want   []string
+	}{
+		{
+			name:   "no match",
+			text:   "nothing to see here",
+			q:      "go",
+			limit:  3,
+			radius: 2,
+		},
+		{
+			name:   "whole text",
+			text:   "Learning Go today",
+			q:      "go",
+			limit:  1,
+			radius: -1,
+			want:   []string{"Learning <mark>Go</mark> today"},
+		},
+		{
+			name:   "stem match",
+			text:   "She was running late",
+			q:      "run",
+			limit:  1,
+			radius: -1,
+			want:   []string{"She was <mark>running</mark> late"},
+		},
+		{
+			name:   "phrase marks every word",
+			text:   "good error handling matters",
+			q:      `"error handling"`,
+			limit:  1,
+			radius: -1,
+			want:   []string{"good <mark>error</mark> <mark>handling</mark> matters"},
+		},
+		{
+			name:   "partial phrase is not highlighted",
+			text:   "error codes and handling",
+			q:      `"error handling"`,
+			limit:  1,
+			radius: -1,
+		},
+		{
+			name:   "snippet is cut with ellipses",
+			text:   "one two three four go five six seven eight",
+			q:      "go",
+			limit:  3,
+			radius: 1,
+			want:   []string{"…four <mark>go</mark> five…"},
+		},
+		{
+			name:   "nearby matches share a snippet",
+			text:   "x a b go c go d e f g h",
+			q:      "go",
+			limit:  3,
+			radius: 2,
+			want:   []string{"…a b <mark>go</mark> c <mark>go</mark> d e…"},
+		},
+		{
+			name:   "limit caps snippets",
+			text:   "go a b c d go e f g h go",
+			q:      "go",
+			limit:  2,
+			radius: 0,
+			want:   []string{"<mark>go</mark>…", "…<mark>go</mark>…"},
+		},
+		{
+			name:   "html is escaped",
+			text:   "<b>go</b> & more",
+			q:      "go",
+			limit:  1,
+			radius: -1,
+			want:   []string{"&lt;b&gt;<mark>go</mark>&lt;/b&gt; &amp; more"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := highlightText(tt.text, parseSearchQuery(tt.q), tt.limit, tt.radius)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("highlightText(%q, %q) = %q, want %q", tt.text, tt.q, got, tt.want)
+			}
+		})
+	}
+}
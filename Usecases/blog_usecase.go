@@ -3,19 +3,39 @@ package usecases
 import (
 	domain "blog-api/Domain"
 	"context"
+	"errors"
 	"fmt"
+	"log"
 	"time"
 )
 
 type BlogUsecase struct {
-	blogRepository domain.IBlogRepository
-	aiService domain.AiService
+	blogRepository        domain.IBlogRepository
+	revisionRepository    domain.IBlogRevisionRepository
+	tagRepository         domain.ITagRepository
+	bookmarkRepository    domain.IBookmarkRepository
+	readingListRepository domain.IReadingListRepository
+	reactionRepository    domain.IReactionRepository
+	viewCounter           domain.IViewCounter
+	events                domain.IEventBus
+	contentRenderer       domain.IContentRenderer
+	aiService             domain.AiService
 }
 
-func NewBlogUseCase(blogRepo domain.IBlogRepository, aiservice domain.AiService) domain.IBlogUsecase {
+func NewBlogUseCase(blogRepo domain.IBlogRepository, revisionRepo domain.IBlogRevisionRepository, tagRepo domain.ITagRepository,
+	bookmarkRepo domain.IBookmarkRepository, readingListRepo domain.IReadingListRepository, reactionRepo domain.IReactionRepository,
+	viewCounter domain.IViewCounter, events domain.IEventBus, renderer domain.IContentRenderer, aiservice domain.AiService) domain.IBlogUsecase {
 	return &BlogUsecase{
-		blogRepository: blogRepo,
-		aiService: aiservice,
+		blogRepository:        blogRepo,
+		revisionRepository:    revisionRepo,
+		tagRepository:         tagRepo,
+		bookmarkRepository:    bookmarkRepo,
+		readingListRepository: readingListRepo,
+		reactionRepository:    reactionRepo,
+		viewCounter:           viewCounter,
+		events:                events,
+		contentRenderer:       renderer,
+		aiService:             aiservice,
 	}
 }
 
@@ -24,115 +44,430 @@ func (bu *BlogUsecase) Create(ctx context.Context, blog *domain.Blog) error {
 	if blog.Title == "" || blog.Content == "" || len(blog.Tags) == 0 {
 		return fmt.Errorf("invalid input: title/content/tags must not be empty")
 	}
-	_, err := bu.blogRepository.Create(ctx, blog)
 
+	if blog.Status == "" {
+		blog.Status = domain.BlogStatusDraft
+	}
+	if !blog.Status.IsValid() {
+		return fmt.Errorf("invalid input: unknown status %q", blog.Status)
+	}
+	if blog.ContentFormat == "" {
+		blog.ContentFormat = domain.ContentFormatPlain
+	}
+	if !blog.ContentFormat.IsValid() {
+		return fmt.Errorf("invalid input: unknown content format %q", blog.ContentFormat)
+	}
+	if blog.CommentMode == "" {
+		blog.CommentMode = domain.CommentModeOpen
+	}
+	if !blog.CommentMode.IsValid() {
+		return fmt.Errorf("invalid input: unknown comment mode %q", blog.CommentMode)
+	}
+	tags, err := canonicalTags(ctx, bu.tagRepository, blog.Tags)
 	if err != nil {
-		return fmt.Errorf("failed to create blog: %w", err)
+		return err
+	}
+	if len(tags) == 0 {
+		return fmt.Errorf("invalid input: title/content/tags must not be empty")
+	}
+	blog.Tags = tags
+	if err := bu.render(blog); err != nil {
+		return err
+	}
+	if blog.PublishAt != nil {
+		// A publish time in the past simply publishes the blog right away.
+		if blog.PublishAt.After(time.Now()) {
+			blog.Status = domain.BlogStatusScheduled
+		} else {
+			blog.Status = domain.BlogStatusPublished
+			blog.PublishAt = nil
+		}
+	} else if blog.Status == domain.BlogStatusScheduled {
+		return fmt.Errorf("invalid input: publish_at is required to schedule a blog")
+	}
+	if blog.Status == domain.BlogStatusPublished && blog.PublishedAt == nil {
+		now := time.Now()
+		blog.PublishedAt = &now
+	}
+
+	// The unique index on slug catches a concurrent create that picked the
+	// same slug between our check and the insert; pick another and retry.
+	for attempt := 0; ; attempt++ {
+		slug, err := bu.uniqueSlug(ctx, blog.Title, "")
+		if err != nil {
+			return err
+		}
+		blog.Slug = slug
+
+		_, err = bu.blogRepository.Create(ctx, blog)
+		if errors.Is(err, domain.ErrSlugTaken) && attempt < maxSlugAttempts {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create blog: %w", err)
+		}
+		if blog.Status == domain.BlogStatusPublished {
+			return bu.publishEvent(ctx, domain.EventBlogPublished, blog, blog.UserID)
+		}
+		return nil
 	}
-	return nil
 }
 
-func (bu *BlogUsecase) Update(ctx context.Context, input domain.UpdateBlogInput) (*domain.Blog, error) {
+func (bu *BlogUsecase) Update(ctx context.Context, input domain.UpdateBlogInput, actor domain.Viewer) (*domain.Blog, error) {
 	blog, err := bu.blogRepository.FindByID(ctx, input.BlogID)
 	if err != nil {
-		return nil, fmt.Errorf("blog not found")
+		return nil, err
+	}
+	if !actor.CanManage(blog) {
+		return nil, domain.ErrForbidden
 	}
 
-	if blog.UserID != input.UserID {
-		return nil, fmt.Errorf("unauthorized: you are not the author")
+	if input.ContentFormat != "" && !input.ContentFormat.IsValid() {
+		return nil, fmt.Errorf("%w: unknown content format %q", domain.ErrInvalidInput, input.ContentFormat)
 	}
 
+	previous := *blog
 	if input.Title != "" {
 		blog.Title = input.Title
 	}
 	if input.Content != "" {
 		blog.Content = input.Content
 	}
+	if input.ContentFormat != "" {
+		blog.ContentFormat = input.ContentFormat
+	}
 	if len(input.Tags) != 0 {
 		blog.Tags = input.Tags
 	}
 
+	return bu.saveWithRevision(ctx, &previous, blog, actor.UserID, 0)
+}
+
+// saveWithRevision stores blog after recording previous as a new revision, so
+// no edit can lose content. The revision is removed again if the blog cannot
+// be saved. Saving a blog whose title, content and tags did not change is a
+// no-op.
+func (bu *BlogUsecase) saveWithRevision(ctx context.Context, previous, blog *domain.Blog, editorID string, restoredFrom int) (*domain.Blog, error) {
+	tags, err := canonicalTags(ctx, bu.tagRepository, blog.Tags)
+	if err != nil {
+		return nil, err
+	}
+	// A blog that lost its last tag when an admin deleted it can still be
+	// edited without choosing a new one.
+	if len(tags) == 0 && len(previous.Tags) > 0 {
+		return nil, fmt.Errorf("%w: a blog needs at least one tag", domain.ErrInvalidInput)
+	}
+	blog.Tags = tags
+
+	contentChanged := previous.Content != blog.Content || previous.ContentFormat != blog.ContentFormat
+	if previous.Title == blog.Title && !contentChanged && sameTags(previous.Tags, blog.Tags) {
+		return blog, nil
+	}
+
+	if contentChanged || blog.ContentHTML == "" {
+		if err := bu.render(blog); err != nil {
+			return nil, err
+		}
+	}
+
+	revision := &domain.BlogRevision{
+		BlogID:        blog.ID,
+		EditorID:      editorID,
+		Title:         previous.Title,
+		Content:       previous.Content,
+		ContentFormat: previous.ContentFormat,
+		Tags:          previous.Tags,
+		RestoredFrom:  restoredFrom,
+		CreatedAt:     time.Now(),
+	}
+	if _, err := bu.revisionRepository.Create(ctx, revision); err != nil {
+		return nil, fmt.Errorf("failed to record blog revision: %w", err)
+	}
+
 	blog.UpdatedAt = time.Now()
 
-	updatedBlog, err := bu.blogRepository.Update(ctx, blog)
+	updatedBlog, err := bu.update(ctx, previous, blog)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update blog: %w", err)
+		if err := bu.revisionRepository.Delete(ctx, revision.ID); err != nil {
+			log.Printf("warning: failed to delete revision %d of blog %s: %v", revision.Version, blog.ID, err)
+		}
+		return nil, err
+	}
+	if updatedBlog.Status == domain.BlogStatusPublished {
+		if err := bu.publishEvent(ctx, domain.EventBlogUpdated, updatedBlog, editorID); err != nil {
+			return nil, err
+		}
 	}
 
 	return updatedBlog, nil
 }
 
-func (bu *BlogUsecase) FilterBlogs(ctx context.Context, tag string, date string, sort string) ([]domain.Blog, error) {
-    blogs, err := bu.blogRepository.Filter(ctx, tag, date, sort)
-    if err != nil {
-        return nil, fmt.Errorf("usecase: failed to filter blogs: %w", err)
-    }
-    return blogs, nil
+// update stores an edited blog, giving it a new slug when its title
+// changed. Like Create, it picks another slug when a concurrent save took
+// the one chosen.
+func (bu *BlogUsecase) update(ctx context.Context, previous, blog *domain.Blog) (*domain.Blog, error) {
+	reslug := previous.Title != blog.Title || blog.Slug == ""
+	for attempt := 0; ; attempt++ {
+		if reslug {
+			blog.Slug, blog.OldSlugs = previous.Slug, previous.OldSlugs
+			if err := bu.reslug(ctx, blog); err != nil {
+				return nil, err
+			}
+		}
+
+		updatedBlog, err := bu.blogRepository.Update(ctx, blog)
+		if errors.Is(err, domain.ErrSlugTaken) && reslug && attempt < maxSlugAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to update blog: %w", err)
+		}
+		return updatedBlog, nil
+	}
+}
+
+func (bu *BlogUsecase) DeleteBlog(ctx context.Context, blogID string, actor domain.Viewer) error {
+	blog, err := bu.blogRepository.FindByID(ctx, blogID)
+	if err != nil {
+		return err
+	}
+	if !actor.CanManage(blog) {
+		return domain.ErrForbidden
+	}
+	if err := bu.blogRepository.DeleteBlog(ctx, blog); err != nil {
+		return err
+	}
+	if err := bu.revisionRepository.DeleteByBlogID(ctx, blog.ID); err != nil {
+		log.Printf("warning: failed to delete revisions of blog %s: %v", blog.ID, err)
+	}
+	if err := bu.bookmarkRepository.RemoveBlog(ctx, blog.ID); err != nil {
+		log.Printf("warning: failed to remove blog %s from bookmarks: %v", blog.ID, err)
+	}
+	if err := bu.readingListRepository.RemoveBlog(ctx, blog.ID); err != nil {
+		log.Printf("warning: failed to remove blog %s from reading lists: %v", blog.ID, err)
+	}
+	if err := bu.reactionRepository.RemoveTarget(ctx, domain.ReactionTargetBlog, blog.ID); err != nil {
+		log.Printf("warning: failed to delete reactions to blog %s: %v", blog.ID, err)
+	}
+	if blog.Status == domain.BlogStatusPublished {
+		return bu.events.Publish(ctx, domain.Event{
+			Type:    domain.EventBlogDeleted,
+			ActorID: actor.UserID,
+			UserID:  blog.UserID,
+			BlogID:  blog.ID,
+			Data:    map[string]string{"title": blog.Title, "slug": blog.Slug},
+		})
+	}
+	return nil
+}
+
+func (bu *BlogUsecase) PublishBlog(ctx context.Context, blogID string, actor domain.Viewer) (*domain.Blog, error) {
+	return bu.changeStatus(ctx, blogID, actor, domain.BlogStatusPublished)
+}
+
+func (bu *BlogUsecase) UnpublishBlog(ctx context.Context, blogID string, actor domain.Viewer) (*domain.Blog, error) {
+	return bu.changeStatus(ctx, blogID, actor, domain.BlogStatusDraft)
+}
+
+func (bu *BlogUsecase) ArchiveBlog(ctx context.Context, blogID string, actor domain.Viewer) (*domain.Blog, error) {
+	return bu.changeStatus(ctx, blogID, actor, domain.BlogStatusArchived)
+}
+
+// changeStatus moves a blog through its draft/published/archived lifecycle.
+// Only the author or an admin may do so, and moving a blog to the status it
+// already has is a no-op.
+func (bu *BlogUsecase) changeStatus(ctx context.Context, blogID string, actor domain.Viewer, status domain.BlogStatus) (*domain.Blog, error) {
+	blog, err := bu.blogRepository.FindByID(ctx, blogID)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.CanManage(blog) {
+		return nil, domain.ErrForbidden
+	}
+	if blog.Status == status {
+		return blog, nil
+	}
+
+	var publishedAt *time.Time
+	switch status {
+	case domain.BlogStatusPublished:
+		now := time.Now()
+		publishedAt = &now
+	case domain.BlogStatusArchived:
+		// Archived posts keep the date they first went live.
+		publishedAt = blog.PublishedAt
+	}
+
+	if err := bu.blogRepository.UpdateStatus(ctx, blog.ID, status, publishedAt); err != nil {
+		return nil, fmt.Errorf("failed to change blog status: %w", err)
+	}
+
+	wasLive := blog.Status == domain.BlogStatusPublished
+	blog.Status = status
+	blog.PublishAt = nil
+	blog.PublishedAt = publishedAt
+	blog.UpdatedAt = time.Now()
+	switch {
+	case status == domain.BlogStatusPublished:
+		err = bu.publishEvent(ctx, domain.EventBlogPublished, blog, actor.UserID)
+	case wasLive:
+		err = bu.publishEvent(ctx, domain.EventBlogUpdated, blog, actor.UserID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return blog, nil
+}
+
+func (bu *BlogUsecase) publishEvent(ctx context.Context, eventType domain.EventType, blog *domain.Blog, actorID string) error {
+	return bu.events.Publish(ctx, domain.Event{Type: eventType, ActorID: actorID, UserID: blog.UserID, BlogID: blog.ID})
 }
 
+func (bu *BlogUsecase) ScheduleBlog(ctx context.Context, blogID string, publishAt time.Time, actor domain.Viewer) (*domain.Blog, error) {
+	if !publishAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: publish_at must be in the future", domain.ErrInvalidInput)
+	}
 
-func (bu *BlogUsecase) DeleteBlog(ctx context.Context, blogID, userID, userRole string) error {
 	blog, err := bu.blogRepository.FindByID(ctx, blogID)
 	if err != nil {
-		return fmt.Errorf("blog not found")
+		return nil, err
+	}
+	if !actor.CanManage(blog) {
+		return nil, domain.ErrForbidden
 	}
-	if blog.UserID != userID && userRole != "admin" {
-		return fmt.Errorf("unauthorized: only the author or admin can delete this blog")
+	if blog.Status == domain.BlogStatusPublished {
+		return nil, fmt.Errorf("%w: blog is already published", domain.ErrInvalidInput)
 	}
-	return bu.blogRepository.DeleteBlog(ctx, blog)
+
+	if err := bu.blogRepository.Schedule(ctx, blog.ID, publishAt); err != nil {
+		return nil, fmt.Errorf("failed to schedule blog: %w", err)
+	}
+
+	blog.Status = domain.BlogStatusScheduled
+	blog.PublishAt = &publishAt
+	blog.PublishedAt = nil
+	blog.UpdatedAt = time.Now()
+	return blog, nil
 }
 
-func (bu *BlogUsecase) SearchBlogs(ctx context.Context, tag, date, sort, title, userID string) ([]domain.Blog, error) {
-    return bu.blogRepository.SearchBlogs(ctx, tag, date, sort, title, userID)
+func (bu *BlogUsecase) SetCommentMode(ctx context.Context, blogID string, mode domain.CommentMode, actor domain.Viewer) (*domain.Blog, error) {
+	if !mode.IsValid() {
+		return nil, fmt.Errorf("%w: comment mode must be %q, %q or %q", domain.ErrInvalidInput,
+			domain.CommentModeOpen, domain.CommentModeClosed, domain.CommentModeApproval)
+	}
+	blog, err := bu.findManageable(ctx, blogID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bu.blogRepository.SetCommentMode(ctx, blog.ID, mode); err != nil {
+		return nil, err
+	}
+	blog.CommentMode = mode
+	return blog, nil
 }
-func (bu *BlogUsecase) GetSuggestion(req domain.AiSuggestionRequest)(string , error){
+
+func (bu *BlogUsecase) GetSuggestion(req domain.AiSuggestionRequest) (string, error) {
 	return bu.aiService.Getsuggestion(req)
 }
 
-func (bu *BlogUsecase) GetBlogs(ctx context.Context, page, limit int, sort, authorID string) (*domain.PaginatedBlogsResponse, error) {
-    if page < 1 {
-        page = 1
-    }
-    if limit < 1 || limit > 100 {
-        limit = 10
-    }
-
-    blogs, total, err := bu.blogRepository.GetPaginated(ctx, page, limit, sort, authorID)
-    if err != nil {
-        return nil, fmt.Errorf("failed to get blogs: %w", err)
-    }
-
-    totalPages := int((total + int64(limit) - 1) / int64(limit)) // Ceiling division
-    
-    response := &domain.PaginatedBlogsResponse{
-        Blogs:      blogs,
-        Page:       page,
-        Limit:      limit,
-        Total:      total,
-        TotalPages: totalPages,
-        HasNext:    page < totalPages,
-        HasPrev:    page > 1,
-    }
-
-    return response, nil
-}
-
-func (bu *BlogUsecase) GetByIDAndIncrementViews(ctx context.Context, blogID string) (*domain.Blog, error) {
-    // First get the blog
-    blog, err := bu.blogRepository.FindByID(ctx, blogID)
-    if err != nil {
-        return nil, fmt.Errorf("blog not found: %w", err)
-    }
-
-    // Increment view count
-    err = bu.blogRepository.IncrementViewCount(ctx, blogID)
-    if err != nil {
-        // Log error but don't fail the request
-        fmt.Printf("Warning: failed to increment view count for blog %s: %v\n", blogID, err)
-    } else {
-        // Update the blog object with incremented count
-        blog.ViewCount++
-    }
-
-    return blog, nil
+func (bu *BlogUsecase) GetByIDAndIncrementViews(ctx context.Context, blogID string, viewer domain.Viewer, viewKey string) (*domain.Blog, error) {
+	// First get the blog
+	blog, err := bu.blogRepository.FindByID(ctx, blogID)
+	if err != nil {
+		return nil, fmt.Errorf("blog not found: %w", err)
+	}
+
+	// Unpublished posts are reported as missing to anyone who cannot manage them
+	if !viewer.CanSee(blog) {
+		return nil, fmt.Errorf("blog not found: %w", domain.ErrBlogNotFound)
+	}
+	bu.renderLegacy(blog)
+
+	bu.countView(ctx, blog, viewKey)
+	bu.fillReactions(ctx, blog)
+
+	return blog, nil
+}
+
+func (bu *BlogUsecase) GetBySlug(ctx context.Context, slug string, viewer domain.Viewer, viewKey string) (*domain.Blog, error) {
+	blog, err := bu.blogRepository.FindBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("blog not found: %w", err)
+	}
+	if !viewer.CanSee(blog) {
+		return nil, fmt.Errorf("blog not found: %w", domain.ErrBlogNotFound)
+	}
+	bu.renderLegacy(blog)
+
+	// A request for a previous slug is answered with a redirect, so only count
+	// the view once the reader lands on the current slug.
+	if blog.Slug == slug {
+		bu.countView(ctx, blog, viewKey)
+	}
+	bu.fillReactions(ctx, blog)
+
+	return blog, nil
+}
+
+// countView records a view of blog. The stored count catches up when the
+// view counter flushes, so the returned blog is bumped by hand.
+func (bu *BlogUsecase) countView(ctx context.Context, blog *domain.Blog, viewKey string) {
+	counted, err := bu.viewCounter.RecordView(ctx, blog.ID, viewKey)
+	if err != nil {
+		// Log error but don't fail the request
+		log.Printf("warning: failed to count view of blog %s: %v", blog.ID, err)
+		return
+	}
+	if counted {
+		blog.ViewCount++
+	}
+}
+
+func (bu *BlogUsecase) ReconcileCounts(ctx context.Context, actor domain.Viewer) (int64, error) {
+	if actor.Role != domain.RoleAdmin {
+		return 0, domain.ErrForbidden
+	}
+	return bu.blogRepository.ReconcileCounts(ctx, time.Time{})
+}
+
+// fillReactions sets the reaction counts of blogs. The counts are extra
+// detail, so a failure to load them is logged rather than returned.
+func (bu *BlogUsecase) fillReactions(ctx context.Context, blogs ...*domain.Blog) {
+	ids := make([]string, len(blogs))
+	for i, blog := range blogs {
+		ids[i] = blog.ID
+	}
+	counts, err := bu.reactionRepository.Counts(ctx, domain.ReactionTargetBlog, ids)
+	if err != nil {
+		log.Printf("warning: failed to count reactions: %v", err)
+		return
+	}
+	for _, blog := range blogs {
+		blog.Reactions = counts[blog.ID]
+	}
+}
+
+// render fills in the HTML and summary fields derived from blog.Content.
+func (bu *BlogUsecase) render(blog *domain.Blog) error {
+	rendered, err := bu.contentRenderer.Render(blog.ContentFormat, blog.Content)
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+	blog.ContentHTML = rendered.HTML
+	blog.Excerpt = rendered.Excerpt
+	blog.WordCount = rendered.WordCount
+	blog.ReadingTime = rendered.ReadingTime
+	return nil
+}
+
+// renderLegacy renders blogs saved before rendering existed on the fly; they
+// get stored HTML the next time they are edited.
+func (bu *BlogUsecase) renderLegacy(blog *domain.Blog) {
+	if blog.ContentHTML != "" {
+		return
+	}
+	if err := bu.render(blog); err != nil {
+		log.Printf("warning: failed to render blog %s: %v", blog.ID, err)
+	}
 }
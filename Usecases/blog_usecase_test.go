@@ -0,0 +1,121 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"testing"
+)
+
+// The cleanups that follow deleting a blog all succeed.
+type revisionCleanup struct{ domain.IBlogRevisionRepository }
+
+func (revisionCleanup) DeleteByBlogID(context.Context, string) error { return nil }
+
+type bookmarkCleanup struct{ domain.IBookmarkRepository }
+
+func (bookmarkCleanup) RemoveBlog(context.Context, string) error { return nil }
+
+type readingListCleanup struct{ domain.IReadingListRepository }
+
+func (readingListCleanup) RemoveBlog(context.Context, string) error { return nil }
+
+type reactionCleanup struct{ domain.IReactionRepository }
+
+func (reactionCleanup) RemoveTarget(context.Context, domain.ReactionTargetType, string) error {
+	return nil
+}
+
+func TestDeleteBlog(t *testing.T) {
+	draft := domain.Blog{ID: "b1", UserID: "alice", Status: domain.BlogStatusDraft}
+	tests := []struct {
+		name    string
+		blogID  string
+		actor   domain.Viewer
+		wantErr error
+	}{
+		{name: "author", blogID: "b1", actor: domain.Viewer{UserID: "alice", Role: domain.RoleUser}},
+		{name: "admin", blogID: "b1", actor: testAdmin},
+		{name: "someone else", blogID: "b1", actor: domain.Viewer{UserID: "bob", Role: domain.RoleUser}, wantErr: domain.ErrForbidden},
+		{name: "anonymous", blogID: "b1", wantErr: domain.ErrForbidden},
+		{name: "missing blog", blogID: "b2", actor: testAdmin, wantErr: domain.ErrBlogNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blogs := newMemBlogRepository(draft)
+			bu := &BlogUsecase{
+				blogRepository:        blogs,
+				revisionRepository:    revisionCleanup{},
+				bookmarkRepository:    bookmarkCleanup{},
+				readingListRepository: readingListCleanup{},
+				reactionRepository:    reactionCleanup{},
+			}
+
+			err := bu.DeleteBlog(context.Background(), tt.blogID, tt.actor)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DeleteBlog error = %v, want %v", err, tt.wantErr)
+			}
+			_, kept := blogs.blogs["b1"]
+			if deleted := !kept; deleted != (tt.wantErr == nil) {
+				t.Errorf("blog deleted = %v, want %v", deleted, tt.wantErr == nil)
+			}
+		})
+	}
+}
+
+// editDesk stores edits to the blogs it was seeded with.
+type editDesk struct {
+	*memBlogRepository
+	saved []domain.Blog
+}
+
+func (d *editDesk) SlugTaken(context.Context, string, string) (bool, error) { return false, nil }
+
+func (d *editDesk) Update(_ context.Context, blog *domain.Blog) (*domain.Blog, error) {
+	d.saved = append(d.saved, *blog)
+	return blog, nil
+}
+
+func TestUpdateBlog(t *testing.T) {
+	essay := domain.Blog{
+		ID: "b7", UserID: "carol", Title: "On gardens", Slug: "on-gardens", Content: "Soil first.",
+		ContentHTML: "<p>Soil first.</p>", Tags: []string{"gardening"}, Status: domain.BlogStatusDraft,
+	}
+	tests := []struct {
+		name    string
+		input   domain.UpdateBlogInput
+		actor   domain.Viewer
+		wantErr error
+	}{
+		{name: "author", input: domain.UpdateBlogInput{BlogID: "b7", Title: "On walled gardens"}, actor: domain.Viewer{UserID: "carol", Role: domain.RoleUser}},
+		{name: "admin", input: domain.UpdateBlogInput{BlogID: "b7", Title: "On walled gardens"}, actor: testAdmin},
+		{name: "someone else", input: domain.UpdateBlogInput{BlogID: "b7", Title: "Mine now"}, actor: domain.Viewer{UserID: "dave", Role: domain.RoleUser}, wantErr: domain.ErrForbidden},
+		{name: "anonymous", input: domain.UpdateBlogInput{BlogID: "b7", Title: "Mine now"}, wantErr: domain.ErrForbidden},
+		{name: "missing blog", input: domain.UpdateBlogInput{BlogID: "b8", Title: "On walled gardens"}, actor: testAdmin, wantErr: domain.ErrBlogNotFound},
+		{name: "unknown format", input: domain.UpdateBlogInput{BlogID: "b7", ContentFormat: "rtf"}, actor: domain.Viewer{UserID: "carol", Role: domain.RoleUser}, wantErr: domain.ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blogs := &editDesk{memBlogRepository: newMemBlogRepository(essay)}
+			revisions := &revisionStore{revisions: map[string]domain.BlogRevision{}}
+			bu := &BlogUsecase{blogRepository: blogs, revisionRepository: revisions, tagRepository: knownTagRepository{}}
+
+			_, err := bu.Update(context.Background(), tt.input, tt.actor)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Update error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(blogs.saved) != 0 || len(revisions.revisions) != 0 {
+					t.Errorf("saved %v with %d revisions, want nothing stored", blogs.saved, len(revisions.revisions))
+				}
+				return
+			}
+			if len(blogs.saved) != 1 || blogs.saved[0].Title != "On walled gardens" || blogs.saved[0].UserID != "carol" {
+				t.Fatalf("saved %+v, want the retitled blog still by carol", blogs.saved)
+			}
+			if revision := revisions.revisions["1"]; revision.EditorID != tt.actor.UserID || revision.Title != "On gardens" {
+				t.Errorf("revision %+v, want the old title edited by %s", revision, tt.actor.UserID)
+			}
+		})
+	}
+}
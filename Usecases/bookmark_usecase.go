@@ -0,0 +1,356 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	defaultBookmarkLimit = 20
+	maxBookmarkLimit     = 100
+	maxReadingLists      = 100 // per user
+	maxReadingListItems  = 500
+	maxListNameLength    = 100
+	maxListDescLength    = 500
+)
+
+type BookmarkUsecase struct {
+	bookmarkRepository    domain.IBookmarkRepository
+	readingListRepository domain.IReadingListRepository
+	blogRepository        domain.IBlogRepository
+	userRepository        domain.IUserRepository
+}
+
+func NewBookmarkUsecase(bookmarkRepo domain.IBookmarkRepository, readingListRepo domain.IReadingListRepository,
+	blogRepo domain.IBlogRepository, userRepo domain.IUserRepository) domain.IBookmarkUsecase {
+	return &BookmarkUsecase{
+		bookmarkRepository:    bookmarkRepo,
+		readingListRepository: readingListRepo,
+		blogRepository:        blogRepo,
+		userRepository:        userRepo,
+	}
+}
+
+// AddBookmark is idempotent: bookmarking a blog again changes nothing.
+func (u *BookmarkUsecase) AddBookmark(ctx context.Context, blogID string, actor domain.Viewer) error {
+	if actor.UserID == "" {
+		return domain.ErrForbidden
+	}
+	if _, err := u.visibleBlog(ctx, blogID, actor); err != nil {
+		return err
+	}
+	_, err := u.bookmarkRepository.Add(ctx, actor.UserID, blogID, time.Now())
+	return err
+}
+
+func (u *BookmarkUsecase) RemoveBookmark(ctx context.Context, blogID string, actor domain.Viewer) error {
+	if actor.UserID == "" {
+		return domain.ErrForbidden
+	}
+	_, err := u.bookmarkRepository.Remove(ctx, actor.UserID, blogID)
+	return err
+}
+
+// ListBookmarks leaves out bookmarked blogs the actor can no longer see,
+// such as ones their author unpublished, so a page may hold fewer than
+// limit bookmarks.
+func (u *BookmarkUsecase) ListBookmarks(ctx context.Context, page, limit int, actor domain.Viewer) (*domain.BookmarkPage, error) {
+	if actor.UserID == "" {
+		return nil, domain.ErrForbidden
+	}
+	if limit == 0 {
+		limit = defaultBookmarkLimit
+	}
+	if limit < 1 || limit > maxBookmarkLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidInput, maxBookmarkLimit)
+	}
+	if page == 0 {
+		page = 1
+	}
+	if page < 1 {
+		return nil, fmt.Errorf("%w: page must be at least 1", domain.ErrInvalidInput)
+	}
+
+	bookmarks, total, err := u.bookmarkRepository.List(ctx, actor.UserID, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(bookmarks))
+	for i, b := range bookmarks {
+		ids[i] = b.BlogID
+	}
+	summaries, err := u.summaries(ctx, ids, actor)
+	if err != nil {
+		return nil, err
+	}
+	visible := make([]domain.Bookmark, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		if summary, ok := summaries[b.BlogID]; ok {
+			b.Blog = &summary
+			visible = append(visible, b)
+		}
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit)) // Ceiling division
+	return &domain.BookmarkPage{
+		Bookmarks:  visible,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1,
+	}, nil
+}
+
+func (u *BookmarkUsecase) CreateReadingList(ctx context.Context, input domain.ReadingListInput, actor domain.Viewer) (*domain.ReadingList, error) {
+	if actor.UserID == "" {
+		return nil, domain.ErrForbidden
+	}
+	if err := normalizeReadingListInput(&input); err != nil {
+		return nil, err
+	}
+	count, err := u.readingListRepository.CountByUser(ctx, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxReadingLists {
+		return nil, fmt.Errorf("%w: you can have at most %d reading lists", domain.ErrInvalidInput, maxReadingLists)
+	}
+
+	now := time.Now()
+	list := &domain.ReadingList{
+		UserID:      actor.UserID,
+		Name:        input.Name,
+		Description: input.Description,
+		Public:      input.Public,
+		Items:       []domain.ReadingListItem{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := u.readingListRepository.Create(ctx, list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetReadingList resolves the items into blog summaries, skipping blogs the
+// viewer may not see.
+func (u *BookmarkUsecase) GetReadingList(ctx context.Context, listID string, viewer domain.Viewer) (*domain.ReadingList, error) {
+	list, err := u.readingListRepository.FindByID(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	if !list.Public && list.UserID != viewer.UserID {
+		return nil, domain.ErrListNotFound
+	}
+	return u.resolve(ctx, list, viewer)
+}
+
+func (u *BookmarkUsecase) ListReadingLists(ctx context.Context, userID string, viewer domain.Viewer) ([]domain.ReadingList, error) {
+	if _, err := u.userRepository.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return u.readingListRepository.ListByUser(ctx, userID, viewer.UserID == userID)
+}
+
+func (u *BookmarkUsecase) UpdateReadingList(ctx context.Context, listID string, input domain.ReadingListInput, actor domain.Viewer) (*domain.ReadingList, error) {
+	list, err := u.ownedList(ctx, listID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if err := normalizeReadingListInput(&input); err != nil {
+		return nil, err
+	}
+	list.Name = input.Name
+	list.Description = input.Description
+	list.Public = input.Public
+	list.UpdatedAt = time.Now()
+	if err := u.readingListRepository.Update(ctx, list); err != nil {
+		return nil, err
+	}
+	return u.resolve(ctx, list, actor)
+}
+
+func (u *BookmarkUsecase) DeleteReadingList(ctx context.Context, listID string, actor domain.Viewer) error {
+	if _, err := u.ownedList(ctx, listID, actor); err != nil {
+		return err
+	}
+	return u.readingListRepository.Delete(ctx, listID)
+}
+
+func (u *BookmarkUsecase) AddToReadingList(ctx context.Context, listID, blogID string, position *int, actor domain.Viewer) (*domain.ReadingList, error) {
+	list, err := u.ownedList(ctx, listID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if len(list.Items) >= maxReadingListItems {
+		return nil, fmt.Errorf("%w: a reading list can hold at most %d blogs", domain.ErrInvalidInput, maxReadingListItems)
+	}
+	at := -1
+	if position != nil {
+		if *position < 0 {
+			return nil, fmt.Errorf("%w: position cannot be negative", domain.ErrInvalidInput)
+		}
+		at = *position
+	}
+	if _, err := u.visibleBlog(ctx, blogID, actor); err != nil {
+		return nil, err
+	}
+
+	added, err := u.readingListRepository.AddItem(ctx, list.ID, domain.ReadingListItem{BlogID: blogID, AddedAt: time.Now()}, at)
+	if err != nil {
+		return nil, err
+	}
+	if !added {
+		return nil, fmt.Errorf("%w: the blog is already in this list", domain.ErrInvalidInput)
+	}
+	return u.GetReadingList(ctx, list.ID, actor)
+}
+
+func (u *BookmarkUsecase) RemoveFromReadingList(ctx context.Context, listID, blogID string, actor domain.Viewer) (*domain.ReadingList, error) {
+	list, err := u.ownedList(ctx, listID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.readingListRepository.RemoveItem(ctx, list.ID, blogID); err != nil {
+		return nil, err
+	}
+	return u.GetReadingList(ctx, list.ID, actor)
+}
+
+func (u *BookmarkUsecase) ReorderReadingList(ctx context.Context, listID string, blogIDs []string, actor domain.Viewer) (*domain.ReadingList, error) {
+	list, err := u.ownedList(ctx, listID, actor)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(list.Items))
+	for i, item := range list.Items {
+		ids[i] = item.BlogID
+	}
+	summaries, err := u.summaries(ctx, ids, actor)
+	if err != nil {
+		return nil, err
+	}
+	byBlog := make(map[string]domain.ReadingListItem, len(summaries))
+	for _, item := range list.Items {
+		if _, ok := summaries[item.BlogID]; ok {
+			byBlog[item.BlogID] = item
+		}
+	}
+	visible := len(byBlog)
+	if len(blogIDs) != visible {
+		return nil, fmt.Errorf("%w: blog_ids must list each of the %d items exactly once", domain.ErrInvalidInput, visible)
+	}
+	ordered := make([]domain.ReadingListItem, 0, len(blogIDs))
+	for _, id := range blogIDs {
+		item, ok := byBlog[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: blog_ids must list each of the %d items exactly once", domain.ErrInvalidInput, visible)
+		}
+		delete(byBlog, id) // a repeated ID is then reported as unknown
+		ordered = append(ordered, item)
+	}
+	// The visible items take the places visible items had, in their new
+	// order; the others stay where they are.
+	items := make([]domain.ReadingListItem, len(list.Items))
+	for i, item := range list.Items {
+		if _, ok := summaries[item.BlogID]; ok {
+			item, ordered = ordered[0], ordered[1:]
+		}
+		items[i] = item
+	}
+
+	reordered, err := u.readingListRepository.ReorderItems(ctx, list.ID, list.Items, items)
+	if err != nil {
+		return nil, err
+	}
+	if !reordered {
+		return nil, fmt.Errorf("%w: the list changed while it was being reordered; reload it and try again", domain.ErrInvalidInput)
+	}
+	return u.GetReadingList(ctx, list.ID, actor)
+}
+
+// ownedList finds a list that actor may change. Other users' lists are
+// reported as missing when private and forbidden when public.
+func (u *BookmarkUsecase) ownedList(ctx context.Context, listID string, actor domain.Viewer) (*domain.ReadingList, error) {
+	if actor.UserID == "" {
+		return nil, domain.ErrForbidden
+	}
+	list, err := u.readingListRepository.FindByID(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	if list.UserID != actor.UserID {
+		if !list.Public {
+			return nil, domain.ErrListNotFound
+		}
+		return nil, domain.ErrForbidden
+	}
+	return list, nil
+}
+
+func (u *BookmarkUsecase) resolve(ctx context.Context, list *domain.ReadingList, viewer domain.Viewer) (*domain.ReadingList, error) {
+	ids := make([]string, len(list.Items))
+	for i, item := range list.Items {
+		ids[i] = item.BlogID
+	}
+	summaries, err := u.summaries(ctx, ids, viewer)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]domain.ReadingListItem, 0, len(list.Items))
+	for _, item := range list.Items {
+		if summary, ok := summaries[item.BlogID]; ok {
+			item.Blog = &summary
+			items = append(items, item)
+		}
+	}
+	list.Items = items
+	return list, nil
+}
+
+// summaries loads the blogs among blogIDs that viewer may see.
+func (u *BookmarkUsecase) summaries(ctx context.Context, blogIDs []string, viewer domain.Viewer) (map[string]domain.BlogSummary, error) {
+	blogs, err := u.blogRepository.FindByIDs(ctx, blogIDs)
+	if err != nil {
+		return nil, err
+	}
+	summaries := make(map[string]domain.BlogSummary, len(blogs))
+	for id, blog := range blogs {
+		if viewer.CanSee(&blog) {
+			summaries[id] = blog.Summary()
+		}
+	}
+	return summaries, nil
+}
+
+func (u *BookmarkUsecase) visibleBlog(ctx context.Context, blogID string, viewer domain.Viewer) (*domain.Blog, error) {
+	blog, err := u.blogRepository.FindByID(ctx, blogID)
+	if err != nil {
+		return nil, err
+	}
+	if !viewer.CanSee(blog) {
+		return nil, domain.ErrBlogNotFound
+	}
+	return blog, nil
+}
+
+func normalizeReadingListInput(input *domain.ReadingListInput) error {
+	input.Name = strings.TrimSpace(input.Name)
+	input.Description = strings.TrimSpace(input.Description)
+	if input.Name == "" {
+		return fmt.Errorf("%w: name is required", domain.ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(input.Name) > maxListNameLength {
+		return fmt.Errorf("%w: name must be at most %d characters", domain.ErrInvalidInput, maxListNameLength)
+	}
+	if utf8.RuneCountInString(input.Description) > maxListDescLength {
+		return fmt.Errorf("%w: description must be at most %d characters", domain.ErrInvalidInput, maxListDescLength)
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// shelf holds reading lists in memory. A list whose ID is in busy changes
+// order between the read and the reorder.
+type shelf struct {
+	domain.IReadingListRepository
+	lists map[string]domain.ReadingList
+	busy  map[string]bool
+}
+
+func (s *shelf) FindByID(_ context.Context, listID string) (*domain.ReadingList, error) {
+	list, ok := s.lists[listID]
+	if !ok {
+		return nil, domain.ErrListNotFound
+	}
+	list.Items = slices.Clone(list.Items)
+	return &list, nil
+}
+
+func (s *shelf) ReorderItems(_ context.Context, listID string, previous, items []domain.ReadingListItem) (bool, error) {
+	list := s.lists[listID]
+	if s.busy[listID] || !slices.EqualFunc(list.Items, previous, func(a, b domain.ReadingListItem) bool { return a.BlogID == b.BlogID }) {
+		return false, nil
+	}
+	list.Items = items
+	s.lists[listID] = list
+	return true, nil
+}
+
+// catalog serves blogs by ID.
+type catalog struct {
+	domain.IBlogRepository
+	blogs map[string]domain.Blog
+}
+
+func (c catalog) FindByIDs(_ context.Context, blogIDs []string) (map[string]domain.Blog, error) {
+	found := map[string]domain.Blog{}
+	for _, id := range blogIDs {
+		if blog, ok := c.blogs[id]; ok {
+			found[id] = blog
+		}
+	}
+	return found, nil
+}
+
+type savedBlogs struct {
+	domain.IBookmarkRepository
+	bookmarks []domain.Bookmark
+}
+
+func (s savedBlogs) List(context.Context, string, int, int) ([]domain.Bookmark, int64, error) {
+	return s.bookmarks, int64(len(s.bookmarks)), nil
+}
+
+func blogIDsOf(items []domain.ReadingListItem) []string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.BlogID
+	}
+	return ids
+}
+
+func TestReorderReadingList(t *testing.T) {
+	reader := domain.Viewer{UserID: "rita", Role: domain.RoleUser}
+	blogs := catalog{blogs: map[string]domain.Blog{
+		"go":      {ID: "go", UserID: "gopher", Title: "Go", Status: domain.BlogStatusPublished},
+		"rust":    {ID: "rust", UserID: "ferris", Title: "Rust", Status: domain.BlogStatusPublished},
+		"zig":     {ID: "zig", UserID: "ziggy", Title: "Zig", Status: domain.BlogStatusPublished},
+		"retired": {ID: "retired", UserID: "ferris", Title: "Old news", Status: domain.BlogStatusDraft},
+		"pulled":  {ID: "pulled", UserID: "ziggy", Title: "Pulled", Status: domain.BlogStatusPublished, Hidden: true},
+	}}
+	item := func(id string) domain.ReadingListItem {
+		return domain.ReadingListItem{BlogID: id, AddedAt: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+	}
+	tests := []struct {
+		name      string
+		listID    string
+		blogIDs   []string
+		actor     domain.Viewer
+		wantErr   error
+		wantOrder []string // stored order
+		wantShown []string // order the reader gets back
+	}{
+		{
+			name:      "every item visible",
+			listID:    "weekend",
+			blogIDs:   []string{"zig", "go", "rust"},
+			actor:     reader,
+			wantOrder: []string{"zig", "go", "rust"},
+			wantShown: []string{"zig", "go", "rust"},
+		},
+		{
+			name:      "unpublished and hidden blogs keep their place",
+			listID:    "backlog",
+			blogIDs:   []string{"rust", "go"},
+			actor:     reader,
+			wantOrder: []string{"retired", "rust", "pulled", "go"},
+			wantShown: []string{"rust", "go"},
+		},
+		{
+			name:    "a visible item left out",
+			listID:  "backlog",
+			blogIDs: []string{"rust"},
+			actor:   reader,
+			wantErr: domain.ErrInvalidInput,
+		},
+		{
+			name:    "an item the reader cannot see",
+			listID:  "backlog",
+			blogIDs: []string{"go", "retired"},
+			actor:   reader,
+			wantErr: domain.ErrInvalidInput,
+		},
+		{
+			name:    "a repeated item",
+			listID:  "weekend",
+			blogIDs: []string{"go", "go", "rust"},
+			actor:   reader,
+			wantErr: domain.ErrInvalidInput,
+		},
+		{
+			name:    "list changed meanwhile",
+			listID:  "crowded",
+			blogIDs: []string{"rust", "go"},
+			actor:   reader,
+			wantErr: domain.ErrInvalidInput,
+		},
+		{
+			name:    "someone else's public list",
+			listID:  "weekend",
+			blogIDs: []string{"zig", "go", "rust"},
+			actor:   domain.Viewer{UserID: "mallory", Role: domain.RoleUser},
+			wantErr: domain.ErrForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lists := &shelf{
+				lists: map[string]domain.ReadingList{
+					"weekend": {ID: "weekend", UserID: "rita", Public: true, Items: []domain.ReadingListItem{item("go"), item("rust"), item("zig")}},
+					"backlog": {ID: "backlog", UserID: "rita", Items: []domain.ReadingListItem{item("retired"), item("go"), item("pulled"), item("rust")}},
+					"crowded": {ID: "crowded", UserID: "rita", Items: []domain.ReadingListItem{item("go"), item("rust")}},
+				},
+				busy: map[string]bool{"crowded": true},
+			}
+			before := blogIDsOf(lists.lists[tt.listID].Items)
+			u := NewBookmarkUsecase(nil, lists, blogs, nil)
+
+			list, err := u.ReorderReadingList(context.Background(), tt.listID, tt.blogIDs, tt.actor)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ReorderReadingList: %v, want %v", err, tt.wantErr)
+				}
+				if got := blogIDsOf(lists.lists[tt.listID].Items); !slices.Equal(got, before) {
+					t.Errorf("stored order = %v, want it unchanged", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReorderReadingList: %v", err)
+			}
+			if got := blogIDsOf(lists.lists[tt.listID].Items); !slices.Equal(got, tt.wantOrder) {
+				t.Errorf("stored order = %v, want %v", got, tt.wantOrder)
+			}
+			if got := blogIDsOf(list.Items); !slices.Equal(got, tt.wantShown) {
+				t.Errorf("returned order = %v, want %v", got, tt.wantShown)
+			}
+			for _, item := range list.Items {
+				if item.Blog == nil || item.Blog.Title != blogs.blogs[item.BlogID].Title {
+					t.Errorf("item %s resolved to %+v", item.BlogID, item.Blog)
+				}
+			}
+		})
+	}
+}
+
+func TestListBookmarksSkipsBlogsOutOfReach(t *testing.T) {
+	blogs := catalog{blogs: map[string]domain.Blog{
+		"tour":  {ID: "tour", UserID: "gopher", Title: "A tour", Status: domain.BlogStatusPublished},
+		"draft": {ID: "draft", UserID: "gopher", Title: "WIP", Status: domain.BlogStatusDraft},
+		"mine":  {ID: "mine", UserID: "rita", Title: "Notes", Status: domain.BlogStatusDraft},
+	}}
+	saved := savedBlogs{bookmarks: []domain.Bookmark{{BlogID: "tour"}, {BlogID: "draft"}, {BlogID: "gone"}, {BlogID: "mine"}}}
+	u := NewBookmarkUsecase(saved, nil, blogs, nil)
+
+	page, err := u.ListBookmarks(context.Background(), 0, 0, domain.Viewer{UserID: "rita", Role: domain.RoleUser})
+	if err != nil {
+		t.Fatalf("ListBookmarks: %v", err)
+	}
+	var got []string
+	for _, b := range page.Bookmarks {
+		got = append(got, b.Blog.Title)
+	}
+	if want := []string{"A tour", "Notes"}; !slices.Equal(got, want) {
+		t.Errorf("bookmarks = %v, want %v", got, want)
+	}
+	if page.Total != 4 || page.Limit != defaultBookmarkLimit {
+		t.Errorf("page = %+v, want 4 bookmarks in total and the default limit", page)
+	}
+}
@@ -1,34 +1,553 @@
 package usecases
 
 import (
-    "blog-api/Domain"
-    "context"
-    "time"
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	defaultCommentLimit = 20
+	maxCommentLimit     = 100
+	// maxThreadComments caps how many replies one thread expansion returns.
+	maxThreadComments = 500
+	// maxModerationBatch caps how many comments one bulk action may touch.
+	maxModerationBatch = 100
 )
 
 type CommentUsecase struct {
-    CommentRepository domain.ICommentRepository
+	CommentRepository domain.ICommentRepository
+	blogRepository    domain.IBlogRepository
+	events            domain.IEventBus
+	maxDepth          int
+	editWindow        time.Duration
+}
+
+// NewCommentUsecase allows replies to nest up to maxDepth levels below a
+// top-level comment, and authors to edit a comment for editWindow after
+// posting it.
+func NewCommentUsecase(repo domain.ICommentRepository, blogRepo domain.IBlogRepository, events domain.IEventBus, maxDepth int,
+	editWindow time.Duration) domain.ICommentUsecase {
+	return &CommentUsecase{
+		CommentRepository: repo,
+		blogRepository:    blogRepo,
+		events:            events,
+		maxDepth:          maxDepth,
+		editWindow:        editWindow,
+	}
+}
+
+func (u *CommentUsecase) CreateComment(ctx context.Context, blogID, parentID, content string, actor domain.Viewer) (*domain.Comment, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, fmt.Errorf("%w: content must not be empty", domain.ErrInvalidInput)
+	}
+	blog, err := u.visibleBlog(ctx, blogID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &domain.Comment{
+		BlogId:    blog.ID,
+		UserId:    actor.UserID,
+		Ancestors: []string{},
+		Content:   content,
+		Status:    domain.CommentStatusApproved,
+		CreatedAt: time.Now(),
+	}
+	// The blog's author and admins are never held back by its settings.
+	if !actor.CanManage(blog) {
+		switch blog.CommentMode {
+		case domain.CommentModeClosed:
+			return nil, fmt.Errorf("%w: comments are closed on this blog", domain.ErrForbidden)
+		case domain.CommentModeApproval:
+			comment.Status = domain.CommentStatusPending
+		}
+	}
+	var parent *domain.Comment
+	if parentID != "" {
+		parent, err = u.findInBlog(ctx, blog.ID, parentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.Deleted {
+			return nil, fmt.Errorf("%w: cannot reply to a deleted comment", domain.ErrInvalidInput)
+		}
+		if parent.Status != domain.CommentStatusApproved {
+			return nil, fmt.Errorf("%w: cannot reply to a comment awaiting moderation", domain.ErrInvalidInput)
+		}
+		if parent.Depth >= u.maxDepth {
+			return nil, fmt.Errorf("%w: replies can only be nested %d levels deep", domain.ErrInvalidInput, u.maxDepth)
+		}
+		comment.ParentID = parent.ID
+		comment.Ancestors = append(append([]string{}, parent.Ancestors...), parent.ID)
+		comment.Depth = parent.Depth + 1
+	}
+	created, err := u.CommentRepository.Create(ctx, comment)
+	if err != nil {
+		return nil, err
+	}
+	if created.Status == domain.CommentStatusApproved {
+		u.countComments(ctx, blog.ID, 1)
+		if err := u.publishComment(ctx, created, blog.UserID, parent); err != nil {
+			return nil, err
+		}
+	}
+	return created, nil
 }
 
-func NewCommentUsecase(repo domain.ICommentRepository) domain.ICommentUsecase {
-    return &CommentUsecase{CommentRepository: repo}
+func (u *CommentUsecase) ListComments(ctx context.Context, query domain.CommentQuery, viewer domain.Viewer) (*domain.CommentPage, error) {
+	blog, err := u.visibleBlog(ctx, query.BlogID, viewer)
+	if err != nil {
+		return nil, err
+	}
+	query.ParentID = ""
+	query.Audience = commentAudience(blog, viewer)
+	return u.list(ctx, query)
 }
 
-func (u *CommentUsecase) CreateComment(ctx context.Context, blogID, userID, content string) (*domain.Comment, error) {
-    comment := &domain.Comment{
-        ID:        "",
-        BlogId:    blogID,
-        UserId:    userID,
-        Content:   content,
-        CreatedAt: time.Now().Format(time.RFC3339),
-    }
-    return u.CommentRepository.Create(ctx, comment)
+func (u *CommentUsecase) GetReplies(ctx context.Context, blogID, commentID string, query domain.CommentQuery, viewer domain.Viewer) (*domain.CommentPage, error) {
+	blog, err := u.visibleBlog(ctx, blogID, viewer)
+	if err != nil {
+		return nil, err
+	}
+	query.Audience = commentAudience(blog, viewer)
+	parent, err := u.findVisible(ctx, blog.ID, commentID, query.Audience)
+	if err != nil {
+		return nil, err
+	}
+	query.BlogID = blog.ID
+	query.ParentID = parent.ID
+	return u.list(ctx, query)
+}
+
+// GetThread returns a comment with every reply beneath it, oldest first at
+// each level.
+func (u *CommentUsecase) GetThread(ctx context.Context, blogID, commentID string, viewer domain.Viewer) (*domain.CommentThread, error) {
+	blog, err := u.visibleBlog(ctx, blogID, viewer)
+	if err != nil {
+		return nil, err
+	}
+	audience := commentAudience(blog, viewer)
+	root, err := u.findVisible(ctx, blog.ID, commentID, audience)
+	if err != nil {
+		return nil, err
+	}
+	descendants, err := u.CommentRepository.Descendants(ctx, root.ID, audience, maxThreadComments+1)
+	if err != nil {
+		return nil, err
+	}
+
+	thread := &domain.CommentThread{Comment: *publicComment(root), Replies: []*domain.CommentThread{}}
+	if len(descendants) > maxThreadComments {
+		descendants = descendants[:maxThreadComments]
+		thread.Truncated = true
+	}
+	// Parents are always older than their replies, so each reply's parent
+	// has been placed by the time the reply comes up.
+	nodes := map[string]*domain.CommentThread{root.ID: thread}
+	for _, c := range descendants {
+		parent, ok := nodes[c.ParentID]
+		if !ok {
+			continue
+		}
+		node := &domain.CommentThread{Comment: *publicComment(&c), Replies: []*domain.CommentThread{}}
+		parent.Replies = append(parent.Replies, node)
+		nodes[c.ID] = node
+	}
+	return thread, nil
+}
+
+func (u *CommentUsecase) UpdateComment(ctx context.Context, commentID, content string, actor domain.Viewer) (*domain.Comment, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, fmt.Errorf("%w: content must not be empty", domain.ErrInvalidInput)
+	}
+	comment, err := u.findLive(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if actor.UserID == "" || actor.UserID != comment.UserId {
+		return nil, domain.ErrForbidden
+	}
+	if time.Since(comment.CreatedAt) > u.editWindow {
+		return nil, fmt.Errorf("%w: comments can only be edited within %s of posting", domain.ErrForbidden, u.editWindow)
+	}
+	if content == comment.Content {
+		return publicComment(comment), nil
+	}
+
+	updated, err := u.CommentRepository.UpdateContent(ctx, comment.ID, content, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return publicComment(updated), nil
+}
+
+func (u *CommentUsecase) GetCommentHistory(ctx context.Context, commentID string, actor domain.Viewer) (*domain.Comment, error) {
+	comment, err := u.CommentRepository.FindByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	canModerate, err := u.canModerate(ctx, comment.BlogId, actor)
+	if err != nil {
+		return nil, err
+	}
+	if !canModerate {
+		return nil, domain.ErrForbidden
+	}
+	if comment.Edits == nil {
+		comment.Edits = []domain.CommentEdit{}
+	}
+	return comment, nil
+}
+
+// DeleteComment removes a comment outright unless it has replies, pending
+// ones included, in which case it leaves a tombstone so the thread stays
+// intact and the moderation queue keeps its replies. Tombstones their
+// authors left without replies are removed along the way; the others stay
+// so a moderator can still unhide them.
+func (u *CommentUsecase) DeleteComment(ctx context.Context, commentID string, actor domain.Viewer) error {
+	comment, err := u.findLive(ctx, commentID)
+	if err != nil {
+		return err
+	}
+	if actor.UserID == "" || actor.UserID != comment.UserId {
+		canModerate, err := u.canModerate(ctx, comment.BlogId, actor)
+		if err != nil {
+			return err
+		}
+		if !canModerate {
+			return domain.ErrForbidden
+		}
+	}
+
+	hasReplies, err := u.hasReplies(ctx, comment)
+	if err != nil {
+		return err
+	}
+	if hasReplies {
+		if err := u.CommentRepository.Tombstone(ctx, comment.ID, actor.UserID, time.Now()); err != nil {
+			return err
+		}
+		u.uncount(ctx, comment)
+		return nil
+	}
+	if err := u.CommentRepository.Delete(ctx, comment.ID); err != nil {
+		return err
+	}
+	u.uncount(ctx, comment)
+	// Tombstones are not counted, so removing them changes no count.
+	for parentID := comment.ParentID; parentID != ""; {
+		parent, err := u.CommentRepository.FindByID(ctx, parentID)
+		if err != nil || !parent.Deleted || parent.DeletedBy != parent.UserId {
+			break
+		}
+		if hasReplies, err := u.hasReplies(ctx, parent); err != nil || hasReplies {
+			break
+		}
+		if err := u.CommentRepository.Delete(ctx, parent.ID); err != nil {
+			return err
+		}
+		parentID = parent.ParentID
+	}
+	return nil
+}
+
+// hasReplies reports whether deleting comment outright would take replies
+// with it. ReplyCount only counts approved replies, so the pending ones are
+// looked up.
+func (u *CommentUsecase) hasReplies(ctx context.Context, comment *domain.Comment) (bool, error) {
+	if comment.ReplyCount > 0 {
+		return true, nil
+	}
+	return u.CommentRepository.HasReplies(ctx, comment.ID)
+}
+
+func (u *CommentUsecase) ListPending(ctx context.Context, blogID string, page, limit int, actor domain.Viewer) (*domain.CommentPage, error) {
+	if blogID == "" && actor.Role != domain.RoleAdmin {
+		return nil, domain.ErrForbidden
+	}
+	if blogID != "" {
+		blog, err := u.blogRepository.FindByID(ctx, blogID)
+		if err != nil {
+			return nil, err
+		}
+		if !actor.CanManage(blog) {
+			return nil, domain.ErrForbidden
+		}
+	}
+	page, limit, err := commentPageParams(page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, total, err := u.CommentRepository.ListPending(ctx, blogID, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	return commentPage(comments, total, page, limit), nil
+}
+
+func (u *CommentUsecase) ModerateComments(ctx context.Context, commentIDs []string, approve bool, actor domain.Viewer) (*domain.ModerationResult, error) {
+	if len(commentIDs) == 0 {
+		return nil, fmt.Errorf("%w: comment_ids must not be empty", domain.ErrInvalidInput)
+	}
+	if len(commentIDs) > maxModerationBatch {
+		return nil, fmt.Errorf("%w: at most %d comments can be moderated at once", domain.ErrInvalidInput, maxModerationBatch)
+	}
+	status := domain.CommentStatusRejected
+	if approve {
+		status = domain.CommentStatusApproved
+	}
+
+	result := &domain.ModerationResult{Processed: []string{}, Skipped: map[string]string{}}
+	allowed := map[string]bool{} // by blog ID
+	seen := map[string]bool{}
+	now := time.Now()
+	for _, id := range commentIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		comment, err := u.CommentRepository.FindByID(ctx, id)
+		if errors.Is(err, domain.ErrCommentNotFound) {
+			result.Skipped[id] = "not found"
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		ok, seen := allowed[comment.BlogId]
+		if !seen {
+			if ok, err = u.canModerate(ctx, comment.BlogId, actor); err != nil {
+				return nil, err
+			}
+			allowed[comment.BlogId] = ok
+		}
+		if !ok {
+			result.Skipped[id] = "forbidden"
+			continue
+		}
+
+		err = u.CommentRepository.SetStatus(ctx, comment.ID, status, actor.UserID, now)
+		if errors.Is(err, domain.ErrCommentNotFound) {
+			result.Skipped[id] = "not pending"
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if approve {
+			u.countComments(ctx, comment.BlogId, 1)
+			if err := u.publishApproved(ctx, comment); err != nil {
+				return nil, err
+			}
+		}
+		result.Processed = append(result.Processed, id)
+	}
+	return result, nil
 }
 
-func (u *CommentUsecase) GetCommentsByBlogID(ctx context.Context, blogID string) ([]domain.Comment, error) {
-    return u.CommentRepository.GetByBlogID(ctx, blogID)
+// countComments adds delta to the comment count stored on a blog. A
+// failure is only logged; the counter reconciler corrects the count later.
+func (u *CommentUsecase) countComments(ctx context.Context, blogID string, delta int) {
+	if err := u.blogRepository.IncrementCounts(ctx, blogID, 0, delta); err != nil {
+		log.Printf("warning: failed to update comment count of blog %s: %v", blogID, err)
+	}
 }
 
-func (u *CommentUsecase) DeleteComment(ctx context.Context, commentID, userID string) error {
-    return u.CommentRepository.Delete(ctx, commentID, userID)
-}
\ No newline at end of file
+// publishComment announces a newly visible comment to the blog's author
+// and, for a reply, to the author of the parent comment.
+func (u *CommentUsecase) publishComment(ctx context.Context, comment *domain.Comment, blogAuthorID string, parent *domain.Comment) error {
+	err := u.events.Publish(ctx, domain.Event{
+		Type:      domain.EventCommentCreated,
+		ActorID:   comment.UserId,
+		UserID:    blogAuthorID,
+		BlogID:    comment.BlogId,
+		CommentID: comment.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if parent != nil {
+		return u.events.Publish(ctx, domain.Event{
+			Type:      domain.EventCommentReplied,
+			ActorID:   comment.UserId,
+			UserID:    parent.UserId,
+			BlogID:    comment.BlogId,
+			CommentID: parent.ID,
+			Data:      map[string]string{"reply_id": comment.ID},
+		})
+	}
+	return nil
+}
+
+// publishApproved looks up what publishComment needs for a comment that was
+// held for moderation. The approval stands even when this fails.
+func (u *CommentUsecase) publishApproved(ctx context.Context, comment *domain.Comment) error {
+	blog, err := u.blogRepository.FindByID(ctx, comment.BlogId)
+	if err != nil {
+		return fmt.Errorf("failed to announce comment %s: %w", comment.ID, err)
+	}
+	var parent *domain.Comment
+	if comment.ParentID != "" {
+		if parent, err = u.CommentRepository.FindByID(ctx, comment.ParentID); err != nil {
+			return fmt.Errorf("failed to announce comment %s: %w", comment.ID, err)
+		}
+	}
+	return u.publishComment(ctx, comment, blog.UserID, parent)
+}
+
+// uncount takes a live comment that was just deleted off its blog's count,
+// if it was counted there.
+func (u *CommentUsecase) uncount(ctx context.Context, comment *domain.Comment) {
+	if comment.Status == domain.CommentStatusApproved {
+		u.countComments(ctx, comment.BlogId, -1)
+	}
+}
+
+func (u *CommentUsecase) list(ctx context.Context, query domain.CommentQuery) (*domain.CommentPage, error) {
+	if query.Sort == "" {
+		query.Sort = domain.CommentSortOldest
+	}
+	if !query.Sort.IsValid() {
+		return nil, fmt.Errorf("%w: sort must be %q, %q or %q", domain.ErrInvalidInput,
+			domain.CommentSortOldest, domain.CommentSortNewest, domain.CommentSortTop)
+	}
+	var err error
+	if query.Page, query.Limit, err = commentPageParams(query.Page, query.Limit); err != nil {
+		return nil, err
+	}
+
+	comments, total, err := u.CommentRepository.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return commentPage(comments, total, query.Page, query.Limit), nil
+}
+
+func commentPageParams(page, limit int) (int, int, error) {
+	if limit == 0 {
+		limit = defaultCommentLimit
+	}
+	if limit < 1 || limit > maxCommentLimit {
+		return 0, 0, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidInput, maxCommentLimit)
+	}
+	if page == 0 {
+		page = 1
+	}
+	if page < 1 {
+		return 0, 0, fmt.Errorf("%w: page must be at least 1", domain.ErrInvalidInput)
+	}
+	return page, limit, nil
+}
+
+func commentPage(comments []domain.Comment, total int64, page, limit int) *domain.CommentPage {
+	for i := range comments {
+		publicComment(&comments[i])
+	}
+	totalPages := int((total + int64(limit) - 1) / int64(limit)) // Ceiling division
+	return &domain.CommentPage{
+		Comments:   comments,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1,
+	}
+}
+
+// visibleBlog finds a blog the viewer may see; any other blog is reported
+// as not found.
+func (u *CommentUsecase) visibleBlog(ctx context.Context, blogID string, viewer domain.Viewer) (*domain.Blog, error) {
+	blog, err := u.blogRepository.FindByID(ctx, blogID)
+	if err != nil {
+		return nil, err
+	}
+	if !viewer.CanSee(blog) {
+		return nil, domain.ErrBlogNotFound
+	}
+	return blog, nil
+}
+
+func commentAudience(blog *domain.Blog, viewer domain.Viewer) domain.CommentAudience {
+	return domain.CommentAudience{UserID: viewer.UserID, Moderator: viewer.CanManage(blog)}
+}
+
+func (u *CommentUsecase) findInBlog(ctx context.Context, blogID, commentID string) (*domain.Comment, error) {
+	comment, err := u.CommentRepository.FindByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.BlogId != blogID {
+		return nil, domain.ErrCommentNotFound
+	}
+	return comment, nil
+}
+
+// findVisible finds a comment of the blog that audience may see.
+func (u *CommentUsecase) findVisible(ctx context.Context, blogID, commentID string, audience domain.CommentAudience) (*domain.Comment, error) {
+	comment, err := u.findInBlog(ctx, blogID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	switch comment.Status {
+	case domain.CommentStatusApproved:
+	case domain.CommentStatusPending:
+		if !audience.Moderator && comment.UserId != audience.UserID {
+			return nil, domain.ErrCommentNotFound
+		}
+	default:
+		return nil, domain.ErrCommentNotFound
+	}
+	return comment, nil
+}
+
+func (u *CommentUsecase) findLive(ctx context.Context, commentID string) (*domain.Comment, error) {
+	comment, err := u.CommentRepository.FindByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.Deleted {
+		return nil, domain.ErrCommentNotFound
+	}
+	return comment, nil
+}
+
+// canModerate reports whether actor moderates the comments of a blog:
+// removing others' comments, seeing edit history and working through the
+// approval queue. That is admins and the blog's owner.
+func (u *CommentUsecase) canModerate(ctx context.Context, blogID string, actor domain.Viewer) (bool, error) {
+	if actor.Role == domain.RoleAdmin {
+		return true, nil
+	}
+	if actor.UserID == "" {
+		return false, nil
+	}
+	blog, err := u.blogRepository.FindByID(ctx, blogID)
+	if errors.Is(err, domain.ErrBlogNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return actor.CanManage(blog), nil
+}
+
+// publicComment strips what only moderators may see: earlier versions and,
+// on a tombstone, the content and author.
+func publicComment(comment *domain.Comment) *domain.Comment {
+	comment.Edits = nil
+	if comment.Deleted {
+		comment.Content = domain.DeletedCommentContent
+		comment.UserId = ""
+		comment.DeletedBy = ""
+	}
+	return comment
+}
@@ -0,0 +1,586 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// memCommentRepository keeps comments in a map and mirrors how the Mongo
+// repository tombstones, restores and deletes them.
+type memCommentRepository struct {
+	domain.ICommentRepository
+	comments map[string]*domain.Comment
+}
+
+func newMemCommentRepository(comments ...domain.Comment) *memCommentRepository {
+	r := &memCommentRepository{comments: map[string]*domain.Comment{}}
+	for _, c := range comments {
+		if c.Status == "" {
+			c.Status = domain.CommentStatusApproved
+		}
+		r.comments[c.ID] = &c
+	}
+	return r
+}
+
+func (r *memCommentRepository) Create(_ context.Context, comment *domain.Comment) (*domain.Comment, error) {
+	created := *comment
+	created.ID = "n" + strconv.Itoa(len(r.comments)+1)
+	r.comments[created.ID] = &created
+	if parent, ok := r.comments[created.ParentID]; ok && created.Status == domain.CommentStatusApproved {
+		parent.ReplyCount++
+	}
+	return &created, nil
+}
+
+func (r *memCommentRepository) SetStatus(_ context.Context, id string, status domain.CommentStatus, moderatorID string, at time.Time) error {
+	c, ok := r.comments[id]
+	if !ok || c.Deleted || c.Status != domain.CommentStatusPending {
+		return domain.ErrCommentNotFound
+	}
+	c.Status = status
+	if parent, ok := r.comments[c.ParentID]; ok && status == domain.CommentStatusApproved {
+		parent.ReplyCount++
+	}
+	return nil
+}
+
+func (r *memCommentRepository) FindByID(_ context.Context, id string) (*domain.Comment, error) {
+	c, ok := r.comments[id]
+	if !ok {
+		return nil, domain.ErrCommentNotFound
+	}
+	found := *c
+	return &found, nil
+}
+
+func (r *memCommentRepository) Tombstone(_ context.Context, id, deletedBy string, deletedAt time.Time) error {
+	c, ok := r.comments[id]
+	if !ok || c.Deleted {
+		return domain.ErrCommentNotFound
+	}
+	c.Edits = append(c.Edits, domain.CommentEdit{Content: c.Content, ReplacedAt: deletedAt})
+	c.Content = ""
+	c.Deleted = true
+	c.DeletedAt = &deletedAt
+	c.DeletedBy = deletedBy
+	return nil
+}
+
+func (r *memCommentRepository) Untombstone(_ context.Context, id string) (*domain.Comment, error) {
+	c, ok := r.comments[id]
+	if !ok || !c.Deleted || len(c.Edits) == 0 {
+		return nil, domain.ErrCommentNotFound
+	}
+	c.Content = c.Edits[len(c.Edits)-1].Content
+	c.Edits = c.Edits[:len(c.Edits)-1]
+	c.Deleted, c.DeletedAt, c.DeletedBy = false, nil, ""
+	restored := *c
+	return &restored, nil
+}
+
+func (r *memCommentRepository) Delete(_ context.Context, id string) error {
+	c, ok := r.comments[id]
+	if !ok {
+		return domain.ErrCommentNotFound
+	}
+	for otherID, other := range r.comments {
+		if otherID == id || slices.Contains(other.Ancestors, id) {
+			delete(r.comments, otherID)
+		}
+	}
+	if parent, ok := r.comments[c.ParentID]; ok && c.Status == domain.CommentStatusApproved {
+		parent.ReplyCount--
+	}
+	return nil
+}
+
+func (r *memCommentRepository) HasReplies(_ context.Context, id string) (bool, error) {
+	for _, c := range r.comments {
+		if c.ParentID == id && c.Status != domain.CommentStatusRejected {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// memBlogRepository serves blogs from a map and sums the count changes.
+type memBlogRepository struct {
+	domain.IBlogRepository
+	blogs    map[string]*domain.Blog
+	likes    int
+	comments int
+}
+
+func newMemBlogRepository(blogs ...domain.Blog) *memBlogRepository {
+	r := &memBlogRepository{blogs: map[string]*domain.Blog{}}
+	for _, b := range blogs {
+		r.blogs[b.ID] = &b
+	}
+	return r
+}
+
+func (r *memBlogRepository) FindByID(_ context.Context, id string) (*domain.Blog, error) {
+	b, ok := r.blogs[id]
+	if !ok {
+		return nil, domain.ErrBlogNotFound
+	}
+	found := *b
+	return &found, nil
+}
+
+func (r *memBlogRepository) DeleteBlog(_ context.Context, blog *domain.Blog) error {
+	delete(r.blogs, blog.ID)
+	return nil
+}
+
+func (r *memBlogRepository) IncrementCounts(_ context.Context, blogID string, likes, comments int) error {
+	r.likes += likes
+	r.comments += comments
+	return nil
+}
+
+type nopModerationActionRepository struct {
+	domain.IModerationActionRepository
+}
+
+func (nopModerationActionRepository) Create(context.Context, *domain.ModerationAction) error {
+	return nil
+}
+
+var (
+	testBlog  = domain.Blog{ID: "b1", UserID: "owner", Status: domain.BlogStatusPublished}
+	testAdmin = domain.Viewer{UserID: "admin", Role: domain.RoleAdmin}
+)
+
+func TestUnhideAfterLastReplyDeleted(t *testing.T) {
+	comments := newMemCommentRepository(
+		domain.Comment{ID: "p", BlogId: "b1", UserId: "alice", Content: "parent", ReplyCount: 1},
+		domain.Comment{ID: "r", BlogId: "b1", UserId: "bob", ParentID: "p", Ancestors: []string{"p"}, Depth: 1, Content: "reply"},
+	)
+	blogs := newMemBlogRepository(testBlog)
+	commentUsecase := NewCommentUsecase(comments, blogs, nil, 5, time.Hour)
+	reportUsecase := &ReportUsecase{
+		moderationActionRepository: nopModerationActionRepository{},
+		blogRepository:             blogs,
+		commentRepository:          comments,
+	}
+	ctx := context.Background()
+
+	report := &domain.Report{TargetType: domain.ReportTargetComment, TargetID: "p", TargetUserID: "alice"}
+	if err := reportUsecase.hide(ctx, report, testAdmin, time.Now()); err != nil {
+		t.Fatalf("hide: %v", err)
+	}
+	if err := commentUsecase.DeleteComment(ctx, "r", domain.Viewer{UserID: "bob"}); err != nil {
+		t.Fatalf("DeleteComment: %v", err)
+	}
+	if _, err := reportUsecase.Unhide(ctx, domain.ReportTargetComment, "p", "", testAdmin); err != nil {
+		t.Fatalf("Unhide: %v", err)
+	}
+	parent, err := comments.FindByID(ctx, "p")
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if parent.Deleted || parent.Content != "parent" {
+		t.Errorf("parent = %+v, want it restored", parent)
+	}
+}
+
+func TestDeleteComment(t *testing.T) {
+	author := domain.Viewer{UserID: "alice", Role: domain.RoleUser}
+	tombstone := func(id, userID, parentID, deletedBy string, ancestors []string, replies int) domain.Comment {
+		return domain.Comment{ID: id, BlogId: "b1", UserId: userID, ParentID: parentID, Ancestors: ancestors,
+			ReplyCount: replies, Deleted: true, DeletedBy: deletedBy}
+	}
+	tests := []struct {
+		name          string
+		comments      []domain.Comment
+		deleteID      string
+		actor         domain.Viewer
+		wantErr       error
+		wantRemaining []string
+		wantTombstone []string
+		wantCount     int
+	}{
+		{
+			name:          "leaf is removed",
+			comments:      []domain.Comment{{ID: "c1", UserId: "alice"}, {ID: "c2", UserId: "bob"}},
+			deleteID:      "c1",
+			actor:         author,
+			wantRemaining: []string{"c2"},
+			wantCount:     -1,
+		},
+		{
+			name: "comment with replies becomes a tombstone",
+			comments: []domain.Comment{
+				{ID: "c1", UserId: "alice", ReplyCount: 1},
+				{ID: "c2", UserId: "bob", ParentID: "c1", Ancestors: []string{"c1"}},
+			},
+			deleteID:      "c1",
+			actor:         author,
+			wantRemaining: []string{"c1", "c2"},
+			wantTombstone: []string{"c1"},
+			wantCount:     -1,
+		},
+		{
+			name: "tombstones left without replies go too",
+			comments: []domain.Comment{
+				tombstone("c1", "carol", "", "carol", nil, 1),
+				tombstone("c2", "bob", "c1", "bob", []string{"c1"}, 1),
+				{ID: "c3", UserId: "alice", ParentID: "c2", Ancestors: []string{"c1", "c2"}},
+			},
+			deleteID:  "c3",
+			actor:     author,
+			wantCount: -1,
+		},
+		{
+			name: "tombstone with other replies stays",
+			comments: []domain.Comment{
+				tombstone("c1", "bob", "", "bob", nil, 2),
+				{ID: "c2", UserId: "alice", ParentID: "c1", Ancestors: []string{"c1"}},
+				{ID: "c3", UserId: "carol", ParentID: "c1", Ancestors: []string{"c1"}},
+			},
+			deleteID:      "c2",
+			actor:         author,
+			wantRemaining: []string{"c1", "c3"},
+			wantTombstone: []string{"c1"},
+			wantCount:     -1,
+		},
+		{
+			name: "hidden comment stays for unhiding",
+			comments: []domain.Comment{
+				tombstone("c1", "bob", "", "admin", nil, 1),
+				{ID: "c2", UserId: "alice", ParentID: "c1", Ancestors: []string{"c1"}},
+			},
+			deleteID:      "c2",
+			actor:         author,
+			wantRemaining: []string{"c1"},
+			wantTombstone: []string{"c1"},
+			wantCount:     -1,
+		},
+		{
+			name: "comment with only pending replies becomes a tombstone",
+			comments: []domain.Comment{
+				{ID: "c1", UserId: "alice"},
+				{ID: "c2", UserId: "bob", ParentID: "c1", Ancestors: []string{"c1"}, Status: domain.CommentStatusPending},
+			},
+			deleteID:      "c1",
+			actor:         author,
+			wantRemaining: []string{"c1", "c2"},
+			wantTombstone: []string{"c1"},
+			wantCount:     -1,
+		},
+		{
+			name: "tombstone with a pending reply stays",
+			comments: []domain.Comment{
+				tombstone("c1", "bob", "", "bob", nil, 1),
+				{ID: "c2", UserId: "alice", ParentID: "c1", Ancestors: []string{"c1"}},
+				{ID: "c3", UserId: "carol", ParentID: "c1", Ancestors: []string{"c1"}, Status: domain.CommentStatusPending},
+			},
+			deleteID:      "c2",
+			actor:         author,
+			wantRemaining: []string{"c1", "c3"},
+			wantTombstone: []string{"c1"},
+			wantCount:     -1,
+		},
+		{
+			name: "rejected replies go with their parent",
+			comments: []domain.Comment{
+				{ID: "c1", UserId: "alice"},
+				{ID: "c2", UserId: "bob", ParentID: "c1", Ancestors: []string{"c1"}, Status: domain.CommentStatusRejected},
+			},
+			deleteID:  "c1",
+			actor:     author,
+			wantCount: -1,
+		},
+		{
+			name:     "pending comment is not counted",
+			comments: []domain.Comment{{ID: "c1", UserId: "alice", Status: domain.CommentStatusPending}},
+			deleteID: "c1",
+			actor:    author,
+		},
+		{
+			name:      "blog owner may delete",
+			comments:  []domain.Comment{{ID: "c1", UserId: "alice"}},
+			deleteID:  "c1",
+			actor:     domain.Viewer{UserID: "owner", Role: domain.RoleUser},
+			wantCount: -1,
+		},
+		{
+			name:          "others may not",
+			comments:      []domain.Comment{{ID: "c1", UserId: "alice"}},
+			deleteID:      "c1",
+			actor:         domain.Viewer{UserID: "bob", Role: domain.RoleUser},
+			wantErr:       domain.ErrForbidden,
+			wantRemaining: []string{"c1"},
+		},
+		{
+			name:          "tombstone cannot be deleted again",
+			comments:      []domain.Comment{tombstone("c1", "alice", "", "alice", nil, 1)},
+			deleteID:      "c1",
+			actor:         author,
+			wantErr:       domain.ErrCommentNotFound,
+			wantRemaining: []string{"c1"},
+			wantTombstone: []string{"c1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := range tt.comments {
+				tt.comments[i].BlogId = "b1"
+			}
+			comments := newMemCommentRepository(tt.comments...)
+			blogs := newMemBlogRepository(testBlog)
+			u := NewCommentUsecase(comments, blogs, nil, 5, time.Hour)
+
+			err := u.DeleteComment(context.Background(), tt.deleteID, tt.actor)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DeleteComment error = %v, want %v", err, tt.wantErr)
+			}
+			var remaining, tombstones []string
+			for id, c := range comments.comments {
+				remaining = append(remaining, id)
+				if c.Deleted {
+					tombstones = append(tombstones, id)
+				}
+			}
+			slices.Sort(remaining)
+			slices.Sort(tombstones)
+			if !slices.Equal(remaining, tt.wantRemaining) {
+				t.Errorf("remaining = %v, want %v", remaining, tt.wantRemaining)
+			}
+			if !slices.Equal(tombstones, tt.wantTombstone) {
+				t.Errorf("tombstones = %v, want %v", tombstones, tt.wantTombstone)
+			}
+			if blogs.comments != tt.wantCount {
+				t.Errorf("comment count change = %d, want %d", blogs.comments, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestCreateCommentThreading(t *testing.T) {
+	reader := domain.Viewer{UserID: "bob", Role: domain.RoleUser}
+	draft := domain.Blog{ID: "b2", UserID: "owner", Status: domain.BlogStatusDraft}
+	tests := []struct {
+		name       string
+		blogID     string
+		parentID   string
+		content    string
+		wantErr    error
+		wantDepth  int
+		wantAncest []string
+		wantEvents []domain.EventType
+	}{
+		{
+			name:       "top-level comment",
+			blogID:     "b1",
+			content:    "hello",
+			wantAncest: []string{},
+			wantEvents: []domain.EventType{domain.EventCommentCreated},
+		},
+		{
+			name:       "reply",
+			blogID:     "b1",
+			parentID:   "c1",
+			content:    "hello",
+			wantDepth:  2,
+			wantAncest: []string{"c0", "c1"},
+			wantEvents: []domain.EventType{domain.EventCommentCreated, domain.EventCommentReplied},
+		},
+		{name: "too deep", blogID: "b1", parentID: "c2", content: "hello", wantErr: domain.ErrInvalidInput},
+		{name: "reply to a tombstone", blogID: "b1", parentID: "gone", content: "hello", wantErr: domain.ErrInvalidInput},
+		{name: "reply to a pending comment", blogID: "b1", parentID: "held", content: "hello", wantErr: domain.ErrInvalidInput},
+		{name: "parent on another blog", blogID: "b1", parentID: "elsewhere", content: "hello", wantErr: domain.ErrCommentNotFound},
+		{name: "missing parent", blogID: "b1", parentID: "nope", content: "hello", wantErr: domain.ErrCommentNotFound},
+		{name: "blank content", blogID: "b1", content: "  ", wantErr: domain.ErrInvalidInput},
+		{name: "draft of someone else", blogID: "b2", content: "hello", wantErr: domain.ErrBlogNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comments := newMemCommentRepository(
+				domain.Comment{ID: "c0", BlogId: "b1", UserId: "alice", ReplyCount: 1},
+				domain.Comment{ID: "c1", BlogId: "b1", UserId: "alice", ParentID: "c0", Ancestors: []string{"c0"}, Depth: 1, ReplyCount: 1},
+				domain.Comment{ID: "c2", BlogId: "b1", UserId: "alice", ParentID: "c1", Ancestors: []string{"c0", "c1"}, Depth: 2},
+				domain.Comment{ID: "gone", BlogId: "b1", UserId: "alice", Deleted: true, DeletedBy: "alice", ReplyCount: 1},
+				domain.Comment{ID: "held", BlogId: "b1", UserId: "alice", Status: domain.CommentStatusPending},
+				domain.Comment{ID: "elsewhere", BlogId: "b3", UserId: "alice"},
+			)
+			blogs := newMemBlogRepository(testBlog, draft)
+			events := &recordingEventBus{}
+			u := NewCommentUsecase(comments, blogs, events, 2, time.Hour)
+
+			created, err := u.CreateComment(context.Background(), tt.blogID, tt.parentID, tt.content, reader)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateComment error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if len(comments.comments) != 6 {
+					t.Errorf("a comment was stored despite the error")
+				}
+				return
+			}
+			if created.Depth != tt.wantDepth || !slices.Equal(created.Ancestors, tt.wantAncest) {
+				t.Errorf("depth %d, ancestors %v, want %d and %v", created.Depth, created.Ancestors, tt.wantDepth, tt.wantAncest)
+			}
+			if created.ParentID != tt.parentID || created.Content != tt.content {
+				t.Errorf("parent %q, content %q, want %q and %q", created.ParentID, created.Content, tt.parentID, tt.content)
+			}
+			if tt.parentID != "" && comments.comments[tt.parentID].ReplyCount != 2 {
+				t.Errorf("parent reply count = %d, want 2", comments.comments[tt.parentID].ReplyCount)
+			}
+			if blogs.comments != 1 {
+				t.Errorf("comment count change = %d, want 1", blogs.comments)
+			}
+			if !slices.Equal(events.types, tt.wantEvents) {
+				t.Errorf("events = %v, want %v", events.types, tt.wantEvents)
+			}
+		})
+	}
+}
+
+func TestCreateCommentModes(t *testing.T) {
+	reader := domain.Viewer{UserID: "bob", Role: domain.RoleUser}
+	owner := domain.Viewer{UserID: "owner", Role: domain.RoleUser}
+	tests := []struct {
+		name       string
+		mode       domain.CommentMode
+		actor      domain.Viewer
+		wantErr    error
+		wantStatus domain.CommentStatus
+	}{
+		{name: "open", mode: domain.CommentModeOpen, actor: reader, wantStatus: domain.CommentStatusApproved},
+		{name: "no mode set", actor: reader, wantStatus: domain.CommentStatusApproved},
+		{name: "closed", mode: domain.CommentModeClosed, actor: reader, wantErr: domain.ErrForbidden},
+		{name: "closed to the author", mode: domain.CommentModeClosed, actor: owner, wantStatus: domain.CommentStatusApproved},
+		{name: "closed to an admin", mode: domain.CommentModeClosed, actor: testAdmin, wantStatus: domain.CommentStatusApproved},
+		{name: "approval", mode: domain.CommentModeApproval, actor: reader, wantStatus: domain.CommentStatusPending},
+		{name: "approval for the author", mode: domain.CommentModeApproval, actor: owner, wantStatus: domain.CommentStatusApproved},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blog := testBlog
+			blog.CommentMode = tt.mode
+			comments := newMemCommentRepository()
+			blogs := newMemBlogRepository(blog)
+			events := &recordingEventBus{}
+			u := NewCommentUsecase(comments, blogs, events, 5, time.Hour)
+
+			created, err := u.CreateComment(context.Background(), "b1", "", "hello", tt.actor)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateComment error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if created.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", created.Status, tt.wantStatus)
+			}
+			// Only approved comments are counted and announced.
+			wantCount, wantEvents := 0, 0
+			if tt.wantStatus == domain.CommentStatusApproved {
+				wantCount, wantEvents = 1, 1
+			}
+			if blogs.comments != wantCount || len(events.types) != wantEvents {
+				t.Errorf("count change %d, %d events, want %d and %d", blogs.comments, len(events.types), wantCount, wantEvents)
+			}
+		})
+	}
+}
+
+func TestModerateComments(t *testing.T) {
+	other := domain.Blog{ID: "b2", UserID: "carol", Status: domain.BlogStatusPublished}
+	owner := domain.Viewer{UserID: "owner", Role: domain.RoleUser}
+	tests := []struct {
+		name          string
+		ids           []string
+		approve       bool
+		actor         domain.Viewer
+		wantErr       error
+		wantProcessed []string
+		wantSkipped   map[string]string
+		wantEvents    []domain.EventType
+	}{
+		{
+			name:          "approve",
+			ids:           []string{"top", "reply", "top"},
+			approve:       true,
+			actor:         owner,
+			wantProcessed: []string{"top", "reply"},
+			wantSkipped:   map[string]string{},
+			wantEvents:    []domain.EventType{domain.EventCommentCreated, domain.EventCommentCreated, domain.EventCommentReplied},
+		},
+		{
+			name:          "reject",
+			ids:           []string{"top", "reply"},
+			actor:         owner,
+			wantProcessed: []string{"top", "reply"},
+			wantSkipped:   map[string]string{},
+		},
+		{
+			name:          "skips what the actor cannot moderate",
+			ids:           []string{"top", "live", "missing", "theirs"},
+			approve:       true,
+			actor:         owner,
+			wantProcessed: []string{"top"},
+			wantSkipped:   map[string]string{"live": "not pending", "missing": "not found", "theirs": "forbidden"},
+			wantEvents:    []domain.EventType{domain.EventCommentCreated},
+		},
+		{
+			name:          "admins moderate every blog",
+			ids:           []string{"theirs"},
+			approve:       true,
+			actor:         testAdmin,
+			wantProcessed: []string{"theirs"},
+			wantSkipped:   map[string]string{},
+			wantEvents:    []domain.EventType{domain.EventCommentCreated},
+		},
+		{name: "empty batch", actor: owner, wantErr: domain.ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comments := newMemCommentRepository(
+				domain.Comment{ID: "live", BlogId: "b1", UserId: "alice"},
+				domain.Comment{ID: "top", BlogId: "b1", UserId: "bob", Status: domain.CommentStatusPending},
+				domain.Comment{ID: "reply", BlogId: "b1", UserId: "bob", ParentID: "live", Ancestors: []string{"live"}, Depth: 1,
+					Status: domain.CommentStatusPending},
+				domain.Comment{ID: "theirs", BlogId: "b2", UserId: "bob", Status: domain.CommentStatusPending},
+			)
+			blogs := newMemBlogRepository(testBlog, other)
+			events := &recordingEventBus{}
+			u := NewCommentUsecase(comments, blogs, events, 5, time.Hour)
+
+			result, err := u.ModerateComments(context.Background(), tt.ids, tt.approve, tt.actor)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ModerateComments error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !slices.Equal(result.Processed, tt.wantProcessed) || !maps.Equal(result.Skipped, tt.wantSkipped) {
+				t.Errorf("processed %v, skipped %v, want %v and %v", result.Processed, result.Skipped, tt.wantProcessed, tt.wantSkipped)
+			}
+			wantStatus, wantCount := domain.CommentStatusRejected, 0
+			if tt.approve {
+				wantStatus, wantCount = domain.CommentStatusApproved, len(tt.wantProcessed)
+			}
+			for _, id := range tt.wantProcessed {
+				if comments.comments[id].Status != wantStatus {
+					t.Errorf("status of %s = %s, want %s", id, comments.comments[id].Status, wantStatus)
+				}
+			}
+			if blogs.comments != wantCount {
+				t.Errorf("count change = %d, want %d", blogs.comments, wantCount)
+			}
+			if !slices.Equal(events.types, tt.wantEvents) {
+				t.Errorf("events = %v, want %v", events.types, tt.wantEvents)
+			}
+		})
+	}
+}
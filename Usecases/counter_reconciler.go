@@ -0,0 +1,52 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"log"
+	"time"
+)
+
+type CounterReconciler struct {
+	blogRepository domain.IBlogRepository
+	interval       time.Duration
+}
+
+func NewCounterReconciler(blogRepo domain.IBlogRepository, interval time.Duration) domain.ICounterReconciler {
+	return &CounterReconciler{
+		blogRepository: blogRepo,
+		interval:       interval,
+	}
+}
+
+// reconcileOverlap widens each pass to cover changes that were in flight
+// while the previous one ran.
+const reconcileOverlap = time.Minute
+
+// Run reconciles every blog's counts right away, which also fills them in
+// on blogs stored before they existed. After that it runs every interval
+// until ctx is cancelled, looking only at blogs with activity since the
+// last successful pass.
+func (c *CounterReconciler) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	var since time.Time
+	for {
+		started := time.Now()
+		if n, err := c.blogRepository.ReconcileCounts(ctx, since); err != nil {
+			log.Printf("counter reconciler: %v", err)
+		} else {
+			since = started.Add(-reconcileOverlap)
+			if n > 0 {
+				log.Printf("counter reconciler: corrected %d blog(s)", n)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// tally answers ReconcileCounts from a script of errors, one per pass, and
+// stops the reconciler once the script runs out.
+type tally struct {
+	domain.IBlogRepository
+	script []error
+	since  []time.Time
+	starts []time.Time
+	stop   context.CancelFunc
+}
+
+func (r *tally) ReconcileCounts(_ context.Context, since time.Time) (int64, error) {
+	r.since = append(r.since, since)
+	r.starts = append(r.starts, time.Now())
+	err := r.script[len(r.since)-1]
+	if len(r.since) == len(r.script) {
+		r.stop()
+	}
+	return 2, err
+}
+
+func TestCounterReconcilerRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	blogs := &tally{script: []error{nil, errors.New("primary stepped down"), nil, nil}, stop: cancel}
+
+	done := make(chan struct{})
+	go func() {
+		NewCounterReconciler(blogs, time.Millisecond).Run(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("reconciler did not stop")
+	}
+
+	if len(blogs.since) != 4 {
+		t.Fatalf("ran %d passes, want 4", len(blogs.since))
+	}
+	if !blogs.since[0].IsZero() {
+		t.Errorf("first pass since %v, want every blog", blogs.since[0])
+	}
+	// A pass looks back from the start of the last one that succeeded, so
+	// the failed second pass is covered by the third.
+	wantSince := []time.Time{{}, blogs.starts[0], blogs.starts[0], blogs.starts[2]}
+	for i := 1; i < len(wantSince); i++ {
+		want := wantSince[i].Add(-reconcileOverlap)
+		if d := blogs.since[i].Sub(want); d < -50*time.Millisecond || d > 50*time.Millisecond {
+			t.Errorf("pass %d since %v, want about %v", i+1, blogs.since[i], want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// passwordResetTTL is how long a reset link works, and how long its email
+// is worth sending.
+const passwordResetTTL = 15 * time.Minute
+
+// HandleEmails has the outbox send the emails that usecases queue. Their
+// payloads only hold references: the verification code is looked up, and
+// the reset token created, when the email goes out.
+func HandleEmails(outbox domain.IOutboxDispatcher, emailService domain.IEmailService,
+	verificationRepo domain.IEmailVerificationRepository, resetTokenRepo domain.IPasswordResetTokenRepository,
+	passwordService domain.IPasswordService) {
+	outbox.Handle(domain.OutboxVerificationEmail, func(ctx context.Context, payload []byte) error {
+		var email domain.VerificationEmail
+		if err := json.Unmarshal(payload, &email); err != nil {
+			return fmt.Errorf("failed to decode verification email: %w", err)
+		}
+		verification, err := verificationRepo.GetByID(ctx, email.VerificationID)
+		if errors.Is(err, domain.ErrVerificationNotFound) {
+			log.Printf("dropping verification email to %s: its code is gone", email.Email)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if verification.Used || !time.Now().Before(verification.ExpiresAt) {
+			return nil
+		}
+		return emailService.SendVerificationEmail(ctx, verification.Email, verification.OTP)
+	})
+	outbox.Handle(domain.OutboxPasswordResetEmail, func(ctx context.Context, payload []byte) error {
+		var email domain.PasswordResetEmail
+		if err := json.Unmarshal(payload, &email); err != nil {
+			return fmt.Errorf("failed to decode password reset email: %w", err)
+		}
+		if email.UserID == "" {
+			log.Printf("dropping password reset email to %s: it names no user", email.Email)
+			return nil
+		}
+		token, err := issueResetToken(ctx, resetTokenRepo, passwordService, email.UserID)
+		if err != nil {
+			return err
+		}
+		return emailService.SendPasswordResetEmail(ctx, email.Email, token)
+	})
+}
+
+// issueResetToken stores the hash of a new reset token for a user and
+// returns the token. A retried email gets a token of its own; the earlier
+// one expires unused.
+func issueResetToken(ctx context.Context, resetTokenRepo domain.IPasswordResetTokenRepository,
+	passwordService domain.IPasswordService, userID string) (string, error) {
+	rawToken, err := passwordService.GenerateRandomToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate reset token: %w", err)
+	}
+	hashedToken, err := passwordService.Hash(rawToken)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	err = resetTokenRepo.Store(ctx, &domain.PasswordResetToken{
+		UserID:    userID,
+		TokenHash: hashedToken,
+		ExpiresAt: now.Add(passwordResetTTL),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to store reset token: %w", err)
+	}
+	return rawToken, nil
+}
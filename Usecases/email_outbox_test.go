@@ -0,0 +1,189 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// postbox queues messages in memory and hands them to its handlers on
+// deliver. With down set, nothing can be queued.
+type postbox struct {
+	domain.IOutboxDispatcher
+	handlers map[string]domain.OutboxHandler
+	queued   []queuedMessage
+	down     bool
+}
+
+type queuedMessage struct {
+	topic   string
+	payload []byte
+}
+
+func newPostbox() *postbox {
+	return &postbox{handlers: map[string]domain.OutboxHandler{}}
+}
+
+func (p *postbox) Handle(topic string, handler domain.OutboxHandler) {
+	p.handlers[topic] = handler
+}
+
+func (p *postbox) EnqueueUntil(_ context.Context, topic string, payload any, _ time.Time) error {
+	if p.down {
+		return errors.New("outbox unavailable")
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	p.queued = append(p.queued, queuedMessage{topic: topic, payload: data})
+	return nil
+}
+
+func (p *postbox) deliver(ctx context.Context, message queuedMessage) error {
+	return p.handlers[message.topic](ctx, message.payload)
+}
+
+// mailbag records the emails sent and the secret each carried.
+type mailbag struct {
+	sent []string // "to secret"
+}
+
+func (m *mailbag) SendVerificationEmail(_ context.Context, to, otp string) error {
+	m.sent = append(m.sent, to+" "+otp)
+	return nil
+}
+
+func (m *mailbag) SendPasswordResetEmail(_ context.Context, to, token string) error {
+	m.sent = append(m.sent, to+" "+token)
+	return nil
+}
+
+type codeBook struct {
+	domain.IEmailVerificationRepository
+	codes      map[string]domain.EmailVerification
+	lookupFail bool
+}
+
+func (c *codeBook) Store(_ context.Context, verification *domain.EmailVerification) error {
+	verification.ID = "v" + strconv.Itoa(len(c.codes)+1)
+	c.codes[verification.ID] = *verification
+	return nil
+}
+
+func (c *codeBook) GetByID(_ context.Context, id string) (*domain.EmailVerification, error) {
+	if c.lookupFail {
+		return nil, errors.New("connection reset")
+	}
+	verification, ok := c.codes[id]
+	if !ok {
+		return nil, domain.ErrVerificationNotFound
+	}
+	return &verification, nil
+}
+
+type resetLedger struct {
+	domain.IPasswordResetTokenRepository
+	tokens []domain.PasswordResetToken
+}
+
+func (l *resetLedger) Store(_ context.Context, token *domain.PasswordResetToken) error {
+	l.tokens = append(l.tokens, *token)
+	return nil
+}
+
+// plainPasswords "hashes" by prefixing and hands out numbered tokens.
+type plainPasswords struct {
+	domain.IPasswordService
+	issued int
+}
+
+func (p *plainPasswords) Hash(password string) (string, error) { return "hashed:" + password, nil }
+func (p *plainPasswords) ValidateStrength(string) error        { return nil }
+func (p *plainPasswords) GenerateRandomToken() (string, error) {
+	p.issued++
+	return "reset-" + strconv.Itoa(p.issued), nil
+}
+
+func TestVerificationEmail(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name         string
+		verification *domain.EmailVerification
+		lookupFail   bool
+		wantSent     bool
+		wantErr      bool
+	}{
+		{name: "fresh code", verification: &domain.EmailVerification{OTP: "482913", ExpiresAt: now.Add(time.Minute)}, wantSent: true},
+		{name: "code already used", verification: &domain.EmailVerification{OTP: "482913", ExpiresAt: now.Add(time.Minute), Used: true}},
+		{name: "code expired", verification: &domain.EmailVerification{OTP: "482913", ExpiresAt: now.Add(-time.Minute)}},
+		{name: "code deleted"},
+		{name: "lookup fails", verification: &domain.EmailVerification{OTP: "482913", ExpiresAt: now.Add(time.Minute)}, lookupFail: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			codes := &codeBook{codes: map[string]domain.EmailVerification{}, lookupFail: tt.lookupFail}
+			outbox, mail := newPostbox(), &mailbag{}
+			HandleEmails(outbox, mail, codes, &resetLedger{}, &plainPasswords{})
+
+			id := "v404"
+			if tt.verification != nil {
+				tt.verification.Email = "ada@example.com"
+				_ = codes.Store(ctx, tt.verification)
+				id = tt.verification.ID
+			}
+			payload, _ := json.Marshal(domain.VerificationEmail{Email: "ada@example.com", VerificationID: id})
+			err := outbox.deliver(ctx, queuedMessage{topic: domain.OutboxVerificationEmail, payload: payload})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("handler: %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantSent != (len(mail.sent) == 1) {
+				t.Fatalf("sent %v, want sent %v", mail.sent, tt.wantSent)
+			}
+			if tt.wantSent && mail.sent[0] != "ada@example.com 482913" {
+				t.Errorf("sent %q, want the stored code", mail.sent[0])
+			}
+		})
+	}
+}
+
+func TestPasswordResetEmail(t *testing.T) {
+	ctx := context.Background()
+	outbox, mail, resets := newPostbox(), &mailbag{}, &resetLedger{}
+	passwords := &plainPasswords{}
+	HandleEmails(outbox, mail, &codeBook{}, resets, passwords)
+	users := &signups{users: []domain.User{{ID: "u7", Email: "grace@example.com", IsVerified: true}}}
+	u := &UserUsecase{userRepository: users, outbox: outbox, passwordService: passwords, contextTimeout: time.Second}
+
+	if err := u.RequestPasswordReset(ctx, domain.RequestPasswordResetInput{Email: "grace@example.com"}); err != nil {
+		t.Fatalf("RequestPasswordReset: %v", err)
+	}
+	if len(outbox.queued) != 1 || len(resets.tokens) != 0 {
+		t.Fatalf("queued %d emails and stored %d tokens, want one email and no token yet", len(outbox.queued), len(resets.tokens))
+	}
+	if payload := string(outbox.queued[0].payload); strings.Contains(payload, "reset-") {
+		t.Errorf("payload %s holds a token", payload)
+	}
+
+	// A retried email carries a token of its own.
+	for i := 0; i < 2; i++ {
+		if err := outbox.deliver(ctx, outbox.queued[0]); err != nil {
+			t.Fatalf("handler: %v", err)
+		}
+	}
+	want := []string{"grace@example.com reset-1", "grace@example.com reset-2"}
+	if strings.Join(mail.sent, ",") != strings.Join(want, ",") {
+		t.Errorf("sent %v, want %v", mail.sent, want)
+	}
+	for i, token := range resets.tokens {
+		if token.UserID != "u7" || token.TokenHash != "hashed:reset-"+strconv.Itoa(i+1) || token.Used {
+			t.Errorf("stored token %+v", token)
+		}
+	}
+}
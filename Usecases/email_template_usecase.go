@@ -0,0 +1,28 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+)
+
+type EmailTemplateUsecase struct {
+	templates domain.IEmailTemplates
+}
+
+func NewEmailTemplateUsecase(templates domain.IEmailTemplates) domain.IEmailTemplateUsecase {
+	return &EmailTemplateUsecase{templates: templates}
+}
+
+func (u *EmailTemplateUsecase) ListTemplates(ctx context.Context, actor domain.Viewer) ([]string, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, domain.ErrForbidden
+	}
+	return u.templates.Names(), nil
+}
+
+func (u *EmailTemplateUsecase) PreviewTemplate(ctx context.Context, name string, actor domain.Viewer) (*domain.EmailMessage, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, domain.ErrForbidden
+	}
+	return u.templates.Preview(name)
+}
@@ -0,0 +1,163 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type FeedUsecase struct {
+	blogRepository  domain.IBlogRepository
+	userRepository  domain.IUserRepository
+	tagRepository   domain.ITagRepository
+	contentRenderer domain.IContentRenderer
+	siteURL         string
+	siteName        string
+	defaultItems    int
+	maxItems        int
+}
+
+// NewFeedUsecase builds feeds whose links point under siteURL. Requests for
+// more than maxItems items get maxItems; requests without a count get
+// defaultItems.
+func NewFeedUsecase(blogRepo domain.IBlogRepository, userRepo domain.IUserRepository, tagRepo domain.ITagRepository,
+	renderer domain.IContentRenderer, siteURL, siteName string, defaultItems, maxItems int) domain.IFeedUsecase {
+	return &FeedUsecase{
+		blogRepository:  blogRepo,
+		userRepository:  userRepo,
+		tagRepository:   tagRepo,
+		contentRenderer: renderer,
+		siteURL:         strings.TrimRight(siteURL, "/"),
+		siteName:        siteName,
+		defaultItems:    defaultItems,
+		maxItems:        max(maxItems, defaultItems),
+	}
+}
+
+func (fu *FeedUsecase) SiteFeed(ctx context.Context, limit int) (*domain.Feed, error) {
+	feed := &domain.Feed{
+		Title:       fu.siteName,
+		Description: "Latest posts on " + fu.siteName,
+		Link:        fu.siteURL + "/blogs",
+		SelfLink:    fu.siteURL + "/feeds",
+	}
+	return fu.fill(ctx, feed, domain.BlogQuery{}, limit)
+}
+
+func (fu *FeedUsecase) AuthorFeed(ctx context.Context, userID string, limit int) (*domain.Feed, error) {
+	user, err := fu.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		// Malformed IDs fail the lookup too; both mean there is no such feed.
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", domain.ErrUserNotFound, err)
+	}
+
+	feed := &domain.Feed{
+		Title:       fu.siteName + " - " + user.Username,
+		Description: "Latest posts by " + user.Username + " on " + fu.siteName,
+		Link:        fu.siteURL + "/blogs?author=" + url.QueryEscape(user.ID),
+		SelfLink:    fu.siteURL + "/feeds/authors/" + url.PathEscape(user.ID),
+	}
+	return fu.fill(ctx, feed, domain.BlogQuery{AuthorID: user.ID}, limit)
+}
+
+func (fu *FeedUsecase) TagFeed(ctx context.Context, tag string, limit int) (*domain.Feed, error) {
+	if domain.NormalizeTag(tag) == "" {
+		return nil, fmt.Errorf("%w: tag is required", domain.ErrInvalidInput)
+	}
+	names, variants, err := tagVariants(ctx, fu.tagRepository, []string{tag})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve tag: %w", err)
+	}
+	tag = names[0]
+
+	feed := &domain.Feed{
+		Title:       fu.siteName + " - #" + tag,
+		Description: "Latest posts tagged " + tag + " on " + fu.siteName,
+		Link:        fu.siteURL + "/blogs?tags=" + url.QueryEscape(tag),
+		SelfLink:    fu.siteURL + "/feeds/tags/" + url.PathEscape(tag),
+	}
+	return fu.fill(ctx, feed, domain.BlogQuery{Tags: names, TagVariants: variants}, limit)
+}
+
+// fill loads the most recently published blogs matching query into feed,
+// exactly as an anonymous reader would list them.
+func (fu *FeedUsecase) fill(ctx context.Context, feed *domain.Feed, query domain.BlogQuery, limit int) (*domain.Feed, error) {
+	if limit <= 0 {
+		limit = fu.defaultItems
+	}
+	query.Status = domain.BlogStatusPublished
+	query.Sort = domain.BlogSortPublished
+	query.Page = 1
+	query.Limit = min(limit, fu.maxItems)
+
+	result, err := fu.blogRepository.FindBlogs(ctx, query, domain.Viewer{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load feed: %w", err)
+	}
+
+	// An empty feed still needs a stable timestamp for conditional requests.
+	feed.Updated = time.Unix(0, 0).UTC()
+	authors := map[string]string{}
+	feed.Items = make([]domain.FeedItem, 0, len(result.Blogs))
+	for i := range result.Blogs {
+		blog := &result.Blogs[i]
+		item := fu.item(ctx, blog, authors)
+		if item.Updated.After(feed.Updated) {
+			feed.Updated = item.Updated
+		}
+		feed.Items = append(feed.Items, item)
+	}
+	return feed, nil
+}
+
+func (fu *FeedUsecase) item(ctx context.Context, blog *domain.Blog, authors map[string]string) domain.FeedItem {
+	name, ok := authors[blog.UserID]
+	if !ok {
+		// Deleted authors simply go unnamed.
+		if user, err := fu.userRepository.GetByID(ctx, blog.UserID); err == nil {
+			name = user.Username
+		}
+		authors[blog.UserID] = name
+	}
+
+	contentHTML, summary := blog.ContentHTML, blog.Excerpt
+	if contentHTML == "" {
+		// Blogs saved before rendering existed.
+		if rendered, err := fu.contentRenderer.Render(blog.ContentFormat, blog.Content); err == nil {
+			contentHTML, summary = rendered.HTML, rendered.Excerpt
+		}
+	}
+
+	published := blog.CreatedAt
+	if blog.PublishedAt != nil {
+		published = *blog.PublishedAt
+	}
+	updated := blog.UpdatedAt
+	if updated.Before(published) {
+		updated = published
+	}
+
+	link := fu.siteURL + "/blogs/" + blog.ID
+	if blog.Slug != "" {
+		link = fu.siteURL + "/blogs/by-slug/" + url.PathEscape(blog.Slug)
+	}
+
+	return domain.FeedItem{
+		ID:          fu.siteURL + "/blogs/" + blog.ID,
+		Title:       blog.Title,
+		Link:        link,
+		Summary:     summary,
+		ContentHTML: contentHTML,
+		Author:      name,
+		Tags:        blog.Tags,
+		Published:   published.UTC(),
+		Updated:     updated.UTC(),
+	}
+}
@@ -0,0 +1,193 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// newsstand serves the same published blogs to every query and records
+// what it was asked.
+type newsstand struct {
+	domain.IBlogRepository
+	blogs   []domain.Blog
+	queries []domain.BlogQuery
+	viewers []domain.Viewer
+}
+
+func (n *newsstand) FindBlogs(_ context.Context, query domain.BlogQuery, viewer domain.Viewer) (*domain.BlogQueryResult, error) {
+	n.queries = append(n.queries, query)
+	n.viewers = append(n.viewers, viewer)
+	return &domain.BlogQueryResult{Blogs: n.blogs}, nil
+}
+
+// masthead knows a few writers and counts how often each is looked up.
+type masthead struct {
+	domain.IUserRepository
+	names   map[string]string
+	lookups map[string]int
+}
+
+func (m *masthead) GetByID(_ context.Context, id string) (*domain.User, error) {
+	m.lookups[id]++
+	name, ok := m.names[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	return &domain.User{ID: id, Username: name}, nil
+}
+
+// aliasedTags knows "golang" as an alias of "go".
+type aliasedTags struct{ domain.ITagRepository }
+
+func (aliasedTags) FindByNames(_ context.Context, names []string) (map[string]*domain.Tag, error) {
+	known := map[string]*domain.Tag{}
+	for _, name := range names {
+		if name == "go" || name == "golang" {
+			known[name] = &domain.Tag{Name: "go", Aliases: []string{"golang"}}
+		}
+	}
+	return known, nil
+}
+
+// paragraphRenderer wraps the source in a paragraph.
+type paragraphRenderer struct{}
+
+func (paragraphRenderer) Render(_ domain.ContentFormat, source string) (*domain.RenderedContent, error) {
+	return &domain.RenderedContent{HTML: "<p>" + source + "</p>", Excerpt: source}, nil
+}
+
+func TestFeedQueries(t *testing.T) {
+	tests := []struct {
+		name       string
+		build      func(fu *FeedUsecase) (*domain.Feed, error)
+		wantLimit  int
+		wantAuthor string
+		wantTags   []string
+		wantSelf   string
+	}{
+		{
+			name:      "site feed with the default count",
+			build:     func(fu *FeedUsecase) (*domain.Feed, error) { return fu.SiteFeed(context.Background(), 0) },
+			wantLimit: 20,
+			wantSelf:  "https://blog.example.org/feeds",
+		},
+		{
+			name:       "author feed with a count of its own",
+			build:      func(fu *FeedUsecase) (*domain.Feed, error) { return fu.AuthorFeed(context.Background(), "u1", 5) },
+			wantLimit:  5,
+			wantAuthor: "u1",
+			wantSelf:   "https://blog.example.org/feeds/authors/u1",
+		},
+		{
+			name:      "tag feed by alias, capped",
+			build:     func(fu *FeedUsecase) (*domain.Feed, error) { return fu.TagFeed(context.Background(), "GoLang", 500) },
+			wantLimit: 50,
+			wantTags:  []string{"go"},
+			wantSelf:  "https://blog.example.org/feeds/tags/go",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blogs := &newsstand{}
+			users := &masthead{names: map[string]string{"u1": "margaret"}, lookups: map[string]int{}}
+			fu := NewFeedUsecase(blogs, users, aliasedTags{}, paragraphRenderer{}, "https://blog.example.org/", "Field Notes", 20, 50).(*FeedUsecase)
+
+			feed, err := tt.build(fu)
+			if err != nil {
+				t.Fatalf("feed: %v", err)
+			}
+			if feed.SelfLink != tt.wantSelf {
+				t.Errorf("self link %q, want %q", feed.SelfLink, tt.wantSelf)
+			}
+			if len(blogs.queries) != 1 {
+				t.Fatalf("ran %d queries, want 1", len(blogs.queries))
+			}
+			query := blogs.queries[0]
+			if query.Status != domain.BlogStatusPublished || query.Sort != domain.BlogSortPublished || query.Limit != tt.wantLimit {
+				t.Errorf("query status %q, sort %q, limit %d; want published blogs by publication date, %d of them",
+					query.Status, query.Sort, query.Limit, tt.wantLimit)
+			}
+			if query.AuthorID != tt.wantAuthor || len(query.Tags) != len(tt.wantTags) || (len(tt.wantTags) > 0 && query.Tags[0] != tt.wantTags[0]) {
+				t.Errorf("query author %q, tags %v; want %q, %v", query.AuthorID, query.Tags, tt.wantAuthor, tt.wantTags)
+			}
+			if blogs.viewers[0] != (domain.Viewer{}) {
+				t.Errorf("queried as %+v, want an anonymous reader", blogs.viewers[0])
+			}
+		})
+	}
+}
+
+func TestFeedItems(t *testing.T) {
+	created := time.Date(2023, 3, 1, 9, 0, 0, 0, time.UTC)
+	published := time.Date(2023, 3, 4, 9, 0, 0, 0, time.UTC)
+	edited := time.Date(2023, 3, 9, 17, 30, 0, 0, time.UTC)
+	blogs := &newsstand{blogs: []domain.Blog{
+		{
+			ID: "b1", UserID: "u1", Title: "Edited after publishing", Slug: "edited", ContentHTML: "<p>new</p>", Excerpt: "new",
+			CreatedAt: created, PublishedAt: &published, UpdatedAt: edited,
+		},
+		{
+			// Saved before slugs, rendering and published_at existed.
+			ID: "b2", UserID: "u1", Title: "Legacy", Content: "old words",
+			CreatedAt: created, UpdatedAt: created.Add(-time.Hour),
+		},
+		{ID: "b3", UserID: "gone", Title: "Orphan", Slug: "orphan", ContentHTML: "<p>x</p>", CreatedAt: created, PublishedAt: &published},
+	}}
+	users := &masthead{names: map[string]string{"u1": "margaret"}, lookups: map[string]int{}}
+	fu := NewFeedUsecase(blogs, users, aliasedTags{}, paragraphRenderer{}, "https://blog.example.org", "Field Notes", 20, 50)
+
+	feed, err := fu.SiteFeed(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("SiteFeed: %v", err)
+	}
+	if len(feed.Items) != 3 {
+		t.Fatalf("got %d items, want 3", len(feed.Items))
+	}
+	if !feed.Updated.Equal(edited) {
+		t.Errorf("feed updated %v, want the latest edit %v", feed.Updated, edited)
+	}
+
+	edit, legacy, orphan := feed.Items[0], feed.Items[1], feed.Items[2]
+	if !edit.Published.Equal(published) || !edit.Updated.Equal(edited) {
+		t.Errorf("edited item published %v, updated %v", edit.Published, edit.Updated)
+	}
+	if edit.Link != "https://blog.example.org/blogs/by-slug/edited" || edit.ID != "https://blog.example.org/blogs/b1" {
+		t.Errorf("edited item link %q, id %q", edit.Link, edit.ID)
+	}
+	if !legacy.Published.Equal(created) || !legacy.Updated.Equal(created) {
+		t.Errorf("legacy item published %v, updated %v, want both at creation", legacy.Published, legacy.Updated)
+	}
+	if legacy.Link != legacy.ID || legacy.ContentHTML != "<p>old words</p>" || legacy.Summary != "old words" {
+		t.Errorf("legacy item %+v, want it linked by ID and rendered", legacy)
+	}
+	if edit.Author != "margaret" || legacy.Author != "margaret" || orphan.Author != "" {
+		t.Errorf("authors %q, %q, %q", edit.Author, legacy.Author, orphan.Author)
+	}
+	if users.lookups["u1"] != 1 {
+		t.Errorf("looked margaret up %d times, want once", users.lookups["u1"])
+	}
+}
+
+func TestEmptyFeedHasStableTimestamp(t *testing.T) {
+	users := &masthead{names: map[string]string{"u1": "margaret"}, lookups: map[string]int{}}
+	fu := NewFeedUsecase(&newsstand{}, users, aliasedTags{}, paragraphRenderer{}, "https://blog.example.org", "Field Notes", 20, 50)
+
+	first, err := fu.AuthorFeed(context.Background(), "u1", 0)
+	if err != nil {
+		t.Fatalf("AuthorFeed: %v", err)
+	}
+	second, _ := fu.AuthorFeed(context.Background(), "u1", 0)
+	if !first.Updated.Equal(second.Updated) || first.Items == nil {
+		t.Errorf("updated %v then %v with items %v, want a fixed time and no items", first.Updated, second.Updated, first.Items)
+	}
+
+	if _, err := fu.AuthorFeed(context.Background(), "nobody", 0); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("unknown author: %v, want %v", err, domain.ErrUserNotFound)
+	}
+	if _, err := fu.TagFeed(context.Background(), " # ", 0); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("blank tag: %v, want %v", err, domain.ErrInvalidInput)
+	}
+}
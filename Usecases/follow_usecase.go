@@ -0,0 +1,279 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultFollowLimit = 20
+	maxFollowLimit     = 100
+)
+
+type FollowUsecase struct {
+	followRepository domain.IFollowRepository
+	userRepository   domain.IUserRepository
+	blogRepository   domain.IBlogRepository
+	tagRepository    domain.ITagRepository
+	events           domain.IEventBus
+}
+
+func NewFollowUsecase(followRepo domain.IFollowRepository, userRepo domain.IUserRepository, blogRepo domain.IBlogRepository,
+	tagRepo domain.ITagRepository, events domain.IEventBus) domain.IFollowUsecase {
+	return &FollowUsecase{
+		followRepository: followRepo,
+		userRepository:   userRepo,
+		blogRepository:   blogRepo,
+		tagRepository:    tagRepo,
+		events:           events,
+	}
+}
+
+// FollowUser is idempotent: following someone again changes nothing.
+func (u *FollowUsecase) FollowUser(ctx context.Context, userID string, actor domain.Viewer) error {
+	if actor.UserID == "" {
+		return domain.ErrForbidden
+	}
+	if userID == actor.UserID {
+		return fmt.Errorf("%w: you cannot follow yourself", domain.ErrInvalidInput)
+	}
+	if _, err := u.userRepository.GetByID(ctx, userID); err != nil {
+		return err
+	}
+	created, err := u.followRepository.Follow(ctx, actor.UserID, userID, time.Now())
+	if err != nil {
+		return err
+	}
+	if created {
+		return u.events.Publish(ctx, domain.Event{Type: domain.EventUserFollowed, ActorID: actor.UserID, UserID: userID})
+	}
+	return nil
+}
+
+func (u *FollowUsecase) UnfollowUser(ctx context.Context, userID string, actor domain.Viewer) error {
+	if actor.UserID == "" {
+		return domain.ErrForbidden
+	}
+	_, err := u.followRepository.Unfollow(ctx, actor.UserID, userID)
+	return err
+}
+
+func (u *FollowUsecase) ListFollowers(ctx context.Context, userID string, page, limit int) (*domain.FollowPage, error) {
+	return u.list(ctx, userID, page, limit, true)
+}
+
+func (u *FollowUsecase) ListFollowing(ctx context.Context, userID string, page, limit int) (*domain.FollowPage, error) {
+	return u.list(ctx, userID, page, limit, false)
+}
+
+func (u *FollowUsecase) list(ctx context.Context, userID string, page, limit int, followers bool) (*domain.FollowPage, error) {
+	page, limit, err := followPageParams(page, limit)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.userRepository.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	var follows []domain.Follow
+	var total int64
+	if followers {
+		follows, total, err = u.followRepository.ListFollowers(ctx, userID, page, limit)
+	} else {
+		follows, total, err = u.followRepository.ListFollowing(ctx, userID, page, limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The other side of each follow is the user to show.
+	ids := make([]string, len(follows))
+	for i, f := range follows {
+		ids[i] = f.FolloweeID
+		if followers {
+			ids[i] = f.FollowerID
+		}
+	}
+	users, err := u.userRepository.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]domain.FollowUser, 0, len(follows))
+	for i, f := range follows {
+		user, ok := users[ids[i]]
+		if !ok {
+			continue
+		}
+		list = append(list, domain.FollowUser{
+			ID:             user.ID,
+			Username:       user.Username,
+			Bio:            user.Bio,
+			ProfilePicture: user.ProfilePicture,
+			FollowedAt:     f.CreatedAt,
+		})
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit)) // Ceiling division
+	return &domain.FollowPage{
+		Users:      list,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1,
+	}, nil
+}
+
+func (u *FollowUsecase) GetFollowCounts(ctx context.Context, userID string, viewer domain.Viewer) (*domain.FollowCounts, error) {
+	if _, err := u.userRepository.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	counts := &domain.FollowCounts{}
+	var err error
+	if counts.Followers, err = u.followRepository.CountFollowers(ctx, userID); err != nil {
+		return nil, err
+	}
+	if counts.Following, err = u.followRepository.CountFollowing(ctx, userID); err != nil {
+		return nil, err
+	}
+	if viewer.UserID != "" && viewer.UserID != userID {
+		if counts.Followed, err = u.followRepository.IsFollowing(ctx, viewer.UserID, userID); err != nil {
+			return nil, err
+		}
+	}
+	return counts, nil
+}
+
+// FollowTag follows a tag by its canonical name, so following an alias
+// follows the tag it points to.
+func (u *FollowUsecase) FollowTag(ctx context.Context, tag string, actor domain.Viewer) (string, error) {
+	if actor.UserID == "" {
+		return "", domain.ErrForbidden
+	}
+	name, err := validTagName(tag)
+	if err != nil {
+		return "", err
+	}
+	canonical, _, err := tagVariants(ctx, u.tagRepository, []string{name})
+	if err != nil {
+		return "", err
+	}
+	if _, err := u.followRepository.FollowTag(ctx, actor.UserID, canonical[0], time.Now()); err != nil {
+		return "", err
+	}
+	return canonical[0], nil
+}
+
+// UnfollowTag removes the follow whether it was stored under the tag's
+// current name or under a name that has since become an alias.
+func (u *FollowUsecase) UnfollowTag(ctx context.Context, tag string, actor domain.Viewer) error {
+	if actor.UserID == "" {
+		return domain.ErrForbidden
+	}
+	name, err := validTagName(tag)
+	if err != nil {
+		return err
+	}
+	_, variants, err := tagVariants(ctx, u.tagRepository, []string{name})
+	if err != nil {
+		return err
+	}
+	for _, spelling := range variants[0] {
+		if _, err := u.followRepository.UnfollowTag(ctx, actor.UserID, spelling); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListFollowedTags reports followed tags under their current names.
+func (u *FollowUsecase) ListFollowedTags(ctx context.Context, userID string) ([]string, error) {
+	if _, err := u.userRepository.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	stored, err := u.followRepository.FollowedTags(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	canonical, _, err := tagVariants(ctx, u.tagRepository, stored)
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]string, 0, len(canonical))
+	for _, name := range canonical {
+		if !containsTag(tags, name) {
+			tags = append(tags, name)
+		}
+	}
+	return tags, nil
+}
+
+func (u *FollowUsecase) GetFeed(ctx context.Context, cursor string, limit int, actor domain.Viewer) (*domain.BlogListResponse, error) {
+	if actor.UserID == "" {
+		return nil, domain.ErrForbidden
+	}
+	if limit == 0 {
+		limit = defaultBlogLimit
+	}
+	if limit < 1 || limit > maxBlogLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidInput, maxBlogLimit)
+	}
+
+	authorIDs, err := u.followRepository.FolloweeIDs(ctx, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	followedTags, err := u.followRepository.FollowedTags(ctx, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	response := &domain.BlogListResponse{Blogs: []domain.Blog{}, Limit: limit}
+	if len(authorIDs) == 0 && len(followedTags) == 0 {
+		return response, nil
+	}
+	_, variants, err := tagVariants(ctx, u.tagRepository, followedTags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve tags: %w", err)
+	}
+	var tags []string
+	for _, spellings := range variants {
+		tags = append(tags, spellings...)
+	}
+
+	// The feed only carries what anyone could read, so the query runs as
+	// an anonymous viewer; that also keeps the actor's own drafts out.
+	result, err := u.blogRepository.FindBlogs(ctx, domain.BlogQuery{
+		Following: &domain.FollowingFilter{AuthorIDs: authorIDs, Tags: tags},
+		Status:    domain.BlogStatusPublished,
+		Sort:      domain.BlogSortPublished,
+		Limit:     limit,
+		Cursor:    cursor,
+		UseCursor: true,
+	}, domain.Viewer{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed: %w", err)
+	}
+	response.Blogs = result.Blogs
+	response.NextCursor = result.NextCursor
+	response.HasNext = result.NextCursor != ""
+	return response, nil
+}
+
+func followPageParams(page, limit int) (int, int, error) {
+	if limit == 0 {
+		limit = defaultFollowLimit
+	}
+	if limit < 1 || limit > maxFollowLimit {
+		return 0, 0, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidInput, maxFollowLimit)
+	}
+	if page == 0 {
+		page = 1
+	}
+	if page < 1 {
+		return 0, 0, fmt.Errorf("%w: page must be at least 1", domain.ErrInvalidInput)
+	}
+	return page, limit, nil
+}
@@ -0,0 +1,336 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// socialGraph keeps follows in memory, oldest first.
+type socialGraph struct {
+	domain.IFollowRepository
+	follows []domain.Follow
+	tags    map[string][]string // user -> tags as stored
+}
+
+func (g *socialGraph) Follow(_ context.Context, followerID, followeeID string, at time.Time) (bool, error) {
+	if ok, _ := g.IsFollowing(context.Background(), followerID, followeeID); ok {
+		return false, nil
+	}
+	g.follows = append(g.follows, domain.Follow{FollowerID: followerID, FolloweeID: followeeID, CreatedAt: at})
+	return true, nil
+}
+
+func (g *socialGraph) IsFollowing(_ context.Context, followerID, followeeID string) (bool, error) {
+	for _, f := range g.follows {
+		if f.FollowerID == followerID && f.FolloweeID == followeeID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (g *socialGraph) ListFollowers(_ context.Context, userID string, _, _ int) ([]domain.Follow, int64, error) {
+	var found []domain.Follow
+	for _, f := range g.follows {
+		if f.FolloweeID == userID {
+			found = append(found, f)
+		}
+	}
+	return found, int64(len(found)), nil
+}
+
+func (g *socialGraph) CountFollowers(ctx context.Context, userID string) (int64, error) {
+	_, n, err := g.ListFollowers(ctx, userID, 1, 0)
+	return n, err
+}
+
+func (g *socialGraph) CountFollowing(ctx context.Context, userID string) (int64, error) {
+	ids, err := g.FolloweeIDs(ctx, userID)
+	return int64(len(ids)), err
+}
+
+func (g *socialGraph) FolloweeIDs(_ context.Context, followerID string) ([]string, error) {
+	var ids []string
+	for _, f := range g.follows {
+		if f.FollowerID == followerID {
+			ids = append(ids, f.FolloweeID)
+		}
+	}
+	return ids, nil
+}
+
+func (g *socialGraph) FollowTag(_ context.Context, userID, tag string, _ time.Time) (bool, error) {
+	if containsTag(g.tags[userID], tag) {
+		return false, nil
+	}
+	g.tags[userID] = append(g.tags[userID], tag)
+	return true, nil
+}
+
+func (g *socialGraph) UnfollowTag(_ context.Context, userID, tag string) (bool, error) {
+	kept := g.tags[userID][:0]
+	for _, t := range g.tags[userID] {
+		if t != tag {
+			kept = append(kept, t)
+		}
+	}
+	removed := len(kept) != len(g.tags[userID])
+	g.tags[userID] = kept
+	return removed, nil
+}
+
+func (g *socialGraph) FollowedTags(_ context.Context, userID string) ([]string, error) {
+	return g.tags[userID], nil
+}
+
+// roster is a fixed set of members; ex-members are followed but gone.
+type roster struct {
+	domain.IUserRepository
+	members map[string]domain.User
+}
+
+func (r *roster) GetByID(_ context.Context, id string) (*domain.User, error) {
+	user, ok := r.members[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	return &user, nil
+}
+
+func (r *roster) GetByIDs(_ context.Context, ids []string) (map[string]domain.User, error) {
+	found := map[string]domain.User{}
+	for _, id := range ids {
+		if user, ok := r.members[id]; ok {
+			found[id] = user
+		}
+	}
+	return found, nil
+}
+
+// renamedTags knows "javascript", once called "js".
+type renamedTags struct{ domain.ITagRepository }
+
+func (renamedTags) FindByNames(_ context.Context, names []string) (map[string]*domain.Tag, error) {
+	known := map[string]*domain.Tag{}
+	for _, name := range names {
+		if name == "javascript" || name == "js" {
+			known[name] = &domain.Tag{Name: "javascript", Aliases: []string{"js"}}
+		}
+	}
+	return known, nil
+}
+
+// homeTimeline answers every feed query with one page and remembers it.
+type homeTimeline struct {
+	domain.IBlogRepository
+	query  *domain.BlogQuery
+	viewer domain.Viewer
+	next   string
+}
+
+func (h *homeTimeline) FindBlogs(_ context.Context, query domain.BlogQuery, viewer domain.Viewer) (*domain.BlogQueryResult, error) {
+	h.query, h.viewer = &query, viewer
+	return &domain.BlogQueryResult{Blogs: []domain.Blog{{ID: "b40"}, {ID: "b39"}}, NextCursor: h.next}, nil
+}
+
+func newFollowFixture() (*FollowUsecase, *socialGraph, *recordingEventBus) {
+	graph := &socialGraph{tags: map[string][]string{}}
+	users := &roster{members: map[string]domain.User{
+		"ada":   {ID: "ada", Username: "ada"},
+		"brian": {ID: "brian", Username: "brian", Bio: "compilers"},
+		"chen":  {ID: "chen", Username: "chen"},
+	}}
+	events := &recordingEventBus{}
+	return NewFollowUsecase(graph, users, &homeTimeline{}, renamedTags{}, events).(*FollowUsecase), graph, events
+}
+
+func TestFollowUser(t *testing.T) {
+	ada := domain.Viewer{UserID: "ada", Role: domain.RoleUser}
+	tests := []struct {
+		name       string
+		follows    []string
+		actor      domain.Viewer
+		wantErr    error
+		wantEvents int
+	}{
+		{name: "follow", follows: []string{"brian"}, actor: ada, wantEvents: 1},
+		{name: "follow twice", follows: []string{"brian", "brian"}, actor: ada, wantEvents: 1},
+		{name: "yourself", follows: []string{"ada"}, actor: ada, wantErr: domain.ErrInvalidInput},
+		{name: "unknown user", follows: []string{"zed"}, actor: ada, wantErr: domain.ErrUserNotFound},
+		{name: "anonymous", follows: []string{"brian"}, wantErr: domain.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, graph, events := newFollowFixture()
+			var err error
+			for _, userID := range tt.follows {
+				if err = u.FollowUser(context.Background(), userID, tt.actor); err != nil {
+					break
+				}
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("FollowUser error = %v, want %v", err, tt.wantErr)
+			}
+			if len(events.types) != tt.wantEvents {
+				t.Errorf("published %v, want %d follow events", events.types, tt.wantEvents)
+			}
+			if tt.wantErr == nil && len(graph.follows) != 1 {
+				t.Errorf("stored %v, want one follow", graph.follows)
+			}
+		})
+	}
+}
+
+func TestFollowersAndCounts(t *testing.T) {
+	u, graph, _ := newFollowFixture()
+	ctx := context.Background()
+	graph.follows = []domain.Follow{
+		{FollowerID: "ada", FolloweeID: "brian"},
+		{FollowerID: "deleted", FolloweeID: "brian"},
+		{FollowerID: "chen", FolloweeID: "brian"},
+		{FollowerID: "brian", FolloweeID: "ada"},
+	}
+
+	page, err := u.ListFollowers(ctx, "brian", 0, 2)
+	if err != nil {
+		t.Fatalf("ListFollowers: %v", err)
+	}
+	var names []string
+	for _, user := range page.Users {
+		names = append(names, user.Username)
+	}
+	if !reflect.DeepEqual(names, []string{"ada", "chen"}) {
+		t.Errorf("followers %v, want ada and chen without the deleted account", names)
+	}
+	if page.Page != 1 || page.Total != 3 || page.TotalPages != 2 || !page.HasNext {
+		t.Errorf("page %d of %d, total %d, has next %v", page.Page, page.TotalPages, page.Total, page.HasNext)
+	}
+	if _, err := u.ListFollowers(ctx, "brian", 1, 101); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("oversized page: %v, want %v", err, domain.ErrInvalidInput)
+	}
+
+	counts := []struct {
+		viewer domain.Viewer
+		want   domain.FollowCounts
+	}{
+		{viewer: domain.Viewer{UserID: "ada"}, want: domain.FollowCounts{Followers: 3, Following: 1, Followed: true}},
+		{viewer: domain.Viewer{UserID: "brian"}, want: domain.FollowCounts{Followers: 3, Following: 1}},
+		{want: domain.FollowCounts{Followers: 3, Following: 1}},
+	}
+	for _, c := range counts {
+		got, err := u.GetFollowCounts(ctx, "brian", c.viewer)
+		if err != nil || *got != c.want {
+			t.Errorf("counts for %q = %+v, %v; want %+v", c.viewer.UserID, got, err, c.want)
+		}
+	}
+}
+
+func TestFollowTagByAlias(t *testing.T) {
+	u, graph, _ := newFollowFixture()
+	ctx := context.Background()
+	chen := domain.Viewer{UserID: "chen", Role: domain.RoleUser}
+	// Chen followed "js" before it became an alias.
+	graph.tags["chen"] = []string{"js", "rust"}
+
+	name, err := u.FollowTag(ctx, "#JavaScript", chen)
+	if err != nil || name != "javascript" {
+		t.Fatalf("FollowTag = %q, %v", name, err)
+	}
+	tags, err := u.ListFollowedTags(ctx, "chen")
+	if err != nil || !reflect.DeepEqual(tags, []string{"javascript", "rust"}) {
+		t.Errorf("followed tags %v, %v; want javascript once and rust", tags, err)
+	}
+
+	if err := u.UnfollowTag(ctx, "js", chen); err != nil {
+		t.Fatalf("UnfollowTag: %v", err)
+	}
+	if !reflect.DeepEqual(graph.tags["chen"], []string{"rust"}) {
+		t.Errorf("stored tags %v, want both spellings gone", graph.tags["chen"])
+	}
+	if _, err := u.FollowTag(ctx, "  ", chen); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("blank tag: %v, want %v", err, domain.ErrInvalidInput)
+	}
+}
+
+func TestGetFeed(t *testing.T) {
+	tests := []struct {
+		name        string
+		authors     []string
+		tags        []string
+		limit       int
+		actor       domain.Viewer
+		next        string
+		wantErr     error
+		wantQueried bool
+		wantLimit   int
+	}{
+		{name: "follows nobody", actor: domain.Viewer{UserID: "ada"}, wantLimit: 10},
+		{
+			name: "authors and tags", authors: []string{"brian", "chen"}, tags: []string{"javascript"}, limit: 2,
+			actor: domain.Viewer{UserID: "ada"}, next: "opaque", wantQueried: true, wantLimit: 2,
+		},
+		{name: "only tags", tags: []string{"rust"}, actor: domain.Viewer{UserID: "ada"}, wantQueried: true, wantLimit: 10},
+		{name: "limit too large", authors: []string{"brian"}, limit: 101, actor: domain.Viewer{UserID: "ada"}, wantErr: domain.ErrInvalidInput},
+		{name: "anonymous", authors: []string{"brian"}, wantErr: domain.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, graph, _ := newFollowFixture()
+			timeline := &homeTimeline{next: tt.next}
+			u.blogRepository = timeline
+			for _, author := range tt.authors {
+				graph.follows = append(graph.follows, domain.Follow{FollowerID: "ada", FolloweeID: author})
+			}
+			graph.tags["ada"] = tt.tags
+
+			feed, err := u.GetFeed(context.Background(), "after-b41", tt.limit, tt.actor)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetFeed error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if (timeline.query != nil) != tt.wantQueried {
+				t.Fatalf("queried = %v, want %v", timeline.query != nil, tt.wantQueried)
+			}
+			if feed.Limit != tt.wantLimit || feed.HasNext != (tt.next != "") || feed.NextCursor != tt.next {
+				t.Errorf("feed limit %d, next %q, has next %v", feed.Limit, feed.NextCursor, feed.HasNext)
+			}
+			if !tt.wantQueried {
+				if feed.Blogs == nil || len(feed.Blogs) != 0 {
+					t.Errorf("blogs %v, want an empty list", feed.Blogs)
+				}
+				return
+			}
+
+			q := timeline.query
+			if q.Status != domain.BlogStatusPublished || q.Sort != domain.BlogSortPublished || !q.UseCursor || q.Cursor != "after-b41" {
+				t.Errorf("query %+v, want published blogs by publication date from the cursor", q)
+			}
+			if timeline.viewer != (domain.Viewer{}) {
+				t.Errorf("queried as %+v, want an anonymous reader", timeline.viewer)
+			}
+			tags := append([]string{}, q.Following.Tags...)
+			sort.Strings(tags)
+			wantTags := []string{}
+			for _, tag := range tt.tags {
+				if tag == "javascript" {
+					wantTags = append(wantTags, "javascript", "js")
+				} else {
+					wantTags = append(wantTags, tag)
+				}
+			}
+			if !reflect.DeepEqual(q.Following.AuthorIDs, tt.authors) || !reflect.DeepEqual(tags, wantTags) {
+				t.Errorf("following authors %v, tags %v; want %v, %v", q.Following.AuthorIDs, tags, tt.authors, wantTags)
+			}
+			if len(feed.Blogs) != 2 {
+				t.Errorf("got %d blogs, want the page", len(feed.Blogs))
+			}
+		})
+	}
+}
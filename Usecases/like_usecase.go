@@ -2,36 +2,43 @@ package usecases
 
 import (
 	domain "blog-api/Domain"
-	"time"
+	"context"
 )
 
 type LikeUsecase struct {
-	likeRepo domain.IBlogLikeRepository
+	reactionUsecase domain.IReactionUsecase
 }
 
-func NewLikeUsecase(likeRepo domain.IBlogLikeRepository) domain.IBlogLikeUsecase {
+func NewLikeUsecase(reactionUsecase domain.IReactionUsecase) domain.IBlogLikeUsecase {
 	return &LikeUsecase{
-		likeRepo: likeRepo,
+		reactionUsecase: reactionUsecase,
 	}
 }
 
+// LikeBlog switches any other reaction the user had to a like.
 func (uc *LikeUsecase) LikeBlog(blogID, userID string) error {
-	like := &domain.Like{
-		BlogID:    blogID,
-		UserID:    userID,
-		CreatedAt: time.Now(),
-	}
-	return uc.likeRepo.AddLike(like)
+	_, err := uc.reactionUsecase.React(context.Background(), domain.ReactionTargetBlog, blogID, domain.ReactionLike, domain.Viewer{UserID: userID})
+	return err
 }
 
+// RemoveLikeBlog leaves reactions other than a like in place.
 func (uc *LikeUsecase) RemoveLikeBlog(blogID, userID string) error {
-	return uc.likeRepo.RemoveLike(blogID, userID)
+	_, err := uc.reactionUsecase.Unreact(context.Background(), domain.ReactionTargetBlog, blogID, domain.ReactionLike, domain.Viewer{UserID: userID})
+	return err
 }
 
 func (uc *LikeUsecase) IsBlogLiked(blogID, userID string) (bool, error) {
-	return uc.likeRepo.IsLiked(blogID, userID)
+	summary, err := uc.reactionUsecase.GetReactions(context.Background(), domain.ReactionTargetBlog, blogID, domain.Viewer{UserID: userID})
+	if err != nil {
+		return false, err
+	}
+	return summary.Mine == domain.ReactionLike, nil
 }
 
 func (uc *LikeUsecase) GetLikeCount(blogID string) (int, error) {
-	return uc.likeRepo.CountLikes(blogID)
+	summary, err := uc.reactionUsecase.GetReactions(context.Background(), domain.ReactionTargetBlog, blogID, domain.Viewer{})
+	if err != nil {
+		return 0, err
+	}
+	return int(summary.Counts[domain.ReactionLike]), nil
 }
@@ -0,0 +1,74 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"strings"
+)
+
+// maxDiffCells bounds the size of the LCS table. Inputs larger than this are
+// reported as a full replacement rather than risking a huge allocation.
+const maxDiffCells = 4_000_000
+
+// diffLines computes a line-level diff from older to newer using the longest
+// common subsequence of their lines.
+func diffLines(older, newer string) []domain.DiffLine {
+	a := splitLines(older)
+	b := splitLines(newer)
+
+	if len(a)*len(b) > maxDiffCells {
+		diff := make([]domain.DiffLine, 0, len(a)+len(b))
+		for _, line := range a {
+			diff = append(diff, domain.DiffLine{Op: domain.DiffDelete, Text: line})
+		}
+		for _, line := range b {
+			diff = append(diff, domain.DiffLine{Op: domain.DiffInsert, Text: line})
+		}
+		return diff
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	diff := make([]domain.DiffLine, 0, max(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			diff = append(diff, domain.DiffLine{Op: domain.DiffEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, domain.DiffLine{Op: domain.DiffDelete, Text: a[i]})
+			i++
+		default:
+			diff = append(diff, domain.DiffLine{Op: domain.DiffInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		diff = append(diff, domain.DiffLine{Op: domain.DiffDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		diff = append(diff, domain.DiffLine{Op: domain.DiffInsert, Text: b[j]})
+	}
+	return diff
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+}
@@ -0,0 +1,49 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	eq := func(s string) domain.DiffLine { return domain.DiffLine{Op: domain.DiffEqual, Text: s} }
+	ins := func(s string) domain.DiffLine { return domain.DiffLine{Op: domain.DiffInsert, Text: s} }
+	del := func(s string) domain.DiffLine { return domain.DiffLine{Op: domain.DiffDelete, Text: s} }
+
+	tests := []struct {
+		name         string
+		older, newer string
+		want         []domain.DiffLine
+	}{
+		{"both empty", "", "", []domain.DiffLine{}},
+		{"all inserted", "", "a\nb", []domain.DiffLine{ins("a"), ins("b")}},
+		{"all deleted", "a\nb", "", []domain.DiffLine{del("a"), del("b")}},
+		{"unchanged", "a\nb", "a\nb", []domain.DiffLine{eq("a"), eq("b")}},
+		{"line changed", "a\nb\nc", "a\nx\nc", []domain.DiffLine{eq("a"), del("b"), ins("x"), eq("c")}},
+		{"line appended", "a", "a\nb", []domain.DiffLine{eq("a"), ins("b")}},
+		{"line removed", "a\nb\nc", "a\nc", []domain.DiffLine{eq("a"), del("b"), eq("c")}},
+		{"CRLF matches LF", "a\r\nb", "a\nb", []domain.DiffLine{eq("a"), eq("b")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffLines(tt.older, tt.newer); !slices.Equal(got, tt.want) {
+				t.Errorf("diffLines(%q, %q) = %v, want %v", tt.older, tt.newer, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffLinesTooLarge(t *testing.T) {
+	// Past maxDiffCells the diff is a full replacement, even of equal lines.
+	older := strings.Repeat("same\n", 2001)
+	newer := strings.Repeat("same\n", 2000)
+	diff := diffLines(older, newer)
+	if len(diff) != 2002+2001 {
+		t.Fatalf("got %d lines, want %d", len(diff), 2002+2001)
+	}
+	if diff[0].Op != domain.DiffDelete || diff[len(diff)-1].Op != domain.DiffInsert {
+		t.Errorf("want deletions followed by insertions, got %v ... %v", diff[0], diff[len(diff)-1])
+	}
+}
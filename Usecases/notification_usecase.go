@@ -0,0 +1,295 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"fmt"
+	"log"
+	"slices"
+)
+
+const (
+	defaultNotificationLimit = 20
+	maxNotificationLimit     = 100
+	shownNotificationActors  = 3
+)
+
+type NotificationUsecase struct {
+	notificationRepository domain.INotificationRepository
+	userRepository         domain.IUserRepository
+	blogRepository         domain.IBlogRepository
+	events                 domain.IEventBus
+}
+
+func NewNotificationUsecase(notificationRepo domain.INotificationRepository, userRepo domain.IUserRepository,
+	blogRepo domain.IBlogRepository, events domain.IEventBus) domain.INotificationUsecase {
+	return &NotificationUsecase{
+		notificationRepository: notificationRepo,
+		userRepository:         userRepo,
+		blogRepository:         blogRepo,
+		events:                 events,
+	}
+}
+
+// HandleEvent ignores events nobody is notified of, such as users reacting
+// to their own blog, and types the recipient muted.
+func (u *NotificationUsecase) HandleEvent(ctx context.Context, event domain.Event) error {
+	notification := notificationFor(event)
+	if notification == nil || event.UserID == "" || event.UserID == event.ActorID {
+		return nil
+	}
+	// Only the types in NotificationTypes can be muted.
+	if notification.Type.IsValid() {
+		muted, err := u.notificationRepository.MutedTypes(ctx, event.UserID)
+		if err != nil {
+			return err
+		}
+		if slices.Contains(muted, notification.Type) {
+			return nil
+		}
+	}
+	id, err := u.notificationRepository.Add(ctx, notification, event.ActorID, event.OccurredAt)
+	if err != nil {
+		return err
+	}
+	// The notification is stored, so handling the event again would only
+	// add the actor twice.
+	err = u.events.Publish(ctx, domain.Event{
+		Type:    domain.EventNotificationAdded,
+		ActorID: event.ActorID,
+		UserID:  event.UserID,
+		BlogID:  notification.BlogID,
+		Data:    map[string]string{"notification_id": id, "type": string(notification.Type)},
+	})
+	if err != nil {
+		log.Printf("warning: failed to announce notification %s: %v", id, err)
+	}
+	return nil
+}
+
+// notificationFor builds the notification an event goes into. Its group key
+// decides which events are shown together.
+func notificationFor(event domain.Event) *domain.Notification {
+	n := &domain.Notification{UserID: event.UserID, BlogID: event.BlogID, CommentID: event.CommentID}
+	switch event.Type {
+	case domain.EventReactionAdded:
+		n.Type = domain.NotificationReaction
+		n.Reaction = event.Data["reaction"]
+		if n.CommentID != "" {
+			n.GroupKey = "reaction:" + n.Reaction + ":comment:" + n.CommentID
+		} else {
+			n.GroupKey = "reaction:" + n.Reaction + ":blog:" + n.BlogID
+		}
+	case domain.EventCommentCreated:
+		// Group by blog; the individual comments are on the blog itself.
+		n.Type = domain.NotificationComment
+		n.CommentID = ""
+		n.GroupKey = "comment:blog:" + n.BlogID
+	case domain.EventCommentReplied:
+		n.Type = domain.NotificationReply
+		n.GroupKey = "reply:comment:" + n.CommentID
+	case domain.EventUserFollowed:
+		n.Type = domain.NotificationFollow
+		n.GroupKey = "follow"
+	case domain.EventUserWarned:
+		// Each warning stands alone.
+		n.Type = domain.NotificationWarning
+		n.Note = event.Data["note"]
+		n.GroupKey = "warning:report:" + event.Data["report_id"]
+	default:
+		return nil
+	}
+	return n
+}
+
+func (u *NotificationUsecase) List(ctx context.Context, unreadOnly bool, page, limit int, actor domain.Viewer) (*domain.NotificationPage, error) {
+	if actor.UserID == "" {
+		return nil, domain.ErrForbidden
+	}
+	if limit == 0 {
+		limit = defaultNotificationLimit
+	}
+	if limit < 1 || limit > maxNotificationLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidInput, maxNotificationLimit)
+	}
+	if page == 0 {
+		page = 1
+	}
+	if page < 1 {
+		return nil, fmt.Errorf("%w: page must be at least 1", domain.ErrInvalidInput)
+	}
+
+	notifications, total, err := u.notificationRepository.List(ctx, actor.UserID, unreadOnly, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.describe(ctx, notifications, actor); err != nil {
+		return nil, err
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit)) // Ceiling division
+	return &domain.NotificationPage{
+		Notifications: notifications,
+		Page:          page,
+		Limit:         limit,
+		Total:         total,
+		TotalPages:    totalPages,
+		HasNext:       page < totalPages,
+		HasPrev:       page > 1,
+	}, nil
+}
+
+// describe fills in the most recent actors, the blog and the message of
+// each notification. Deleted users are left out of the actors, and the blog
+// is left out when viewer can no longer see it.
+func (u *NotificationUsecase) describe(ctx context.Context, notifications []domain.Notification, viewer domain.Viewer) error {
+	var userIDs, blogIDs []string
+	for _, n := range notifications {
+		// Moderators stay anonymous.
+		if n.Type != domain.NotificationWarning {
+			userIDs = append(userIDs, n.ActorIDs[:min(len(n.ActorIDs), shownNotificationActors)]...)
+		}
+		if n.BlogID != "" {
+			blogIDs = append(blogIDs, n.BlogID)
+		}
+	}
+	users, err := u.userRepository.GetByIDs(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+	blogs, err := u.blogRepository.FindByIDs(ctx, blogIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range notifications {
+		n := &notifications[i]
+		n.Actors = []domain.NotificationActor{}
+		for _, id := range n.ActorIDs[:min(len(n.ActorIDs), shownNotificationActors)] {
+			if user, ok := users[id]; ok && n.Type != domain.NotificationWarning {
+				n.Actors = append(n.Actors, domain.NotificationActor{UserID: user.ID, Username: user.Username})
+			}
+		}
+		if blog, ok := blogs[n.BlogID]; ok && viewer.CanSee(&blog) {
+			summary := blog.Summary()
+			n.Blog = &summary
+		}
+		n.Message = notificationMessage(n)
+	}
+	return nil
+}
+
+// notificationMessage reads like "alice and 11 others liked your blog".
+func notificationMessage(n *domain.Notification) string {
+	who := "Someone"
+	if len(n.Actors) > 0 {
+		who = n.Actors[0].Username
+	}
+	switch {
+	case n.ActorCount == 2 && len(n.Actors) == 2:
+		who += " and " + n.Actors[1].Username
+	case n.ActorCount == 2:
+		who += " and 1 other"
+	case n.ActorCount > 2:
+		who += fmt.Sprintf(" and %d others", n.ActorCount-1)
+	}
+
+	what := "your blog"
+	switch {
+	case n.Type == domain.NotificationWarning && n.BlogID == "" && n.CommentID == "":
+		what = "your account"
+	case n.CommentID != "":
+		what = "your comment"
+	case n.Blog != nil:
+		what = fmt.Sprintf("your blog %q", n.Blog.Title)
+	}
+
+	switch n.Type {
+	case domain.NotificationReaction:
+		if n.Reaction == domain.ReactionLike {
+			return who + " liked " + what
+		}
+		return who + " reacted with " + n.Reaction + " to " + what
+	case domain.NotificationComment:
+		return who + " commented on " + what
+	case domain.NotificationReply:
+		return who + " replied to your comment"
+	case domain.NotificationFollow:
+		return who + " started following you"
+	case domain.NotificationWarning:
+		if n.Note != "" {
+			return "A moderator warned you about " + what + ": " + n.Note
+		}
+		return "A moderator warned you about " + what
+	default:
+		return who + " did something"
+	}
+}
+
+func (u *NotificationUsecase) CountUnread(ctx context.Context, actor domain.Viewer) (int64, error) {
+	if actor.UserID == "" {
+		return 0, domain.ErrForbidden
+	}
+	return u.notificationRepository.CountUnread(ctx, actor.UserID)
+}
+
+func (u *NotificationUsecase) MarkRead(ctx context.Context, notificationID string, actor domain.Viewer) error {
+	if actor.UserID == "" {
+		return domain.ErrForbidden
+	}
+	return u.notificationRepository.MarkRead(ctx, actor.UserID, notificationID)
+}
+
+func (u *NotificationUsecase) MarkAllRead(ctx context.Context, actor domain.Viewer) (int64, error) {
+	if actor.UserID == "" {
+		return 0, domain.ErrForbidden
+	}
+	return u.notificationRepository.MarkAllRead(ctx, actor.UserID)
+}
+
+func (u *NotificationUsecase) Delete(ctx context.Context, notificationID string, actor domain.Viewer) error {
+	if actor.UserID == "" {
+		return domain.ErrForbidden
+	}
+	return u.notificationRepository.Delete(ctx, actor.UserID, notificationID)
+}
+
+func (u *NotificationUsecase) GetPreferences(ctx context.Context, actor domain.Viewer) (domain.NotificationPreferences, error) {
+	if actor.UserID == "" {
+		return nil, domain.ErrForbidden
+	}
+	muted, err := u.notificationRepository.MutedTypes(ctx, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	prefs := make(domain.NotificationPreferences, len(domain.NotificationTypes))
+	for _, t := range domain.NotificationTypes {
+		prefs[t] = !slices.Contains(muted, t)
+	}
+	return prefs, nil
+}
+
+func (u *NotificationUsecase) UpdatePreferences(ctx context.Context, changes domain.NotificationPreferences, actor domain.Viewer) (domain.NotificationPreferences, error) {
+	for t := range changes {
+		if !t.IsValid() {
+			return nil, fmt.Errorf("%w: unknown notification type %q", domain.ErrInvalidInput, t)
+		}
+	}
+	prefs, err := u.GetPreferences(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+	var muted []domain.NotificationType
+	for _, t := range domain.NotificationTypes {
+		if enabled, ok := changes[t]; ok {
+			prefs[t] = enabled
+		}
+		if !prefs[t] {
+			muted = append(muted, t)
+		}
+	}
+	if err := u.notificationRepository.SetMutedTypes(ctx, actor.UserID, muted); err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
@@ -0,0 +1,182 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"testing"
+	"time"
+)
+
+// groupingNotifications groups added notifications by user and group key,
+// like the Mongo repository, and mutes types per user.
+type groupingNotifications struct {
+	domain.INotificationRepository
+	muted  map[string][]domain.NotificationType
+	groups map[string][]string // actor IDs by user and group key
+}
+
+func (r *groupingNotifications) MutedTypes(_ context.Context, userID string) ([]domain.NotificationType, error) {
+	return r.muted[userID], nil
+}
+
+func (r *groupingNotifications) Add(_ context.Context, notification *domain.Notification, actorID string, at time.Time) (string, error) {
+	key := notification.UserID + "|" + notification.GroupKey
+	r.groups[key] = append(r.groups[key], actorID)
+	return key, nil
+}
+
+func TestNotificationGrouping(t *testing.T) {
+	liked := func(actorID, blogID string) domain.Event {
+		return domain.Event{Type: domain.EventReactionAdded, ActorID: actorID, UserID: "alice", BlogID: blogID,
+			Data: map[string]string{"reaction": "like"}}
+	}
+	tests := []struct {
+		name   string
+		muted  []domain.NotificationType
+		events []domain.Event
+		want   map[string]int // actors by group
+	}{
+		{
+			name:   "likes on one blog are grouped",
+			events: []domain.Event{liked("bob", "b1"), liked("carol", "b1"), liked("bob", "b2")},
+			want:   map[string]int{"alice|reaction:like:blog:b1": 2, "alice|reaction:like:blog:b2": 1},
+		},
+		{
+			name: "reaction types are kept apart",
+			events: []domain.Event{liked("bob", "b1"), {Type: domain.EventReactionAdded, ActorID: "carol", UserID: "alice", BlogID: "b1",
+				Data: map[string]string{"reaction": "love"}}},
+			want: map[string]int{"alice|reaction:like:blog:b1": 1, "alice|reaction:love:blog:b1": 1},
+		},
+		{
+			name: "reactions to a comment are grouped by comment",
+			events: []domain.Event{
+				{Type: domain.EventReactionAdded, ActorID: "bob", UserID: "alice", BlogID: "b1", CommentID: "c1", Data: map[string]string{"reaction": "like"}},
+				{Type: domain.EventReactionAdded, ActorID: "carol", UserID: "alice", BlogID: "b1", CommentID: "c1", Data: map[string]string{"reaction": "like"}},
+			},
+			want: map[string]int{"alice|reaction:like:comment:c1": 2},
+		},
+		{
+			name: "comments are grouped by blog",
+			events: []domain.Event{
+				{Type: domain.EventCommentCreated, ActorID: "bob", UserID: "alice", BlogID: "b1", CommentID: "c1"},
+				{Type: domain.EventCommentCreated, ActorID: "carol", UserID: "alice", BlogID: "b1", CommentID: "c2"},
+			},
+			want: map[string]int{"alice|comment:blog:b1": 2},
+		},
+		{
+			name: "replies are grouped by comment",
+			events: []domain.Event{
+				{Type: domain.EventCommentReplied, ActorID: "bob", UserID: "alice", BlogID: "b1", CommentID: "c1"},
+				{Type: domain.EventCommentReplied, ActorID: "carol", UserID: "alice", BlogID: "b1", CommentID: "c2"},
+			},
+			want: map[string]int{"alice|reply:comment:c1": 1, "alice|reply:comment:c2": 1},
+		},
+		{
+			name: "follows are grouped",
+			events: []domain.Event{
+				{Type: domain.EventUserFollowed, ActorID: "bob", UserID: "alice"},
+				{Type: domain.EventUserFollowed, ActorID: "carol", UserID: "alice"},
+			},
+			want: map[string]int{"alice|follow": 2},
+		},
+		{
+			name:   "own actions are ignored",
+			events: []domain.Event{liked("alice", "b1")},
+			want:   map[string]int{},
+		},
+		{
+			name:   "muted types are ignored",
+			muted:  []domain.NotificationType{domain.NotificationReaction},
+			events: []domain.Event{liked("bob", "b1"), {Type: domain.EventUserFollowed, ActorID: "bob", UserID: "alice"}},
+			want:   map[string]int{"alice|follow": 1},
+		},
+		{
+			name:  "warnings stand alone and cannot be muted",
+			muted: []domain.NotificationType{domain.NotificationWarning},
+			events: []domain.Event{
+				{Type: domain.EventUserWarned, ActorID: "admin", UserID: "alice", BlogID: "b1", Data: map[string]string{"report_id": "r1"}},
+				{Type: domain.EventUserWarned, ActorID: "admin", UserID: "alice", BlogID: "b1", Data: map[string]string{"report_id": "r2"}},
+			},
+			want: map[string]int{"alice|warning:report:r1": 1, "alice|warning:report:r2": 1},
+		},
+		{
+			name:   "other events are ignored",
+			events: []domain.Event{{Type: domain.EventBlogPublished, UserID: "alice", BlogID: "b1"}},
+			want:   map[string]int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &groupingNotifications{muted: map[string][]domain.NotificationType{"alice": tt.muted}, groups: map[string][]string{}}
+			events := &recordingEventBus{}
+			u := NewNotificationUsecase(repo, nil, nil, events)
+			for _, event := range tt.events {
+				if err := u.HandleEvent(context.Background(), event); err != nil {
+					t.Fatalf("HandleEvent: %v", err)
+				}
+			}
+
+			if len(repo.groups) != len(tt.want) {
+				t.Errorf("groups = %v, want %v", repo.groups, tt.want)
+			}
+			added := 0
+			for key, want := range tt.want {
+				if got := len(repo.groups[key]); got != want {
+					t.Errorf("actors in %s = %d, want %d", key, got, want)
+				}
+				added += want
+			}
+			if len(events.types) != added {
+				t.Errorf("announced %d notifications, want %d", len(events.types), added)
+			}
+		})
+	}
+}
+
+func TestNotificationMessage(t *testing.T) {
+	bob := domain.NotificationActor{UserID: "bob", Username: "bob"}
+	carol := domain.NotificationActor{UserID: "carol", Username: "carol"}
+	tests := []struct {
+		name string
+		n    domain.Notification
+		want string
+	}{
+		{
+			name: "one like",
+			n:    domain.Notification{Type: domain.NotificationReaction, Reaction: "like", Actors: []domain.NotificationActor{bob}, ActorCount: 1},
+			want: "bob liked your blog",
+		},
+		{
+			name: "two actors",
+			n: domain.Notification{Type: domain.NotificationReaction, Reaction: "love", Actors: []domain.NotificationActor{bob, carol}, ActorCount: 2,
+				Blog: &domain.BlogSummary{Title: "Go"}},
+			want: `bob and carol reacted with love to your blog "Go"`,
+		},
+		{
+			name: "many actors on a comment",
+			n:    domain.Notification{Type: domain.NotificationReaction, Reaction: "like", CommentID: "c1", Actors: []domain.NotificationActor{bob}, ActorCount: 12},
+			want: "bob and 11 others liked your comment",
+		},
+		{
+			name: "actor no longer around",
+			n:    domain.Notification{Type: domain.NotificationFollow, ActorCount: 1},
+			want: "Someone started following you",
+		},
+		{
+			name: "warning about a blog",
+			n: domain.Notification{Type: domain.NotificationWarning, BlogID: "b1", Note: "no spam, please", ActorCount: 1,
+				Blog: &domain.BlogSummary{Title: "Cheap pills"}},
+			want: `A moderator warned you about your blog "Cheap pills": no spam, please`,
+		},
+		{
+			name: "warning about an account",
+			n:    domain.Notification{Type: domain.NotificationWarning, ActorCount: 1},
+			want: "A moderator warned you about your account",
+		},
+	}
+	for _, tt := range tests {
+		if got := notificationMessage(&tt.n); got != tt.want {
+			t.Errorf("%s: notificationMessage = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// outboxLease holds a claimed message back from other dispatchers long
+	// enough for any handler to finish.
+	outboxLease = 2 * time.Minute
+	// outboxConcurrency is how many messages are handled at once.
+	outboxConcurrency = 4
+	outboxBaseBackoff = 5 * time.Second
+	outboxMaxBackoff  = 15 * time.Minute
+)
+
+type OutboxDispatcher struct {
+	outboxRepository domain.IOutboxRepository
+	interval         time.Duration
+	maxAttempts      int
+
+	mu       sync.RWMutex
+	handlers map[string]domain.OutboxHandler
+}
+
+// NewOutboxDispatcher gives each message up to maxAttempts attempts before
+// dead-lettering it.
+func NewOutboxDispatcher(outboxRepo domain.IOutboxRepository, interval time.Duration, maxAttempts int) domain.IOutboxDispatcher {
+	return &OutboxDispatcher{
+		outboxRepository: outboxRepo,
+		interval:         interval,
+		maxAttempts:      maxAttempts,
+		handlers:         make(map[string]domain.OutboxHandler),
+	}
+}
+
+func (d *OutboxDispatcher) Enqueue(ctx context.Context, topic string, payload any) error {
+	return d.enqueue(ctx, topic, payload, nil)
+}
+
+func (d *OutboxDispatcher) EnqueueUntil(ctx context.Context, topic string, payload any, expiresAt time.Time) error {
+	return d.enqueue(ctx, topic, payload, &expiresAt)
+}
+
+func (d *OutboxDispatcher) enqueue(ctx context.Context, topic string, payload any, expiresAt *time.Time) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s message: %w", topic, err)
+	}
+	now := time.Now()
+	return d.outboxRepository.Add(ctx, &domain.OutboxMessage{
+		Topic:         topic,
+		Payload:       string(data),
+		ExpiresAt:     expiresAt,
+		Status:        domain.OutboxPending,
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+}
+
+func (d *OutboxDispatcher) Handle(topic string, handler domain.OutboxHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers[topic] = handler
+}
+
+// Durable gives each subscriber its own message per event, so one that
+// keeps failing does not make the others see the event again.
+func (d *OutboxDispatcher) Durable(name string, handler domain.EventHandler, types ...domain.EventType) domain.EventHandler {
+	topic := domain.OutboxEventPrefix + name
+	d.Handle(topic, func(ctx context.Context, payload []byte) error {
+		var event domain.Event
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return fmt.Errorf("failed to decode event: %w", err)
+		}
+		return handler(ctx, event)
+	})
+	return func(ctx context.Context, event domain.Event) error {
+		if !slices.Contains(types, event.Type) {
+			return nil
+		}
+		if err := d.Enqueue(ctx, topic, event); err != nil {
+			return fmt.Errorf("failed to queue event for %s: %w", name, err)
+		}
+		return nil
+	}
+}
+
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DispatchDue(ctx); err != nil {
+			log.Printf("outbox dispatcher: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *OutboxDispatcher) DispatchDue(ctx context.Context) (int, error) {
+	// Expired messages, dead ones included, lose their payload first so
+	// that nothing stale is sent and no one-time code outlives its use.
+	if n, err := d.outboxRepository.ExpireDue(ctx, time.Now()); err != nil {
+		return 0, err
+	} else if n > 0 {
+		log.Printf("outbox dispatcher: expired %d message(s)", n)
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, outboxConcurrency)
+	defer wg.Wait()
+
+	attempted := 0
+	for ctx.Err() == nil {
+		slots <- struct{}{}
+		message, err := d.outboxRepository.ClaimDue(ctx, time.Now(), outboxLease)
+		if err != nil || message == nil {
+			<-slots
+			return attempted, err
+		}
+		attempted++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			if err := d.attempt(ctx, message); err != nil {
+				log.Printf("outbox dispatcher: message %s: %v", message.ID, err)
+			}
+		}()
+	}
+	return attempted, nil
+}
+
+// attempt handles a message once and schedules a retry with exponential
+// backoff when that fails, or dead-letters it once it is out of attempts or
+// has expired.
+func (d *OutboxDispatcher) attempt(ctx context.Context, message *domain.OutboxMessage) error {
+	d.mu.RLock()
+	handler, ok := d.handlers[message.Topic]
+	d.mu.RUnlock()
+
+	var err error
+	if ok {
+		err = runOutboxHandler(ctx, handler, message)
+		if ctx.Err() != nil {
+			// Shutting down: the lease runs out and the message is handled
+			// again later, without this attempt counting.
+			return nil
+		}
+	} else {
+		err = fmt.Errorf("no handler for topic %s", message.Topic)
+	}
+
+	now := time.Now()
+	message.Attempts++
+	message.UpdatedAt = now
+	message.LastError = ""
+	switch {
+	case err == nil:
+		message.Status = domain.OutboxDelivered
+		message.NextAttemptAt = nil
+		message.DeliveredAt = &now
+		message.Payload = ""
+	case !ok || message.Attempts >= d.maxAttempts || message.Expired(now):
+		message.Status = domain.OutboxDead
+		message.NextAttemptAt = nil
+		message.LastError = err.Error()
+		if message.Expired(now) {
+			message.Payload = ""
+		}
+		log.Printf("outbox dispatcher: dead-lettered %s message %s after %d attempts: %v", message.Topic, message.ID, message.Attempts, err)
+	default:
+		next := now.Add(outboxBackoff(message.Attempts))
+		message.NextAttemptAt = &next
+		message.LastError = err.Error()
+	}
+	return d.outboxRepository.Save(ctx, message)
+}
+
+// runOutboxHandler turns a panicking handler into a failed attempt.
+func runOutboxHandler(ctx context.Context, handler domain.OutboxHandler, message *domain.OutboxMessage) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+	return handler(ctx, []byte(message.Payload))
+}
+
+// outboxBackoff is the wait after the given number of failed attempts:
+// 5s, 10s, 20s, ... up to 15 minutes.
+func outboxBackoff(attempts int) time.Duration {
+	backoff := outboxBaseBackoff
+	for i := 1; i < attempts && backoff < outboxMaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, outboxMaxBackoff)
+}
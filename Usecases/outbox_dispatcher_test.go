@@ -0,0 +1,96 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOutboxBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 5 * time.Second},
+		{2, 10 * time.Second},
+		{3, 20 * time.Second},
+		{8, 640 * time.Second},
+		{9, 15 * time.Minute},
+		{50, 15 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := outboxBackoff(tt.attempts); got != tt.want {
+			t.Errorf("outboxBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+// savingOutboxRepository records the last message saved.
+type savingOutboxRepository struct {
+	domain.IOutboxRepository
+	saved *domain.OutboxMessage
+}
+
+func (r *savingOutboxRepository) Save(ctx context.Context, message *domain.OutboxMessage) error {
+	saved := *message
+	r.saved = &saved
+	return nil
+}
+
+func TestOutboxAttempt(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+	failing := func(ctx context.Context, payload []byte) error { return errors.New("smtp down") }
+
+	tests := []struct {
+		name        string
+		handler     domain.OutboxHandler
+		attempts    int
+		expiresAt   *time.Time
+		wantStatus  domain.OutboxStatus
+		wantPayload bool
+		wantRetry   bool
+	}{
+		{name: "delivered", handler: func(ctx context.Context, payload []byte) error { return nil }, wantStatus: domain.OutboxDelivered},
+		{name: "failure is retried", handler: failing, wantStatus: domain.OutboxPending, wantPayload: true, wantRetry: true},
+		{name: "failure before expiry is retried", handler: failing, expiresAt: &future, wantStatus: domain.OutboxPending, wantPayload: true, wantRetry: true},
+		{name: "out of attempts", handler: failing, attempts: 2, wantStatus: domain.OutboxDead, wantPayload: true},
+		{name: "expired", handler: failing, expiresAt: &past, wantStatus: domain.OutboxDead},
+		{name: "no handler", wantStatus: domain.OutboxDead, wantPayload: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &savingOutboxRepository{}
+			d := NewOutboxDispatcher(repo, time.Minute, 3).(*OutboxDispatcher)
+			if tt.handler != nil {
+				d.Handle(domain.OutboxVerificationEmail, tt.handler)
+			}
+			message := &domain.OutboxMessage{
+				ID: "m1", Topic: domain.OutboxVerificationEmail, Payload: `{"otp":"123456"}`,
+				Status: domain.OutboxPending, Attempts: tt.attempts, ExpiresAt: tt.expiresAt,
+			}
+			if err := d.attempt(context.Background(), message); err != nil {
+				t.Fatalf("attempt: %v", err)
+			}
+
+			saved := repo.saved
+			if saved == nil {
+				t.Fatal("message was not saved")
+			}
+			if saved.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", saved.Status, tt.wantStatus)
+			}
+			if saved.Attempts != tt.attempts+1 {
+				t.Errorf("attempts = %d, want %d", saved.Attempts, tt.attempts+1)
+			}
+			if got := saved.Payload != ""; got != tt.wantPayload {
+				t.Errorf("payload kept = %v, want %v", got, tt.wantPayload)
+			}
+			if got := saved.NextAttemptAt != nil; got != tt.wantRetry {
+				t.Errorf("retry scheduled = %v, want %v", got, tt.wantRetry)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultOutboxLimit = 20
+	maxOutboxLimit     = 100
+)
+
+type OutboxUsecase struct {
+	outboxRepository domain.IOutboxRepository
+}
+
+func NewOutboxUsecase(outboxRepo domain.IOutboxRepository) domain.IOutboxUsecase {
+	return &OutboxUsecase{outboxRepository: outboxRepo}
+}
+
+func (u *OutboxUsecase) ListMessages(ctx context.Context, status domain.OutboxStatus, topic string, page, limit int,
+	actor domain.Viewer) (*domain.OutboxPage, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, domain.ErrForbidden
+	}
+	switch status {
+	case "", domain.OutboxPending, domain.OutboxDelivered, domain.OutboxDead:
+	default:
+		return nil, fmt.Errorf("%w: status must be %q, %q or %q", domain.ErrInvalidInput,
+			domain.OutboxPending, domain.OutboxDelivered, domain.OutboxDead)
+	}
+	if limit == 0 {
+		limit = defaultOutboxLimit
+	}
+	if limit < 1 || limit > maxOutboxLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidInput, maxOutboxLimit)
+	}
+	if page == 0 {
+		page = 1
+	}
+	if page < 1 {
+		return nil, fmt.Errorf("%w: page must be at least 1", domain.ErrInvalidInput)
+	}
+
+	messages, total, err := u.outboxRepository.List(ctx, status, topic, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	totalPages := int((total + int64(limit) - 1) / int64(limit)) // Ceiling division
+	return &domain.OutboxPage{
+		Messages:   messages,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1,
+	}, nil
+}
+
+func (u *OutboxUsecase) GetMessage(ctx context.Context, messageID string, actor domain.Viewer) (*domain.OutboxMessage, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, domain.ErrForbidden
+	}
+	return u.outboxRepository.FindByID(ctx, messageID)
+}
+
+func (u *OutboxUsecase) RetryMessage(ctx context.Context, messageID string, actor domain.Viewer) (*domain.OutboxMessage, error) {
+	message, err := u.GetMessage(ctx, messageID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if message.Status != domain.OutboxDead {
+		return nil, fmt.Errorf("%w: only dead messages can be retried", domain.ErrInvalidInput)
+	}
+	now := time.Now()
+	if message.Expired(now) || message.Payload == "" {
+		return nil, fmt.Errorf("%w: the message has expired", domain.ErrInvalidInput)
+	}
+	message.Status = domain.OutboxPending
+	message.Attempts = 0
+	message.NextAttemptAt = &now
+	message.UpdatedAt = now
+	if err := u.outboxRepository.Save(ctx, message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
@@ -0,0 +1,263 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	defaultReactorLimit = 20
+	maxReactorLimit     = 100
+)
+
+type ReactionUsecase struct {
+	reactionRepository domain.IReactionRepository
+	blogRepository     domain.IBlogRepository
+	commentRepository  domain.ICommentRepository
+	userRepository     domain.IUserRepository
+	events             domain.IEventBus
+	types              []string
+}
+
+// NewReactionUsecase allows the given reaction types; "like" is always
+// among them.
+func NewReactionUsecase(reactionRepo domain.IReactionRepository, blogRepo domain.IBlogRepository, commentRepo domain.ICommentRepository,
+	userRepo domain.IUserRepository, events domain.IEventBus, types []string) domain.IReactionUsecase {
+	allowed := []string{domain.ReactionLike}
+	for _, t := range types {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" && !slices.Contains(allowed, t) {
+			allowed = append(allowed, t)
+		}
+	}
+	return &ReactionUsecase{
+		reactionRepository: reactionRepo,
+		blogRepository:     blogRepo,
+		commentRepository:  commentRepo,
+		userRepository:     userRepo,
+		events:             events,
+		types:              allowed,
+	}
+}
+
+func (u *ReactionUsecase) Types() []string {
+	return append([]string(nil), u.types...)
+}
+
+// React sets actor's reaction, switching from any earlier one.
+func (u *ReactionUsecase) React(ctx context.Context, targetType domain.ReactionTargetType, targetID, reactionType string, actor domain.Viewer) (*domain.ReactionSummary, error) {
+	if actor.UserID == "" {
+		return nil, domain.ErrForbidden
+	}
+	reactionType = strings.ToLower(strings.TrimSpace(reactionType))
+	if !slices.Contains(u.types, reactionType) {
+		return nil, fmt.Errorf("%w: reaction must be one of %s", domain.ErrInvalidInput, strings.Join(u.types, ", "))
+	}
+	target, err := u.checkTarget(ctx, targetType, targetID, actor)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := u.reactionRepository.Set(ctx, targetType, targetID, actor.UserID, reactionType, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	u.countLike(ctx, targetType, targetID, previous, reactionType)
+	if previous != reactionType {
+		if err := u.publish(ctx, domain.EventReactionAdded, targetType, targetID, target, reactionType, actor); err != nil {
+			return nil, err
+		}
+	}
+	return u.summary(ctx, targetType, targetID, actor)
+}
+
+func (u *ReactionUsecase) Unreact(ctx context.Context, targetType domain.ReactionTargetType, targetID, onlyType string, actor domain.Viewer) (*domain.ReactionSummary, error) {
+	if actor.UserID == "" {
+		return nil, domain.ErrForbidden
+	}
+	target, err := u.checkTarget(ctx, targetType, targetID, actor)
+	if err != nil {
+		return nil, err
+	}
+	removed, err := u.reactionRepository.Remove(ctx, targetType, targetID, actor.UserID, onlyType)
+	if err != nil {
+		return nil, err
+	}
+	u.countLike(ctx, targetType, targetID, removed, "")
+	if removed != "" {
+		if err := u.publish(ctx, domain.EventReactionRemoved, targetType, targetID, target, removed, actor); err != nil {
+			return nil, err
+		}
+	}
+	return u.summary(ctx, targetType, targetID, actor)
+}
+
+func (u *ReactionUsecase) GetReactions(ctx context.Context, targetType domain.ReactionTargetType, targetID string, viewer domain.Viewer) (*domain.ReactionSummary, error) {
+	if _, err := u.checkTarget(ctx, targetType, targetID, viewer); err != nil {
+		return nil, err
+	}
+	return u.summary(ctx, targetType, targetID, viewer)
+}
+
+func (u *ReactionUsecase) ListReactors(ctx context.Context, targetType domain.ReactionTargetType, targetID, reactionType string, page, limit int,
+	viewer domain.Viewer) (*domain.ReactorPage, error) {
+	if reactionType != "" && !slices.Contains(u.types, reactionType) {
+		return nil, fmt.Errorf("%w: reaction must be one of %s", domain.ErrInvalidInput, strings.Join(u.types, ", "))
+	}
+	if limit == 0 {
+		limit = defaultReactorLimit
+	}
+	if limit < 1 || limit > maxReactorLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidInput, maxReactorLimit)
+	}
+	if page == 0 {
+		page = 1
+	}
+	if page < 1 {
+		return nil, fmt.Errorf("%w: page must be at least 1", domain.ErrInvalidInput)
+	}
+	if _, err := u.checkTarget(ctx, targetType, targetID, viewer); err != nil {
+		return nil, err
+	}
+
+	reactions, total, err := u.reactionRepository.List(ctx, targetType, targetID, reactionType, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(reactions))
+	for i, r := range reactions {
+		ids[i] = r.UserID
+	}
+	users, err := u.userRepository.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	reactors := make([]domain.Reactor, 0, len(reactions))
+	for _, r := range reactions {
+		user, ok := users[r.UserID]
+		if !ok {
+			continue
+		}
+		reactors = append(reactors, domain.Reactor{UserID: user.ID, Username: user.Username, Type: r.Type, ReactedAt: r.UpdatedAt})
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit)) // Ceiling division
+	return &domain.ReactorPage{
+		Reactors:   reactors,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1,
+	}, nil
+}
+
+// reactionTarget is the blog a reaction is on, or that its comment is on,
+// and the author of the blog or comment.
+type reactionTarget struct {
+	blogID  string
+	ownerID string
+}
+
+// checkTarget makes sure the blog or comment exists and viewer may see it.
+// Comments count as visible once approved, while their blog is visible.
+func (u *ReactionUsecase) checkTarget(ctx context.Context, targetType domain.ReactionTargetType, targetID string, viewer domain.Viewer) (*reactionTarget, error) {
+	target := &reactionTarget{blogID: targetID}
+	switch targetType {
+	case domain.ReactionTargetBlog:
+	case domain.ReactionTargetComment:
+		comment, err := u.commentRepository.FindByID(ctx, targetID)
+		if err != nil {
+			return nil, err
+		}
+		if comment.Deleted || comment.Status != domain.CommentStatusApproved {
+			return nil, domain.ErrCommentNotFound
+		}
+		target.blogID, target.ownerID = comment.BlogId, comment.UserId
+	default:
+		return nil, fmt.Errorf("%w: unknown reaction target %q", domain.ErrInvalidInput, targetType)
+	}
+
+	blog, err := u.blogRepository.FindByID(ctx, target.blogID)
+	if err != nil {
+		return nil, err
+	}
+	if !viewer.CanSee(blog) {
+		return nil, domain.ErrBlogNotFound
+	}
+	if target.ownerID == "" {
+		target.ownerID = blog.UserID
+	}
+	return target, nil
+}
+
+func (u *ReactionUsecase) publish(ctx context.Context, eventType domain.EventType, targetType domain.ReactionTargetType, targetID string,
+	target *reactionTarget, reactionType string, actor domain.Viewer) error {
+	event := domain.Event{
+		Type:    eventType,
+		ActorID: actor.UserID,
+		UserID:  target.ownerID,
+		BlogID:  target.blogID,
+		Data:    map[string]string{"reaction": reactionType},
+	}
+	if targetType == domain.ReactionTargetComment {
+		event.CommentID = targetID
+	}
+	return u.events.Publish(ctx, event)
+}
+
+// countLike keeps the like count stored on a blog in step with a reaction
+// changing from previous to current. A failure is only logged; the counter
+// reconciler corrects the count later.
+func (u *ReactionUsecase) countLike(ctx context.Context, targetType domain.ReactionTargetType, targetID, previous, current string) {
+	if targetType != domain.ReactionTargetBlog {
+		return
+	}
+	delta := 0
+	if current == domain.ReactionLike {
+		delta++
+	}
+	if previous == domain.ReactionLike {
+		delta--
+	}
+	if delta == 0 {
+		return
+	}
+	if err := u.blogRepository.IncrementCounts(ctx, targetID, delta, 0); err != nil {
+		log.Printf("warning: failed to update like count of blog %s: %v", targetID, err)
+	}
+}
+
+func (u *ReactionUsecase) summary(ctx context.Context, targetType domain.ReactionTargetType, targetID string, viewer domain.Viewer) (*domain.ReactionSummary, error) {
+	counts, err := u.reactionRepository.Counts(ctx, targetType, []string{targetID})
+	if err != nil {
+		return nil, err
+	}
+	summary := &domain.ReactionSummary{Counts: reactionCounts(u.types, counts[targetID])}
+	for _, n := range summary.Counts {
+		summary.Total += n
+	}
+	if viewer.UserID != "" {
+		if summary.Mine, err = u.reactionRepository.Find(ctx, targetType, targetID, viewer.UserID); err != nil {
+			return nil, err
+		}
+	}
+	return summary, nil
+}
+
+// reactionCounts lists every configured type, with zero for types nobody
+// used, alongside the counts of any type no longer configured.
+func reactionCounts(types []string, stored map[string]int64) map[string]int64 {
+	counts := make(map[string]int64, len(types)+len(stored))
+	for _, t := range types {
+		counts[t] = 0
+	}
+	for t, n := range stored {
+		counts[t] = n
+	}
+	return counts
+}
@@ -0,0 +1,168 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memReactionRepository keeps one reaction per user and target.
+type memReactionRepository struct {
+	domain.IReactionRepository
+	reactions map[string]string // by target and user
+}
+
+func (r *memReactionRepository) key(targetType domain.ReactionTargetType, targetID, userID string) string {
+	return string(targetType) + "/" + targetID + "/" + userID
+}
+
+func (r *memReactionRepository) Set(_ context.Context, targetType domain.ReactionTargetType, targetID, userID, reactionType string, at time.Time) (string, error) {
+	key := r.key(targetType, targetID, userID)
+	previous := r.reactions[key]
+	r.reactions[key] = reactionType
+	return previous, nil
+}
+
+func (r *memReactionRepository) Remove(_ context.Context, targetType domain.ReactionTargetType, targetID, userID, onlyType string) (string, error) {
+	key := r.key(targetType, targetID, userID)
+	previous := r.reactions[key]
+	if onlyType != "" && previous != onlyType {
+		return "", nil
+	}
+	delete(r.reactions, key)
+	return previous, nil
+}
+
+func (r *memReactionRepository) Find(_ context.Context, targetType domain.ReactionTargetType, targetID, userID string) (string, error) {
+	return r.reactions[r.key(targetType, targetID, userID)], nil
+}
+
+func (r *memReactionRepository) Counts(_ context.Context, targetType domain.ReactionTargetType, targetIDs []string) (map[string]map[string]int64, error) {
+	counts := map[string]map[string]int64{}
+	for _, id := range targetIDs {
+		counts[id] = map[string]int64{}
+		for key, t := range r.reactions {
+			if strings.HasPrefix(key, r.key(targetType, id, "")) {
+				counts[id][t]++
+			}
+		}
+	}
+	return counts, nil
+}
+
+// recordingEventBus keeps the types of the events published.
+type recordingEventBus struct {
+	domain.IEventBus
+	types []domain.EventType
+}
+
+func (b *recordingEventBus) Publish(_ context.Context, event domain.Event) error {
+	b.types = append(b.types, event.Type)
+	return nil
+}
+
+func TestReactionSwitching(t *testing.T) {
+	tests := []struct {
+		name       string
+		steps      []string // reactions; "" removes any reaction and "-love" only a love
+		wantLikes  int
+		wantMine   string
+		wantCounts map[string]int64
+		wantEvents []domain.EventType
+	}{
+		{
+			name:       "like",
+			steps:      []string{"like"},
+			wantLikes:  1,
+			wantMine:   "like",
+			wantCounts: map[string]int64{"like": 1, "love": 0},
+			wantEvents: []domain.EventType{domain.EventReactionAdded},
+		},
+		{
+			name:       "same reaction twice",
+			steps:      []string{"like", "like"},
+			wantLikes:  1,
+			wantMine:   "like",
+			wantCounts: map[string]int64{"like": 1, "love": 0},
+			wantEvents: []domain.EventType{domain.EventReactionAdded},
+		},
+		{
+			name:       "like to love",
+			steps:      []string{"like", "love"},
+			wantLikes:  0,
+			wantMine:   "love",
+			wantCounts: map[string]int64{"like": 0, "love": 1},
+			wantEvents: []domain.EventType{domain.EventReactionAdded, domain.EventReactionAdded},
+		},
+		{
+			name:       "love to like",
+			steps:      []string{"love", "like"},
+			wantLikes:  1,
+			wantMine:   "like",
+			wantCounts: map[string]int64{"like": 1, "love": 0},
+			wantEvents: []domain.EventType{domain.EventReactionAdded, domain.EventReactionAdded},
+		},
+		{
+			name:       "unlike",
+			steps:      []string{"like", ""},
+			wantLikes:  0,
+			wantCounts: map[string]int64{"like": 0, "love": 0},
+			wantEvents: []domain.EventType{domain.EventReactionAdded, domain.EventReactionRemoved},
+		},
+		{
+			name:       "removing another type keeps the reaction",
+			steps:      []string{"like", "-love"},
+			wantLikes:  1,
+			wantMine:   "like",
+			wantCounts: map[string]int64{"like": 1, "love": 0},
+			wantEvents: []domain.EventType{domain.EventReactionAdded},
+		},
+		{
+			name:       "removing nothing",
+			steps:      []string{""},
+			wantCounts: map[string]int64{"like": 0, "love": 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			blogs := newMemBlogRepository(testBlog)
+			events := &recordingEventBus{}
+			u := NewReactionUsecase(&memReactionRepository{reactions: map[string]string{}}, blogs, nil, nil, events, []string{"love"})
+			actor := domain.Viewer{UserID: "alice", Role: domain.RoleUser}
+
+			var summary *domain.ReactionSummary
+			var err error
+			for _, s := range tt.steps {
+				switch {
+				case s == "":
+					summary, err = u.Unreact(ctx, domain.ReactionTargetBlog, "b1", "", actor)
+				case s[0] == '-':
+					summary, err = u.Unreact(ctx, domain.ReactionTargetBlog, "b1", s[1:], actor)
+				default:
+					summary, err = u.React(ctx, domain.ReactionTargetBlog, "b1", s, actor)
+				}
+				if err != nil {
+					t.Fatalf("%q: %v", s, err)
+				}
+			}
+
+			if blogs.likes != tt.wantLikes {
+				t.Errorf("like count change = %d, want %d", blogs.likes, tt.wantLikes)
+			}
+			if summary.Mine != tt.wantMine {
+				t.Errorf("mine = %q, want %q", summary.Mine, tt.wantMine)
+			}
+			if !maps.Equal(summary.Counts, tt.wantCounts) {
+				t.Errorf("counts = %v, want %v", summary.Counts, tt.wantCounts)
+			}
+			if !slices.Equal(events.types, tt.wantEvents) {
+				t.Errorf("events = %v, want %v", events.types, tt.wantEvents)
+			}
+		})
+	}
+}
@@ -0,0 +1,439 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	defaultReportLimit = 20
+	maxReportLimit     = 100
+	// maxReportDetails caps the free-text explanation of a report.
+	maxReportDetails = 1000
+)
+
+type ReportUsecase struct {
+	reportRepository           domain.IReportRepository
+	moderationActionRepository domain.IModerationActionRepository
+	blogRepository             domain.IBlogRepository
+	commentRepository          domain.ICommentRepository
+	userRepository             domain.IUserRepository
+	refreshTokenRepository     domain.IRefreshTokenRepository
+	events                     domain.IEventBus
+}
+
+func NewReportUsecase(reportRepo domain.IReportRepository, actionRepo domain.IModerationActionRepository, blogRepo domain.IBlogRepository,
+	commentRepo domain.ICommentRepository, userRepo domain.IUserRepository, refreshTokenRepo domain.IRefreshTokenRepository,
+	events domain.IEventBus) domain.IReportUsecase {
+	return &ReportUsecase{
+		reportRepository:           reportRepo,
+		moderationActionRepository: actionRepo,
+		blogRepository:             blogRepo,
+		commentRepository:          commentRepo,
+		userRepository:             userRepo,
+		refreshTokenRepository:     refreshTokenRepo,
+		events:                     events,
+	}
+}
+
+// CreateReport files a report, or updates the actor's open report on the
+// same target; the bool tells whether a new report was filed.
+func (u *ReportUsecase) CreateReport(ctx context.Context, input domain.CreateReportInput, actor domain.Viewer) (*domain.Report, bool, error) {
+	if actor.UserID == "" {
+		return nil, false, domain.ErrForbidden
+	}
+	if !input.TargetType.IsValid() {
+		return nil, false, fmt.Errorf("%w: target_type must be %q, %q or %q", domain.ErrInvalidInput,
+			domain.ReportTargetBlog, domain.ReportTargetComment, domain.ReportTargetUser)
+	}
+	if !input.Reason.IsValid() {
+		return nil, false, fmt.Errorf("%w: unknown reason %q", domain.ErrInvalidInput, input.Reason)
+	}
+	details := strings.TrimSpace(input.Details)
+	if utf8.RuneCountInString(details) > maxReportDetails {
+		return nil, false, fmt.Errorf("%w: details must be at most %d characters", domain.ErrInvalidInput, maxReportDetails)
+	}
+
+	targetUserID, err := u.targetOwner(ctx, input.TargetType, input.TargetID, actor)
+	if err != nil {
+		return nil, false, err
+	}
+	if targetUserID == actor.UserID {
+		return nil, false, fmt.Errorf("%w: you cannot report your own content", domain.ErrInvalidInput)
+	}
+
+	report := &domain.Report{
+		TargetType:   input.TargetType,
+		TargetID:     input.TargetID,
+		TargetUserID: targetUserID,
+		ReporterID:   actor.UserID,
+		Reason:       input.Reason,
+		Details:      details,
+	}
+	created, err := u.reportRepository.Upsert(ctx, report)
+	if err != nil {
+		return nil, false, err
+	}
+	return report, created, nil
+}
+
+func (u *ReportUsecase) ListReports(ctx context.Context, query domain.ReportQuery, actor domain.Viewer) (*domain.ReportPage, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, domain.ErrForbidden
+	}
+	if query.Status != "" && !query.Status.IsValid() {
+		return nil, fmt.Errorf("%w: unknown status %q", domain.ErrInvalidInput, query.Status)
+	}
+	if query.TargetType != "" && !query.TargetType.IsValid() {
+		return nil, fmt.Errorf("%w: unknown target_type %q", domain.ErrInvalidInput, query.TargetType)
+	}
+	if query.Reason != "" && !query.Reason.IsValid() {
+		return nil, fmt.Errorf("%w: unknown reason %q", domain.ErrInvalidInput, query.Reason)
+	}
+	var err error
+	if query.Page, query.Limit, err = reportPageParams(query.Page, query.Limit); err != nil {
+		return nil, err
+	}
+
+	reports, total, err := u.reportRepository.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	totalPages := int((total + int64(query.Limit) - 1) / int64(query.Limit)) // Ceiling division
+	return &domain.ReportPage{
+		Reports:    reports,
+		Page:       query.Page,
+		Limit:      query.Limit,
+		Total:      total,
+		TotalPages: totalPages,
+		HasNext:    query.Page < totalPages,
+		HasPrev:    query.Page > 1,
+	}, nil
+}
+
+func (u *ReportUsecase) GetReport(ctx context.Context, reportID string, actor domain.Viewer) (*domain.Report, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, domain.ErrForbidden
+	}
+	return u.reportRepository.FindByID(ctx, reportID)
+}
+
+func (u *ReportUsecase) CountReports(ctx context.Context, actor domain.Viewer) (*domain.ReportCounts, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, domain.ErrForbidden
+	}
+	return u.reportRepository.Counts(ctx)
+}
+
+func (u *ReportUsecase) ResolveReport(ctx context.Context, reportID string, input domain.ResolveReportInput, actor domain.Viewer) (*domain.Report, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, domain.ErrForbidden
+	}
+	if !input.Action.IsValid() {
+		return nil, fmt.Errorf("%w: action must be %q, %q, %q or %q", domain.ErrInvalidInput,
+			domain.ModerationDismiss, domain.ModerationHide, domain.ModerationWarn, domain.ModerationSuspend)
+	}
+	if input.SuspendFor < 0 {
+		return nil, fmt.Errorf("%w: suspension length must not be negative", domain.ErrInvalidInput)
+	}
+	report, err := u.reportRepository.FindByID(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+	if report.Status != domain.ReportStatusOpen {
+		return nil, fmt.Errorf("%w: report is already %s", domain.ErrInvalidInput, report.Status)
+	}
+
+	now := time.Now()
+	action := &domain.ModerationAction{
+		ActorID:      actor.UserID,
+		Action:       input.Action,
+		TargetType:   report.TargetType,
+		TargetID:     report.TargetID,
+		TargetUserID: report.TargetUserID,
+		ReportID:     report.ID,
+		Note:         strings.TrimSpace(input.Note),
+		CreatedAt:    now,
+	}
+	status := domain.ReportStatusResolved
+	if input.Action == domain.ModerationDismiss {
+		status = domain.ReportStatusDismissed
+	}
+
+	// Claim the report before acting on it, so that of two moderators
+	// resolving it at once only one takes action.
+	claimed, err := u.reportRepository.Close(ctx, report.ID, status, input.Action, actor.UserID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, fmt.Errorf("%w: report is already closed", domain.ErrInvalidInput)
+	}
+	if err := u.apply(ctx, report, action, input.SuspendFor, actor, now); err != nil {
+		if reopenErr := u.reportRepository.Reopen(ctx, report.ID); reopenErr != nil {
+			log.Printf("warning: failed to reopen report %s: %v", report.ID, reopenErr)
+		}
+		return nil, err
+	}
+
+	if err := u.moderationActionRepository.Create(ctx, action); err != nil {
+		return nil, err
+	}
+	if _, err := u.reportRepository.CloseTarget(ctx, report.TargetType, report.TargetID, status, input.Action, actor.UserID, now); err != nil {
+		return nil, err
+	}
+	return u.reportRepository.FindByID(ctx, report.ID)
+}
+
+// apply carries out action on the reported target.
+func (u *ReportUsecase) apply(ctx context.Context, report *domain.Report, action *domain.ModerationAction, suspendFor time.Duration,
+	actor domain.Viewer, now time.Time) error {
+	var err error
+	switch action.Action {
+	case domain.ModerationHide:
+		return u.hide(ctx, report, actor, now)
+	case domain.ModerationWarn:
+		return u.warn(ctx, report, action)
+	case domain.ModerationSuspend:
+		action.Until, err = u.suspend(ctx, report.TargetUserID, suspendFor, now)
+	}
+	return err
+}
+
+func (u *ReportUsecase) Unhide(ctx context.Context, targetType domain.ReportTargetType, targetID, note string, actor domain.Viewer) (*domain.ModerationAction, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, domain.ErrForbidden
+	}
+	var targetUserID string
+	switch targetType {
+	case domain.ReportTargetBlog:
+		blog, err := u.blogRepository.FindByID(ctx, targetID)
+		if err != nil {
+			return nil, err
+		}
+		if !blog.Hidden {
+			return nil, fmt.Errorf("%w: blog is not hidden", domain.ErrInvalidInput)
+		}
+		if err := u.blogRepository.SetHidden(ctx, blog.ID, false); err != nil {
+			return nil, err
+		}
+		targetUserID = blog.UserID
+	case domain.ReportTargetComment:
+		comment, err := u.commentRepository.FindByID(ctx, targetID)
+		if err != nil {
+			return nil, err
+		}
+		if !comment.Deleted {
+			return nil, fmt.Errorf("%w: comment is not hidden", domain.ErrInvalidInput)
+		}
+		if comment.DeletedBy == comment.UserId {
+			return nil, fmt.Errorf("%w: the comment was deleted by its author", domain.ErrInvalidInput)
+		}
+		if comment, err = u.commentRepository.Untombstone(ctx, comment.ID); err != nil {
+			return nil, err
+		}
+		if comment.Status == domain.CommentStatusApproved {
+			if err := u.blogRepository.IncrementCounts(ctx, comment.BlogId, 0, 1); err != nil {
+				log.Printf("warning: failed to update comment count of blog %s: %v", comment.BlogId, err)
+			}
+		}
+		targetUserID = comment.UserId
+	default:
+		return nil, fmt.Errorf("%w: target_type must be %q or %q", domain.ErrInvalidInput,
+			domain.ReportTargetBlog, domain.ReportTargetComment)
+	}
+
+	action := &domain.ModerationAction{
+		ActorID:      actor.UserID,
+		Action:       domain.ModerationUnhide,
+		TargetType:   targetType,
+		TargetID:     targetID,
+		TargetUserID: targetUserID,
+		Note:         strings.TrimSpace(note),
+		CreatedAt:    time.Now(),
+	}
+	if err := u.moderationActionRepository.Create(ctx, action); err != nil {
+		return nil, err
+	}
+	return action, nil
+}
+
+func (u *ReportUsecase) LiftSuspension(ctx context.Context, userID, note string, actor domain.Viewer) (*domain.ModerationAction, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, domain.ErrForbidden
+	}
+	user, err := u.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !user.IsSuspended(now) {
+		return nil, fmt.Errorf("%w: user is not suspended", domain.ErrInvalidInput)
+	}
+	if err := u.userRepository.SetSuspension(ctx, user.ID, false, nil); err != nil {
+		return nil, err
+	}
+
+	action := &domain.ModerationAction{
+		ActorID:      actor.UserID,
+		Action:       domain.ModerationUnsuspend,
+		TargetType:   domain.ReportTargetUser,
+		TargetID:     user.ID,
+		TargetUserID: user.ID,
+		Note:         strings.TrimSpace(note),
+		CreatedAt:    now,
+	}
+	if err := u.moderationActionRepository.Create(ctx, action); err != nil {
+		return nil, err
+	}
+	return action, nil
+}
+
+func (u *ReportUsecase) ListModerationActions(ctx context.Context, query domain.ModerationActionQuery, actor domain.Viewer) (*domain.ModerationActionPage, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, domain.ErrForbidden
+	}
+	var err error
+	if query.Page, query.Limit, err = reportPageParams(query.Page, query.Limit); err != nil {
+		return nil, err
+	}
+
+	actions, total, err := u.moderationActionRepository.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	totalPages := int((total + int64(query.Limit) - 1) / int64(query.Limit)) // Ceiling division
+	return &domain.ModerationActionPage{
+		Actions:    actions,
+		Page:       query.Page,
+		Limit:      query.Limit,
+		Total:      total,
+		TotalPages: totalPages,
+		HasNext:    query.Page < totalPages,
+		HasPrev:    query.Page > 1,
+	}, nil
+}
+
+// targetOwner checks that the reported target exists and is visible to the
+// reporter, and returns the ID of the user it belongs to.
+func (u *ReportUsecase) targetOwner(ctx context.Context, targetType domain.ReportTargetType, targetID string, actor domain.Viewer) (string, error) {
+	switch targetType {
+	case domain.ReportTargetBlog:
+		blog, err := u.blogRepository.FindByID(ctx, targetID)
+		if err != nil {
+			return "", err
+		}
+		if !actor.CanSee(blog) {
+			return "", domain.ErrBlogNotFound
+		}
+		return blog.UserID, nil
+	case domain.ReportTargetComment:
+		comment, err := u.commentRepository.FindByID(ctx, targetID)
+		if err != nil {
+			return "", err
+		}
+		if comment.Deleted || comment.Status == domain.CommentStatusRejected {
+			return "", domain.ErrCommentNotFound
+		}
+		return comment.UserId, nil
+	default:
+		user, err := u.userRepository.GetByID(ctx, targetID)
+		if err != nil {
+			return "", err
+		}
+		return user.ID, nil
+	}
+}
+
+// hide takes the reported blog or comment out of public view.
+func (u *ReportUsecase) hide(ctx context.Context, report *domain.Report, actor domain.Viewer, now time.Time) error {
+	switch report.TargetType {
+	case domain.ReportTargetBlog:
+		return u.blogRepository.SetHidden(ctx, report.TargetID, true)
+	case domain.ReportTargetComment:
+		comment, err := u.commentRepository.FindByID(ctx, report.TargetID)
+		if err != nil {
+			return err
+		}
+		if comment.Deleted {
+			return nil
+		}
+		if err := u.commentRepository.Tombstone(ctx, comment.ID, actor.UserID, now); err != nil {
+			return err
+		}
+		if comment.Status == domain.CommentStatusApproved {
+			if err := u.blogRepository.IncrementCounts(ctx, comment.BlogId, 0, -1); err != nil {
+				log.Printf("warning: failed to update comment count of blog %s: %v", comment.BlogId, err)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: a user cannot be hidden; warn or suspend instead", domain.ErrInvalidInput)
+	}
+}
+
+// warn tells the author of the reported target that a moderator warned
+// them about it.
+func (u *ReportUsecase) warn(ctx context.Context, report *domain.Report, action *domain.ModerationAction) error {
+	event := domain.Event{
+		Type:    domain.EventUserWarned,
+		ActorID: action.ActorID,
+		UserID:  report.TargetUserID,
+		Data:    map[string]string{"report_id": report.ID, "note": action.Note},
+	}
+	switch report.TargetType {
+	case domain.ReportTargetBlog:
+		event.BlogID = report.TargetID
+	case domain.ReportTargetComment:
+		event.CommentID = report.TargetID
+	}
+	if err := u.events.Publish(ctx, event); err != nil {
+		return fmt.Errorf("failed to warn user %s: %w", report.TargetUserID, err)
+	}
+	return nil
+}
+
+// suspend stops a user from signing in for the given duration (zero for no
+// end) and revokes their sessions. It returns the end of the suspension.
+func (u *ReportUsecase) suspend(ctx context.Context, userID string, duration time.Duration, now time.Time) (*time.Time, error) {
+	user, err := u.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == domain.RoleAdmin {
+		return nil, fmt.Errorf("%w: admins cannot be suspended", domain.ErrInvalidInput)
+	}
+	var until *time.Time
+	if duration > 0 {
+		end := now.Add(duration)
+		until = &end
+	}
+	if err := u.userRepository.SetSuspension(ctx, user.ID, true, until); err != nil {
+		return nil, err
+	}
+	if err := u.refreshTokenRepository.DeleteAllTokensForUser(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	return until, nil
+}
+
+func reportPageParams(page, limit int) (int, int, error) {
+	if limit == 0 {
+		limit = defaultReportLimit
+	}
+	if limit < 1 || limit > maxReportLimit {
+		return 0, 0, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidInput, maxReportLimit)
+	}
+	if page == 0 {
+		page = 1
+	}
+	if page < 1 {
+		return 0, 0, fmt.Errorf("%w: page must be at least 1", domain.ErrInvalidInput)
+	}
+	return page, limit, nil
+}
@@ -0,0 +1,167 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// reportDesk keeps reports in memory. With staleReads set, FindByID keeps
+// returning reports as they were filed, as when another moderator closes a
+// report between the read and the write.
+type reportDesk struct {
+	domain.IReportRepository
+	reports    map[string]domain.Report
+	filed      map[string]domain.Report
+	staleReads bool
+}
+
+func newReportDesk(reports ...domain.Report) *reportDesk {
+	d := &reportDesk{reports: map[string]domain.Report{}, filed: map[string]domain.Report{}}
+	for _, report := range reports {
+		d.reports[report.ID] = report
+		d.filed[report.ID] = report
+	}
+	return d
+}
+
+func (d *reportDesk) FindByID(_ context.Context, reportID string) (*domain.Report, error) {
+	reports := d.reports
+	if d.staleReads {
+		reports = d.filed
+	}
+	report, ok := reports[reportID]
+	if !ok {
+		return nil, domain.ErrReportNotFound
+	}
+	return &report, nil
+}
+
+func (d *reportDesk) Close(_ context.Context, reportID string, status domain.ReportStatus, resolution domain.ModerationActionType,
+	resolvedBy string, resolvedAt time.Time) (bool, error) {
+	report, ok := d.reports[reportID]
+	if !ok || report.Status != domain.ReportStatusOpen {
+		return false, nil
+	}
+	report.Status, report.Resolution, report.ResolvedBy, report.ResolvedAt = status, resolution, resolvedBy, &resolvedAt
+	d.reports[reportID] = report
+	return true, nil
+}
+
+func (d *reportDesk) Reopen(_ context.Context, reportID string) error {
+	report := d.reports[reportID]
+	report.Status, report.Resolution, report.ResolvedBy, report.ResolvedAt = domain.ReportStatusOpen, "", "", nil
+	d.reports[reportID] = report
+	return nil
+}
+
+func (d *reportDesk) CloseTarget(context.Context, domain.ReportTargetType, string, domain.ReportStatus,
+	domain.ModerationActionType, string, time.Time) (int64, error) {
+	return 0, nil
+}
+
+type moderationLog struct {
+	domain.IModerationActionRepository
+	actions []domain.ModerationAction
+}
+
+func (l *moderationLog) Create(_ context.Context, action *domain.ModerationAction) error {
+	l.actions = append(l.actions, *action)
+	return nil
+}
+
+func TestResolveReport(t *testing.T) {
+	spam := domain.Report{ID: "r1", TargetType: domain.ReportTargetBlog, TargetID: "b7", TargetUserID: "mallory",
+		ReporterID: "bob", Reason: "spam", Status: domain.ReportStatusOpen}
+	tests := []struct {
+		name        string
+		input       domain.ResolveReportInput
+		staleReads  bool
+		busFailures int
+		resolves    int
+		wantErr     error
+		wantStatus  domain.ReportStatus
+		wantActions int
+		wantWarning bool
+	}{
+		{
+			name:        "warning tells the author",
+			input:       domain.ResolveReportInput{Action: domain.ModerationWarn, Note: " keep it on topic "},
+			resolves:    1,
+			wantStatus:  domain.ReportStatusResolved,
+			wantActions: 1,
+			wantWarning: true,
+		},
+		{
+			name:        "dismissal is quiet",
+			input:       domain.ResolveReportInput{Action: domain.ModerationDismiss},
+			resolves:    1,
+			wantStatus:  domain.ReportStatusDismissed,
+			wantActions: 1,
+		},
+		{
+			name:        "a second moderator cannot act on a closed report",
+			input:       domain.ResolveReportInput{Action: domain.ModerationWarn},
+			staleReads:  true,
+			resolves:    2,
+			wantErr:     domain.ErrInvalidInput,
+			wantStatus:  domain.ReportStatusResolved,
+			wantActions: 1,
+			wantWarning: true,
+		},
+		{
+			name:        "a failed warning reopens the report",
+			input:       domain.ResolveReportInput{Action: domain.ModerationWarn},
+			busFailures: 1,
+			resolves:    1,
+			wantErr:     errors.New("outbox unavailable"),
+			wantStatus:  domain.ReportStatusOpen,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			reports := newReportDesk(spam)
+			reports.staleReads = tt.staleReads
+			actions := &moderationLog{}
+			events := &flakyEventBus{failures: tt.busFailures}
+			u := NewReportUsecase(reports, actions, nil, nil, nil, nil, events)
+
+			var err error
+			for i := 0; i < tt.resolves; i++ {
+				_, err = u.ResolveReport(ctx, "r1", tt.input, domain.Viewer{UserID: "admin", Role: domain.RoleAdmin})
+			}
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("ResolveReport: %v", err)
+			case tt.wantErr != nil && err == nil:
+				t.Fatalf("ResolveReport succeeded, want %v", tt.wantErr)
+			case errors.Is(tt.wantErr, domain.ErrInvalidInput) && !errors.Is(err, domain.ErrInvalidInput):
+				t.Fatalf("ResolveReport: %v, want %v", err, tt.wantErr)
+			}
+
+			if got := reports.reports["r1"].Status; got != tt.wantStatus {
+				t.Errorf("status = %q, want %q", got, tt.wantStatus)
+			}
+			if len(actions.actions) != tt.wantActions {
+				t.Errorf("recorded %d actions, want %d", len(actions.actions), tt.wantActions)
+			}
+			if !tt.wantWarning {
+				if len(events.published) != 0 {
+					t.Errorf("published %v, want nothing", events.published)
+				}
+				return
+			}
+			if len(events.published) != 1 {
+				t.Fatalf("published %d events, want one warning", len(events.published))
+			}
+			warning := events.published[0]
+			if warning.Type != domain.EventUserWarned || warning.UserID != "mallory" || warning.BlogID != "b7" ||
+				warning.Data["report_id"] != "r1" || warning.Data["note"] != actions.actions[0].Note {
+				t.Errorf("warning = %+v", warning)
+			}
+		})
+	}
+}
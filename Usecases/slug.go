@@ -0,0 +1,200 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const maxSlugLength = 80
+
+// transliterations covers letters that do not decompose into an ASCII base
+// letter plus combining marks under NFKD.
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d", 'ł': "l",
+	'þ': "th", 'ı': "i", 'ħ': "h", 'ŋ': "ng",
+
+	// Cyrillic
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya", 'є': "ye",
+	'і': "i", 'ї': "yi", 'ґ': "g",
+
+	// Greek
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i",
+	'θ': "th", 'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x",
+	'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y",
+	'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+}
+
+// slugify turns a title into a lowercase, hyphen-separated ASCII slug.
+// Accented letters lose their accents and common non-Latin letters are
+// transliterated; anything else is treated as a separator.
+func slugify(title string) string {
+	var b strings.Builder
+	pendingHyphen := false
+
+	write := func(s string) {
+		if s == "" {
+			return
+		}
+		if pendingHyphen && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		pendingHyphen = false
+		b.WriteString(s)
+	}
+
+	for _, r := range norm.NFKD.String(strings.ToLower(title)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// combining accent left over from decomposition
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			write(string(r))
+		case r == '\'' || r == '’':
+			// "don't" -> "dont" rather than "don-t"
+		default:
+			if t, ok := transliterations[r]; ok {
+				write(t)
+			} else {
+				pendingHyphen = true
+			}
+		}
+	}
+
+	slug := b.String()
+	if len(slug) > maxSlugLength {
+		slug = slug[:maxSlugLength]
+		if i := strings.LastIndexByte(slug, '-'); i > maxSlugLength/2 {
+			slug = slug[:i]
+		}
+		slug = strings.TrimRight(slug, "-")
+	}
+	if slug == "" {
+		slug = "post"
+	}
+	return slug
+}
+
+const (
+	// numberedSlugProbes is how many of base, base-2, base-3, ... are tried
+	// before falling back to a random suffix, so a crowded base such as
+	// "post" costs a bounded number of lookups.
+	numberedSlugProbes = 4
+	maxSlugAttempts    = numberedSlugProbes + 4
+	// slugBackfillBatch is how many blogs BackfillSlugs loads at a time.
+	slugBackfillBatch = 100
+)
+
+// uniqueSlug returns the slug for title, suffixed with -2, -3, ... when the
+// plain slug already belongs to another blog, and with a short random suffix
+// once the first few numbers are taken too.
+func (bu *BlogUsecase) uniqueSlug(ctx context.Context, title, blogID string) (string, error) {
+	base := slugify(title)
+	for n := 1; n <= maxSlugAttempts; n++ {
+		candidate := base
+		if n > 1 {
+			suffix := "-" + strconv.Itoa(n)
+			if n > numberedSlugProbes {
+				suffix = "-" + randomSlugSuffix()
+			}
+			candidate = strings.TrimRight(base[:min(len(base), maxSlugLength-len(suffix))], "-") + suffix
+		}
+
+		taken, err := bu.blogRepository.SlugTaken(ctx, candidate, blogID)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("failed to find a free slug for %q", title)
+}
+
+func randomSlugSuffix() string {
+	b := make([]byte, 4)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// reslug gives blog a slug for its current title, keeping the slug it had
+// before in OldSlugs so existing links redirect to the new one.
+func (bu *BlogUsecase) reslug(ctx context.Context, blog *domain.Blog) error {
+	slug, err := bu.uniqueSlug(ctx, blog.Title, blog.ID)
+	if err != nil {
+		return err
+	}
+	if slug == blog.Slug {
+		return nil
+	}
+
+	oldSlugs := make([]string, 0, len(blog.OldSlugs)+1)
+	for _, s := range blog.OldSlugs {
+		if s != slug {
+			oldSlugs = append(oldSlugs, s)
+		}
+	}
+	if blog.Slug != "" {
+		oldSlugs = append(oldSlugs, blog.Slug)
+	}
+
+	blog.Slug = slug
+	blog.OldSlugs = oldSlugs
+	return nil
+}
+
+// BackfillSlugs gives a slug to every blog stored before blogs had one.
+// Blogs are taken oldest first, so when titles collide the oldest blog gets
+// the plain slug.
+func BackfillSlugs(ctx context.Context, blogRepo domain.IBlogRepository) error {
+	bu := &BlogUsecase{blogRepository: blogRepo}
+	afterID, filled := "", 0
+	for {
+		blogs, err := blogRepo.FindWithoutSlug(ctx, afterID, slugBackfillBatch)
+		if err != nil {
+			return err
+		}
+		for _, blog := range blogs {
+			afterID = blog.ID
+			set, err := bu.backfillSlug(ctx, &blog)
+			if err != nil {
+				return fmt.Errorf("failed to give blog %s a slug: %w", blog.ID, err)
+			}
+			if set {
+				filled++
+			}
+		}
+		if len(blogs) < slugBackfillBatch {
+			log.Printf("gave %d blogs a slug", filled)
+			return nil
+		}
+	}
+}
+
+// backfillSlug gives blog its first slug, picking another when a concurrent
+// save took the same one. It tells whether the blog still needed one.
+func (bu *BlogUsecase) backfillSlug(ctx context.Context, blog *domain.Blog) (bool, error) {
+	for attempt := 1; ; attempt++ {
+		slug, err := bu.uniqueSlug(ctx, blog.Title, blog.ID)
+		if err != nil {
+			return false, err
+		}
+		set, err := bu.blogRepository.SetSlug(ctx, blog.ID, slug)
+		if errors.Is(err, domain.ErrSlugTaken) && attempt < maxSlugAttempts {
+			continue
+		}
+		return set, err
+	}
+}
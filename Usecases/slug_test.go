@@ -0,0 +1,216 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Hello World", "hello-world"},
+		{"  Leading and trailing  ", "leading-and-trailing"},
+		{"Don't Panic!", "dont-panic"},
+		{"Crème brûlée", "creme-brulee"},
+		{"Straße", "strasse"},
+		{"Привет мир", "privet-mir"},
+		{"Γεια σου", "geia-soy"},
+		{"Go 1.24 -- released", "go-1-24-released"},
+		{"日本語", "post"},
+		{"", "post"},
+		{"!!!", "post"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			if got := slugify(tt.title); got != tt.want {
+				t.Errorf("slugify(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlugifyTruncatesAtWordBoundary(t *testing.T) {
+	title := strings.Repeat("word ", 40)
+	got := slugify(title)
+	if len(got) > maxSlugLength {
+		t.Fatalf("len(slugify) = %d, want at most %d", len(got), maxSlugLength)
+	}
+	if strings.HasSuffix(got, "-") || !strings.HasSuffix(got, "word") {
+		t.Errorf("slugify cut mid-word or left a trailing hyphen: %q", got)
+	}
+}
+
+// slugRepository reports every slug in taken as belonging to another blog.
+type slugRepository struct {
+	domain.IBlogRepository
+	taken  map[string]bool
+	probes int
+}
+
+func (r *slugRepository) SlugTaken(_ context.Context, slug, _ string) (bool, error) {
+	r.probes++
+	return r.taken[slug], nil
+}
+
+func TestUniqueSlug(t *testing.T) {
+	tests := []struct {
+		name  string
+		taken []string
+		want  string
+	}{
+		{"free", nil, "post"},
+		{"numbered", []string{"post", "post-2"}, "post-3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &slugRepository{taken: map[string]bool{}}
+			for _, s := range tt.taken {
+				repo.taken[s] = true
+			}
+			bu := &BlogUsecase{blogRepository: repo}
+			got, err := bu.uniqueSlug(context.Background(), "!!!", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("uniqueSlug = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUniqueSlugFallsBackToRandomSuffix(t *testing.T) {
+	repo := &slugRepository{taken: map[string]bool{"post": true}}
+	for n := 2; n <= 1000; n++ {
+		repo.taken["post-"+strconv.Itoa(n)] = true
+	}
+	bu := &BlogUsecase{blogRepository: repo}
+
+	got, err := bu.uniqueSlug(context.Background(), "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^post-[0-9a-f]{8}$`).MatchString(got) {
+		t.Errorf("uniqueSlug = %q, want post-<random hex>", got)
+	}
+	if repo.probes != numberedSlugProbes+1 {
+		t.Errorf("probed %d slugs, want %d", repo.probes, numberedSlugProbes+1)
+	}
+}
+
+// legacyArchive holds blogs in ID order, some of them from before slugs.
+// sneakIn maps a slug to a blog that takes it just before the backfill
+// stores it, as a concurrent save would.
+type legacyArchive struct {
+	domain.IBlogRepository
+	blogs   []domain.Blog
+	sneakIn map[string]string
+	pages   int
+}
+
+func (a *legacyArchive) SlugTaken(_ context.Context, slug, excludeBlogID string) (bool, error) {
+	for _, blog := range a.blogs {
+		if blog.ID != excludeBlogID && blog.Slug == slug {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (a *legacyArchive) FindWithoutSlug(_ context.Context, afterID string, limit int) ([]domain.Blog, error) {
+	a.pages++
+	var found []domain.Blog
+	for _, blog := range a.blogs {
+		if blog.Slug == "" && blog.ID > afterID && len(found) < limit {
+			found = append(found, blog)
+		}
+	}
+	return found, nil
+}
+
+func (a *legacyArchive) SetSlug(ctx context.Context, blogID, slug string) (bool, error) {
+	if owner, ok := a.sneakIn[slug]; ok {
+		delete(a.sneakIn, slug)
+		a.blog(owner).Slug = slug
+	}
+	if taken, _ := a.SlugTaken(ctx, slug, blogID); taken {
+		return false, domain.ErrSlugTaken
+	}
+	blog := a.blog(blogID)
+	if blog.Slug != "" {
+		return false, nil
+	}
+	blog.Slug = slug
+	return true, nil
+}
+
+func (a *legacyArchive) blog(id string) *domain.Blog {
+	for i := range a.blogs {
+		if a.blogs[i].ID == id {
+			return &a.blogs[i]
+		}
+	}
+	return nil
+}
+
+func TestBackfillSlugs(t *testing.T) {
+	archive := &legacyArchive{
+		blogs: []domain.Blog{
+			{ID: "b01", Title: "Hello, World!"},
+			{ID: "b02", Title: "Release notes"},
+			{ID: "b03", Title: "Hello World"},
+			{ID: "b04", Title: "Straße"},
+			{ID: "b05", Title: "Hello world, again", Slug: "hello-world-again"},
+			{ID: "b06", Title: "Drafted elsewhere"},
+			{ID: "b07", Title: "Hello World"},
+		},
+		// b05 is renamed and takes release-notes under the backfill's nose; b06
+		// is edited and so gets a slug of its own.
+		sneakIn: map[string]string{"release-notes": "b05", "drafted-elsewhere": "b06"},
+	}
+	if err := BackfillSlugs(context.Background(), archive); err != nil {
+		t.Fatalf("BackfillSlugs: %v", err)
+	}
+
+	want := map[string]string{
+		"b01": "hello-world",
+		"b02": "release-notes-2",
+		"b03": "hello-world-2",
+		"b04": "strasse",
+		"b05": "release-notes",
+		"b06": "drafted-elsewhere",
+		"b07": "hello-world-3",
+	}
+	for _, blog := range archive.blogs {
+		if blog.Slug != want[blog.ID] {
+			t.Errorf("blog %s (%q) got slug %q, want %q", blog.ID, blog.Title, blog.Slug, want[blog.ID])
+		}
+	}
+}
+
+func TestBackfillSlugsPages(t *testing.T) {
+	archive := &legacyArchive{}
+	for i := 0; i < 2*slugBackfillBatch+17; i++ {
+		archive.blogs = append(archive.blogs, domain.Blog{ID: "b" + strconv.Itoa(1000+i), Title: "Week " + strconv.Itoa(i%40)})
+	}
+	if err := BackfillSlugs(context.Background(), archive); err != nil {
+		t.Fatalf("BackfillSlugs: %v", err)
+	}
+
+	seen := map[string]bool{}
+	for _, blog := range archive.blogs {
+		if blog.Slug == "" || seen[blog.Slug] {
+			t.Fatalf("blog %s got slug %q, want a unique one", blog.ID, blog.Slug)
+		}
+		seen[blog.Slug] = true
+	}
+	if archive.pages != 3 {
+		t.Errorf("read %d pages, want 3", archive.pages)
+	}
+}
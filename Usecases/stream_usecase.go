@@ -0,0 +1,136 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"fmt"
+	"log"
+	"slices"
+)
+
+// maxStreamBlogs caps how many blogs one client can follow.
+const maxStreamBlogs = 50
+
+// streamReactions is the data of a reactions.changed event.
+type streamReactions struct {
+	BlogID    string           `json:"blog_id"`
+	CommentID string           `json:"comment_id,omitempty"`
+	Counts    map[string]int64 `json:"counts"`
+	Total     int64            `json:"total"`
+}
+
+// streamNotification is the data of a notification event; clients fetch
+// the notification itself from the notifications endpoints.
+type streamNotification struct {
+	NotificationID string `json:"notification_id"`
+	Type           string `json:"type"`
+	Unread         int64  `json:"unread"`
+}
+
+type StreamUsecase struct {
+	broker                 domain.IStreamBroker
+	hub                    domain.IStreamHub
+	blogRepository         domain.IBlogRepository
+	commentRepository      domain.ICommentRepository
+	reactionRepository     domain.IReactionRepository
+	notificationRepository domain.INotificationRepository
+}
+
+func NewStreamUsecase(broker domain.IStreamBroker, hub domain.IStreamHub, blogRepo domain.IBlogRepository, commentRepo domain.ICommentRepository,
+	reactionRepo domain.IReactionRepository, notificationRepo domain.INotificationRepository) domain.IStreamUsecase {
+	return &StreamUsecase{
+		broker:                 broker,
+		hub:                    hub,
+		blogRepository:         blogRepo,
+		commentRepository:      commentRepo,
+		reactionRepository:     reactionRepo,
+		notificationRepository: notificationRepo,
+	}
+}
+
+// HandleEvent publishes new comments and reaction counts to the blog's
+// topic and notifications to the recipient's topic. The stream event takes
+// the ID of the domain event, so every instance replays it under the same
+// ID.
+func (u *StreamUsecase) HandleEvent(ctx context.Context, event domain.Event) error {
+	if err := u.stream(ctx, event); err != nil {
+		log.Printf("warning: failed to stream %s %s: %v", event.Type, event.ID, err)
+	}
+	return nil
+}
+
+func (u *StreamUsecase) stream(ctx context.Context, event domain.Event) error {
+	switch event.Type {
+	case domain.EventCommentCreated:
+		comment, err := u.commentRepository.FindByID(ctx, event.CommentID)
+		if err != nil {
+			return err
+		}
+		return u.publish(ctx, event, domain.BlogTopic(event.BlogID), domain.StreamCommentCreated, publicComment(comment))
+
+	case domain.EventReactionAdded, domain.EventReactionRemoved:
+		targetType, targetID := domain.ReactionTargetBlog, event.BlogID
+		if event.CommentID != "" {
+			targetType, targetID = domain.ReactionTargetComment, event.CommentID
+		}
+		counts, err := u.reactionRepository.Counts(ctx, targetType, []string{targetID})
+		if err != nil {
+			return err
+		}
+		data := streamReactions{BlogID: event.BlogID, CommentID: event.CommentID, Counts: counts[targetID]}
+		if data.Counts == nil {
+			data.Counts = map[string]int64{}
+		}
+		for _, n := range data.Counts {
+			data.Total += n
+		}
+		return u.publish(ctx, event, domain.BlogTopic(event.BlogID), domain.StreamReactionsChanged, data)
+
+	case domain.EventNotificationAdded:
+		unread, err := u.notificationRepository.CountUnread(ctx, event.UserID)
+		if err != nil {
+			return err
+		}
+		return u.publish(ctx, event, domain.UserTopic(event.UserID), domain.StreamNotification, streamNotification{
+			NotificationID: event.Data["notification_id"],
+			Type:           event.Data["type"],
+			Unread:         unread,
+		})
+	}
+	return nil
+}
+
+func (u *StreamUsecase) publish(ctx context.Context, event domain.Event, topic, streamType string, data any) error {
+	return u.broker.Publish(ctx, domain.StreamEvent{
+		ID:    event.ID,
+		Topic: topic,
+		Type:  streamType,
+		Data:  data,
+		At:    event.OccurredAt,
+	})
+}
+
+func (u *StreamUsecase) Subscribe(ctx context.Context, blogIDs []string, lastEventID string, actor domain.Viewer) (*domain.StreamSubscription, error) {
+	if actor.UserID == "" {
+		return nil, domain.ErrForbidden
+	}
+	slices.Sort(blogIDs)
+	blogIDs = slices.Compact(blogIDs)
+	if len(blogIDs) > maxStreamBlogs {
+		return nil, fmt.Errorf("%w: at most %d blogs can be followed at once", domain.ErrInvalidInput, maxStreamBlogs)
+	}
+
+	blogs, err := u.blogRepository.FindByIDs(ctx, blogIDs)
+	if err != nil {
+		return nil, err
+	}
+	topics := []string{domain.UserTopic(actor.UserID)}
+	for _, id := range blogIDs {
+		blog, ok := blogs[id]
+		if !ok || !actor.CanSee(&blog) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrBlogNotFound, id)
+		}
+		topics = append(topics, domain.BlogTopic(id))
+	}
+	return u.hub.Subscribe(topics, lastEventID), nil
+}
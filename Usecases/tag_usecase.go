@@ -0,0 +1,368 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxTagDescriptionLength = 500
+
+type TagUsecase struct {
+	tagRepository    domain.ITagRepository
+	blogRepository   domain.IBlogRepository
+	followRepository domain.IFollowRepository
+}
+
+func NewTagUsecase(tagRepo domain.ITagRepository, blogRepo domain.IBlogRepository, followRepo domain.IFollowRepository) domain.ITagUsecase {
+	return &TagUsecase{
+		tagRepository:    tagRepo,
+		blogRepository:   blogRepo,
+		followRepository: followRepo,
+	}
+}
+
+// ListTags returns every tag in use or known, most used first. Blogs written
+// before tags were normalized are counted under the tag they normalize to.
+func (tu *TagUsecase) ListTags(ctx context.Context) ([]domain.Tag, error) {
+	tags, err := tu.tagRepository.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := tu.blogRepository.CountTags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tags: %w", err)
+	}
+
+	byName := make(map[string]int, len(tags))
+	for i, tag := range tags {
+		byName[tag.Name] = i
+		for _, alias := range tag.Aliases {
+			byName[alias] = i
+		}
+	}
+	for stored, count := range counts {
+		name := domain.NormalizeTag(stored)
+		if name == "" {
+			continue
+		}
+		i, ok := byName[name]
+		if !ok {
+			tags = append(tags, domain.Tag{Name: name, Aliases: []string{}})
+			i = len(tags) - 1
+			byName[name] = i
+		}
+		tags[i].PostCount += count
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		if tags[i].PostCount != tags[j].PostCount {
+			return tags[i].PostCount > tags[j].PostCount
+		}
+		return tags[i].Name < tags[j].Name
+	})
+	return tags, nil
+}
+
+// GetTag looks a tag up by its name or any alias. Tags that only exist on
+// blogs written before tags were managed are found as long as they are used.
+func (tu *TagUsecase) GetTag(ctx context.Context, name string) (*domain.Tag, error) {
+	tag, err := tu.find(ctx, name)
+	unmanaged := errors.Is(err, domain.ErrTagNotFound)
+	if unmanaged {
+		tag, err = &domain.Tag{Name: domain.NormalizeTag(name), Aliases: []string{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := tu.blogRepository.FindBlogs(ctx, domain.BlogQuery{
+		Tags:        []string{tag.Name},
+		TagMatch:    domain.TagMatchAny,
+		TagVariants: [][]string{append([]string{tag.Name}, tag.Aliases...)},
+		Status:      domain.BlogStatusPublished,
+		Sort:        domain.BlogSortRecent,
+		Page:        1,
+		Limit:       1,
+	}, domain.Viewer{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tag posts: %w", err)
+	}
+	if unmanaged && result.Total == 0 {
+		return nil, domain.ErrTagNotFound
+	}
+	tag.PostCount = int(result.Total)
+	return tag, nil
+}
+
+func (tu *TagUsecase) UpdateTag(ctx context.Context, name string, input domain.UpdateTagInput, actor domain.Viewer) (*domain.Tag, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, domain.ErrForbidden
+	}
+	tag, err := tu.find(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Description != nil {
+		description := strings.TrimSpace(*input.Description)
+		if utf8.RuneCountInString(description) > maxTagDescriptionLength {
+			return nil, fmt.Errorf("%w: description must be at most %d characters", domain.ErrInvalidInput, maxTagDescriptionLength)
+		}
+		tag.Description = description
+	}
+
+	oldName := tag.Name
+	if input.Name != nil {
+		newName, err := validTagName(*input.Name)
+		if err != nil {
+			return nil, err
+		}
+		if newName != oldName {
+			if other, err := tu.tagRepository.FindByName(ctx, newName); err == nil && other.ID != tag.ID {
+				return nil, fmt.Errorf("%w: %q already belongs to tag %q; merge the tags instead", domain.ErrTagExists, newName, other.Name)
+			} else if err != nil && !errors.Is(err, domain.ErrTagNotFound) {
+				return nil, err
+			}
+			// Renaming to one of its own aliases swaps the two.
+			tag.Aliases = append(withoutTag(tag.Aliases, newName), oldName)
+			tag.Name = newName
+		}
+	}
+
+	if err := tu.tagRepository.Update(ctx, tag); err != nil {
+		return nil, err
+	}
+	if tag.Name != oldName {
+		if _, err := tu.blogRepository.ReplaceTags(ctx, []string{oldName}, tag.Name); err != nil {
+			return nil, fmt.Errorf("failed to rename tag on blogs: %w", err)
+		}
+	}
+	return tag, nil
+}
+
+// MergeTag folds source into target: every blog tagged source is retagged
+// target, and source with its aliases become aliases of target.
+func (tu *TagUsecase) MergeTag(ctx context.Context, source, target string, actor domain.Viewer) (*domain.Tag, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, domain.ErrForbidden
+	}
+	from, err := tu.find(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	into, err := tu.find(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if from.ID == into.ID {
+		return nil, fmt.Errorf("%w: cannot merge a tag into itself", domain.ErrInvalidInput)
+	}
+
+	names := append([]string{from.Name}, from.Aliases...)
+	for _, name := range names {
+		if !containsTag(into.Aliases, name) {
+			into.Aliases = append(into.Aliases, name)
+		}
+	}
+	if into.Description == "" {
+		into.Description = from.Description
+	}
+
+	if err := tu.tagRepository.Update(ctx, into); err != nil {
+		return nil, err
+	}
+	if err := tu.tagRepository.Delete(ctx, from.ID); err != nil {
+		return nil, err
+	}
+	if _, err := tu.blogRepository.ReplaceTags(ctx, names, into.Name); err != nil {
+		return nil, fmt.Errorf("failed to retag blogs: %w", err)
+	}
+	return into, nil
+}
+
+// DeleteTag removes a tag and its aliases from every blog and drops the
+// follows of the tag. Blogs left without tags can still be edited.
+func (tu *TagUsecase) DeleteTag(ctx context.Context, name string, actor domain.Viewer) error {
+	if actor.Role != domain.RoleAdmin {
+		return domain.ErrForbidden
+	}
+	tag, err := tu.find(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	// The tag itself goes last, so a deletion that fails halfway can be
+	// repeated.
+	names := append([]string{tag.Name}, tag.Aliases...)
+	if _, err := tu.blogRepository.RemoveTags(ctx, names); err != nil {
+		return fmt.Errorf("failed to remove tag from blogs: %w", err)
+	}
+	if _, err := tu.followRepository.RemoveTagFollows(ctx, names); err != nil {
+		return err
+	}
+	return tu.tagRepository.Delete(ctx, tag.ID)
+}
+
+// AddAlias makes alias another spelling of a tag. Blogs already using the
+// alias are retagged with the tag's name.
+func (tu *TagUsecase) AddAlias(ctx context.Context, name, alias string, actor domain.Viewer) (*domain.Tag, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, domain.ErrForbidden
+	}
+	tag, err := tu.find(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	alias, err = validTagName(alias)
+	if err != nil {
+		return nil, err
+	}
+	if alias == tag.Name || containsTag(tag.Aliases, alias) {
+		return tag, nil
+	}
+
+	if other, err := tu.tagRepository.FindByName(ctx, alias); err == nil {
+		return nil, fmt.Errorf("%w: %q already belongs to tag %q; merge the tags instead", domain.ErrTagExists, alias, other.Name)
+	} else if !errors.Is(err, domain.ErrTagNotFound) {
+		return nil, err
+	}
+
+	tag.Aliases = append(tag.Aliases, alias)
+	if err := tu.tagRepository.Update(ctx, tag); err != nil {
+		return nil, err
+	}
+	if _, err := tu.blogRepository.ReplaceTags(ctx, []string{alias}, tag.Name); err != nil {
+		return nil, fmt.Errorf("failed to retag blogs: %w", err)
+	}
+	return tag, nil
+}
+
+func (tu *TagUsecase) RemoveAlias(ctx context.Context, name, alias string, actor domain.Viewer) (*domain.Tag, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, domain.ErrForbidden
+	}
+	tag, err := tu.find(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	alias = domain.NormalizeTag(alias)
+	if !containsTag(tag.Aliases, alias) {
+		return nil, fmt.Errorf("%w: %q is not an alias of %q", domain.ErrTagNotFound, alias, tag.Name)
+	}
+
+	tag.Aliases = withoutTag(tag.Aliases, alias)
+	if err := tu.tagRepository.Update(ctx, tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+func (tu *TagUsecase) find(ctx context.Context, name string) (*domain.Tag, error) {
+	name = domain.NormalizeTag(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: tag name is required", domain.ErrInvalidInput)
+	}
+	return tu.tagRepository.FindByName(ctx, name)
+}
+
+func validTagName(raw string) (string, error) {
+	name := domain.NormalizeTag(raw)
+	if name == "" {
+		return "", fmt.Errorf("%w: tag name is required", domain.ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(name) > domain.MaxTagLength {
+		return "", fmt.Errorf("%w: tag %q is longer than %d characters", domain.ErrInvalidInput, name, domain.MaxTagLength)
+	}
+	return name, nil
+}
+
+// canonicalTags normalizes raw tags, replaces aliases with the name of
+// their tag and drops duplicates. Tags seen for the first time are created.
+func canonicalTags(ctx context.Context, tagRepo domain.ITagRepository, raw []string) ([]string, error) {
+	names := make([]string, 0, len(raw))
+	for _, r := range raw {
+		if strings.TrimSpace(r) == "" {
+			continue
+		}
+		name, err := validTagName(r)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+
+	known, err := tagRepo.FindByNames(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]string, 0, len(names))
+	var unknown []string
+	for _, name := range names {
+		if tag, ok := known[name]; ok {
+			name = tag.Name
+		} else if !containsTag(unknown, name) {
+			unknown = append(unknown, name)
+		}
+		if !containsTag(tags, name) {
+			tags = append(tags, name)
+		}
+	}
+
+	if err := tagRepo.EnsureExists(ctx, unknown); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// tagVariants resolves each of tags to its canonical name and lists every
+// spelling a blog may have stored it under: the name, its aliases and, for
+// blogs predating normalization, the tag exactly as given.
+func tagVariants(ctx context.Context, tagRepo domain.ITagRepository, tags []string) ([]string, [][]string, error) {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = domain.NormalizeTag(tag)
+	}
+	known, err := tagRepo.FindByNames(ctx, names)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	canonical := make([]string, len(tags))
+	variants := make([][]string, len(tags))
+	for i, name := range names {
+		spellings := []string{name}
+		if tag, ok := known[name]; ok {
+			name = tag.Name
+			spellings = append([]string{tag.Name}, tag.Aliases...)
+		}
+		if !containsTag(spellings, tags[i]) {
+			spellings = append(spellings, tags[i])
+		}
+		canonical[i], variants[i] = name, spellings
+	}
+	return canonical, variants, nil
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func withoutTag(tags []string, tag string) []string {
+	kept := make([]string, 0, len(tags))
+	for _, t := range tags {
+		if t != tag {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,234 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+)
+
+// tagRegistry keeps tags by ID and finds them by name or alias.
+type tagRegistry struct {
+	domain.ITagRepository
+	tags map[string]*domain.Tag
+}
+
+func newTagRegistry(tags ...domain.Tag) *tagRegistry {
+	r := &tagRegistry{tags: map[string]*domain.Tag{}}
+	for _, tag := range tags {
+		r.tags[tag.ID] = &tag
+	}
+	return r
+}
+
+func (r *tagRegistry) FindByName(_ context.Context, name string) (*domain.Tag, error) {
+	for _, tag := range r.tags {
+		if tag.Name == name || slices.Contains(tag.Aliases, name) {
+			found := *tag
+			found.Aliases = slices.Clone(tag.Aliases)
+			return &found, nil
+		}
+	}
+	return nil, domain.ErrTagNotFound
+}
+
+func (r *tagRegistry) Update(_ context.Context, tag *domain.Tag) error {
+	updated := *tag
+	r.tags[tag.ID] = &updated
+	return nil
+}
+
+func (r *tagRegistry) Delete(_ context.Context, tagID string) error {
+	delete(r.tags, tagID)
+	return nil
+}
+
+// taggedBlogs keeps the tags of each blog and retags them like the Mongo
+// repository.
+type taggedBlogs struct {
+	domain.IBlogRepository
+	tags map[string][]string // by blog ID
+}
+
+func (r *taggedBlogs) ReplaceTags(_ context.Context, from []string, to string) (int64, error) {
+	var changed int64
+	for id, tags := range r.tags {
+		kept := slices.DeleteFunc(slices.Clone(tags), func(tag string) bool { return slices.Contains(from, tag) })
+		if len(kept) == len(tags) {
+			continue
+		}
+		if !slices.Contains(kept, to) {
+			kept = append(kept, to)
+		}
+		r.tags[id] = kept
+		changed++
+	}
+	return changed, nil
+}
+
+func (r *taggedBlogs) RemoveTags(_ context.Context, names []string) (int64, error) {
+	var changed int64
+	for id, tags := range r.tags {
+		kept := slices.DeleteFunc(slices.Clone(tags), func(tag string) bool { return slices.Contains(names, tag) })
+		if len(kept) != len(tags) {
+			r.tags[id] = kept
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// tagFollows keeps the followers of each tag.
+type tagFollows struct {
+	domain.IFollowRepository
+	followers map[string][]string // by tag
+}
+
+func (r *tagFollows) RemoveTagFollows(_ context.Context, tags []string) (int64, error) {
+	var removed int64
+	for _, tag := range tags {
+		removed += int64(len(r.followers[tag]))
+		delete(r.followers, tag)
+	}
+	return removed, nil
+}
+
+func TestTagAdministration(t *testing.T) {
+	golang := domain.Tag{ID: "t1", Name: "golang", Aliases: []string{"go-lang"}}
+	goTag := domain.Tag{ID: "t2", Name: "go", Aliases: []string{}, Description: "The Go language"}
+	rust := domain.Tag{ID: "t3", Name: "rust", Aliases: []string{}}
+
+	tests := []struct {
+		name string
+		run  func(u domain.ITagUsecase) error
+		// wantErr is checked with errors.Is; the state is only checked
+		// when it is nil.
+		wantErr       error
+		wantTags      map[string][]string // name -> aliases
+		wantBlogTags  map[string][]string
+		wantFollowers map[string][]string
+	}{
+		{
+			name: "merge folds the source and its aliases into the target",
+			run: func(u domain.ITagUsecase) error {
+				_, err := u.MergeTag(context.Background(), "golang", "go", testAdmin)
+				return err
+			},
+			wantTags:      map[string][]string{"go": {"golang", "go-lang"}, "rust": {}},
+			wantBlogTags:  map[string][]string{"b1": {"go"}, "b2": {"go", "rust"}, "b3": {"rust", "rust-lang"}},
+			wantFollowers: map[string][]string{"golang": {"alice"}, "rust": {"bob"}},
+		},
+		{
+			name: "merging into itself",
+			run: func(u domain.ITagUsecase) error {
+				_, err := u.MergeTag(context.Background(), "go-lang", "golang", testAdmin)
+				return err
+			},
+			wantErr: domain.ErrInvalidInput,
+		},
+		{
+			name: "alias retags the blogs using it",
+			run: func(u domain.ITagUsecase) error {
+				_, err := u.AddAlias(context.Background(), "rust", " #Rust-Lang ", testAdmin)
+				return err
+			},
+			wantTags:      map[string][]string{"golang": {"go-lang"}, "go": {}, "rust": {"rust-lang"}},
+			wantBlogTags:  map[string][]string{"b1": {"golang"}, "b2": {"go", "rust"}, "b3": {"rust"}},
+			wantFollowers: map[string][]string{"golang": {"alice"}, "rust": {"bob"}},
+		},
+		{
+			name: "alias taken by another tag",
+			run: func(u domain.ITagUsecase) error {
+				_, err := u.AddAlias(context.Background(), "go", "go-lang", testAdmin)
+				return err
+			},
+			wantErr: domain.ErrTagExists,
+		},
+		{
+			name: "delete drops the tag from blogs and follows",
+			run: func(u domain.ITagUsecase) error {
+				return u.DeleteTag(context.Background(), "go-lang", testAdmin)
+			},
+			wantTags:      map[string][]string{"go": {}, "rust": {}},
+			wantBlogTags:  map[string][]string{"b1": {}, "b2": {"go", "rust"}, "b3": {"rust", "rust-lang"}},
+			wantFollowers: map[string][]string{"rust": {"bob"}},
+		},
+		{
+			name: "only admins delete",
+			run: func(u domain.ITagUsecase) error {
+				return u.DeleteTag(context.Background(), "rust", domain.Viewer{UserID: "bob", Role: domain.RoleUser})
+			},
+			wantErr: domain.ErrForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags := newTagRegistry(golang, goTag, rust)
+			blogs := &taggedBlogs{tags: map[string][]string{
+				"b1": {"golang"},
+				"b2": {"go", "rust"},
+				"b3": {"rust", "rust-lang"},
+			}}
+			follows := &tagFollows{followers: map[string][]string{"golang": {"alice"}, "rust": {"bob"}}}
+			u := NewTagUsecase(tags, blogs, follows)
+
+			err := tt.run(u)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			gotTags := map[string][]string{}
+			for _, tag := range tags.tags {
+				gotTags[tag.Name] = tag.Aliases
+			}
+			if !maps.EqualFunc(gotTags, tt.wantTags, slices.Equal) {
+				t.Errorf("tags = %v, want %v", gotTags, tt.wantTags)
+			}
+			if !maps.EqualFunc(blogs.tags, tt.wantBlogTags, slices.Equal) {
+				t.Errorf("blog tags = %v, want %v", blogs.tags, tt.wantBlogTags)
+			}
+			if !maps.EqualFunc(follows.followers, tt.wantFollowers, slices.Equal) {
+				t.Errorf("tag followers = %v, want %v", follows.followers, tt.wantFollowers)
+			}
+		})
+	}
+}
+
+func TestEditBlogWithoutTags(t *testing.T) {
+	untagged := domain.Blog{ID: "b1", UserID: "alice", Title: "Old", Content: "Body", Tags: []string{}}
+	tests := []struct {
+		name     string
+		previous domain.Blog
+		tags     []string
+		wantErr  error
+	}{
+		{name: "blog whose tags were deleted", previous: untagged, tags: []string{}},
+		{name: "dropping the last tag", previous: domain.Blog{ID: "b1", Title: "Old", Content: "Body", Tags: []string{"go"}},
+			tags: []string{}, wantErr: domain.ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blogs := &updateFailingBlogRepository{}
+			bu := &BlogUsecase{
+				blogRepository:     blogs,
+				revisionRepository: &revisionStore{revisions: map[string]domain.BlogRevision{}},
+				tagRepository:      knownTagRepository{},
+				contentRenderer:    sourceRenderer{},
+			}
+			blog := tt.previous
+			blog.Title, blog.Tags = "New", tt.tags
+
+			_, err := bu.saveWithRevision(context.Background(), &tt.previous, &blog, "alice", 0)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("saveWithRevision error = %v, want %v", err, tt.wantErr)
+			}
+			if saved := len(blogs.updates) == 1; saved != (tt.wantErr == nil) {
+				t.Errorf("saved = %v, want %v", saved, tt.wantErr == nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"log"
+	"time"
+)
+
+// How much one view, like and comment adds to a trending score before decay.
+var trendingWeights = domain.TrendingWeights{View: 1, Like: 4, Comment: 6}
+
+type TrendingUpdater struct {
+	blogRepository domain.IBlogRepository
+	interval       time.Duration
+}
+
+func NewTrendingUpdater(blogRepo domain.IBlogRepository, interval time.Duration) domain.ITrendingUpdater {
+	return &TrendingUpdater{
+		blogRepository: blogRepo,
+		interval:       interval,
+	}
+}
+
+// Run updates the scores right away and then every interval until ctx is
+// cancelled.
+func (t *TrendingUpdater) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := t.Update(ctx); err != nil {
+			log.Printf("trending updater: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Update rescores every blog. The decay runs in the database, so a burst
+// this morning outranks a bigger one at the start of the week without the
+// month of activity being loaded here.
+func (t *TrendingUpdater) Update(ctx context.Context) error {
+	return t.blogRepository.UpdateTrendingScores(ctx, time.Now(), trendingWeights)
+}
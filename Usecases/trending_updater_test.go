@@ -0,0 +1,63 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// scoreboard records each rescoring and fails the ones listed in failing.
+// It cancels the updater after rounds rescorings.
+type scoreboard struct {
+	domain.IBlogRepository
+	rounds  int
+	failing map[int]bool
+	nows    []time.Time
+	weights []domain.TrendingWeights
+	cancel  context.CancelFunc
+}
+
+func (s *scoreboard) UpdateTrendingScores(_ context.Context, now time.Time, weights domain.TrendingWeights) error {
+	s.nows = append(s.nows, now)
+	s.weights = append(s.weights, weights)
+	if len(s.nows) == s.rounds {
+		s.cancel()
+	}
+	if s.failing[len(s.nows)] {
+		return errors.New("exceeded memory limit")
+	}
+	return nil
+}
+
+func TestTrendingUpdaterRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	blogs := &scoreboard{rounds: 3, failing: map[int]bool{1: true}, cancel: cancel}
+	started := time.Now()
+
+	done := make(chan struct{})
+	go func() {
+		NewTrendingUpdater(blogs, time.Millisecond).Run(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("updater did not stop")
+	}
+
+	// A failed round is logged and the next one still runs.
+	if len(blogs.nows) != 3 {
+		t.Fatalf("rescored %d times, want 3", len(blogs.nows))
+	}
+	for i, now := range blogs.nows {
+		if now.Before(started) || (i > 0 && now.Before(blogs.nows[i-1])) {
+			t.Errorf("round %d scored as of %v, want the current time", i+1, now)
+		}
+		if blogs.weights[i] != trendingWeights {
+			t.Errorf("round %d weighted %+v, want %+v", i+1, blogs.weights[i], trendingWeights)
+		}
+	}
+}
@@ -17,15 +17,16 @@ type UserUsecase struct {
 	refreshTokenRepo       domain.IRefreshTokenRepository
 	passwordResetTokenRepo domain.IPasswordResetTokenRepository
 	JWTService             domain.IJWTService
-	emailService           domain.IEmailService
+	outbox                 domain.IOutbox
 	passwordService        domain.IPasswordService
+	events                 domain.IEventBus
 	contextTimeout         time.Duration
 }
 
 func NewUserUseCase(userRepo domain.IUserRepository, emailVerificationRepo domain.IEmailVerificationRepository,
 	refreshRepo domain.IRefreshTokenRepository, resetTokenRepo domain.IPasswordResetTokenRepository,
-	jwt domain.IJWTService, passwordService domain.IPasswordService, emailService domain.IEmailService,
-	timeout time.Duration) domain.IUserUsecase {
+	jwt domain.IJWTService, passwordService domain.IPasswordService, outbox domain.IOutbox,
+	events domain.IEventBus, timeout time.Duration) domain.IUserUsecase {
 	return &UserUsecase{
 		userRepository:         userRepo,
 		emailVerificationRepo:  emailVerificationRepo,
@@ -33,7 +34,8 @@ func NewUserUseCase(userRepo domain.IUserRepository, emailVerificationRepo domai
 		passwordResetTokenRepo: resetTokenRepo,
 		JWTService:             jwt,
 		passwordService:        passwordService,
-		emailService:           emailService,
+		outbox:                 outbox,
+		events:                 events,
 		contextTimeout:         timeout,
 	}
 }
@@ -60,13 +62,22 @@ func (uc *UserUsecase) Register(ctx context.Context, user *domain.User) error {
 		return err
 	}
 
-	// Check if email or username already exists
-	emailExists, err := uc.userRepository.ExistsByEmail(ctx, user.Email)
-	if err != nil {
+	// Check if email or username already exists. Registering again with the
+	// same password before verifying sends a new code, so an account whose
+	// email could not be queued is not stuck.
+	existing, err := uc.userRepository.GetByEmail(ctx, user.Email)
+	if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
 		return fmt.Errorf("failed to check email: %w", err)
 	}
-	if emailExists {
-		return domain.ErrEmailTaken
+	if existing != nil {
+		if existing.IsVerified || uc.passwordService.Compare(existing.Password, user.Password) != nil {
+			return domain.ErrEmailTaken
+		}
+		if err := uc.issueVerification(ctx, existing.Email); err != nil {
+			return err
+		}
+		log.Printf("User %s re-registered before verifying; sent a new code", existing.Email)
+		return nil
 	}
 
 	usernameExists, err := uc.userRepository.ExistsByUsername(ctx, user.Username)
@@ -84,10 +95,10 @@ func (uc *UserUsecase) Register(ctx context.Context, user *domain.User) error {
 	}
 	user.Password = hashedPassword
 
-	// Generate OTP
-	otp, err := uc.generateOTP()
-	if err != nil {
-		return fmt.Errorf("failed to generate OTP: %w", err)
+	// Queue the verification email first, so that no account is left
+	// without a way to verify it when the email cannot be queued.
+	if err := uc.issueVerification(ctx, user.Email); err != nil {
+		return err
 	}
 
 	// Save user (not verified yet)
@@ -97,28 +108,45 @@ func (uc *UserUsecase) Register(ctx context.Context, user *domain.User) error {
 		return fmt.Errorf("failed to create user: %w", err)
 	}
 
-	// Store email verification
+	err = uc.events.Publish(ctx, domain.Event{
+		Type:    domain.EventUserRegistered,
+		ActorID: savedUser.ID,
+		UserID:  savedUser.ID,
+		Data:    map[string]string{"username": savedUser.Username},
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("User %s registered successfully with ID: %s", savedUser.Email, savedUser.ID)
+	return nil
+}
+
+// issueVerification stores a new OTP for email and queues the email that
+// carries it.
+func (uc *UserUsecase) issueVerification(ctx context.Context, email string) error {
+	otp, err := uc.generateOTP()
+	if err != nil {
+		return fmt.Errorf("failed to generate OTP: %w", err)
+	}
+
 	verification := &domain.EmailVerification{
-		Email:     user.Email,
+		Email:     email,
 		OTP:       otp,
 		ExpiresAt: time.Now().Add(15 * time.Minute), // OTP expires in 15 minutes
 		CreatedAt: time.Now(),
 		Used:      false,
 	}
-
-	err = uc.emailVerificationRepo.Store(ctx, verification)
-	if err != nil {
+	if err := uc.emailVerificationRepo.Store(ctx, verification); err != nil {
 		return fmt.Errorf("failed to store verification: %w", err)
 	}
 
-	// Send verification email
-	err = uc.emailService.SendVerificationEmail(ctx, user.Email, otp)
+	// Queue verification email; the outbox retries it until the OTP expires
+	err = uc.outbox.EnqueueUntil(ctx, domain.OutboxVerificationEmail,
+		domain.VerificationEmail{Email: email, VerificationID: verification.ID}, verification.ExpiresAt)
 	if err != nil {
-		log.Printf("Failed to send verification email to %s: %v", user.Email, err)
-		// Don't fail registration if email sending fails
+		return fmt.Errorf("failed to queue verification email: %w", err)
 	}
-
-	log.Printf("User %s registered successfully with ID: %s", savedUser.Email, savedUser.ID)
 	return nil
 }
 
@@ -196,6 +224,10 @@ func (uc *UserUsecase) Login(ctx context.Context, user *domain.User) (*domain.To
 		return nil, errors.New("incorrect email or password")
 	}
 
+	if dbUser.IsSuspended(time.Now()) {
+		return nil, domain.ErrAccountSuspended
+	}
+
 	// Generate access token
 	accessToken, err := uc.JWTService.CreateAccessToken(dbUser)
 	if err != nil {
@@ -309,49 +341,27 @@ func (uc *UserUsecase) UpdateProfile(ctx context.Context, userID string, bio, pr
 	return uc.userRepository.Update(ctx, user)
 }
 
-func (uc *UserUsecase) RequestPasswordReset(ctx context.Context, input domain.RequestPasswordResetInput) (string, error) {
+// RequestPasswordReset queues the reset email; its token is created when
+// the email is sent (see HandleEmails).
+func (uc *UserUsecase) RequestPasswordReset(ctx context.Context, input domain.RequestPasswordResetInput) error {
 	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
 	defer cancel()
 
 	if input.Email == "" {
-		return "", domain.ErrInvalidInput
+		return domain.ErrInvalidInput
 	}
 
 	user, err := uc.userRepository.GetByEmail(ctx, input.Email)
 	if err != nil {
-		return "", fmt.Errorf("user not found: %w", err)
-	}
-
-	rawToken, err := uc.passwordService.GenerateRandomToken()
-
-	if err != nil {
-		return "", fmt.Errorf("Error: %w", err)
-	}
-
-	hashedToken, err := uc.passwordService.Hash(rawToken)
-	if err != nil {
-		return "", err
-	}
-
-	resetToken := &domain.PasswordResetToken{
-		UserID:    user.ID,
-		TokenHash: hashedToken,
-		ExpiresAt: time.Now().Add(15 * time.Minute),
-		CreatedAt: time.Now(),
-		Used:      false,
-	}
-
-	err = uc.passwordResetTokenRepo.Store(ctx, resetToken)
-	if err != nil {
-		return "", fmt.Errorf("failed to store reset token: %w", err)
+		return fmt.Errorf("user not found: %w", err)
 	}
 
-	err = uc.emailService.SendPasswordResetEmail(ctx, user.Email, rawToken)
+	err = uc.outbox.EnqueueUntil(ctx, domain.OutboxPasswordResetEmail,
+		domain.PasswordResetEmail{UserID: user.ID, Email: user.Email}, time.Now().Add(passwordResetTTL))
 	if err != nil {
-		return "", fmt.Errorf("failed to send email: %w", err)
+		return fmt.Errorf("failed to queue email: %w", err)
 	}
-
-	return rawToken, nil
+	return nil
 }
 
 func (uc *UserUsecase) ResetPassword(ctx context.Context, input domain.ResetPasswordInput) error {
@@ -0,0 +1,85 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// signups keeps users in memory.
+type signups struct {
+	domain.IUserRepository
+	users []domain.User
+}
+
+func (s *signups) GetByEmail(_ context.Context, email string) (*domain.User, error) {
+	for _, user := range s.users {
+		if user.Email == email {
+			return &user, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (s *signups) ExistsByUsername(_ context.Context, username string) (bool, error) {
+	for _, user := range s.users {
+		if user.Username == username {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *signups) Create(_ context.Context, user *domain.User) (*domain.User, error) {
+	user.ID = "u" + strconv.Itoa(len(s.users)+1)
+	s.users = append(s.users, *user)
+	return user, nil
+}
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name       string
+		outboxDown bool
+		wantUsers  int
+	}{
+		{name: "email queued", wantUsers: 1},
+		{name: "email cannot be queued", outboxDown: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			users, codes := &signups{}, &codeBook{codes: map[string]domain.EmailVerification{}}
+			outbox, mail := newPostbox(), &mailbag{}
+			outbox.down = tt.outboxDown
+			passwords := &plainPasswords{}
+			HandleEmails(outbox, mail, codes, &resetLedger{}, passwords)
+			u := NewUserUseCase(users, codes, nil, nil, nil, passwords, outbox, &flakyEventBus{}, time.Second)
+
+			err := u.Register(ctx, &domain.User{Username: "lin", Email: "lin@example.com", Password: "correct horse battery"})
+			if tt.outboxDown != (err != nil) {
+				t.Fatalf("Register: %v", err)
+			}
+			if len(users.users) != tt.wantUsers {
+				t.Fatalf("stored %d users, want %d", len(users.users), tt.wantUsers)
+			}
+			if tt.outboxDown {
+				return
+			}
+
+			// The code reaches the inbox without passing through the outbox.
+			otp := codes.codes["v1"].OTP
+			if len(outbox.queued) != 1 || strings.Contains(string(outbox.queued[0].payload), otp) {
+				t.Fatalf("queued %q, want one email without the code %s", outbox.queued, otp)
+			}
+			if err := outbox.deliver(ctx, outbox.queued[0]); err != nil {
+				t.Fatalf("handler: %v", err)
+			}
+			if len(mail.sent) != 1 || mail.sent[0] != "lin@example.com "+otp {
+				t.Errorf("sent %v, want the code %s to lin@example.com", mail.sent, otp)
+			}
+		})
+	}
+}
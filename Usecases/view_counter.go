@@ -0,0 +1,102 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+type ViewCounter struct {
+	blogRepository domain.IBlogRepository
+	viewRepository domain.IBlogViewRepository
+	window         time.Duration
+	interval       time.Duration
+
+	mu      sync.Mutex
+	pending map[string]*domain.BlogViews // by blog ID
+}
+
+// NewViewCounter counts each reader once per window and writes the counts
+// to the blogs every interval.
+func NewViewCounter(blogRepo domain.IBlogRepository, viewRepo domain.IBlogViewRepository, window, interval time.Duration) domain.IViewCounter {
+	return &ViewCounter{
+		blogRepository: blogRepo,
+		viewRepository: viewRepo,
+		window:         window,
+		interval:       interval,
+		pending:        map[string]*domain.BlogViews{},
+	}
+}
+
+func (c *ViewCounter) RecordView(ctx context.Context, blogID, viewerKey string) (bool, error) {
+	if viewerKey == "" {
+		return false, nil
+	}
+	now := time.Now()
+	first, err := c.viewRepository.Record(ctx, blogID, viewerKey, now, c.window)
+	if err != nil || !first {
+		return false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	views, ok := c.pending[blogID]
+	if !ok {
+		views = &domain.BlogViews{BlogID: blogID}
+		c.pending[blogID] = views
+	}
+	views.Count++
+	views.LastViewedAt = now
+	return true, nil
+}
+
+func (c *ViewCounter) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Flush(ctx); err != nil {
+				log.Printf("view counter: %v", err)
+			}
+		}
+	}
+}
+
+// Flush writes the buffered views. When that fails they go back into the
+// buffer to be tried again with the next flush.
+func (c *ViewCounter) Flush(ctx context.Context) error {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = map[string]*domain.BlogViews{}
+	c.mu.Unlock()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	views := make([]domain.BlogViews, 0, len(pending))
+	for _, v := range pending {
+		views = append(views, *v)
+	}
+	if err := c.blogRepository.AddViews(ctx, views); err != nil {
+		c.mu.Lock()
+		for id, v := range pending {
+			if current, ok := c.pending[id]; ok {
+				current.Count += v.Count
+				if v.LastViewedAt.After(current.LastViewedAt) {
+					current.LastViewedAt = v.LastViewedAt
+				}
+			} else {
+				c.pending[id] = v
+			}
+		}
+		c.mu.Unlock()
+		return err
+	}
+	return nil
+}
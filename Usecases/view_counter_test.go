@@ -0,0 +1,114 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// firstViews counts a reader the first time they view a blog.
+type firstViews struct {
+	domain.IBlogViewRepository
+	seen map[string]bool
+}
+
+func (r *firstViews) Record(_ context.Context, blogID, viewerKey string, at time.Time, window time.Duration) (bool, error) {
+	key := blogID + "/" + viewerKey
+	if r.seen[key] {
+		return false, nil
+	}
+	r.seen[key] = true
+	return true, nil
+}
+
+// viewStore adds flushed views to its counts. While failing it rejects them,
+// after running duringFlush to stand in for views arriving meanwhile.
+type viewStore struct {
+	domain.IBlogRepository
+	counts      map[string]int
+	failing     bool
+	duringFlush func()
+}
+
+func (r *viewStore) AddViews(_ context.Context, views []domain.BlogViews) error {
+	if r.duringFlush != nil {
+		r.duringFlush()
+	}
+	if r.failing {
+		return errors.New("write failed")
+	}
+	for _, v := range views {
+		r.counts[v.BlogID] += v.Count
+	}
+	return nil
+}
+
+func TestViewCounterFlush(t *testing.T) {
+	type view struct{ blogID, reader string }
+	tests := []struct {
+		name       string
+		views      []view
+		failFirst  bool
+		duringFail []view
+		want       map[string]int
+	}{
+		{
+			name:  "unique readers",
+			views: []view{{"b1", "r1"}, {"b1", "r1"}, {"b1", "r2"}, {"b2", "r1"}},
+			want:  map[string]int{"b1": 2, "b2": 1},
+		},
+		{
+			name:      "failed flush is kept for the next",
+			views:     []view{{"b1", "r1"}, {"b2", "r1"}},
+			failFirst: true,
+			want:      map[string]int{"b1": 1, "b2": 1},
+		},
+		{
+			name:       "views during a failed flush are merged",
+			views:      []view{{"b1", "r1"}, {"b1", "r2"}},
+			failFirst:  true,
+			duringFail: []view{{"b1", "r3"}, {"b2", "r1"}},
+			want:       map[string]int{"b1": 3, "b2": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			blogs := &viewStore{counts: map[string]int{}}
+			c := NewViewCounter(blogs, &firstViews{seen: map[string]bool{}}, time.Hour, time.Minute).(*ViewCounter)
+			record := func(views []view) {
+				for _, v := range views {
+					if _, err := c.RecordView(ctx, v.blogID, v.reader); err != nil {
+						t.Fatalf("RecordView: %v", err)
+					}
+				}
+			}
+			record(tt.views)
+
+			if tt.failFirst {
+				blogs.failing = true
+				blogs.duringFlush = func() { record(tt.duringFail) }
+				if err := c.Flush(ctx); err == nil {
+					t.Fatal("Flush succeeded, want an error")
+				}
+				blogs.failing, blogs.duringFlush = false, nil
+			}
+			if err := c.Flush(ctx); err != nil {
+				t.Fatalf("Flush: %v", err)
+			}
+			if len(blogs.counts) != len(tt.want) {
+				t.Errorf("counts = %v, want %v", blogs.counts, tt.want)
+			}
+			for blogID, want := range tt.want {
+				if blogs.counts[blogID] != want {
+					t.Errorf("views of %s = %d, want %d", blogID, blogs.counts[blogID], want)
+				}
+			}
+			if len(c.pending) != 0 {
+				t.Errorf("%d blogs still pending after flush", len(c.pending))
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// webhookLease holds a claimed delivery back from other dispatchers. It
+	// outlasts the sender's timeout, so a delivery is only sent twice when
+	// a dispatcher dies mid-attempt.
+	webhookLease = 2 * time.Minute
+	// webhookConcurrency is how many deliveries are sent at once.
+	webhookConcurrency = 8
+	webhookBaseBackoff = 30 * time.Second
+	webhookMaxBackoff  = time.Hour
+)
+
+type WebhookDispatcher struct {
+	webhookRepository  domain.IWebhookRepository
+	deliveryRepository domain.IWebhookDeliveryRepository
+	sender             domain.IWebhookSender
+	interval           time.Duration
+	maxAttempts        int
+	disableAfter       int
+}
+
+// NewWebhookDispatcher gives each delivery up to maxAttempts attempts, and
+// disables a webhook after disableAfter failed attempts in a row.
+func NewWebhookDispatcher(webhookRepo domain.IWebhookRepository, deliveryRepo domain.IWebhookDeliveryRepository, sender domain.IWebhookSender,
+	interval time.Duration, maxAttempts, disableAfter int) domain.IWebhookDispatcher {
+	return &WebhookDispatcher{
+		webhookRepository:  webhookRepo,
+		deliveryRepository: deliveryRepo,
+		sender:             sender,
+		interval:           interval,
+		maxAttempts:        maxAttempts,
+		disableAfter:       disableAfter,
+	}
+}
+
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DispatchDue(ctx); err != nil {
+			log.Printf("webhook dispatcher: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue sends every delivery that is due and returns how many it
+// attempted.
+func (d *WebhookDispatcher) DispatchDue(ctx context.Context) (int, error) {
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, webhookConcurrency)
+	defer wg.Wait()
+
+	attempted := 0
+	for ctx.Err() == nil {
+		slots <- struct{}{}
+		delivery, err := d.deliveryRepository.ClaimDue(ctx, time.Now(), webhookLease)
+		if err != nil || delivery == nil {
+			<-slots
+			return attempted, err
+		}
+		attempted++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			if err := d.attempt(ctx, delivery); err != nil {
+				log.Printf("webhook dispatcher: delivery %s: %v", delivery.ID, err)
+			}
+		}()
+	}
+	return attempted, nil
+}
+
+// attempt sends a delivery once and schedules a retry with exponential
+// backoff when that fails. Pings are tried once and do not count against
+// the webhook.
+func (d *WebhookDispatcher) attempt(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	now := time.Now()
+	delivery.UpdatedAt = now
+	ping := delivery.EventType == domain.WebhookPing
+
+	webhook, err := d.webhookRepository.FindByID(ctx, delivery.WebhookID)
+	if err != nil && !errors.Is(err, domain.ErrWebhookNotFound) {
+		return err // retried once the lease runs out
+	}
+	if err != nil {
+		delivery.Status = domain.DeliveryFailed
+		delivery.NextAttemptAt = nil
+		delivery.LastError = err.Error()
+		return d.deliveryRepository.Save(ctx, delivery)
+	}
+	if !webhook.Active && !ping {
+		delivery.Status = domain.DeliveryFailed
+		delivery.NextAttemptAt = nil
+		delivery.LastError = "webhook is disabled"
+		return d.deliveryRepository.Save(ctx, delivery)
+	}
+
+	result := d.sender.Send(ctx, webhook, delivery)
+	if ctx.Err() != nil {
+		// Shutting down: the lease runs out and the delivery is sent again
+		// later, without this attempt counting.
+		return nil
+	}
+	delivery.Attempts++
+	delivery.LastStatusCode = result.StatusCode
+	delivery.LastResponse = result.Body
+	delivery.LastError = ""
+	if result.Err != nil {
+		delivery.LastError = result.Err.Error()
+	} else if !result.OK() {
+		delivery.LastError = fmt.Sprintf("endpoint answered %d", result.StatusCode)
+	}
+
+	if result.OK() {
+		delivery.Status = domain.DeliverySucceeded
+		delivery.NextAttemptAt = nil
+		delivery.DeliveredAt = &now
+		if !ping {
+			if err := d.webhookRepository.RecordSuccess(ctx, webhook.ID); err != nil {
+				log.Printf("warning: failed to record success of webhook %s: %v", webhook.ID, err)
+			}
+		}
+		return d.deliveryRepository.Save(ctx, delivery)
+	}
+
+	if ping || delivery.Attempts >= d.maxAttempts {
+		delivery.Status = domain.DeliveryFailed
+		delivery.NextAttemptAt = nil
+	} else {
+		next := now.Add(webhookBackoff(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+	}
+	if !ping {
+		reason := fmt.Sprintf("%d failed attempts in a row, the last: %s", d.disableAfter, delivery.LastError)
+		disabled, err := d.webhookRepository.RecordFailure(ctx, webhook.ID, d.disableAfter, reason, now)
+		if err != nil {
+			log.Printf("warning: failed to record failure of webhook %s: %v", webhook.ID, err)
+		} else if disabled {
+			log.Printf("webhook dispatcher: disabled webhook %s after %d failed attempts in a row", webhook.ID, d.disableAfter)
+		}
+	}
+	return d.deliveryRepository.Save(ctx, delivery)
+}
+
+// webhookBackoff is the wait after the given number of failed attempts:
+// 30s, 1m, 2m, ... up to an hour.
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookBaseBackoff
+	for i := 1; i < attempts && backoff < webhookMaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, webhookMaxBackoff)
+}
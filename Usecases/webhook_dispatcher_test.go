@@ -0,0 +1,278 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	infrastructure "blog-api/Infrastructure"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWebhookBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, time.Hour},
+		{50, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := webhookBackoff(tt.attempts); got != tt.want {
+			t.Errorf("webhookBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+// webhookStore keeps webhooks and their deliveries in memory, with the
+// failure counting of the Mongo repositories.
+type webhookStore struct {
+	mu         sync.Mutex
+	webhooks   map[string]*domain.Webhook
+	deliveries map[string]*domain.WebhookDelivery
+}
+
+func newWebhookStore(webhooks ...domain.Webhook) *webhookStore {
+	s := &webhookStore{webhooks: map[string]*domain.Webhook{}, deliveries: map[string]*domain.WebhookDelivery{}}
+	for i := range webhooks {
+		s.webhooks[webhooks[i].ID] = &webhooks[i]
+	}
+	return s
+}
+
+func (s *webhookStore) queue(webhookID string, ids ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	due := time.Now().Add(-time.Second)
+	for _, id := range ids {
+		s.deliveries[id] = &domain.WebhookDelivery{
+			ID: id, WebhookID: webhookID, EventType: domain.WebhookBlogPublished,
+			Payload: "{}", Status: domain.DeliveryPending, NextAttemptAt: &due,
+		}
+	}
+}
+
+// makeDue moves every scheduled retry into the past.
+func (s *webhookStore) makeDue() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	past := time.Now().Add(-time.Second)
+	for _, d := range s.deliveries {
+		if d.NextAttemptAt != nil {
+			d.NextAttemptAt = &past
+		}
+	}
+}
+
+func (s *webhookStore) delivery(id string) domain.WebhookDelivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.deliveries[id]
+}
+
+func (s *webhookStore) webhook(id string) domain.Webhook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.webhooks[id]
+}
+
+type fakeWebhookRepository struct {
+	domain.IWebhookRepository
+	*webhookStore
+}
+
+type fakeDeliveryRepository struct {
+	domain.IWebhookDeliveryRepository
+	*webhookStore
+}
+
+func (s fakeWebhookRepository) FindByID(_ context.Context, id string) (*domain.Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w, ok := s.webhooks[id]
+	if !ok {
+		return nil, domain.ErrWebhookNotFound
+	}
+	found := *w
+	return &found, nil
+}
+
+func (s fakeWebhookRepository) RecordSuccess(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.webhooks[id].ConsecutiveFailures = 0
+	return nil
+}
+
+func (s fakeWebhookRepository) RecordFailure(_ context.Context, id string, disableAfter int, reason string, at time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w := s.webhooks[id]
+	w.ConsecutiveFailures++
+	if !w.Active || w.ConsecutiveFailures < disableAfter {
+		return false, nil
+	}
+	w.Active, w.DisabledAt, w.DisabledReason = false, &at, reason
+	return true, nil
+}
+
+func (s fakeDeliveryRepository) ClaimDue(_ context.Context, now time.Time, lease time.Duration) (*domain.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var due []*domain.WebhookDelivery
+	for _, d := range s.deliveries {
+		if d.Status == domain.DeliveryPending && d.NextAttemptAt != nil && !d.NextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+	if len(due) == 0 {
+		return nil, nil
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].NextAttemptAt.Before(*due[j].NextAttemptAt) })
+	until := now.Add(lease)
+	due[0].NextAttemptAt = &until
+	claimed := *due[0]
+	return &claimed, nil
+}
+
+func (s fakeDeliveryRepository) Save(_ context.Context, delivery *domain.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	saved := *delivery
+	s.deliveries[delivery.ID] = &saved
+	return nil
+}
+
+// webhookEndpoint answers with the given status codes in turn, repeating
+// the last one, and counts its requests.
+func webhookEndpoint(t *testing.T, codes ...int) (*httptest.Server, *atomic.Int32) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(hits.Add(1))
+		w.WriteHeader(codes[min(n, len(codes))-1])
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func newTestDispatcher(store *webhookStore, maxAttempts, disableAfter int) domain.IWebhookDispatcher {
+	return NewWebhookDispatcher(fakeWebhookRepository{webhookStore: store}, fakeDeliveryRepository{webhookStore: store}, infrastructure.NewWebhookSender(time.Second), time.Minute, maxAttempts, disableAfter)
+}
+
+func dispatch(t *testing.T, d domain.IWebhookDispatcher, want int) {
+	t.Helper()
+	n, err := d.DispatchDue(context.Background())
+	if err != nil {
+		t.Fatalf("DispatchDue: %v", err)
+	}
+	if n != want {
+		t.Fatalf("DispatchDue attempted %d deliveries, want %d", n, want)
+	}
+}
+
+func TestWebhookDispatcherRetriesWithBackoff(t *testing.T) {
+	server, hits := webhookEndpoint(t, http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusOK)
+	store := newWebhookStore(domain.Webhook{ID: "w1", URL: server.URL, Active: true})
+	store.queue("w1", "d1")
+	dispatcher := newTestDispatcher(store, 5, 10)
+
+	for attempt, wantBackoff := range []time.Duration{30 * time.Second, time.Minute} {
+		before := time.Now()
+		dispatch(t, dispatcher, 1)
+		d := store.delivery("d1")
+		if d.Status != domain.DeliveryPending || d.Attempts != attempt+1 {
+			t.Fatalf("after attempt %d: status %s, %d attempts", attempt+1, d.Status, d.Attempts)
+		}
+		if d.NextAttemptAt == nil || d.NextAttemptAt.Before(before.Add(wantBackoff)) || d.NextAttemptAt.After(time.Now().Add(wantBackoff)) {
+			t.Fatalf("after attempt %d: next attempt at %v, want %v from now", attempt+1, d.NextAttemptAt, wantBackoff)
+		}
+		if d.LastStatusCode < 500 || d.LastError == "" {
+			t.Errorf("after attempt %d: last status %d, error %q", attempt+1, d.LastStatusCode, d.LastError)
+		}
+
+		// Not due yet, so nothing is sent until the retry time passes.
+		dispatch(t, dispatcher, 0)
+		store.makeDue()
+	}
+
+	dispatch(t, dispatcher, 1)
+	d := store.delivery("d1")
+	if d.Status != domain.DeliverySucceeded || d.Attempts != 3 || d.DeliveredAt == nil || d.NextAttemptAt != nil || d.LastError != "" {
+		t.Errorf("after success: %+v", d)
+	}
+	if w := store.webhook("w1"); w.ConsecutiveFailures != 0 || !w.Active {
+		t.Errorf("webhook after success: %+v", w)
+	}
+	if hits.Load() != 3 {
+		t.Errorf("endpoint got %d requests, want 3", hits.Load())
+	}
+}
+
+func TestWebhookDispatcherGivesUp(t *testing.T) {
+	server, _ := webhookEndpoint(t, http.StatusBadGateway)
+	store := newWebhookStore(domain.Webhook{ID: "w1", URL: server.URL, Active: true})
+	store.queue("w1", "d1")
+	dispatcher := newTestDispatcher(store, 2, 10)
+
+	dispatch(t, dispatcher, 1)
+	store.makeDue()
+	dispatch(t, dispatcher, 1)
+
+	d := store.delivery("d1")
+	if d.Status != domain.DeliveryFailed || d.Attempts != 2 || d.NextAttemptAt != nil {
+		t.Errorf("after the last attempt: status %s, %d attempts, next %v", d.Status, d.Attempts, d.NextAttemptAt)
+	}
+	store.makeDue()
+	dispatch(t, dispatcher, 0)
+}
+
+func TestWebhookDispatcherDisablesFailingWebhook(t *testing.T) {
+	server, hits := webhookEndpoint(t, http.StatusInternalServerError)
+	store := newWebhookStore(domain.Webhook{ID: "w1", URL: server.URL, Active: true})
+	store.queue("w1", "d1", "d2", "d3")
+	dispatcher := newTestDispatcher(store, 5, 3)
+
+	dispatch(t, dispatcher, 3)
+	w := store.webhook("w1")
+	if w.Active || w.DisabledAt == nil || w.DisabledReason == "" || w.ConsecutiveFailures != 3 {
+		t.Fatalf("webhook after 3 failures: %+v", w)
+	}
+
+	// Retries of a disabled webhook fail without being sent.
+	store.makeDue()
+	dispatch(t, dispatcher, 3)
+	for _, id := range []string{"d1", "d2", "d3"} {
+		d := store.delivery(id)
+		if d.Status != domain.DeliveryFailed || d.LastError != "webhook is disabled" {
+			t.Errorf("%s: status %s, error %q", id, d.Status, d.LastError)
+		}
+	}
+	if hits.Load() != 3 {
+		t.Errorf("endpoint got %d requests, want 3", hits.Load())
+	}
+}
+
+func TestWebhookDispatcherSuccessResetsFailures(t *testing.T) {
+	server, _ := webhookEndpoint(t, http.StatusInternalServerError, http.StatusNoContent)
+	store := newWebhookStore(domain.Webhook{ID: "w1", URL: server.URL, Active: true})
+	store.queue("w1", "d1")
+	dispatcher := newTestDispatcher(store, 5, 2)
+
+	dispatch(t, dispatcher, 1)
+	if w := store.webhook("w1"); w.ConsecutiveFailures != 1 || !w.Active {
+		t.Fatalf("webhook after one failure: %+v", w)
+	}
+	store.makeDue()
+	dispatch(t, dispatcher, 1)
+	if w := store.webhook("w1"); w.ConsecutiveFailures != 0 || !w.Active {
+		t.Errorf("webhook after a success: %+v", w)
+	}
+}
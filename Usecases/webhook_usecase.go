@@ -0,0 +1,382 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	defaultDeliveryLimit   = 20
+	maxDeliveryLimit       = 100
+	minWebhookSecretLength = 16
+	maxWebhookDescLength   = 500
+	generatedSecretBytes   = 32 // hex encoded
+)
+
+// webhookPayload is the JSON body sent to webhooks. ID is the event's, so a
+// receiver can recognise a retried or replayed event.
+type webhookPayload struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+type webhookBlog struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Slug        string     `json:"slug"`
+	AuthorID    string     `json:"author_id"`
+	Status      string     `json:"status,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	Excerpt     string     `json:"excerpt,omitempty"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+type webhookComment struct {
+	ID        string    `json:"id"`
+	BlogID    string    `json:"blog_id"`
+	UserID    string    `json:"user_id"`
+	ParentID  string    `json:"parent_id,omitempty"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type webhookUser struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+type WebhookUsecase struct {
+	webhookRepository  domain.IWebhookRepository
+	deliveryRepository domain.IWebhookDeliveryRepository
+	blogRepository     domain.IBlogRepository
+	commentRepository  domain.ICommentRepository
+}
+
+func NewWebhookUsecase(webhookRepo domain.IWebhookRepository, deliveryRepo domain.IWebhookDeliveryRepository,
+	blogRepo domain.IBlogRepository, commentRepo domain.ICommentRepository) domain.IWebhookUsecase {
+	return &WebhookUsecase{
+		webhookRepository:  webhookRepo,
+		deliveryRepository: deliveryRepo,
+		blogRepository:     blogRepo,
+		commentRepository:  commentRepo,
+	}
+}
+
+// HandleEvent takes a snapshot of the event's subject when it handles the
+// event, which behind the outbox can be a little after it happened, so
+// every webhook and every retry gets the same payload. An event whose blog
+// or comment is gone by then is dropped; deleting a blog has its own event.
+func (u *WebhookUsecase) HandleEvent(ctx context.Context, event domain.Event) error {
+	eventType := string(event.Type)
+	if !slices.Contains(domain.WebhookEventTypes, eventType) {
+		return nil
+	}
+	webhooks, err := u.webhookRepository.ListActive(ctx, eventType)
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+	data, err := u.eventData(ctx, event)
+	if errors.Is(err, domain.ErrBlogNotFound) || errors.Is(err, domain.ErrCommentNotFound) {
+		log.Printf("webhooks: dropped %s %s: %v", eventType, event.ID, err)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(webhookPayload{ID: event.ID, Type: eventType, CreatedAt: event.OccurredAt, Data: data})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	now := time.Now()
+	deliveries := make([]domain.WebhookDelivery, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		deliveries = append(deliveries, newDelivery(webhook.ID, event.ID, eventType, string(payload), now))
+	}
+	return u.deliveryRepository.CreateMany(ctx, deliveries)
+}
+
+func (u *WebhookUsecase) eventData(ctx context.Context, event domain.Event) (any, error) {
+	switch event.Type {
+	case domain.EventBlogPublished, domain.EventBlogUpdated:
+		blog, err := u.blogRepository.FindByID(ctx, event.BlogID)
+		if err != nil {
+			return nil, err
+		}
+		return webhookBlog{
+			ID:          blog.ID,
+			Title:       blog.Title,
+			Slug:        blog.Slug,
+			AuthorID:    blog.UserID,
+			Status:      string(blog.Status),
+			Tags:        blog.Tags,
+			Excerpt:     blog.Excerpt,
+			PublishedAt: blog.PublishedAt,
+			UpdatedAt:   &blog.UpdatedAt,
+		}, nil
+	case domain.EventBlogDeleted:
+		return webhookBlog{ID: event.BlogID, Title: event.Data["title"], Slug: event.Data["slug"], AuthorID: event.UserID}, nil
+	case domain.EventCommentCreated:
+		comment, err := u.commentRepository.FindByID(ctx, event.CommentID)
+		if err != nil {
+			return nil, err
+		}
+		if comment.Deleted {
+			return nil, domain.ErrCommentNotFound
+		}
+		return webhookComment{
+			ID:        comment.ID,
+			BlogID:    comment.BlogId,
+			UserID:    comment.UserId,
+			ParentID:  comment.ParentID,
+			Content:   comment.Content,
+			CreatedAt: comment.CreatedAt,
+		}, nil
+	case domain.EventUserRegistered:
+		return webhookUser{ID: event.UserID, Username: event.Data["username"]}, nil
+	}
+	return nil, fmt.Errorf("no webhook payload for %s", event.Type)
+}
+
+func newDelivery(webhookID, eventID, eventType, payload string, now time.Time) domain.WebhookDelivery {
+	return domain.WebhookDelivery{
+		WebhookID:     webhookID,
+		EventID:       eventID,
+		EventType:     eventType,
+		Payload:       payload,
+		Status:        domain.DeliveryPending,
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+func (u *WebhookUsecase) CreateWebhook(ctx context.Context, input domain.WebhookInput, actor domain.Viewer) (*domain.Webhook, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, domain.ErrForbidden
+	}
+	webhook := &domain.Webhook{
+		URL:         strings.TrimSpace(input.URL),
+		Secret:      input.Secret,
+		Events:      input.Events,
+		Description: strings.TrimSpace(input.Description),
+		Active:      true,
+		CreatedBy:   actor.UserID,
+	}
+	if webhook.Secret == "" {
+		secret := make([]byte, generatedSecretBytes)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+		}
+		webhook.Secret = hex.EncodeToString(secret)
+	}
+	if err := validateWebhook(webhook); err != nil {
+		return nil, err
+	}
+	webhook.CreatedAt = time.Now()
+	webhook.UpdatedAt = webhook.CreatedAt
+	if err := u.webhookRepository.Create(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (u *WebhookUsecase) ListWebhooks(ctx context.Context, actor domain.Viewer) ([]domain.Webhook, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, domain.ErrForbidden
+	}
+	return u.webhookRepository.List(ctx)
+}
+
+func (u *WebhookUsecase) GetWebhook(ctx context.Context, webhookID string, actor domain.Viewer) (*domain.Webhook, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, domain.ErrForbidden
+	}
+	return u.webhookRepository.FindByID(ctx, webhookID)
+}
+
+func (u *WebhookUsecase) UpdateWebhook(ctx context.Context, webhookID string, update domain.WebhookUpdate, actor domain.Viewer) (*domain.Webhook, error) {
+	webhook, err := u.GetWebhook(ctx, webhookID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if update.URL != nil {
+		webhook.URL = strings.TrimSpace(*update.URL)
+	}
+	if update.Secret != nil {
+		webhook.Secret = *update.Secret
+	}
+	if update.Events != nil {
+		webhook.Events = update.Events
+	}
+	if update.Description != nil {
+		webhook.Description = strings.TrimSpace(*update.Description)
+	}
+	if update.Active != nil {
+		webhook.Active = *update.Active
+		if webhook.Active {
+			webhook.ConsecutiveFailures = 0
+			webhook.DisabledAt = nil
+			webhook.DisabledReason = ""
+		}
+	}
+	if err := validateWebhook(webhook); err != nil {
+		return nil, err
+	}
+	webhook.UpdatedAt = time.Now()
+	if err := u.webhookRepository.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// DeleteWebhook drops its delivery log too.
+func (u *WebhookUsecase) DeleteWebhook(ctx context.Context, webhookID string, actor domain.Viewer) error {
+	if actor.Role != domain.RoleAdmin {
+		return domain.ErrForbidden
+	}
+	if err := u.webhookRepository.Delete(ctx, webhookID); err != nil {
+		return err
+	}
+	return u.deliveryRepository.DeleteByWebhook(ctx, webhookID)
+}
+
+func (u *WebhookUsecase) PingWebhook(ctx context.Context, webhookID string, actor domain.Viewer) (*domain.WebhookDelivery, error) {
+	webhook, err := u.GetWebhook(ctx, webhookID, actor)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	eventID := newWebhookEventID()
+	payload, err := json.Marshal(webhookPayload{
+		ID:        eventID,
+		Type:      domain.WebhookPing,
+		CreatedAt: now,
+		Data:      map[string]string{"webhook_id": webhook.ID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+	deliveries := []domain.WebhookDelivery{newDelivery(webhook.ID, eventID, domain.WebhookPing, string(payload), now)}
+	if err := u.deliveryRepository.CreateMany(ctx, deliveries); err != nil {
+		return nil, err
+	}
+	return &deliveries[0], nil
+}
+
+func (u *WebhookUsecase) ListDeliveries(ctx context.Context, webhookID string, status domain.WebhookDeliveryStatus, page, limit int,
+	actor domain.Viewer) (*domain.WebhookDeliveryPage, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, domain.ErrForbidden
+	}
+	switch status {
+	case "", domain.DeliveryPending, domain.DeliverySucceeded, domain.DeliveryFailed:
+	default:
+		return nil, fmt.Errorf("%w: status must be %q, %q or %q", domain.ErrInvalidInput,
+			domain.DeliveryPending, domain.DeliverySucceeded, domain.DeliveryFailed)
+	}
+	if limit == 0 {
+		limit = defaultDeliveryLimit
+	}
+	if limit < 1 || limit > maxDeliveryLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidInput, maxDeliveryLimit)
+	}
+	if page == 0 {
+		page = 1
+	}
+	if page < 1 {
+		return nil, fmt.Errorf("%w: page must be at least 1", domain.ErrInvalidInput)
+	}
+	if _, err := u.webhookRepository.FindByID(ctx, webhookID); err != nil {
+		return nil, err
+	}
+
+	deliveries, total, err := u.deliveryRepository.ListByWebhook(ctx, webhookID, status, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	totalPages := int((total + int64(limit) - 1) / int64(limit)) // Ceiling division
+	return &domain.WebhookDeliveryPage{
+		Deliveries: deliveries,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1,
+	}, nil
+}
+
+func (u *WebhookUsecase) GetDelivery(ctx context.Context, deliveryID string, actor domain.Viewer) (*domain.WebhookDelivery, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, domain.ErrForbidden
+	}
+	return u.deliveryRepository.FindByID(ctx, deliveryID)
+}
+
+// ReplayDelivery sends the original payload, event ID included, as a new
+// delivery with its own attempts.
+func (u *WebhookUsecase) ReplayDelivery(ctx context.Context, deliveryID string, actor domain.Viewer) (*domain.WebhookDelivery, error) {
+	original, err := u.GetDelivery(ctx, deliveryID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.webhookRepository.FindByID(ctx, original.WebhookID); err != nil {
+		return nil, err
+	}
+	replay := newDelivery(original.WebhookID, original.EventID, original.EventType, original.Payload, time.Now())
+	replay.ReplayOf = original.ID
+	deliveries := []domain.WebhookDelivery{replay}
+	if err := u.deliveryRepository.CreateMany(ctx, deliveries); err != nil {
+		return nil, err
+	}
+	return &deliveries[0], nil
+}
+
+func validateWebhook(webhook *domain.Webhook) error {
+	parsed, err := url.Parse(webhook.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", domain.ErrInvalidInput)
+	}
+	if len(webhook.Secret) < minWebhookSecretLength {
+		return fmt.Errorf("%w: secret must be at least %d characters", domain.ErrInvalidInput, minWebhookSecretLength)
+	}
+	if len(webhook.Events) == 0 {
+		return fmt.Errorf("%w: subscribe to at least one event", domain.ErrInvalidInput)
+	}
+	events := make([]string, 0, len(webhook.Events))
+	for _, e := range webhook.Events {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if !slices.Contains(domain.WebhookEventTypes, e) {
+			return fmt.Errorf("%w: events must be among %s", domain.ErrInvalidInput, strings.Join(domain.WebhookEventTypes, ", "))
+		}
+		if !slices.Contains(events, e) {
+			events = append(events, e)
+		}
+	}
+	webhook.Events = events
+	if utf8.RuneCountInString(webhook.Description) > maxWebhookDescLength {
+		return fmt.Errorf("%w: description must be at most %d characters", domain.ErrInvalidInput, maxWebhookDescLength)
+	}
+	return nil
+}
+
+func newWebhookEventID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,62 @@
+package usecases
+
+import (
+	domain "blog-api/Domain"
+	"context"
+	"testing"
+	"time"
+)
+
+// subscribedWebhooks has every webhook subscribed to every event type.
+type subscribedWebhooks struct {
+	domain.IWebhookRepository
+	webhooks []domain.Webhook
+}
+
+func (r subscribedWebhooks) ListActive(context.Context, string) ([]domain.Webhook, error) {
+	return r.webhooks, nil
+}
+
+type queuedDeliveries struct {
+	domain.IWebhookDeliveryRepository
+	deliveries []domain.WebhookDelivery
+}
+
+func (r *queuedDeliveries) CreateMany(_ context.Context, deliveries []domain.WebhookDelivery) error {
+	r.deliveries = append(r.deliveries, deliveries...)
+	return nil
+}
+
+func TestWebhookHandleEvent(t *testing.T) {
+	comments := newMemCommentRepository(
+		domain.Comment{ID: "c1", BlogId: "b1", UserId: "alice", Content: "hi", CreatedAt: time.Now()},
+		domain.Comment{ID: "c2", BlogId: "b1", UserId: "alice", Deleted: true},
+	)
+	blogs := newMemBlogRepository(testBlog)
+	tests := []struct {
+		name           string
+		event          domain.Event
+		wantDeliveries int
+	}{
+		{name: "blog", event: domain.Event{Type: domain.EventBlogUpdated, BlogID: "b1"}, wantDeliveries: 2},
+		{name: "comment", event: domain.Event{Type: domain.EventCommentCreated, BlogID: "b1", CommentID: "c1"}, wantDeliveries: 2},
+		{name: "blog since deleted", event: domain.Event{Type: domain.EventBlogPublished, BlogID: "b2"}},
+		{name: "comment since deleted", event: domain.Event{Type: domain.EventCommentCreated, BlogID: "b1", CommentID: "c3"}},
+		{name: "comment since tombstoned", event: domain.Event{Type: domain.EventCommentCreated, BlogID: "b1", CommentID: "c2"}},
+		{name: "not a webhook event", event: domain.Event{Type: domain.EventUserFollowed}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deliveries := &queuedDeliveries{}
+			u := NewWebhookUsecase(subscribedWebhooks{webhooks: []domain.Webhook{{ID: "w1"}, {ID: "w2"}}}, deliveries, blogs, comments)
+
+			tt.event.ID = "e1"
+			if err := u.HandleEvent(context.Background(), tt.event); err != nil {
+				t.Fatalf("HandleEvent: %v", err)
+			}
+			if len(deliveries.deliveries) != tt.wantDeliveries {
+				t.Errorf("deliveries = %d, want %d", len(deliveries.deliveries), tt.wantDeliveries)
+			}
+		})
+	}
+}
@@ -1,10 +1,18 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	controllers "blog-api/Delivery/Controllers"
 	router "blog-api/Delivery/Router"
+	domain "blog-api/Domain"
 	infrastructure "blog-api/Infrastructure"
 	repositories "blog-api/Repositories"
 	usecases "blog-api/Usecases"
@@ -19,17 +27,27 @@ func main() {
 
 	jwtService := infrastructure.NewJWTService()
 	passwordService := infrastructure.NewPasswordService()
-	authMiddleware := infrastructure.NewAuthMiddleware(jwtService)
 
 	smtpPort := infrastructure.ParsePort(infrastructure.Env.EMAIL_PORT, 465)
 
-	emailService := infrastructure.NewSMTPEmailService(
-		infrastructure.Env.EMAIL_FROM,
-		infrastructure.Env.EMAIL_HOST,
-		smtpPort,
-		infrastructure.Env.EMAIL_USERNAME,
-		infrastructure.Env.EMAIL_PASSWORD,
-	)
+	transportName := infrastructure.Env.EMAIL_TRANSPORT
+	if transportName == "" {
+		transportName = "smtp"
+	}
+	emailTransport, err := infrastructure.NewEmailTransport(transportName, infrastructure.EmailTransportConfig{
+		Host:     infrastructure.Env.EMAIL_HOST,
+		Port:     smtpPort,
+		Username: infrastructure.Env.EMAIL_USERNAME,
+		Password: infrastructure.Env.EMAIL_PASSWORD,
+		Security: infrastructure.Env.EMAIL_SECURITY,
+		PoolSize: infrastructure.ParsePositiveInt(infrastructure.Env.EMAIL_POOL_SIZE, 2),
+		Dir:      infrastructure.Env.EMAIL_DIR,
+	})
+	if err != nil {
+		log.Fatalf("email transport: %v", err)
+	}
+	emailTemplates := infrastructure.NewEmailTemplates()
+	emailService := infrastructure.NewEmailService(infrastructure.Env.EMAIL_FROM, emailTemplates, emailTransport)
 
 	// Initialize repositories
 	userRepository := repositories.NewUserRepository(db)
@@ -37,13 +55,50 @@ func main() {
 	refreshRepository := repositories.NewRefreshTokenRepository(db)
 	blogRepository := repositories.NewBlogRepository(db)
 	resetPasswordRepo := repositories.NewPasswordResetTokenRepo(db)
-	likeRepo := repositories.NewLikeRepository(db)
 	commentRepository := repositories.NewCommentRepository(db)
+	blogRevisionRepository := repositories.NewBlogRevisionRepository(db)
+	tagRepository := repositories.NewTagRepository(db)
+	reportRepository := repositories.NewReportRepository(db)
+	moderationActionRepository := repositories.NewModerationActionRepository(db)
+	followRepository := repositories.NewFollowRepository(db)
+	bookmarkRepository := repositories.NewBookmarkRepository(db)
+	readingListRepository := repositories.NewReadingListRepository(db)
+	reactionRepository := repositories.NewReactionRepository(db)
+	blogViewRepository := repositories.NewBlogViewRepository(db)
+	notificationRepository := repositories.NewNotificationRepository(db)
+	webhookRepository := repositories.NewWebhookRepository(db)
+	webhookDeliveryRepository := repositories.NewWebhookDeliveryRepository(db)
+	outboxRepository := repositories.NewOutboxRepository(db)
+
+	// Blogs stored before slugs existed have none.
+	repositories.Migrate(db, "blogs.slug", func(ctx context.Context) error {
+		return usecases.BackfillSlugs(ctx, blogRepository)
+	})
+
+	authMiddleware := infrastructure.NewAuthMiddleware(jwtService, userRepository)
 
 	// Initialize AI service
 	Aiservice := infrastructure.NewAiService()
+	contentRenderer := infrastructure.NewContentRenderer()
+	feedEncoder := infrastructure.NewFeedEncoder()
+	eventBus := infrastructure.NewEventBus()
+	// Side effects that must not be lost (emails, notifications, webhooks)
+	// are recorded in the outbox and carried out by its dispatcher.
+	outbox := usecases.NewOutboxDispatcher(
+		outboxRepository,
+		infrastructure.ParseDuration(infrastructure.Env.OUTBOX_POLL_INTERVAL, 2*time.Second),
+		infrastructure.ParsePositiveInt(infrastructure.Env.OUTBOX_MAX_ATTEMPTS, 10),
+	)
+	// The in-process broker only reaches clients of this instance; a shared
+	// broker behind the same interface lets several instances fan out.
+	streamBroker := infrastructure.NewMemoryBroker()
+	streamHub := infrastructure.NewStreamHub(
+		streamBroker,
+		infrastructure.ParsePositiveInt(infrastructure.Env.STREAM_REPLAY_BUFFER, 1000),
+	)
 
 	// Initialize use cases
+	usecases.HandleEmails(outbox, emailService, emailVerificationRepo, resetPasswordRepo, passwordService)
 	userUsecase := usecases.NewUserUseCase(
 		userRepository,
 		emailVerificationRepo,
@@ -51,13 +106,114 @@ func main() {
 		resetPasswordRepo,
 		jwtService,
 		passwordService,
-		emailService,
+		outbox,
+		eventBus,
 		3*time.Second,
 	)
 	authUsecase := usecases.NewAuthUsecase(jwtService, userRepository, refreshRepository, 3*time.Second)
-	blogUsecase := usecases.NewBlogUseCase(blogRepository, Aiservice)
-	likeUsecase := usecases.NewLikeUsecase(likeRepo)
-	commentUsecase := usecases.NewCommentUsecase(commentRepository)
+	viewCounter := usecases.NewViewCounter(
+		blogRepository,
+		blogViewRepository,
+		infrastructure.ParseDuration(infrastructure.Env.VIEW_WINDOW, 24*time.Hour),
+		infrastructure.ParseDuration(infrastructure.Env.VIEW_FLUSH_INTERVAL, 10*time.Second),
+	)
+	blogUsecase := usecases.NewBlogUseCase(
+		blogRepository,
+		blogRevisionRepository,
+		tagRepository,
+		bookmarkRepository,
+		readingListRepository,
+		reactionRepository,
+		viewCounter,
+		eventBus,
+		contentRenderer,
+		Aiservice,
+	)
+	reactionUsecase := usecases.NewReactionUsecase(
+		reactionRepository,
+		blogRepository,
+		commentRepository,
+		userRepository,
+		eventBus,
+		infrastructure.ParseList(infrastructure.Env.REACTION_TYPES, []string{"like", "love", "insightful", "funny", "celebrate"}),
+	)
+	likeUsecase := usecases.NewLikeUsecase(reactionUsecase)
+	commentUsecase := usecases.NewCommentUsecase(
+		commentRepository,
+		blogRepository,
+		eventBus,
+		infrastructure.ParsePositiveInt(infrastructure.Env.COMMENT_MAX_DEPTH, 5),
+		infrastructure.ParseDuration(infrastructure.Env.COMMENT_EDIT_WINDOW, 15*time.Minute),
+	)
+	tagUsecase := usecases.NewTagUsecase(tagRepository, blogRepository, followRepository)
+	reportUsecase := usecases.NewReportUsecase(
+		reportRepository,
+		moderationActionRepository,
+		blogRepository,
+		commentRepository,
+		userRepository,
+		refreshRepository,
+		eventBus,
+	)
+	followUsecase := usecases.NewFollowUsecase(followRepository, userRepository, blogRepository, tagRepository, eventBus)
+	bookmarkUsecase := usecases.NewBookmarkUsecase(bookmarkRepository, readingListRepository, blogRepository, userRepository)
+	notificationUsecase := usecases.NewNotificationUsecase(notificationRepository, userRepository, blogRepository, eventBus)
+	eventBus.Subscribe(outbox.Durable("notifications", notificationUsecase.HandleEvent, domain.NotificationEvents...))
+	streamUsecase := usecases.NewStreamUsecase(
+		streamBroker,
+		streamHub,
+		blogRepository,
+		commentRepository,
+		reactionRepository,
+		notificationRepository,
+	)
+	eventBus.Subscribe(streamUsecase.HandleEvent)
+	webhookUsecase := usecases.NewWebhookUsecase(webhookRepository, webhookDeliveryRepository, blogRepository, commentRepository)
+	eventBus.Subscribe(outbox.Durable("webhooks", webhookUsecase.HandleEvent, domain.WebhookEvents...))
+	outboxUsecase := usecases.NewOutboxUsecase(outboxRepository)
+	emailTemplateUsecase := usecases.NewEmailTemplateUsecase(emailTemplates)
+	feedUsecase := usecases.NewFeedUsecase(
+		blogRepository,
+		userRepository,
+		tagRepository,
+		contentRenderer,
+		infrastructure.Env.SITE_URL,
+		infrastructure.Env.SITE_NAME,
+		infrastructure.ParsePositiveInt(infrastructure.Env.FEED_ITEMS, 20),
+		infrastructure.ParsePositiveInt(infrastructure.Env.FEED_MAX_ITEMS, 100),
+	)
+
+	// Background workers, stopped once the server has shut down
+	workers, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	blogPublisher := usecases.NewBlogPublisher(
+		blogRepository,
+		eventBus,
+		infrastructure.ParseDuration(infrastructure.Env.PUBLISH_INTERVAL, 30*time.Second),
+	)
+	go blogPublisher.Run(workers)
+	counterReconciler := usecases.NewCounterReconciler(
+		blogRepository,
+		infrastructure.ParseDuration(infrastructure.Env.COUNTER_RECONCILE_INTERVAL, time.Hour),
+	)
+	go counterReconciler.Run(workers)
+	go viewCounter.Run(workers)
+	trendingUpdater := usecases.NewTrendingUpdater(
+		blogRepository,
+		infrastructure.ParseDuration(infrastructure.Env.TRENDING_INTERVAL, 15*time.Minute),
+	)
+	go trendingUpdater.Run(workers)
+	go streamHub.Run(workers)
+	webhookDispatcher := usecases.NewWebhookDispatcher(
+		webhookRepository,
+		webhookDeliveryRepository,
+		infrastructure.NewWebhookSender(10*time.Second),
+		infrastructure.ParseDuration(infrastructure.Env.WEBHOOK_POLL_INTERVAL, 5*time.Second),
+		infrastructure.ParsePositiveInt(infrastructure.Env.WEBHOOK_MAX_ATTEMPTS, 8),
+		infrastructure.ParsePositiveInt(infrastructure.Env.WEBHOOK_DISABLE_AFTER, 15),
+	)
+	go webhookDispatcher.Run(workers)
+	go outbox.Run(workers)
 
 	// Initialize controllers
 	userController := controllers.NewUserController(userUsecase)
@@ -65,14 +221,52 @@ func main() {
 	blogController := controllers.NewBlogController(blogUsecase)
 	likeController := controllers.NewLikeController(likeUsecase)
 	commentController := controllers.NewCommentController(commentUsecase)
+	feedController := controllers.NewFeedController(feedUsecase, feedEncoder)
+	tagController := controllers.NewTagController(tagUsecase)
+	reportController := controllers.NewReportController(reportUsecase)
+	followController := controllers.NewFollowController(followUsecase)
+	bookmarkController := controllers.NewBookmarkController(bookmarkUsecase)
+	reactionController := controllers.NewReactionController(reactionUsecase)
+	notificationController := controllers.NewNotificationController(notificationUsecase)
+	streamController := controllers.NewStreamController(streamUsecase)
+	webhookController := controllers.NewWebhookController(webhookUsecase)
+	outboxController := controllers.NewOutboxController(outboxUsecase)
+	emailTemplateController := controllers.NewEmailTemplateController(emailTemplateUsecase)
 
 	// Setup router
-	r := router.SetupRouter(userController, authController, blogController, likeController, authMiddleware, commentController)
+	r := router.SetupRouter(userController, authController, blogController, likeController, authMiddleware, commentController, feedController, tagController, reportController, followController, bookmarkController, reactionController, notificationController, streamController, webhookController, outboxController, emailTemplateController)
 
 	port := infrastructure.Env.PORT
 	if port == "" {
 		port = "8080"
 	}
-	
-	r.Run(":" + port)
+
+	server := &http.Server{Addr: ":" + port, Handler: r}
+	// Open event streams would otherwise hold up a graceful shutdown.
+	server.RegisterOnShutdown(streamHub.Close)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("server failed: %v", err)
+		}
+	}()
+
+	// On SIGINT or SIGTERM, finish the requests in flight and then write out
+	// the views still buffered.
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	<-stop
+	log.Println("shutting down")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("warning: server shutdown: %v", err)
+	}
+	stopWorkers()
+	if err := emailTransport.Close(); err != nil {
+		log.Printf("warning: failed to close email transport: %v", err)
+	}
+	if err := viewCounter.Flush(ctx); err != nil {
+		log.Printf("warning: failed to flush views: %v", err)
+	}
 }